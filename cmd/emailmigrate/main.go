@@ -0,0 +1,60 @@
+// Command emailmigrate stores the canonical form of the email addresses
+// added before the addresses were canonicalized on write, or after the
+// provider rules were switched. Invalid addresses and addresses colliding
+// with each other are left untouched and listed in a CSV report.
+//
+// It must run after the migration 000011 and before the service is deployed,
+// as the addresses without the canonical form are not found by email. The
+// service refuses to start while such addresses exist, unless the
+// database.allow_uncanonical_emails option is set, e.g. to keep serving the
+// addresses left untouched.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"io"
+	"log"
+	"os"
+
+	_ "github.com/lib/pq"
+
+	"github.com/chutommy/user-microservice/pkg/mailaddr"
+	"github.com/chutommy/user-microservice/pkg/repo"
+)
+
+var dbURL = flag.String("db_url", "", "database URL of the user service")
+var providerRules = flag.Bool("provider-rules", false, "ignore provider aliases, must match the email-provider-rules flag of the service")
+var dryRun = flag.Bool("dry-run", false, "report the problems without writing any changes")
+var reportFile = flag.String("report", "", "file of the CSV report, standard output if empty")
+
+func main() {
+	flag.Parse()
+
+	db, err := sql.Open("postgres", *dbURL)
+	if err != nil {
+		log.Fatalf("failed to open the database: %v", err)
+	}
+	defer db.Close()
+
+	report, err := mailaddr.Migrate(context.Background(), repo.New(db), *providerRules, *dryRun)
+	if err != nil {
+		log.Fatalf("migration failed after %d canonicalized addresses: %v", report.Canonicalized, err)
+	}
+
+	var w io.Writer = os.Stdout
+	if *reportFile != "" {
+		f, err := os.Create(*reportFile)
+		if err != nil {
+			log.Fatalf("failed to create the report: %v", err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err = report.WriteCSV(w); err != nil {
+		log.Fatalf("failed to write the report: %v", err)
+	}
+
+	log.Printf("canonicalized %d email addresses, %d problems (dry run: %t)", report.Canonicalized, len(report.Problems), *dryRun)
+}
@@ -1,9 +1,31 @@
 package main
 
 import (
+	"errors"
+	"flag"
+	"log"
+	"os"
+
 	"github.com/chutommy/user-microservice/cmd/service"
 )
 
 func main() {
-	service.Run()
+	args := os.Args[1:]
+
+	var err error
+	switch {
+	case len(args) >= 2 && args[0] == "config" && args[1] == "print":
+		err = service.PrintConfig(os.Stdout, args[2:])
+	case len(args) >= 1 && args[0] == "migrate":
+		err = service.Migrate(os.Stdout, args[1:])
+	default:
+		err = service.Run(args)
+	}
+
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
 }
@@ -0,0 +1,53 @@
+// Command phonemigrate normalizes the phone numbers stored before the
+// numbers were converted to E.164 on write. Numbers which can not be
+// converted are left untouched and listed in a CSV report.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"io"
+	"log"
+	"os"
+
+	_ "github.com/lib/pq"
+
+	"github.com/chutommy/user-microservice/pkg/phone"
+	"github.com/chutommy/user-microservice/pkg/repo"
+)
+
+var dbURL = flag.String("db_url", "", "database URL of the user service")
+var region = flag.String("region", "", "region of the numbers stored without the international prefix")
+var dryRun = flag.Bool("dry-run", false, "report the problems without writing any changes")
+var reportFile = flag.String("report", "", "file of the CSV report, standard output if empty")
+
+func main() {
+	flag.Parse()
+
+	db, err := sql.Open("postgres", *dbURL)
+	if err != nil {
+		log.Fatalf("failed to open the database: %v", err)
+	}
+	defer db.Close()
+
+	report, err := phone.Migrate(context.Background(), repo.New(db), *region, *dryRun)
+	if err != nil {
+		log.Fatalf("migration failed after %d converted numbers: %v", report.Converted, err)
+	}
+
+	var w io.Writer = os.Stdout
+	if *reportFile != "" {
+		f, err := os.Create(*reportFile)
+		if err != nil {
+			log.Fatalf("failed to create the report: %v", err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err = report.WriteCSV(w); err != nil {
+		log.Fatalf("failed to write the report: %v", err)
+	}
+
+	log.Printf("converted %d phone numbers, %d problems (dry run: %t)", report.Converted, len(report.Problems), *dryRun)
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/chutommy/user-microservice/pkg/config"
+	"github.com/chutommy/user-microservice/pkg/migration"
+)
+
+// ErrMigrateUsage is returned by Migrate if the command is not recognized.
+var ErrMigrateUsage = errors.New("usage: migrate up [N] | down [N] | status | force VERSION [flags]")
+
+// newMigrator opens a dedicated connection to the database of the service
+// and constructs a Migrator of it.
+func newMigrator(cfg *config.Config, logger *zap.Logger) (*migration.Migrator, error) {
+	db, err := sql.Open("postgres", string(cfg.Database.URL))
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := migration.New(db, logger)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// autoMigrate applies the pending migrations on start, see
+// migration.Migrator.UpLocked.
+func autoMigrate(cfg *config.Config, logger *zap.Logger) error {
+	m, err := newMigrator(cfg, logger)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Database.MigrateTimeout)
+	defer cancel()
+
+	return m.UpLocked(ctx)
+}
+
+// parseSteps parses the optional number of migrations to apply or revert.
+func parseSteps(operand string, def int) (int, error) {
+	if operand == "" {
+		return def, nil
+	}
+
+	n, err := strconv.Atoi(operand)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("%w: %q", migration.ErrInvalidSteps, operand)
+	}
+
+	return n, nil
+}
+
+// Migrate runs the migrate command given by the arguments: "up [N]" applies
+// N or all pending migrations, "down [N]" reverts N or one migration,
+// "status" only reports the version and "force VERSION" marks the schema as
+// clean at the version. The configuration flags follow the command. The
+// status of the schema is written to w.
+func Migrate(w io.Writer, args []string) error {
+	if len(args) == 0 {
+		return ErrMigrateUsage
+	}
+	command, args := args[0], args[1:]
+	var operand string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		operand, args = args[0], args[1:]
+	}
+
+	var run func(m *migration.Migrator) error
+	switch command {
+	case "up":
+		n, err := parseSteps(operand, 0)
+		if err != nil {
+			return err
+		}
+		run = func(m *migration.Migrator) error { return m.Up(n) }
+	case "down":
+		n, err := parseSteps(operand, 1)
+		if err != nil {
+			return err
+		}
+		run = func(m *migration.Migrator) error { return m.Down(n) }
+	case "force":
+		version, err := strconv.Atoi(operand)
+		if err != nil {
+			return ErrMigrateUsage
+		}
+		run = func(m *migration.Migrator) error { return m.Force(version) }
+	case "status":
+		if operand != "" {
+			return ErrMigrateUsage
+		}
+		run = func(*migration.Migrator) error { return nil }
+	default:
+		return ErrMigrateUsage
+	}
+
+	cfg, err := loadConfig(args)
+	if err != nil {
+		return err
+	}
+	if err = cfg.Validate(); err != nil {
+		return err
+	}
+
+	var logger *zap.Logger
+	if cfg.Debug {
+		logger, err = zap.NewDevelopment()
+	} else {
+		logger, err = zap.NewProduction()
+	}
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = logger.Sync()
+	}()
+
+	m, err := newMigrator(cfg, logger)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	if err = run(m); err != nil {
+		return err
+	}
+
+	status, err := m.Status()
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "version: %d\nlatest: %d\ndirty: %t\n", status.Version, status.Latest, status.Dirty)
+
+	return err
+}
@@ -1,10 +1,12 @@
 package service
 
 import (
-	"database/sql"
-	"flag"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -12,32 +14,74 @@ import (
 
 	gmdw "github.com/grpc-ecosystem/go-grpc-middleware"
 	gzap "github.com/grpc-ecosystem/go-grpc-middleware/logging/zap"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
 	gtags "github.com/grpc-ecosystem/go-grpc-middleware/tags"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	_ "github.com/lib/pq"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/protobuf/encoding/protojson"
 
+	"github.com/chutommy/user-microservice/pkg/age"
+	"github.com/chutommy/user-microservice/pkg/apierror"
+	"github.com/chutommy/user-microservice/pkg/attribute"
+	"github.com/chutommy/user-microservice/pkg/blob"
+	"github.com/chutommy/user-microservice/pkg/cache"
+	"github.com/chutommy/user-microservice/pkg/certs"
+	"github.com/chutommy/user-microservice/pkg/config"
+	"github.com/chutommy/user-microservice/pkg/dbconn"
+	"github.com/chutommy/user-microservice/pkg/event"
+	"github.com/chutommy/user-microservice/pkg/gateway"
 	"github.com/chutommy/user-microservice/pkg/grpc/userpb"
+	"github.com/chutommy/user-microservice/pkg/health"
+	"github.com/chutommy/user-microservice/pkg/idempotency"
+	"github.com/chutommy/user-microservice/pkg/metrics"
+	"github.com/chutommy/user-microservice/pkg/migration"
+	"github.com/chutommy/user-microservice/pkg/preference"
 	"github.com/chutommy/user-microservice/pkg/repo"
 	"github.com/chutommy/user-microservice/pkg/service"
+	"github.com/chutommy/user-microservice/pkg/tracing"
+	"github.com/chutommy/user-microservice/pkg/validation"
 )
 
-var fs = flag.NewFlagSet("user", flag.ExitOnError)
-var debugMode = fs.Bool("debug", false, "enable development level logging")
-var _ = fs.String("http-port", "8081", "HTTP listen address port")
-var grpcPort = fs.String("grpc-port", "8082", "gRPC listen address port")
-var dbURL = fs.String("db_url", "", "database URL of the user service")
+// loadConfig loads the configuration of the service from the arguments, the
+// environment and the config file.
+func loadConfig(args []string) (*config.Config, error) {
+	return config.Load("user", args, os.LookupEnv, os.Stderr)
+}
+
+// PrintConfig writes the effective configuration with the secrets masked,
+// and reports its problems.
+func PrintConfig(w io.Writer, args []string) error {
+	cfg, err := loadConfig(args)
+	if err != nil {
+		return err
+	}
+	if err = config.Print(w, cfg); err != nil {
+		return err
+	}
+
+	return cfg.Validate()
+}
 
-func Run() (err error) {
-	err = fs.Parse(os.Args[1:])
+// Run runs the user service configured by the arguments.
+func Run(args []string) (err error) {
+	cfg, err := loadConfig(args)
 	if err != nil {
 		return err
 	}
+	if err = cfg.Validate(); err != nil {
+		return err
+	}
 
 	// create a structured logger
 	var logger *zap.Logger
-	if *debugMode {
+	if cfg.Debug {
 		logger, err = zap.NewDevelopment()
 	} else {
 		logger, err = zap.NewProduction()
@@ -49,59 +93,233 @@ func Run() (err error) {
 		err = logger.Sync()
 	}()
 
-	// connect to the DB
-	dbLog := logger.With(zap.String("db_conn_url", *dbURL))
-	var attempts int8 = 3
-	var db *sql.DB
-	for a := int8(1); a <= attempts; a++ {
-		time.Sleep(3000 * time.Millisecond)
-
-		db, err = sql.Open("postgres", *dbURL)
-		if err != nil {
-			dbLog.Warn(
-				"failed to open database",
-				zap.Int8("attempt", a),
-				zap.Error(err),
-			)
-		} else {
-			break
-		}
+	// init tracing
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing.Exporter, cfg.Tracing.OTLPEndpoint)
+	if err != nil {
+		logger.Fatal(
+			"failed to set up tracing",
+			zap.String("exporter", cfg.Tracing.Exporter),
+			zap.Error(err),
+		)
 	}
+
+	// connect to the DB
+	connectCtx, cancelConnect := context.WithTimeout(context.Background(), cfg.Database.ConnectTimeout)
+	db, err := dbconn.Open(connectCtx, "postgres", string(cfg.Database.URL), dbconn.Pool{
+		MaxOpenConns:    cfg.Database.MaxOpenConns,
+		MaxIdleConns:    cfg.Database.MaxIdleConns,
+		ConnMaxLifetime: cfg.Database.ConnMaxLifetime,
+		ConnMaxIdleTime: cfg.Database.ConnMaxIdleTime,
+	}, dbconn.DefaultBackoff, logger)
+	cancelConnect()
 	if err != nil {
-		dbLog.Fatal(
-			"failed to connect to the database after the max number of attempts",
-			zap.Int8("max_attempts", attempts),
+		logger.Fatal(
+			"failed to connect to the database",
+			zap.Duration("timeout", cfg.Database.ConnectTimeout),
 			zap.Error(err),
 		)
 	}
-	// test the DB connection
-	if db.Ping() != nil {
-		dbLog.Fatal(
-			"failed to ping the database",
+
+	// migrate the schema
+	schemaVersion, err := migration.Latest()
+	if err != nil {
+		logger.Fatal("failed to read the embedded migrations", zap.Error(err))
+	}
+	if cfg.Database.AutoMigrate {
+		if err = autoMigrate(cfg, logger); err != nil {
+			logger.Fatal("failed to migrate the schema", zap.Error(err))
+		}
+	}
+	schemaProbe := health.SchemaProbe(db, schemaVersion)
+	if err = schemaProbe(context.Background()); err != nil {
+		logger.Fatal(
+			"refusing to serve with an outdated schema, run the migrations",
+			zap.Uint("required_version", schemaVersion),
 			zap.Error(err),
 		)
 	}
-	dbLog.Info("successfully connected to the database")
 
 	// construct a repo
 	qrs := repo.New(db)
+	var querier repo.Querier = tracing.NewQuerier(qrs)
+
+	// the addresses added before their canonicalization can not be looked up
+	// until cmd/emailmigrate stores their canonical form
+	n, err := qrs.CountUncanonicalEmails(context.Background())
+	switch {
+	case err != nil:
+		logger.Fatal("failed to count the email addresses without the canonical form", zap.Error(err))
+	case n > 0 && cfg.Database.AllowUncanonicalEmails:
+		logger.Warn("email addresses without the canonical form found, run cmd/emailmigrate", zap.Int64("count", n))
+	case n > 0:
+		logger.Fatal(
+			"refusing to serve with email addresses without the canonical form, run cmd/emailmigrate",
+			zap.Int64("count", n),
+		)
+	}
+
+	// init metrics
+	mtr := metrics.New()
+	mtr.MustRegister(
+		metrics.NewDBStatsCollector(db),
+		metrics.NewUserCountCollector(qrs),
+	)
+
+	// cache the users
+	if cfg.Cache.Size > 0 {
+		cacheOpts := []cache.Option{
+			cache.WithCounters(mtr.CacheLookups.WithLabelValues("hit"), mtr.CacheLookups.WithLabelValues("miss")),
+		}
+		if cfg.Cache.InvalidationChannel != "" {
+			cacheOpts = append(cacheOpts, cache.WithNotifications(db, cfg.Cache.InvalidationChannel, logger))
+		}
+
+		cached, err := cache.NewQuerier(querier, cfg.Cache.Size, cfg.Cache.TTL, cfg.Cache.NegativeTTL, cacheOpts...)
+		if err != nil {
+			logger.Fatal("failed to create the user cache", zap.Error(err))
+		}
+		querier = cached
+
+		listenCtx, listenCancel := context.WithCancel(context.Background())
+		defer listenCancel()
+		go cached.Listen(listenCtx, string(cfg.Database.URL))
+	}
 
 	// build a logger interceptor middleware
 	opts := []gzap.Option{}
 
+	// open the blob store
+	blobs, err := blob.NewFileStore(cfg.Storage.BlobDir)
+	if err != nil {
+		logger.Fatal(
+			"failed to open the blob store",
+			zap.String("blob_dir", cfg.Storage.BlobDir),
+			zap.Error(err),
+		)
+	}
+
+	// load the default preferences
+	var prefDefaults preference.Defaults
+	if cfg.Profile.PreferenceDefaults != "" {
+		prefDefaults, err = loadPreferenceDefaults(cfg.Profile.PreferenceDefaults)
+		if err != nil {
+			logger.Fatal(
+				"failed to load the default preferences",
+				zap.String("file", cfg.Profile.PreferenceDefaults),
+				zap.Error(err),
+			)
+		}
+	}
+
+	// load the attributes schema
+	var attrValidator *attribute.Validator
+	if cfg.Profile.AttributesSchema != "" {
+		attrValidator, err = loadAttributeSchema(cfg.Profile.AttributesSchema)
+		if err != nil {
+			logger.Fatal(
+				"failed to load the attributes schema",
+				zap.String("file", cfg.Profile.AttributesSchema),
+				zap.Error(err),
+			)
+		}
+	}
+
+	agePolicy, err := age.ParsePolicy(cfg.Profile.MinimumAge)
+	if err != nil {
+		logger.Fatal("invalid minimum age policy", zap.Error(err))
+	}
+
 	// init user service's server
-	userSrv := service.NewUserServer(qrs)
-	grpcSrv := grpc.NewServer(
+	userSrv := service.NewUserServer(
+		querier,
+		service.WithPublisher(event.NewPostgresPublisher(db, cfg.Database.EventsChannel)),
+		service.WithBlobStore(blobs),
+		service.WithSigningKey([]byte(cfg.Exports.SigningKey)),
+		service.WithPreferenceDefaults(prefDefaults),
+		service.WithAttributeSchema(attrValidator),
+		service.WithIndexedAttributes(cfg.Profile.IndexedAttributes),
+		service.WithAvatarBaseURL(cfg.Storage.AvatarBaseURL),
+		service.WithDefaultPhoneRegion(cfg.Profile.PhoneRegion),
+		service.WithEmailProviderRules(cfg.Profile.EmailProviderRules),
+		service.WithAdminIdentities(cfg.TLS.AdminIdentities...),
+		service.WithMinimumAge(agePolicy),
+		service.WithBcryptObserver(mtr.BcryptDuration),
+	)
+
+	// republish the erasure events which failed to be delivered
+	eventsCtx, eventsCancel := context.WithCancel(ctxzap.ToContext(context.Background(), logger))
+	defer eventsCancel()
+	go userSrv.RunErasureEvents(eventsCtx, cfg.Database.EventsRetryInterval)
+
+	// load the certificates
+	var reloader *certs.Reloader
+	grpcCreds := []grpc.ServerOption{}
+	dialCreds := grpc.WithInsecure()
+	if cfg.TLS.Enabled() {
+		reloader, err = certs.NewReloader(cfg.TLS.CertFile, cfg.TLS.KeyFile, cfg.TLS.ClientCAFile, logger,
+			certs.WithGatewayIdentity(cfg.TLS.GatewayIdentity))
+		if err != nil {
+			logger.Fatal("failed to load the certificates", zap.Error(err))
+		}
+		logger.Info(
+			"TLS enabled",
+			zap.Bool("mutual", reloader.MutualTLS()),
+			zap.Time("not_after", reloader.Leaf().NotAfter),
+		)
+		grpcCreds = append(grpcCreds, grpc.Creds(credentials.NewTLS(reloader.ServerConfig())))
+		dialCreds = grpc.WithTransportCredentials(credentials.NewTLS(reloader.ClientConfig(cfg.TLS.ServerName)))
+
+		reloadCtx, reloadCancel := context.WithCancel(context.Background())
+		defer reloadCancel()
+		go reloader.Run(reloadCtx, cfg.TLS.ReloadInterval)
+	}
+
+	// replay the responses of the retried mutations
+	var keys *idempotency.Keys
+	if cfg.Idempotency.TTL > 0 {
+		keys = idempotency.New(querier, cfg.Idempotency.TTL, cfg.Idempotency.LockTimeout, logger, idempotentMethods()...)
+
+		cleanupCtx, cleanupCancel := context.WithCancel(context.Background())
+		defer cleanupCancel()
+		go keys.Run(cleanupCtx, cfg.Idempotency.CleanupInterval)
+	}
+
+	grpcSrv := grpc.NewServer(append(grpcCreds,
 		gmdw.WithUnaryServerChain(
+			mtr.GRPC.UnaryServerInterceptor(),
+			otelgrpc.UnaryServerInterceptor(),
 			gtags.UnaryServerInterceptor(gtags.WithFieldExtractor(gtags.CodeGenRequestFieldExtractor)),
+			tracing.UnaryServerInterceptor(),
+			reloader.UnaryServerInterceptor(),
 			gzap.UnaryServerInterceptor(logger, opts...),
+			apierror.UnaryServerInterceptor(),
+			validation.UnaryServerInterceptor(),
+			keys.UnaryServerInterceptor(),
 		),
-	)
+		gmdw.WithStreamServerChain(
+			mtr.GRPC.StreamServerInterceptor(),
+			otelgrpc.StreamServerInterceptor(),
+			gtags.StreamServerInterceptor(gtags.WithFieldExtractor(gtags.CodeGenRequestFieldExtractor)),
+			tracing.StreamServerInterceptor(),
+			reloader.StreamServerInterceptor(),
+			gzap.StreamServerInterceptor(logger, opts...),
+			apierror.StreamServerInterceptor(),
+			validation.StreamServerInterceptor(),
+		),
+	)...)
 	userpb.RegisterUserServiceServer(grpcSrv, userSrv)
 	reflection.Register(grpcSrv)
 
+	// health checks
+	checker := health.NewChecker(db, schemaProbe, logger, userpb.UserService_ServiceDesc.ServiceName)
+	healthpb.RegisterHealthServer(grpcSrv, checker.Server())
+	healthCtx, healthCancel := context.WithCancel(context.Background())
+	defer healthCancel()
+	go checker.Run(healthCtx, cfg.Server.HealthInterval)
+	mtr.GRPC.InitializeMetrics(grpcSrv)
+
 	// listen
-	address := fmt.Sprintf("0.0.0.0:%s", *grpcPort)
+	address := fmt.Sprintf("0.0.0.0:%s", cfg.Server.GRPCPort)
 	l, err := net.Listen("tcp", address)
 	if err != nil {
 		logger.Fatal(
@@ -123,6 +341,100 @@ func Run() (err error) {
 		}
 	}()
 
+	// connect the gateway to the gRPC server
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	grpcEndpoint := fmt.Sprintf("localhost:%s", cfg.Server.GRPCPort)
+	conn, err := grpc.DialContext(ctx, grpcEndpoint,
+		dialCreds,
+		grpc.WithUnaryInterceptor(otelgrpc.UnaryClientInterceptor()),
+		grpc.WithStreamInterceptor(otelgrpc.StreamClientInterceptor()),
+	)
+	if err != nil {
+		logger.Fatal(
+			"failed to dial the gRPC server",
+			zap.String("endpoint", grpcEndpoint),
+			zap.Error(err),
+		)
+	}
+	defer conn.Close()
+
+	// init gateway, dates are represented in ISO 8601
+	mux := runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.HTTPBodyMarshaler{
+			Marshaler: &gateway.DateMarshaler{
+				JSONPb: runtime.JSONPb{
+					MarshalOptions:   protojson.MarshalOptions{EmitUnpopulated: true},
+					UnmarshalOptions: protojson.UnmarshalOptions{DiscardUnknown: true},
+				},
+			},
+		}),
+		runtime.WithMetadata(metrics.GatewayMetadata),
+		runtime.WithMetadata(certs.GatewayMetadata),
+		runtime.WithMetadata(idempotency.GatewayMetadata),
+		runtime.WithIncomingHeaderMatcher(certs.HeaderMatcher),
+		runtime.WithErrorHandler(apierror.ErrorHandler),
+		runtime.WithRoutingErrorHandler(apierror.RoutingErrorHandler),
+	)
+	if err = userpb.RegisterUserServiceHandler(ctx, mux, conn); err != nil {
+		logger.Fatal("failed to register the gateway handler", zap.Error(err))
+	}
+	client := userpb.NewUserServiceClient(conn)
+	if err = mux.HandlePath(http.MethodGet, gateway.ExportPath, metrics.Route("ExportUsers", certs.ForwardCaller(gateway.ExportHandler(client)))); err != nil {
+		logger.Fatal("failed to register the export handler", zap.Error(err))
+	}
+	if err = mux.HandlePath(http.MethodPost, gateway.AvatarUploadPath, metrics.Route("UploadAvatar", certs.ForwardCaller(gateway.AvatarUploadHandler(client)))); err != nil {
+		logger.Fatal("failed to register the avatar upload handler", zap.Error(err))
+	}
+	if err = mux.HandlePath(http.MethodGet, gateway.AvatarPath, metrics.Route("GetAvatar", gateway.AvatarHandler(blobs))); err != nil {
+		logger.Fatal("failed to register the avatar handler", zap.Error(err))
+	}
+
+	httpSrv := &http.Server{
+		Addr:    fmt.Sprintf("0.0.0.0:%s", cfg.Server.HTTPPort),
+		Handler: otelhttp.NewHandler(mtr.Gateway(mux), "gateway"),
+	}
+
+	// gateway
+	go func() {
+		logger.Info("user service's gateway online", zap.String("address", httpSrv.Addr))
+
+		var err error
+		if reloader != nil {
+			httpSrv.TLSConfig = reloader.ServerConfig()
+			err = httpSrv.ListenAndServeTLS("", "")
+		} else {
+			err = httpSrv.ListenAndServe()
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Fatal(
+				"gateway shutdown err",
+				zap.Error(err),
+			)
+		}
+	}()
+
+	// admin server
+	adminMux := http.NewServeMux()
+	adminMux.Handle(metrics.Path, mtr.Handler())
+	adminMux.HandleFunc(health.LivenessPath, checker.Liveness)
+	adminMux.HandleFunc(health.ReadinessPath, checker.Readiness)
+	adminSrv := &http.Server{
+		Addr:    fmt.Sprintf("0.0.0.0:%s", cfg.Server.AdminPort),
+		Handler: adminMux,
+	}
+	go func() {
+		logger.Info("user service's admin server online", zap.String("address", adminSrv.Addr))
+
+		if err := adminSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Fatal(
+				"admin server shutdown err",
+				zap.Error(err),
+			)
+		}
+	}()
+
 	// listen for signal
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, syscall.SIGINT, syscall.SIGTERM)
@@ -130,5 +442,63 @@ func Run() (err error) {
 	logger.Info("received terminate signal", zap.String("signal", sig.String()))
 	close(c)
 
+	// shutdown gracefully, stop accepting the traffic first
+	checker.Shutdown()
+	healthCancel()
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer shutdownCancel()
+	if err := httpSrv.Shutdown(shutdownCtx); err != nil {
+		logger.Warn("failed to shutdown the gateway", zap.Error(err))
+	}
+	grpcSrv.GracefulStop()
+	if err := adminSrv.Shutdown(shutdownCtx); err != nil {
+		logger.Warn("failed to shutdown the admin server", zap.Error(err))
+	}
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Warn("failed to flush the traces", zap.Error(err))
+	}
+
 	return nil
 }
+
+// loadPreferenceDefaults reads the default preferences from the file.
+func loadPreferenceDefaults(name string) (preference.Defaults, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return preference.LoadDefaults(f)
+}
+
+// loadAttributeSchema compiles the JSON Schema of the custom attributes from the file.
+func loadAttributeSchema(name string) (*attribute.Validator, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return attribute.NewValidator(f)
+}
+
+// idempotentMethods lists the mutating unary methods which accept an
+// idempotency key.
+func idempotentMethods() []string {
+	names := []string{
+		"RegisterUser", "UpdateUser", "DeleteUser", "EraseUser",
+		"RequestDataExport", "RecordConsent", "WithdrawConsent", "PublishConsentPolicy",
+		"SetPreferences", "DeletePreference",
+		"CreateAddress", "UpdateAddress", "DeleteAddress",
+		"AddEmail", "RemoveEmail", "SetPrimaryEmail",
+		"RequestGuardianConsent", "ConfirmGuardianConsent",
+	}
+
+	methods := make([]string, 0, len(names))
+	for _, name := range names {
+		methods = append(methods, "/"+userpb.UserService_ServiceDesc.ServiceName+"/"+name)
+	}
+
+	return methods
+}
@@ -0,0 +1,166 @@
+// Package ctl implements userctl, the command-line administration tool of
+// the user service.
+package ctl
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/chutommy/user-microservice/pkg/grpc/userpb"
+)
+
+// AddressEnv is the environment variable of the default server address.
+const AddressEnv = "USERCTL_ADDRESS"
+
+// DefaultAddress is the address of the server if neither the flag nor the
+// environment variable is set.
+const DefaultAddress = "localhost:8082"
+
+// ErrNoCACertificates is returned if the CA file contains no certificate.
+var ErrNoCACertificates = errors.New("no CA certificates found")
+
+// options are the global flags of the commands.
+type options struct {
+	address string
+	timeout time.Duration
+	output  string
+
+	tls        bool
+	caFile     string
+	certFile   string
+	keyFile    string
+	serverName string
+	insecure   bool
+
+	// out prints the results, set before the commands run
+	out printer
+}
+
+// tlsConfig returns the client TLS configuration, nil if TLS is disabled.
+func (o *options) tlsConfig() (*tls.Config, error) {
+	if !o.tls && o.caFile == "" && o.certFile == "" {
+		return nil, nil
+	}
+
+	cfg := &tls.Config{
+		ServerName:         o.serverName,
+		InsecureSkipVerify: o.insecure, //nolint:gosec
+		MinVersion:         tls.VersionTLS12,
+	}
+	if o.caFile != "" {
+		pem, err := ioutil.ReadFile(o.caFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%w: %s", ErrNoCACertificates, o.caFile)
+		}
+	}
+	if o.certFile != "" || o.keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(o.certFile, o.keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
+
+// dial connects to the user service.
+func (o *options) dial(ctx context.Context) (*grpc.ClientConn, error) {
+	tlsCfg, err := o.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	creds := grpc.WithInsecure()
+	if tlsCfg != nil {
+		creds = grpc.WithTransportCredentials(credentials.NewTLS(tlsCfg))
+	}
+
+	return grpc.DialContext(ctx, o.address, creds, grpc.WithBlock(), grpc.FailOnNonTempDialError(true))
+}
+
+// call connects to the user service and calls fn within the timeout.
+func (o *options) call(fn func(ctx context.Context, client userpb.UserServiceClient) error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), o.timeout)
+	defer cancel()
+
+	conn, err := o.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", o.address, err)
+	}
+	defer conn.Close()
+
+	return fn(ctx, userpb.NewUserServiceClient(conn))
+}
+
+// NewCommand returns the root command of userctl. The results are written
+// to out.
+func NewCommand(out io.Writer) *cobra.Command {
+	opts := &options{}
+
+	cmd := &cobra.Command{
+		Use:           "userctl",
+		Short:         "Manage the users of the user service",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+			var err error
+			opts.out, err = newPrinter(opts.output, cmd.OutOrStdout())
+			return err
+		},
+	}
+	cmd.SetOut(out)
+
+	address := os.Getenv(AddressEnv)
+	if address == "" {
+		address = DefaultAddress
+	}
+
+	flags := cmd.PersistentFlags()
+	flags.StringVarP(&opts.address, "address", "a", address, "address of the gRPC server, also read from "+AddressEnv)
+	flags.DurationVar(&opts.timeout, "timeout", 30*time.Second, "timeout of the command")
+	flags.StringVarP(&opts.output, "output", "o", formatTable, "output format: table, json or yaml")
+	flags.BoolVar(&opts.tls, "tls", false, "connect over TLS, implied by the other TLS flags")
+	flags.StringVar(&opts.caFile, "ca-file", "", "PEM bundle of the CAs verifying the server, the system roots if empty")
+	flags.StringVar(&opts.certFile, "cert-file", "", "PEM client certificate authenticating to a server with mutual TLS")
+	flags.StringVar(&opts.keyFile, "key-file", "", "PEM private key of the client certificate")
+	flags.StringVar(&opts.serverName, "server-name", "", "name of the server verified in its certificate, the host of the address if empty")
+	flags.BoolVar(&opts.insecure, "insecure-skip-verify", false, "do not verify the server certificate")
+
+	_ = cmd.RegisterFlagCompletionFunc("output", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return formats, cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = cmd.MarkPersistentFlagFilename("ca-file", "pem", "crt")
+	_ = cmd.MarkPersistentFlagFilename("cert-file", "pem", "crt")
+	_ = cmd.MarkPersistentFlagFilename("key-file", "pem", "key")
+
+	cmd.AddCommand(
+		newRegisterCommand(opts),
+		newGetCommand(opts),
+		newUpdateCommand(opts),
+		newDeleteCommand(opts),
+		newEraseCommand(opts),
+		newListCommand(opts),
+		newConsentsCommand(opts),
+		newAddressesCommand(opts),
+		newPolicyCommand(opts),
+		newCompletionCommand(),
+	)
+
+	return cmd
+}
@@ -0,0 +1,288 @@
+package ctl_test
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/type/date"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/chutommy/user-microservice/cmd/userctl/ctl"
+	"github.com/chutommy/user-microservice/pkg/grpc/userpb"
+)
+
+// stubServer answers the calls of the tests and records the requests.
+type stubServer struct {
+	userpb.UnimplementedUserServiceServer
+
+	register *userpb.RegisterUserRequest
+	update   *userpb.UpdateUserRequest
+	export   *userpb.ExportUsersRequest
+	publish  *userpb.PublishConsentPolicyRequest
+}
+
+var alice = &userpb.User{
+	Id:        "4f6d3b8a-1c1e-4e7a-9a53-7c1c2f0f6a11",
+	Email:     "alice@example.org",
+	Phone:     "+12015550123",
+	FirstName: "Alice",
+	LastName:  "Smith",
+	Gender:    userpb.User_FEMALE,
+	BirthDate: &date.Date{Year: 1990, Month: 4, Day: 16},
+	Country:   "US",
+}
+
+func (s *stubServer) RegisterUser(_ context.Context, req *userpb.RegisterUserRequest) (*userpb.RegisterUserResponse, error) {
+	s.register = req
+	return &userpb.RegisterUserResponse{Id: alice.Id, State: userpb.User_ACTIVE}, nil
+}
+
+func (s *stubServer) GetUser(_ context.Context, req *userpb.GetUserRequest) (*userpb.GetUserResponse, error) {
+	if req.GetId() != alice.Id && req.GetEmail() != alice.Email {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+
+	return &userpb.GetUserResponse{User: alice}, nil
+}
+
+func (s *stubServer) UpdateUser(_ context.Context, req *userpb.UpdateUserRequest) (*userpb.UpdateUserResponse, error) {
+	s.update = req
+	return &userpb.UpdateUserResponse{Id: req.GetId()}, nil
+}
+
+func (s *stubServer) ExportUsers(req *userpb.ExportUsersRequest, stream userpb.UserService_ExportUsersServer) error {
+	s.export = req
+	lines := `{"id":"1","email":"alice@example.org","gender":2,"phone_number":null,"created_at":"2021-03-01T10:00:00Z"}` + "\n" +
+		`{"id":"2","email":"bob@example.org","gender":1,"phone_number":"+12015550124","created_at":"2021-03-02T10:00:00Z"}` + "\n"
+
+	// chunks split the records
+	for _, chunk := range []string{lines[:40], lines[40:]} {
+		if err := stream.Send(&userpb.ExportUsersResponse{Chunk: []byte(chunk)}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *stubServer) PublishConsentPolicy(_ context.Context, req *userpb.PublishConsentPolicyRequest) (*userpb.PublishConsentPolicyResponse, error) {
+	s.publish = req
+	return &userpb.PublishConsentPolicyResponse{Policy: &userpb.ConsentPolicy{
+		Policy:   req.GetPolicy(),
+		Required: req.GetRequired(),
+	}}, nil
+}
+
+func (s *stubServer) ListUsersRequiringConsent(_ context.Context, req *userpb.ListUsersRequiringConsentRequest) (*userpb.ListUsersRequiringConsentResponse, error) {
+	// two pages
+	if req.GetPageToken() == "" {
+		return &userpb.ListUsersRequiringConsentResponse{Ids: []string{"1", "2"}, NextPageToken: "2"}, nil
+	}
+
+	return &userpb.ListUsersRequiringConsentResponse{Ids: []string{"3"}}, nil
+}
+
+func serve(t *testing.T) (*stubServer, string) {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	stub := &stubServer{}
+	srv := grpc.NewServer()
+	userpb.RegisterUserServiceServer(srv, stub)
+	go func() {
+		_ = srv.Serve(l)
+	}()
+	t.Cleanup(srv.Stop)
+
+	return stub, l.Addr().String()
+}
+
+func run(t *testing.T, address, stdin string, args ...string) (string, error) {
+	t.Helper()
+
+	var out bytes.Buffer
+	cmd := ctl.NewCommand(&out)
+	cmd.SetIn(strings.NewReader(stdin))
+	cmd.SetArgs(append([]string{"--address", address}, args...))
+	err := cmd.Execute()
+
+	return out.String(), err
+}
+
+func TestGet(t *testing.T) {
+	t.Parallel()
+
+	_, address := serve(t)
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{
+			name: "table",
+			args: []string{"get", alice.Id},
+			want: []string{
+				"ID  ", "FIRST NAME", "BIRTH DATE",
+				alice.Id, "alice@example.org", "female", "1990-04-16", "active",
+			},
+		},
+		{
+			name: "json by email",
+			args: []string{"get", "--email", alice.Email, "-o", "json"},
+			want: []string{`"id": "` + alice.Id + `"`, `"first_name": "Alice"`, `"gender": "FEMALE"`, `"year": 1990`},
+		},
+		{
+			name: "yaml",
+			args: []string{"get", alice.Id, "--output", "yaml"},
+			want: []string{"id: " + alice.Id + "\n", "email: alice@example.org\n", "birth_date:\n  year: 1990\n  month: 4\n  day: 16\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			out, err := run(t, address, "", tt.args...)
+			require.NoError(t, err)
+			for _, w := range tt.want {
+				require.Contains(t, out, w)
+			}
+		})
+	}
+}
+
+func TestGet_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, address := serve(t)
+
+	_, err := run(t, address, "", "get", "unknown")
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = run(t, address, "", "get")
+	require.ErrorIs(t, err, ctl.ErrInvalidFlag)
+
+	_, err = run(t, address, "", "get", alice.Id, "-o", "xml")
+	require.ErrorIs(t, err, ctl.ErrUnknownFormat)
+}
+
+func TestRegister(t *testing.T) {
+	t.Parallel()
+
+	stub, address := serve(t)
+
+	out, err := run(t, address, "secret\n", "register",
+		"--email", "alice@example.org", "--password-stdin", "--gender", "Female",
+		"--birth-date", "1990-04-16", "--attributes", `{"company":{"department":"sales"}}`,
+		"--accept", "terms:3", "--accept", "privacy:1", "--phone-region", "US")
+	require.NoError(t, err)
+	require.Contains(t, out, alice.Id)
+
+	req := stub.register
+	require.Equal(t, "secret", req.GetUser().GetPassword())
+	require.Equal(t, userpb.User_FEMALE, req.GetUser().GetGender())
+	require.Equal(t, int32(16), req.GetUser().GetBirthDate().GetDay())
+	require.Equal(t, "sales", req.GetUser().GetAttributes().GetFields()["company"].GetStructValue().GetFields()["department"].GetStringValue())
+	require.Len(t, req.GetAcceptedPolicies(), 2)
+	require.Equal(t, "terms", req.GetAcceptedPolicies()[0].GetKind())
+	require.Equal(t, int32(3), req.GetAcceptedPolicies()[0].GetVersion())
+	require.Equal(t, "US", req.GetPhoneRegion())
+
+	for _, args := range [][]string{
+		{"register", "--gender", "robot"},
+		{"register", "--birth-date", "16.04.1990"},
+		{"register", "--accept", "terms"},
+		{"register", "--password", "a", "--password-stdin"},
+	} {
+		_, err = run(t, address, "", args...)
+		require.ErrorIs(t, err, ctl.ErrInvalidFlag, args)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	t.Parallel()
+
+	stub, address := serve(t)
+
+	_, err := run(t, address, "", "update", alice.Id, "--last-name", "Jones")
+	require.NoError(t, err)
+	require.Equal(t, alice.Id, stub.update.GetId())
+	require.Equal(t, &userpb.User{LastName: "Jones"}, stub.update.GetUser())
+
+	_, err = run(t, address, "", "update", alice.Id)
+	require.ErrorIs(t, err, ctl.ErrInvalidFlag)
+}
+
+func TestList(t *testing.T) {
+	t.Parallel()
+
+	stub, address := serve(t)
+
+	out, err := run(t, address, "", "list", "--gender", "female", "--created-after", "2021-01-01T00:00:00Z",
+		"--attribute", "company.department=sales", "--attribute", "level=3")
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, lines, 3)
+	require.Contains(t, lines[1], "alice@example.org")
+	require.Contains(t, lines[1], "female")
+	require.Contains(t, lines[2], "+12015550124")
+
+	require.Equal(t, userpb.ExportUsersRequest_JSONL, stub.export.GetFormat())
+	require.Equal(t, userpb.User_FEMALE, stub.export.GetGender())
+	require.Equal(t, int64(1609459200), stub.export.GetCreatedAfter().GetSeconds())
+	require.Equal(t, "sales", stub.export.GetAttributeFilters()["company.department"].GetStringValue())
+	require.Equal(t, float64(3), stub.export.GetAttributeFilters()["level"].GetNumberValue())
+
+	out, err = run(t, address, "", "list", "-o", "json")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(out, "[\n"))
+	require.Contains(t, out, `"email": "bob@example.org"`)
+}
+
+func TestPolicy(t *testing.T) {
+	t.Parallel()
+
+	stub, address := serve(t)
+
+	out, err := run(t, address, "", "policy", "publish", "terms_of_service", "4", "--required")
+	require.NoError(t, err)
+	require.Contains(t, out, "terms_of_service")
+	require.Equal(t, int32(4), stub.publish.GetPolicy().GetVersion())
+	require.True(t, stub.publish.GetRequired())
+
+	_, err = run(t, address, "", "policy", "publish", "terms_of_service", "latest")
+	require.ErrorIs(t, err, ctl.ErrInvalidArgument)
+
+	out, err = run(t, address, "", "policy", "pending", "terms_of_service")
+	require.NoError(t, err)
+	require.Equal(t, []string{"ID", "1", "2", "3"}, strings.Fields(out))
+
+	out, err = run(t, address, "", "policy", "pending", "terms_of_service", "-o", "yaml")
+	require.NoError(t, err)
+	require.Contains(t, out, `- id: "3"`)
+}
+
+func TestCompletion(t *testing.T) {
+	t.Parallel()
+
+	for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+		out, err := run(t, "localhost:0", "", "completion", shell)
+		require.NoError(t, err, shell)
+		require.Contains(t, out, "userctl", shell)
+	}
+
+	_, err := run(t, "localhost:0", "", "completion", "tcsh")
+	require.Error(t, err)
+}
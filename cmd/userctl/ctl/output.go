@@ -0,0 +1,114 @@
+package ctl
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v2"
+)
+
+// Output formats.
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatYAML  = "yaml"
+)
+
+var formats = []string{formatTable, formatJSON, formatYAML}
+
+// ErrUnknownFormat is returned if the output format is not supported.
+var ErrUnknownFormat = errors.New("unknown output format")
+
+// table is the tabular view of a result.
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+// printer writes the results in the output format.
+type printer struct {
+	format string
+	w      io.Writer
+}
+
+func newPrinter(format string, w io.Writer) (printer, error) {
+	for _, f := range formats {
+		if f == format {
+			return printer{format: format, w: w}, nil
+		}
+	}
+
+	return printer{}, fmt.Errorf("%w %q, use one of %s", ErrUnknownFormat, format, strings.Join(formats, ", "))
+}
+
+// marshalJSON encodes the result, the messages by their proto field names.
+func marshalJSON(v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	case proto.Message:
+		return protojson.MarshalOptions{UseProtoNames: true}.Marshal(v)
+	case []proto.Message:
+		items := make([]json.RawMessage, len(v))
+		for i, m := range v {
+			b, err := marshalJSON(m)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = b
+		}
+		return json.Marshal(items)
+	}
+
+	return json.Marshal(v)
+}
+
+// print writes the result v, or its table in the table format.
+func (p printer) print(v interface{}, t table) error {
+	if p.format == formatTable {
+		tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.Join(t.header, "\t"))
+		for _, row := range t.rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	}
+
+	raw, err := marshalJSON(v)
+	if err != nil {
+		return err
+	}
+
+	if p.format == formatJSON {
+		var buf bytes.Buffer
+		if err = json.Indent(&buf, raw, "", "  "); err != nil {
+			return err
+		}
+		buf.WriteByte('\n')
+		_, err = buf.WriteTo(p.w)
+		return err
+	}
+
+	// JSON is a subset of YAML
+	var doc yaml.MapSlice
+	var list []yaml.MapSlice
+	var out interface{} = &doc
+	if bytes.HasPrefix(bytes.TrimSpace(raw), []byte("[")) {
+		out = &list
+	}
+	if err = yaml.Unmarshal(raw, out); err != nil {
+		return err
+	}
+	b, err := yaml.Marshal(out)
+	if err != nil {
+		return err
+	}
+	_, err = p.w.Write(b)
+
+	return err
+}
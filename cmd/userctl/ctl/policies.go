@@ -0,0 +1,104 @@
+package ctl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/chutommy/user-microservice/pkg/grpc/userpb"
+)
+
+// ErrInvalidArgument is returned if a command argument cannot be parsed.
+var ErrInvalidArgument = errors.New("invalid argument")
+
+func newPolicyCommand(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "policy",
+		Short: "Manage the consent policies",
+	}
+
+	cmd.AddCommand(
+		newPolicyPublishCommand(opts),
+		newPolicyPendingCommand(opts),
+	)
+
+	return cmd
+}
+
+func newPolicyPublishCommand(opts *options) *cobra.Command {
+	var required bool
+
+	cmd := &cobra.Command{
+		Use:   "publish KIND VERSION",
+		Short: "Publish a new version of a consent policy",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			version, err := strconv.ParseInt(args[1], 10, 32)
+			if err != nil {
+				return fmt.Errorf("%w VERSION %q: use a positive integer", ErrInvalidArgument, args[1])
+			}
+
+			req := &userpb.PublishConsentPolicyRequest{
+				Policy:   &userpb.PolicyVersion{Kind: args[0], Version: int32(version)},
+				Required: required,
+			}
+
+			return opts.call(func(ctx context.Context, client userpb.UserServiceClient) error {
+				resp, err := client.PublishConsentPolicy(ctx, req)
+				if err != nil {
+					return err
+				}
+
+				p := resp.GetPolicy()
+				return opts.out.print(p, table{
+					header: []string{"POLICY", "VERSION", "REQUIRED", "PUBLISHED AT"},
+					rows: [][]string{{
+						p.GetPolicy().GetKind(), strconv.Itoa(int(p.GetPolicy().GetVersion())),
+						strconv.FormatBool(p.GetRequired()), formatTime(p.GetPublishedAt()),
+					}},
+				})
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&required, "required", false, "the users must consent to the policy")
+
+	return cmd
+}
+
+func newPolicyPendingCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "pending KIND",
+		Short: "List the users who have not consented to the latest required version of a policy",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.call(func(ctx context.Context, client userpb.UserServiceClient) error {
+				t := table{header: []string{"ID"}}
+				var users []map[string]string
+
+				req := &userpb.ListUsersRequiringConsentRequest{PolicyKind: args[0]}
+				for {
+					resp, err := client.ListUsersRequiringConsent(ctx, req)
+					if err != nil {
+						return err
+					}
+
+					for _, id := range resp.GetIds() {
+						t.rows = append(t.rows, []string{id})
+						users = append(users, map[string]string{"id": id})
+					}
+
+					if resp.GetNextPageToken() == "" {
+						break
+					}
+					req.PageToken = resp.GetNextPageToken()
+				}
+
+				return opts.out.print(users, t)
+			})
+		},
+	}
+}
@@ -0,0 +1,572 @@
+package ctl
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"google.golang.org/genproto/googleapis/type/date"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/chutommy/user-microservice/pkg/grpc/userpb"
+)
+
+// dateLayout is the layout of the date flags.
+const dateLayout = "2006-01-02"
+
+// ErrInvalidFlag is returned if a flag value cannot be parsed.
+var ErrInvalidFlag = errors.New("invalid flag")
+
+// userFlags are the profile fields of the register and update commands.
+type userFlags struct {
+	email         string
+	phone         string
+	password      string
+	passwordStdin bool
+	firstName     string
+	lastName      string
+	gender        string
+	birthDate     string
+	country       string
+	phoneRegion   string
+	attributes    string
+}
+
+func (f *userFlags) register(fs *pflag.FlagSet) {
+	fs.StringVar(&f.email, "email", "", "email address")
+	fs.StringVar(&f.phone, "phone", "", "phone number")
+	fs.StringVar(&f.password, "password", "", "password, prefer --password-stdin")
+	fs.BoolVar(&f.passwordStdin, "password-stdin", false, "read the password from the standard input")
+	fs.StringVar(&f.firstName, "first-name", "", "first name")
+	fs.StringVar(&f.lastName, "last-name", "", "last name")
+	fs.StringVar(&f.gender, "gender", "", "gender: unknown, male, female or other")
+	fs.StringVar(&f.birthDate, "birth-date", "", "date of birth, e.g. 1990-04-16")
+	fs.StringVar(&f.country, "country", "", "ISO 3166-1 alpha-2 code of the country of residence")
+	fs.StringVar(&f.phoneRegion, "phone-region", "", "region of a phone number given without the international prefix")
+	fs.StringVar(&f.attributes, "attributes", "", "custom profile attributes as a JSON object")
+}
+
+// completeGender completes the values of the gender flags.
+func completeGender(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return enumNames(userpb.User_Gender_name), cobra.ShellCompDirectiveNoFileComp
+}
+
+// enumNames returns the lower-case names of the enum values.
+func enumNames(names map[int32]string) []string {
+	out := make([]string, 0, len(names))
+	for i := int32(0); i < int32(len(names)); i++ {
+		out = append(out, strings.ToLower(names[i]))
+	}
+
+	return out
+}
+
+// parseGender parses the gender by its case-insensitive name.
+func parseGender(s string) (userpb.User_Gender, error) {
+	v, ok := userpb.User_Gender_value[strings.ToUpper(s)]
+	if !ok {
+		return 0, fmt.Errorf("%w --gender %q: use one of %s", ErrInvalidFlag, s, strings.Join(enumNames(userpb.User_Gender_name), ", "))
+	}
+
+	return userpb.User_Gender(v), nil
+}
+
+// user returns the user of the set flags, the password may be read from in.
+func (f *userFlags) user(in io.Reader) (*userpb.User, error) {
+	user := &userpb.User{
+		Email:     f.email,
+		Phone:     f.phone,
+		Password:  f.password,
+		FirstName: f.firstName,
+		LastName:  f.lastName,
+		Country:   f.country,
+	}
+
+	if f.passwordStdin {
+		if f.password != "" {
+			return nil, fmt.Errorf("%w: --password and --password-stdin are exclusive", ErrInvalidFlag)
+		}
+		line, err := bufio.NewReader(in).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to read the password: %w", err)
+		}
+		user.Password = strings.TrimRight(line, "\r\n")
+	}
+
+	if f.gender != "" {
+		g, err := parseGender(f.gender)
+		if err != nil {
+			return nil, err
+		}
+		user.Gender = g
+	}
+
+	if f.birthDate != "" {
+		t, err := time.Parse(dateLayout, f.birthDate)
+		if err != nil {
+			return nil, fmt.Errorf("%w --birth-date %q: use the YYYY-MM-DD format", ErrInvalidFlag, f.birthDate)
+		}
+		user.BirthDate = &date.Date{Year: int32(t.Year()), Month: int32(t.Month()), Day: int32(t.Day())}
+	}
+
+	if f.attributes != "" {
+		attrs := &structpb.Struct{}
+		if err := protojson.Unmarshal([]byte(f.attributes), attrs); err != nil {
+			return nil, fmt.Errorf("%w --attributes: %v", ErrInvalidFlag, err)
+		}
+		user.Attributes = attrs
+	}
+
+	return user, nil
+}
+
+// parsePolicies parses the accepted policy versions given as kind:version.
+func parsePolicies(values []string) ([]*userpb.PolicyVersion, error) {
+	policies := make([]*userpb.PolicyVersion, 0, len(values))
+	for _, v := range values {
+		i := strings.LastIndex(v, ":")
+		if i <= 0 {
+			return nil, fmt.Errorf("%w --accept %q: use the kind:version format", ErrInvalidFlag, v)
+		}
+		version, err := strconv.ParseInt(v[i+1:], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%w --accept %q: use the kind:version format", ErrInvalidFlag, v)
+		}
+		policies = append(policies, &userpb.PolicyVersion{Kind: v[:i], Version: int32(version)})
+	}
+
+	return policies, nil
+}
+
+// enumName returns the lower-case name of an enum value.
+func enumName(s fmt.Stringer) string {
+	return strings.ToLower(s.String())
+}
+
+// formatTime formats a timestamp, empty if unset.
+func formatTime(t *timestamppb.Timestamp) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.AsTime().Format(time.RFC3339)
+}
+
+// formatDate formats a date, empty if unset.
+func formatDate(d *date.Date) string {
+	if d == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%04d-%02d-%02d", d.GetYear(), d.GetMonth(), d.GetDay())
+}
+
+func newRegisterCommand(opts *options) *cobra.Command {
+	var f userFlags
+	var accept []string
+
+	cmd := &cobra.Command{
+		Use:   "register",
+		Short: "Register a new user",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			user, err := f.user(cmd.InOrStdin())
+			if err != nil {
+				return err
+			}
+			policies, err := parsePolicies(accept)
+			if err != nil {
+				return err
+			}
+
+			return opts.call(func(ctx context.Context, client userpb.UserServiceClient) error {
+				resp, err := client.RegisterUser(ctx, &userpb.RegisterUserRequest{
+					User:             user,
+					AcceptedPolicies: policies,
+					PhoneRegion:      f.phoneRegion,
+				})
+				if err != nil {
+					return err
+				}
+
+				return opts.out.print(resp, table{
+					header: []string{"ID", "STATE"},
+					rows:   [][]string{{resp.GetId(), enumName(resp.GetState())}},
+				})
+			})
+		},
+	}
+
+	f.register(cmd.Flags())
+	cmd.Flags().StringSliceVar(&accept, "accept", nil, "accepted policy versions as kind:version, e.g. terms:3")
+	_ = cmd.RegisterFlagCompletionFunc("gender", completeGender)
+
+	return cmd
+}
+
+// userTable returns the table of the users.
+func userTable(users ...*userpb.User) table {
+	t := table{header: []string{"ID", "EMAIL", "PHONE", "FIRST NAME", "LAST NAME", "GENDER", "BIRTH DATE", "COUNTRY", "STATE"}}
+	for _, u := range users {
+		t.rows = append(t.rows, []string{
+			u.GetId(), u.GetEmail(), u.GetPhone(), u.GetFirstName(), u.GetLastName(),
+			enumName(u.GetGender()), formatDate(u.GetBirthDate()), u.GetCountry(), enumName(u.GetState()),
+		})
+	}
+
+	return t
+}
+
+func newGetCommand(opts *options) *cobra.Command {
+	var email string
+
+	cmd := &cobra.Command{
+		Use:   "get [ID]",
+		Short: "Get a user by the ID or an email address",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req := &userpb.GetUserRequest{Email: email}
+			if len(args) == 1 {
+				req.Id = args[0]
+			}
+			if (req.Id == "") == (req.Email == "") {
+				return fmt.Errorf("%w: give either the ID or --email", ErrInvalidFlag)
+			}
+
+			return opts.call(func(ctx context.Context, client userpb.UserServiceClient) error {
+				resp, err := client.GetUser(ctx, req)
+				if err != nil {
+					return err
+				}
+
+				return opts.out.print(resp.GetUser(), userTable(resp.GetUser()))
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&email, "email", "", "any email address of the user")
+
+	return cmd
+}
+
+func newUpdateCommand(opts *options) *cobra.Command {
+	var f userFlags
+
+	cmd := &cobra.Command{
+		Use:   "update ID",
+		Short: "Update the given fields of a user",
+		Long:  "Update the given fields of a user, the fields of the flags which are not set are kept.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			user, err := f.user(cmd.InOrStdin())
+			if err != nil {
+				return err
+			}
+			if proto.Equal(user, &userpb.User{}) {
+				return fmt.Errorf("%w: no field to update", ErrInvalidFlag)
+			}
+
+			return opts.call(func(ctx context.Context, client userpb.UserServiceClient) error {
+				resp, err := client.UpdateUser(ctx, &userpb.UpdateUserRequest{
+					Id:          args[0],
+					User:        user,
+					PhoneRegion: f.phoneRegion,
+				})
+				if err != nil {
+					return err
+				}
+
+				return opts.out.print(resp, table{header: []string{"ID"}, rows: [][]string{{resp.GetId()}}})
+			})
+		},
+	}
+
+	f.register(cmd.Flags())
+	_ = cmd.RegisterFlagCompletionFunc("gender", completeGender)
+
+	return cmd
+}
+
+func newDeleteCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete ID",
+		Short: "Delete a user",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.call(func(ctx context.Context, client userpb.UserServiceClient) error {
+				resp, err := client.DeleteUser(ctx, &userpb.DeleteUserRequest{Id: args[0]})
+				if err != nil {
+					return err
+				}
+
+				return opts.out.print(resp, table{header: []string{"ID"}, rows: [][]string{{resp.GetId()}}})
+			})
+		},
+	}
+}
+
+func newEraseCommand(opts *options) *cobra.Command {
+	var reason string
+
+	cmd := &cobra.Command{
+		Use:   "erase ID",
+		Short: "Irreversibly anonymize the personal data of a user",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.call(func(ctx context.Context, client userpb.UserServiceClient) error {
+				resp, err := client.EraseUser(ctx, &userpb.EraseUserRequest{Id: args[0], Reason: reason})
+				if err != nil {
+					return err
+				}
+
+				return opts.out.print(resp, table{
+					header: []string{"ID", "RECEIPT ID", "ERASED AT"},
+					rows:   [][]string{{resp.GetId(), resp.GetReceiptId(), formatTime(resp.GetErasedAt())}},
+				})
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&reason, "reason", "", "reason stored in the erasure receipt")
+
+	return cmd
+}
+
+// listColumns are the exported columns of the listed users.
+var listColumns = []string{"id", "email", "phone_number", "first_name", "last_name", "gender", "created_at"}
+
+func newListCommand(opts *options) *cobra.Command {
+	var createdAfter, createdBefore, gender string
+	var attributes []string
+	var erased bool
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the users matching the filters",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			req := &userpb.ExportUsersRequest{
+				Format:        userpb.ExportUsersRequest_JSONL,
+				Columns:       listColumns,
+				IncludeErased: erased,
+			}
+
+			for _, t := range []struct {
+				flag  string
+				value string
+				field **timestamppb.Timestamp
+			}{
+				{"created-after", createdAfter, &req.CreatedAfter},
+				{"created-before", createdBefore, &req.CreatedBefore},
+			} {
+				if t.value == "" {
+					continue
+				}
+				parsed, err := time.Parse(time.RFC3339, t.value)
+				if err != nil {
+					return fmt.Errorf("%w --%s %q: use the RFC 3339 format", ErrInvalidFlag, t.flag, t.value)
+				}
+				*t.field = timestamppb.New(parsed)
+			}
+
+			if gender != "" {
+				g, err := parseGender(gender)
+				if err != nil {
+					return err
+				}
+				req.Gender = g
+			}
+
+			if len(attributes) > 0 {
+				req.AttributeFilters = make(map[string]*structpb.Value, len(attributes))
+				for _, a := range attributes {
+					i := strings.Index(a, "=")
+					if i <= 0 {
+						return fmt.Errorf("%w --attribute %q: use the path=value format", ErrInvalidFlag, a)
+					}
+					v := &structpb.Value{}
+					if err := protojson.Unmarshal([]byte(a[i+1:]), v); err != nil {
+						// a bare string
+						v = structpb.NewStringValue(a[i+1:])
+					}
+					req.AttributeFilters[a[:i]] = v
+				}
+			}
+
+			return opts.call(func(ctx context.Context, client userpb.UserServiceClient) error {
+				users, err := listUsers(ctx, client, req)
+				if err != nil {
+					return err
+				}
+
+				t := table{header: []string{"ID", "EMAIL", "PHONE", "FIRST NAME", "LAST NAME", "GENDER", "CREATED AT"}}
+				for _, u := range users {
+					row := make([]string, len(listColumns))
+					for i, c := range listColumns {
+						row[i] = formatValue(c, u[c])
+					}
+					t.rows = append(t.rows, row)
+				}
+
+				return opts.out.print(users, t)
+			})
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&createdAfter, "created-after", "", "only users created after the RFC 3339 time")
+	flags.StringVar(&createdBefore, "created-before", "", "only users created before the RFC 3339 time")
+	flags.StringVar(&gender, "gender", "", "only users of the gender")
+	flags.StringArrayVar(&attributes, "attribute", nil, "only users with the indexed attribute value as path=value, the value may be JSON")
+	flags.BoolVar(&erased, "erased", false, "include the erased users")
+	_ = cmd.RegisterFlagCompletionFunc("gender", completeGender)
+
+	return cmd
+}
+
+// listUsers streams the users exported as JSON lines.
+func listUsers(ctx context.Context, client userpb.UserServiceClient, req *userpb.ExportUsersRequest) ([]map[string]interface{}, error) {
+	stream, err := client.ExportUsers(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		for {
+			resp, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				_ = pw.Close()
+				return
+			}
+			if err != nil {
+				_ = pw.CloseWithError(err)
+				return
+			}
+			if _, err = pw.Write(resp.GetChunk()); err != nil {
+				return
+			}
+		}
+	}()
+	defer pr.Close()
+
+	users := []map[string]interface{}{}
+	dec := json.NewDecoder(pr)
+	dec.UseNumber()
+	for {
+		var u map[string]interface{}
+		err := dec.Decode(&u)
+		if errors.Is(err, io.EOF) {
+			return users, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+}
+
+// formatValue formats an exported column value in the table.
+func formatValue(column string, v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case json.Number:
+		if column == "gender" {
+			if n, err := v.Int64(); err == nil {
+				return enumName(userpb.User_Gender(n))
+			}
+		}
+		return v.String()
+	case string:
+		return v
+	}
+
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+func newConsentsCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "consents ID",
+		Short: "List the policy consents of a user",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.call(func(ctx context.Context, client userpb.UserServiceClient) error {
+				resp, err := client.ListConsents(ctx, &userpb.ListConsentsRequest{Id: args[0]})
+				if err != nil {
+					return err
+				}
+
+				t := table{header: []string{"POLICY", "VERSION", "ACCEPTED AT", "WITHDRAWN AT"}}
+				items := make([]proto.Message, 0, len(resp.GetConsents()))
+				for _, c := range resp.GetConsents() {
+					t.rows = append(t.rows, []string{
+						c.GetPolicy().GetKind(), strconv.Itoa(int(c.GetPolicy().GetVersion())),
+						formatTime(c.GetAcceptedAt()), formatTime(c.GetWithdrawnAt()),
+					})
+					items = append(items, c)
+				}
+
+				return opts.out.print(items, t)
+			})
+		},
+	}
+}
+
+func newAddressesCommand(opts *options) *cobra.Command {
+	var kind string
+
+	cmd := &cobra.Command{
+		Use:   "addresses ID",
+		Short: "List the postal addresses of a user",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req := &userpb.ListAddressesRequest{UserId: args[0]}
+			if kind != "" {
+				v, ok := userpb.Address_Kind_value[strings.ToUpper(kind)]
+				if !ok {
+					return fmt.Errorf("%w --kind %q: use one of %s", ErrInvalidFlag, kind, strings.Join(enumNames(userpb.Address_Kind_name), ", "))
+				}
+				req.Kind = userpb.Address_Kind(v)
+			}
+
+			return opts.call(func(ctx context.Context, client userpb.UserServiceClient) error {
+				resp, err := client.ListAddresses(ctx, req)
+				if err != nil {
+					return err
+				}
+
+				t := table{header: []string{"ID", "KIND", "DEFAULT", "RECIPIENT", "LINE 1", "CITY", "POSTAL CODE", "COUNTRY"}}
+				items := make([]proto.Message, 0, len(resp.GetAddresses()))
+				for _, a := range resp.GetAddresses() {
+					t.rows = append(t.rows, []string{
+						a.GetId(), enumName(a.GetKind()), strconv.FormatBool(a.GetDefault()), a.GetRecipient(),
+						a.GetLine1(), a.GetCity(), a.GetPostalCode(), a.GetCountryCode(),
+					})
+					items = append(items, a)
+				}
+
+				return opts.out.print(items, t)
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&kind, "kind", "", "only addresses of the kind")
+	_ = cmd.RegisterFlagCompletionFunc("kind", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return enumNames(userpb.Address_Kind_name), cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
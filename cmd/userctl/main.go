@@ -0,0 +1,22 @@
+// Command userctl administers the users of the user service over gRPC.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc/status"
+
+	"github.com/chutommy/user-microservice/cmd/userctl/ctl"
+)
+
+func main() {
+	if err := ctl.NewCommand(os.Stdout).Execute(); err != nil {
+		if s, ok := status.FromError(err); ok {
+			fmt.Fprintf(os.Stderr, "Error: %s: %s\n", s.Code(), s.Message())
+		} else {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		os.Exit(1)
+	}
+}
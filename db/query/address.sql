@@ -0,0 +1,101 @@
+-- name: CreateAddress :one
+with cleared as
+         (
+             update user_addresses
+                 set is_default = false
+                 where user_addresses.user_id = @user_id
+                     and user_addresses.kind = @kind
+                     and user_addresses.is_default
+                     and @is_default::boolean
+         )
+insert
+into user_addresses (id, user_id, kind, is_default, recipient, line1, line2, city, region, postal_code, country_code)
+values (@id, @user_id, @kind,
+           -- the first address of the kind becomes the default
+        @is_default::boolean or not exists(select 1
+                                           from user_addresses
+                                           where user_addresses.user_id = @user_id
+                                             and user_addresses.kind = @kind),
+        @recipient, @line1, @line2, @city, @region, @postal_code, @country_code)
+returning *;
+
+-- name: ListAddresses :many
+select *
+from user_addresses
+where user_id = @user_id
+  and (@kind::smallint = 0 or kind = @kind)
+order by kind, created_at;
+
+-- name: UpdateAddress :one
+with cleared as
+         (
+             update user_addresses
+                 set is_default = false
+                 where user_addresses.user_id = @user_id
+                     and user_addresses.kind = @kind
+                     and user_addresses.id <> @id
+                     and user_addresses.is_default
+                     and @is_default::boolean
+         ),
+     -- the oldest other address of the kind replaces the default leaving it
+     promoted as
+         (
+             update user_addresses
+                 set is_default = true,
+                     updated_at = now()
+                 where user_addresses.id = (select other.id
+                                            from user_addresses target
+                                                     join user_addresses other
+                                                          on other.user_id = target.user_id
+                                                              and other.kind = target.kind
+                                                              and other.id <> target.id
+                                            where target.id = @id
+                                              and target.user_id = @user_id
+                                              and target.is_default
+                                              and (not @is_default::boolean or target.kind <> @kind)
+                                            order by other.created_at
+                                            limit 1)
+         )
+update user_addresses
+set kind         = @kind,
+    -- the only address of the kind stays the default
+    is_default   = @is_default::boolean or not exists(select 1
+                                                      from user_addresses other
+                                                      where other.user_id = @user_id
+                                                        and other.kind = @kind
+                                                        and other.id <> @id),
+    recipient    = @recipient,
+    line1        = @line1,
+    line2        = @line2,
+    city         = @city,
+    region       = @region,
+    postal_code  = @postal_code,
+    country_code = @country_code,
+    updated_at   = now()
+where user_addresses.id = @id
+  and user_addresses.user_id = @user_id
+returning *;
+
+-- name: DeleteAddress :execrows
+with promoted as
+         (
+             -- the oldest other address of the kind replaces a deleted default
+             update user_addresses
+                 set is_default = true,
+                     updated_at = now()
+                 where user_addresses.id = (select other.id
+                                            from user_addresses deleted
+                                                     join user_addresses other
+                                                          on other.user_id = deleted.user_id
+                                                              and other.kind = deleted.kind
+                                                              and other.id <> deleted.id
+                                            where deleted.id = @id
+                                              and deleted.user_id = @user_id
+                                              and deleted.is_default
+                                            order by other.created_at
+                                            limit 1)
+         )
+delete
+from user_addresses
+where user_addresses.id = @id
+  and user_addresses.user_id = @user_id;
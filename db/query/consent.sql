@@ -0,0 +1,54 @@
+-- name: CreateConsentPolicy :one
+insert into consent_policies (kind, version, required)
+values (@kind, @version, @required)
+returning *;
+
+-- name: GetConsentPolicy :one
+select *
+from consent_policies
+where kind = @kind
+  and version = @version
+limit 1;
+
+-- name: RecordConsent :one
+insert into user_consents (user_id, policy_kind, policy_version)
+values (@user_id, @policy_kind, @policy_version)
+on conflict (user_id, policy_kind, policy_version) do update
+    set accepted_at  = now(),
+        withdrawn_at = null
+returning *;
+
+-- name: WithdrawConsent :execrows
+update user_consents
+set withdrawn_at = now()
+where user_id = @user_id
+  and policy_kind = @policy_kind
+  and withdrawn_at is null;
+
+-- name: ListConsents :many
+select *
+from user_consents
+where user_id = @user_id
+order by accepted_at;
+
+-- name: ListUsersRequiringConsent :many
+select u.id
+from users u
+         join lateral (select p.version
+                       from consent_policies p
+                       where p.kind = @policy_kind
+                         and p.required
+                       order by p.version desc
+                       limit 1) latest on true
+where u.id > @after_id
+  and u.erased_at is null
+  and not exists(
+        select 1
+        from user_consents c
+        where c.user_id = u.id
+          and c.policy_kind = @policy_kind
+          and c.policy_version = latest.version
+          and c.withdrawn_at is null
+    )
+order by u.id
+limit @row_limit;
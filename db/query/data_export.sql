@@ -0,0 +1,31 @@
+-- name: CreateDataExport :one
+insert into data_exports (id, user_id)
+values (@id, @user_id)
+returning *;
+
+-- name: GetDataExport :one
+select *
+from data_exports
+where id = @id
+limit 1;
+
+-- name: CompleteDataExport :one
+update data_exports
+set status       = @status,
+    blob_key     = @blob_key,
+    signature    = @signature,
+    completed_at = now()
+where id = @id
+returning *;
+
+-- name: ListDataExportBlobKeys :many
+select blob_key
+from data_exports
+where user_id = @user_id
+  and blob_key <> '';
+
+-- name: ListErasureReceipts :many
+select *
+from erasure_receipts
+where user_id = @user_id
+order by erased_at;
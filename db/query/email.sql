@@ -0,0 +1,38 @@
+-- name: AddEmail :one
+insert into user_emails (email, email_canonical, user_id, verified)
+select @email, @email_canonical::varchar(254), users.id, @verified
+from users
+where users.id = @user_id
+  and users.erased_at is null
+returning *;
+
+-- name: ListEmails :many
+select *
+from user_emails
+where user_id = @user_id
+order by is_primary desc, created_at;
+
+-- name: RemoveEmail :execrows
+delete
+from user_emails
+where user_id = @user_id
+  and email_canonical = @email_canonical::varchar(254)
+  and not is_primary;
+
+-- name: SetPrimaryEmail :one
+update users
+set email           = user_emails.email,
+    email_canonical = user_emails.email_canonical
+from user_emails
+where users.id = @id
+  and users.erased_at is null
+  and user_emails.user_id = users.id
+  and user_emails.email_canonical = @email_canonical::varchar(254)
+returning users.*;
+
+-- name: GetUserByEmail :one
+select users.*
+from users
+         join user_emails on user_emails.user_id = users.id
+where user_emails.email_canonical = @email_canonical::varchar(254)
+limit 1;
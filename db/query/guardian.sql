@@ -0,0 +1,50 @@
+-- name: CreateGuardianConsent :one
+insert into guardian_consents (id, minor_id, guardian_id, expires_at)
+select @id, users.id, @guardian_id, @expires_at
+from users
+where users.id = @minor_id
+  and users.erased_at is null
+returning *;
+
+-- name: DeleteGuardianConsent :exec
+-- withdraws a request the guardian was not notified about
+delete
+from guardian_consents
+where id = @id
+  and confirmed_at is null;
+
+-- name: ConfirmGuardianConsent :one
+with confirmed as
+         (
+             update guardian_consents
+                 set confirmed_at = now()
+                 where guardian_consents.id = @id
+                     and guardian_consents.guardian_id = @guardian_id
+                     and guardian_consents.confirmed_at is null
+                     and guardian_consents.expires_at > now()
+                 returning *
+         ),
+     activated as
+         (
+             -- the minor's account becomes active once a guardian consents
+             update users
+                 set state = 0
+                 from confirmed
+                 where users.id = confirmed.minor_id
+                     and users.state = 1
+         )
+select *
+from confirmed;
+
+-- name: HasGuardianConsent :one
+select exists(select 1
+              from guardian_consents
+              where minor_id = @minor_id
+                and confirmed_at is not null);
+
+-- name: ListGuardianConsents :many
+select *
+from guardian_consents
+where minor_id = @user_id
+   or guardian_id = @user_id
+order by created_at;
@@ -0,0 +1,48 @@
+-- name: ReserveIdempotencyKey :one
+-- reserves an unused key, a key which expired or whose call was abandoned
+-- before @stale_before is taken over
+insert into idempotency_keys (caller, method, idempotency_key, fingerprint, expires_at)
+values (@caller, @method, @idempotency_key, @fingerprint, @expires_at)
+on conflict (caller, method, idempotency_key) do update
+    set fingerprint = excluded.fingerprint,
+        response    = null,
+        created_at  = now(),
+        expires_at  = excluded.expires_at
+where idempotency_keys.expires_at <= now()
+   or (idempotency_keys.response is null and idempotency_keys.created_at <= @stale_before)
+returning *;
+
+-- name: GetIdempotencyKey :one
+select *
+from idempotency_keys
+where caller = @caller
+  and method = @method
+  and idempotency_key = @idempotency_key
+  and expires_at > now()
+limit 1;
+
+-- name: CompleteIdempotencyKey :execrows
+-- the created_at of the reservation tells it apart from a later takeover
+update idempotency_keys
+set response = @response
+where caller = @caller
+  and method = @method
+  and idempotency_key = @idempotency_key
+  and fingerprint = @fingerprint
+  and created_at = @reserved_at
+  and response is null;
+
+-- name: ReleaseIdempotencyKey :execrows
+delete
+from idempotency_keys
+where caller = @caller
+  and method = @method
+  and idempotency_key = @idempotency_key
+  and fingerprint = @fingerprint
+  and created_at = @reserved_at
+  and response is null;
+
+-- name: DeleteExpiredIdempotencyKeys :execrows
+delete
+from idempotency_keys
+where expires_at <= now();
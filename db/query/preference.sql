@@ -0,0 +1,26 @@
+-- name: ListPreferences :many
+select *
+from user_preferences
+where user_id = @user_id
+  and (coalesce(@namespace::varchar(64), '') = '' or namespace = @namespace)
+order by namespace, key;
+
+-- name: SetPreferences :many
+insert into user_preferences (user_id, namespace, key, value_type, value)
+select @user_id,
+       unnest(@namespaces::varchar[]),
+       unnest(@keys::varchar[]),
+       unnest(@value_types::smallint[]),
+       unnest(@preference_values::text[])::jsonb
+on conflict (user_id, namespace, key) do update
+    set value_type = excluded.value_type,
+        value      = excluded.value,
+        updated_at = now()
+returning *;
+
+-- name: DeletePreference :execrows
+delete
+from user_preferences
+where user_id = @user_id
+  and namespace = @namespace
+  and key = @key;
@@ -1,6 +1,17 @@
 -- name: CreateUser :one
-insert into users (id, email, phone_number, hashed_password, first_name, last_name, gender, birth_day)
-values (@id, @email, @phone_number, @hashed_password, @first_name, @last_name, @gender, @birth_day)
+with consents as
+         (
+             -- the foreign keys are checked once the user is inserted
+             insert into user_consents (user_id, policy_kind, policy_version)
+                 select @id::uuid,
+                        unnest(@policy_kinds::varchar[]),
+                        unnest(@policy_versions::integer[])
+         )
+insert
+into users (id, email, email_canonical, phone_number, phone_country, hashed_password, first_name, last_name,
+                   gender, birth_day, attributes, country, state)
+values (@id, @email, @email_canonical::varchar(254), @phone_number, @phone_country, @hashed_password, @first_name,
+        @last_name, @gender, @birth_day, @attributes, @country, @state)
 returning *;
 
 -- name: GetUser :one
@@ -11,16 +22,24 @@ limit 1;
 
 -- name: UpdateUser :one
 update users
-set email           = case when coalesce(@email::varchar(64), '') = '' then email else @email end,
+set email           = case when coalesce(@email::varchar(254), '') = '' then email else @email end,
+    email_canonical = case
+                          when coalesce(@email::varchar(254), '') = '' then email_canonical
+                          else @email_canonical::varchar(254) end,
     phone_number    = case when coalesce(@phone_number::varchar(32), '') = '' then phone_number else @phone_number end,
+    phone_country   = case when coalesce(@phone_number::varchar(32), '') = '' then phone_country else @phone_country end,
     hashed_password = case
                           when coalesce(@hashed_password::varchar, '') = '' then hashed_password
                           else @hashed_password end,
     first_name      = case when coalesce(@first_name::varchar(64), '') = '' then first_name else @first_name end,
     last_name       = case when coalesce(@last_name::varchar(64), '') = '' then last_name else @last_name end,
     gender          = case when coalesce(@gender::smallint, 0) = 0 then gender else @gender end,
-    birth_day       = case when @birth_day::date = '0001-01-01' then birth_day else @birth_day end
+    birth_day       = case when @birth_day::date = '0001-01-01' then birth_day else @birth_day end,
+    attributes      = case when @attributes::text = '' then attributes else (@attributes::text)::jsonb end,
+    country         = case when coalesce(@country::varchar(2), '') = '' then country else @country end,
+    state           = coalesce(sqlc.narg(state)::smallint, state)
 where id = @id
+  and erased_at is null
 returning *;
 
 -- name: DeleteUser :one
@@ -32,3 +51,162 @@ with deleted as
          )
 select count(*)
 from deleted;
+
+-- name: ListUsers :many
+select *
+from users
+where id > @after_id
+  and (sqlc.narg(created_after)::timestamptz is null or created_at >= sqlc.narg(created_after))
+  and (sqlc.narg(created_before)::timestamptz is null or created_at < sqlc.narg(created_before))
+  and (coalesce(@gender::smallint, 0) = 0 or gender = @gender)
+  and attributes @> @attributes_filter::jsonb
+  and (@include_erased::boolean or erased_at is null)
+order by id
+limit @row_limit;
+
+-- name: EraseUser :one
+with erased as
+         (
+             update users
+                 set email = 'erased-' || users.id || '@erased.invalid',
+                     email_canonical = 'erased-' || users.id || '@erased.invalid',
+                     phone_number = null,
+                     phone_country = null,
+                     hashed_password = '',
+                     first_name = '',
+                     last_name = '',
+                     birth_day = null,
+                     attributes = '{}',
+                     avatar_key = null,
+                     country = null,
+                     erased_at = now()
+                 where users.id = @user_id
+                     and users.erased_at is null
+                 returning users.id, users.erased_at
+         ),
+     addresses as
+         (
+             delete
+                 from user_addresses
+                     using erased
+                 where user_addresses.user_id = erased.id
+         ),
+     emails as
+         (
+             -- the primary address is replaced by the trigger on users
+             delete
+                 from user_emails
+                     using erased
+                 where user_emails.user_id = erased.id
+                     and not user_emails.is_primary
+         ),
+     exports as
+         (
+             delete
+                 from data_exports
+                     using erased
+                 where data_exports.user_id = erased.id
+         ),
+     guardians as
+         (
+             delete
+                 from guardian_consents
+                     using erased
+                 where guardian_consents.minor_id = erased.id
+                     or guardian_consents.guardian_id = erased.id
+         ),
+     preferences as
+         (
+             delete
+                 from user_preferences
+                     using erased
+                 where user_preferences.user_id = erased.id
+         ),
+     consents as
+         (
+             delete
+                 from user_consents
+                     using erased
+                 where user_consents.user_id = erased.id
+         )
+insert
+into erasure_receipts (id, user_id, reason, erased_at)
+select @id::uuid, erased.id, @reason::varchar, erased.erased_at
+from erased
+returning *;
+
+-- name: ListUnpublishedErasureReceipts :many
+select *
+from erasure_receipts
+where published_at is null
+  and erased_at < @erased_before
+order by erased_at
+limit @row_limit;
+
+-- name: MarkErasureReceiptPublished :exec
+update erasure_receipts
+set published_at = now()
+where id = @id;
+
+-- name: SetUserAvatar :one
+with previous as
+         (
+             select users.id, users.avatar_key
+             from users
+             where users.id = @id
+               and users.erased_at is null
+                 for update
+         )
+update users
+set avatar_key = @avatar_key,
+    updated_at = now()
+from previous
+where users.id = previous.id
+returning previous.avatar_key;
+
+-- name: ListUnnormalizedPhones :many
+select id, phone_number
+from users
+where phone_number is not null
+  and phone_country is null
+  and id > @after_id
+order by id
+limit @row_limit;
+
+-- name: SetUserPhone :exec
+update users
+set phone_number  = @phone_number,
+    phone_country = @phone_country
+where id = @id;
+
+-- name: ListEmailCanonicals :many
+select email, user_id, email_canonical
+from user_emails
+where email > @after_email
+order by email
+limit @row_limit;
+
+-- name: CountUncanonicalEmails :one
+select count(*)
+from user_emails
+where email_canonical is null;
+
+-- name: SetEmailCanonical :exec
+with updated as
+         (
+             update user_emails
+                 set email_canonical = @email_canonical::varchar(254)
+                 where user_emails.email = @email
+                 returning user_id, is_primary
+         )
+update users
+set email_canonical = @email_canonical::varchar(254)
+from updated
+where users.id = updated.user_id
+  and updated.is_primary;
+
+-- name: CountUsers :many
+select state, (erased_at is not null)::boolean as erased, count(*) as count
+from users
+group by state, erased
+order by state, erased;
@@ -0,0 +1,11 @@
+// Package db embeds the schema migrations of the user service, see package
+// migration.
+package db
+
+import "embed"
+
+// Migrations holds the migration files of the schema directory, numbered
+// sequentially in the golang-migrate format.
+//
+//go:embed schema/*.sql
+var Migrations embed.FS
@@ -0,0 +1,3 @@
+drop table if exists erasure_receipts;
+alter table users
+    drop column if exists erased_at;
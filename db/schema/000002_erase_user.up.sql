@@ -0,0 +1,12 @@
+alter table users
+    add column if not exists erased_at timestamptz;
+
+create table if not exists erasure_receipts
+(
+    id         uuid primary key,
+    user_id    uuid        not null references users (id),
+    reason     varchar     not null default '',
+    erased_at  timestamptz not null default now()
+);
+
+create index if not exists erasure_receipts_user_id_idx on erasure_receipts (user_id);
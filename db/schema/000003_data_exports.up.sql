@@ -0,0 +1,12 @@
+create table if not exists data_exports
+(
+    id           uuid primary key,
+    user_id      uuid        not null references users (id),
+    status       smallint    not null default 0,
+    blob_key     varchar     not null default '',
+    signature    varchar     not null default '',
+    created_at   timestamptz not null default now(),
+    completed_at timestamptz
+);
+
+create index if not exists data_exports_user_id_idx on data_exports (user_id);
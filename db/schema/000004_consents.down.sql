@@ -0,0 +1,2 @@
+drop table if exists user_consents;
+drop table if exists consent_policies;
@@ -0,0 +1,19 @@
+create table if not exists consent_policies
+(
+    kind         varchar(32) not null,
+    version      integer     not null,
+    required     boolean     not null default false,
+    published_at timestamptz not null default now(),
+    primary key (kind, version)
+);
+
+create table if not exists user_consents
+(
+    user_id        uuid        not null references users (id),
+    policy_kind    varchar(32) not null,
+    policy_version integer     not null,
+    accepted_at    timestamptz not null default now(),
+    withdrawn_at   timestamptz,
+    primary key (user_id, policy_kind, policy_version),
+    foreign key (policy_kind, policy_version) references consent_policies (kind, version)
+);
@@ -0,0 +1,10 @@
+create table if not exists user_preferences
+(
+    user_id    uuid        not null references users (id),
+    namespace  varchar(64) not null,
+    key        varchar(64) not null,
+    value_type smallint    not null,
+    value      jsonb       not null,
+    updated_at timestamptz not null default now(),
+    primary key (user_id, namespace, key)
+);
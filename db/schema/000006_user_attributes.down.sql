@@ -0,0 +1,3 @@
+drop index if exists users_attributes_idx;
+alter table users
+    drop column if exists attributes;
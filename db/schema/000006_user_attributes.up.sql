@@ -0,0 +1,4 @@
+alter table users
+    add column if not exists attributes jsonb not null default '{}';
+
+create index if not exists users_attributes_idx on users using gin (attributes jsonb_path_ops);
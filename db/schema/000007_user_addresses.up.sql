@@ -0,0 +1,23 @@
+create table if not exists user_addresses
+(
+    id           uuid primary key,
+    user_id      uuid         not null references users (id),
+    kind         smallint     not null,
+    is_default   boolean      not null default false,
+    recipient    varchar(128) not null,
+    line1        varchar(128) not null,
+    line2        varchar(128) not null default '',
+    city         varchar(64)  not null,
+    region       varchar(64)  not null default '',
+    postal_code  varchar(16)  not null default '',
+    country_code char(2)      not null,
+    updated_at   timestamptz,
+    created_at   timestamptz  not null default now(),
+    -- checked at the end of the transaction so the default can be moved
+    -- to another address within a single statement
+    constraint user_addresses_default_excl
+        exclude (user_id with =, kind with =) where (is_default)
+            deferrable initially deferred
+);
+
+create index if not exists user_addresses_user_id_idx on user_addresses (user_id);
@@ -0,0 +1,2 @@
+alter table users
+    add column if not exists avatar_key varchar(256);
@@ -0,0 +1,3 @@
+drop trigger if exists sync_primary_email_trigger on users;
+drop function if exists sync_primary_email();
+drop table if exists user_emails;
@@ -0,0 +1,54 @@
+create table if not exists user_emails
+(
+    -- the primary key keeps the addresses unique across all users
+    email      varchar(64) primary key,
+    user_id    uuid        not null references users (id) on delete cascade,
+    verified   boolean     not null default false,
+    is_primary boolean     not null default false,
+    created_at timestamptz not null default now(),
+    constraint user_emails_primary_excl
+        exclude (user_id with =) where (is_primary)
+            deferrable initially deferred
+);
+
+create index if not exists user_emails_user_id_idx on user_emails (user_id);
+
+insert into user_emails (email, user_id, is_primary)
+select email, id, true
+from users
+on conflict do nothing;
+
+-- users.email mirrors the primary address of the user
+create or replace function sync_primary_email()
+    returns trigger
+    language plpgsql
+as
+$$
+begin
+    if tg_op = 'INSERT' then
+        insert into user_emails (email, user_id, is_primary) values (new.email, new.id, true);
+    elsif new.email <> old.email then
+        if exists(select 1 from user_emails where email = new.email and user_id = new.id) then
+            -- switch to one of the user's addresses, the old primary is kept
+            update user_emails
+            set is_primary = (email = new.email)
+            where user_id = new.id
+              and (is_primary or email = new.email);
+        else
+            -- replace the primary address
+            update user_emails
+            set email      = new.email,
+                verified   = false,
+                created_at = now()
+            where user_id = new.id
+              and is_primary;
+        end if;
+    end if;
+    return new;
+end;
+$$;
+create trigger sync_primary_email_trigger
+    after insert or update of email
+    on users
+    for each row
+execute procedure sync_primary_email();
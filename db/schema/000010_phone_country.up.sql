@@ -0,0 +1,4 @@
+-- existing numbers are normalized to E.164 by cmd/phonemigrate,
+-- which fills the country of every number it converts
+alter table users
+    add column if not exists phone_country varchar(3);
@@ -0,0 +1,40 @@
+create or replace function sync_primary_email()
+    returns trigger
+    language plpgsql
+as
+$$
+begin
+    if tg_op = 'INSERT' then
+        insert into user_emails (email, user_id, is_primary) values (new.email, new.id, true);
+    elsif new.email <> old.email then
+        if exists(select 1 from user_emails where email = new.email and user_id = new.id) then
+            -- switch to one of the user's addresses, the old primary is kept
+            update user_emails
+            set is_primary = (email = new.email)
+            where user_id = new.id
+              and (is_primary or email = new.email);
+        else
+            -- replace the primary address
+            update user_emails
+            set email      = new.email,
+                verified   = false,
+                created_at = now()
+            where user_id = new.id
+              and is_primary;
+        end if;
+    end if;
+    return new;
+end;
+$$;
+
+drop index if exists user_emails_email_canonical_key;
+
+alter table user_emails
+    drop column if exists email_canonical;
+alter table users
+    drop column if exists email_canonical;
+
+alter table user_emails
+    alter column email type varchar(64);
+alter table users
+    alter column email type varchar(64);
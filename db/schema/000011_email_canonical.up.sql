@@ -0,0 +1,58 @@
+-- RFC 5322 allows addresses of up to 254 octets
+alter table users
+    alter column email type varchar(254);
+alter table user_emails
+    alter column email type varchar(254);
+
+-- the canonical form keeps the mailboxes unique regardless of the letter case
+-- and the provider aliases; existing addresses are canonicalized by
+-- cmd/emailmigrate, which reports the addresses colliding with each other.
+-- It can not be computed in SQL, so emailmigrate must run before the
+-- service is deployed: the addresses without it are not found by email
+alter table users
+    add column if not exists email_canonical varchar(254);
+alter table user_emails
+    add column if not exists email_canonical varchar(254);
+
+create unique index if not exists user_emails_email_canonical_key on user_emails (email_canonical);
+
+-- users.email_canonical is written along with users.email
+create or replace function sync_primary_email()
+    returns trigger
+    language plpgsql
+as
+$$
+begin
+    if tg_op = 'INSERT' then
+        insert into user_emails (email, email_canonical, user_id, is_primary)
+        values (new.email, new.email_canonical, new.id, true);
+    elsif new.email <> old.email then
+        if exists(select 1
+                  from user_emails
+                  where user_id = new.id
+                    and (email = new.email or email_canonical = new.email_canonical)) then
+            -- switch to one of the user's addresses, the old primary is kept
+            update user_emails
+            set is_primary = (email = new.email or email_canonical = new.email_canonical) is true
+            where user_id = new.id
+              and (is_primary or email = new.email or email_canonical = new.email_canonical);
+            -- keep the spelling of the new primary address
+            update user_emails
+            set email           = new.email,
+                email_canonical = new.email_canonical
+            where user_id = new.id
+              and is_primary;
+        else
+            -- replace the primary address
+            update user_emails
+            set email           = new.email,
+                email_canonical = new.email_canonical,
+                verified        = false,
+                created_at      = now()
+            where user_id = new.id
+              and is_primary;
+        end if;
+    end if;
+    return new;
+end;
+$$;
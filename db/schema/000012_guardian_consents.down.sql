@@ -0,0 +1,6 @@
+drop table if exists guardian_consents;
+
+alter table users
+    drop column if exists state;
+alter table users
+    drop column if exists country;
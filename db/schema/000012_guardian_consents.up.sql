@@ -0,0 +1,21 @@
+-- country of residence, decides the minimum age of registration
+alter table users
+    add column if not exists country varchar(2);
+
+-- 0 = active, 1 = pending guardian consent
+alter table users
+    add column if not exists state smallint not null default 0;
+
+create table if not exists guardian_consents
+(
+    id           uuid primary key,
+    minor_id     uuid        not null references users (id) on delete cascade,
+    guardian_id  uuid        not null references users (id) on delete cascade,
+    expires_at   timestamptz not null,
+    confirmed_at timestamptz,
+    created_at   timestamptz not null default now(),
+    constraint guardian_consents_distinct check (minor_id <> guardian_id)
+);
+
+create index if not exists guardian_consents_minor_id_idx on guardian_consents (minor_id);
+create index if not exists guardian_consents_guardian_id_idx on guardian_consents (guardian_id);
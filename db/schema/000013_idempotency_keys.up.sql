@@ -0,0 +1,15 @@
+-- responses of the mutating calls replayed to the retries with the same key
+create table if not exists idempotency_keys
+(
+    caller          text         not null,
+    method          text         not null,
+    idempotency_key varchar(255) not null,
+    fingerprint     bytea        not null,
+    -- null while the first call is in progress
+    response        bytea,
+    created_at      timestamptz  not null default now(),
+    expires_at      timestamptz  not null,
+    primary key (caller, method, idempotency_key)
+);
+
+create index if not exists idempotency_keys_expires_at_idx on idempotency_keys (expires_at);
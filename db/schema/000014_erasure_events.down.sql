@@ -0,0 +1,4 @@
+drop index if exists erasure_receipts_unpublished_idx;
+
+alter table erasure_receipts
+    drop column if exists published_at;
@@ -0,0 +1,11 @@
+-- published_at is set once the erasure event is delivered, the receipts
+-- without it are republished; the events of the earlier erasures are
+-- assumed to be delivered
+alter table erasure_receipts
+    add column if not exists published_at timestamptz;
+
+update erasure_receipts
+set published_at = erased_at
+where published_at is null;
+
+create index if not exists erasure_receipts_unpublished_idx on erasure_receipts (erased_at) where published_at is null;
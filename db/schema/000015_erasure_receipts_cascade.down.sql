@@ -0,0 +1,4 @@
+alter table erasure_receipts
+    drop constraint if exists erasure_receipts_user_id_fkey,
+    add constraint erasure_receipts_user_id_fkey
+        foreign key (user_id) references users (id);
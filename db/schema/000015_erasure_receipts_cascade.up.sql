@@ -0,0 +1,5 @@
+-- the erasure receipts are deleted with their user
+alter table erasure_receipts
+    drop constraint if exists erasure_receipts_user_id_fkey,
+    add constraint erasure_receipts_user_id_fkey
+        foreign key (user_id) references users (id) on delete cascade;
@@ -0,0 +1,4 @@
+alter table data_exports
+    drop constraint if exists data_exports_user_id_fkey,
+    add constraint data_exports_user_id_fkey
+        foreign key (user_id) references users (id);
@@ -0,0 +1,4 @@
+alter table user_consents
+    drop constraint if exists user_consents_user_id_fkey,
+    add constraint user_consents_user_id_fkey
+        foreign key (user_id) references users (id);
@@ -0,0 +1,5 @@
+-- the consents are deleted with their user
+alter table user_consents
+    drop constraint if exists user_consents_user_id_fkey,
+    add constraint user_consents_user_id_fkey
+        foreign key (user_id) references users (id) on delete cascade;
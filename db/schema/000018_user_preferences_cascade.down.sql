@@ -0,0 +1,4 @@
+alter table user_preferences
+    drop constraint if exists user_preferences_user_id_fkey,
+    add constraint user_preferences_user_id_fkey
+        foreign key (user_id) references users (id);
@@ -0,0 +1,5 @@
+-- the preferences are deleted with their user
+alter table user_preferences
+    drop constraint if exists user_preferences_user_id_fkey,
+    add constraint user_preferences_user_id_fkey
+        foreign key (user_id) references users (id) on delete cascade;
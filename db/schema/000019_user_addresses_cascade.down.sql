@@ -0,0 +1,4 @@
+alter table user_addresses
+    drop constraint if exists user_addresses_user_id_fkey,
+    add constraint user_addresses_user_id_fkey
+        foreign key (user_id) references users (id);
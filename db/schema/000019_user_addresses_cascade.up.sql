@@ -0,0 +1,5 @@
+-- the addresses are deleted with their user
+alter table user_addresses
+    drop constraint if exists user_addresses_user_id_fkey,
+    add constraint user_addresses_user_id_fkey
+        foreign key (user_id) references users (id) on delete cascade;
@@ -0,0 +1,169 @@
+package address
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/language"
+)
+
+// ErrInvalidAddress is returned if the address does not satisfy the rules
+// of its country.
+var ErrInvalidAddress = errors.New("invalid address")
+
+// FieldError is an ErrInvalidAddress caused by a single field.
+type FieldError struct {
+	// Field is the name of the invalid field, e.g. "postal_code".
+	Field string
+	msg   string
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrInvalidAddress, e.msg)
+}
+
+func (e *FieldError) Unwrap() error {
+	return ErrInvalidAddress
+}
+
+// invalid returns the FieldError of the field.
+func invalid(field, format string, args ...interface{}) error {
+	return &FieldError{Field: field, msg: fmt.Sprintf(format, args...)}
+}
+
+// Kind is the purpose of an address.
+type Kind int16
+
+// Kinds of the addresses. The values match the Address.Kind enum of the API.
+const (
+	KindHome Kind = iota + 1
+	KindBilling
+	KindShipping
+)
+
+// Valid reports whether the kind is known.
+func (k Kind) Valid() bool {
+	return k >= KindHome && k <= KindShipping
+}
+
+// Maximal lengths of the fields.
+const (
+	maxLineLength   = 128
+	maxCityLength   = 64
+	maxRegionLength = 64
+	maxPostalLength = 16
+)
+
+// Address is a postal address.
+type Address struct {
+	Recipient   string
+	Line1       string
+	Line2       string
+	City        string
+	Region      string
+	PostalCode  string
+	CountryCode string
+}
+
+// rule holds the country specific requirements of an address.
+type rule struct {
+	// postalCode matches valid postal codes, the postal code is optional if nil.
+	postalCode     *regexp.Regexp
+	regionRequired bool
+}
+
+// rules of the countries with a known postal code format. Postal codes
+// are matched after they are converted to upper case.
+var rules = map[string]rule{
+	"AT": {postalCode: regexp.MustCompile(`^\d{4}$`)},
+	"AU": {postalCode: regexp.MustCompile(`^\d{4}$`), regionRequired: true},
+	"BE": {postalCode: regexp.MustCompile(`^\d{4}$`)},
+	"BR": {postalCode: regexp.MustCompile(`^\d{5}-?\d{3}$`), regionRequired: true},
+	"CA": {postalCode: regexp.MustCompile(`^[A-Z]\d[A-Z] ?\d[A-Z]\d$`), regionRequired: true},
+	"CH": {postalCode: regexp.MustCompile(`^\d{4}$`)},
+	"CZ": {postalCode: regexp.MustCompile(`^\d{3} ?\d{2}$`)},
+	"DE": {postalCode: regexp.MustCompile(`^\d{5}$`)},
+	"DK": {postalCode: regexp.MustCompile(`^\d{4}$`)},
+	"ES": {postalCode: regexp.MustCompile(`^\d{5}$`)},
+	"FR": {postalCode: regexp.MustCompile(`^\d{5}$`)},
+	"GB": {postalCode: regexp.MustCompile(`^[A-Z]{1,2}\d[A-Z\d]? ?\d[A-Z]{2}$`)},
+	"IN": {postalCode: regexp.MustCompile(`^\d{6}$`), regionRequired: true},
+	"IT": {postalCode: regexp.MustCompile(`^\d{5}$`)},
+	"JP": {postalCode: regexp.MustCompile(`^\d{3}-?\d{4}$`), regionRequired: true},
+	"NL": {postalCode: regexp.MustCompile(`^\d{4} ?[A-Z]{2}$`)},
+	"NO": {postalCode: regexp.MustCompile(`^\d{4}$`)},
+	"PL": {postalCode: regexp.MustCompile(`^\d{2}-\d{3}$`)},
+	"SE": {postalCode: regexp.MustCompile(`^\d{3} ?\d{2}$`)},
+	"SK": {postalCode: regexp.MustCompile(`^\d{3} ?\d{2}$`)},
+	"US": {postalCode: regexp.MustCompile(`^\d{5}(-\d{4})?$`), regionRequired: true},
+}
+
+// countries without postal codes.
+var noPostalCode = map[string]bool{
+	"AE": true,
+	"HK": true,
+	"QA": true,
+}
+
+// Normalize trims the fields, upper-cases the country and postal codes and
+// validates the address against the rules of its country.
+func Normalize(a Address) (Address, error) {
+	a = Address{
+		Recipient:   strings.TrimSpace(a.Recipient),
+		Line1:       strings.TrimSpace(a.Line1),
+		Line2:       strings.TrimSpace(a.Line2),
+		City:        strings.TrimSpace(a.City),
+		Region:      strings.TrimSpace(a.Region),
+		PostalCode:  strings.ToUpper(strings.TrimSpace(a.PostalCode)),
+		CountryCode: strings.ToUpper(strings.TrimSpace(a.CountryCode)),
+	}
+
+	for _, f := range []struct {
+		name     string
+		value    string
+		required bool
+		max      int
+	}{
+		{"recipient", a.Recipient, true, maxLineLength},
+		{"line1", a.Line1, true, maxLineLength},
+		{"line2", a.Line2, false, maxLineLength},
+		{"city", a.City, true, maxCityLength},
+		{"region", a.Region, false, maxRegionLength},
+		{"postal_code", a.PostalCode, false, maxPostalLength},
+	} {
+		switch {
+		case f.required && f.value == "":
+			return Address{}, invalid(f.name, "missing %s", f.name)
+		case utf8.RuneCountInString(f.value) > f.max:
+			return Address{}, invalid(f.name, "%s exceeds %d characters", f.name, f.max)
+		}
+	}
+
+	// country
+	if len(a.CountryCode) != 2 {
+		return Address{}, invalid("country_code", "country code %q is not an ISO 3166-1 alpha-2 code", a.CountryCode)
+	}
+	if r, err := language.ParseRegion(a.CountryCode); err != nil || !r.IsCountry() {
+		return Address{}, invalid("country_code", "unknown country code %q", a.CountryCode)
+	}
+
+	// country specific rules
+	if noPostalCode[a.CountryCode] && a.PostalCode != "" {
+		return Address{}, invalid("postal_code", "%s does not use postal codes", a.CountryCode)
+	}
+	r, ok := rules[a.CountryCode]
+	if !ok {
+		return a, nil
+	}
+	if r.postalCode != nil && !r.postalCode.MatchString(a.PostalCode) {
+		return Address{}, invalid("postal_code", "invalid postal code %q for %s", a.PostalCode, a.CountryCode)
+	}
+	if r.regionRequired && a.Region == "" {
+		return Address{}, invalid("region", "missing region, required for %s", a.CountryCode)
+	}
+
+	return a, nil
+}
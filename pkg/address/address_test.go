@@ -0,0 +1,76 @@
+package address_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/chutommy/user-microservice/pkg/address"
+)
+
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+
+	valid := func(country, region, postal string) address.Address {
+		return address.Address{
+			Recipient:   "Jane Doe",
+			Line1:       "1 Main Street",
+			City:        "Springfield",
+			Region:      region,
+			PostalCode:  postal,
+			CountryCode: country,
+		}
+	}
+
+	tests := []struct {
+		name      string
+		addr      address.Address
+		expPostal string
+		expField  string
+	}{
+		{"us", valid("US", "IL", "62701"), "62701", ""},
+		{"us zip+4", valid("US", "IL", "62701-1234"), "62701-1234", ""},
+		{"us missing region", valid("US", "", "62701"), "", "region"},
+		{"us invalid zip", valid("US", "IL", "6270"), "", "postal_code"},
+		{"gb lower case", valid("gb", "", " sw1a 1aa "), "SW1A 1AA", ""},
+		{"ca", valid("CA", "ON", "k1a 0b1"), "K1A 0B1", ""},
+		{"cz", valid("CZ", "", "110 00"), "110 00", ""},
+		{"cz invalid", valid("CZ", "", "1100"), "", "postal_code"},
+		{"de", valid("DE", "", "10115"), "10115", ""},
+		{"hong kong without postal code", valid("HK", "", ""), "", ""},
+		{"hong kong with postal code", valid("HK", "", "999077"), "", "postal_code"},
+		{"unlisted country", valid("KE", "", "00100"), "00100", ""},
+		{"unknown country", valid("XX", "", "12345"), "", "country_code"},
+		{"not a country", valid("EU", "", "12345"), "", "country_code"},
+		{"alpha-3 country", valid("USA", "IL", "62701"), "", "country_code"},
+		{"missing recipient", address.Address{Line1: "1 Main Street", City: "Berlin", PostalCode: "10115", CountryCode: "DE"}, "", "recipient"},
+		{"missing city", address.Address{Recipient: "Jane Doe", Line1: "1 Main Street", PostalCode: "10115", CountryCode: "DE"}, "", "city"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			a, err := address.Normalize(tt.addr)
+			if tt.expField == "" {
+				require.NoError(t, err)
+				require.Equal(t, tt.expPostal, a.PostalCode)
+				require.Len(t, a.CountryCode, 2)
+			} else {
+				require.ErrorIs(t, err, address.ErrInvalidAddress)
+
+				var fieldErr *address.FieldError
+				require.ErrorAs(t, err, &fieldErr)
+				require.Equal(t, tt.expField, fieldErr.Field)
+			}
+		})
+	}
+}
+
+func TestKind_Valid(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, address.KindHome.Valid())
+	require.True(t, address.KindShipping.Valid())
+	require.False(t, address.Kind(0).Valid())
+	require.False(t, address.Kind(4).Valid())
+}
@@ -0,0 +1,138 @@
+package age
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// Adult is the age from which a user may act as a guardian.
+const Adult = 18
+
+// ErrInvalidPolicy is returned if a policy can not be parsed.
+var ErrInvalidPolicy = errors.New("invalid minimum age policy")
+
+// Policy holds the minimum age from which users may register without the
+// consent of a guardian. The zero Policy requires no consent at all.
+type Policy struct {
+	// Default applies to the countries which are not listed, and to the
+	// users of an unknown country.
+	Default int
+
+	// Countries maps the ISO 3166-1 alpha-2 codes to their minimum age.
+	Countries map[string]int
+}
+
+// ParsePolicy parses a comma separated list of COUNTRY=AGE pairs, the country
+// "*" sets the default, e.g. "*=16,US=13,KR=14".
+func ParsePolicy(s string) (Policy, error) {
+	p := Policy{Countries: make(map[string]int)}
+	if strings.TrimSpace(s) == "" {
+		return p, nil
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		kv := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(kv) != 2 {
+			return Policy{}, fmt.Errorf("%w: %q is not a COUNTRY=AGE pair", ErrInvalidPolicy, pair)
+		}
+
+		years, err := strconv.Atoi(strings.TrimSpace(kv[1]))
+		if err != nil || years < 0 {
+			return Policy{}, fmt.Errorf("%w: invalid age %q", ErrInvalidPolicy, kv[1])
+		}
+
+		country := strings.ToUpper(strings.TrimSpace(kv[0]))
+		if country == "*" {
+			p.Default = years
+			continue
+		}
+		if err = ValidateCountry(country); err != nil {
+			return Policy{}, fmt.Errorf("%w: %v", ErrInvalidPolicy, err)
+		}
+		p.Countries[country] = years
+	}
+
+	return p, nil
+}
+
+// ValidateCountry reports an error if the code is not an ISO 3166-1 alpha-2
+// country code. An empty code is valid.
+func ValidateCountry(country string) error {
+	if country == "" {
+		return nil
+	}
+
+	r, err := language.ParseRegion(country)
+	if err != nil || len(country) != 2 || !r.IsCountry() {
+		return fmt.Errorf("unknown country %q", country)
+	}
+
+	return nil
+}
+
+// IsZero reports whether the policy requires no consent in any country.
+func (p Policy) IsZero() bool {
+	if p.Default > 0 {
+		return false
+	}
+	for _, years := range p.Countries {
+		if years > 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// MinimumAge returns the minimum age of the country.
+func (p Policy) MinimumAge(country string) int {
+	if years, ok := p.Countries[strings.ToUpper(country)]; ok {
+		return years
+	}
+
+	return p.Default
+}
+
+// RequiresConsent reports whether a user born on the birthday and living in
+// the country needs the consent of a guardian at the given time.
+func (p Policy) RequiresConsent(birthday time.Time, country string, now time.Time) bool {
+	return Of(birthday, now) < p.MinimumAge(country)
+}
+
+// String formats the policy in the format of ParsePolicy.
+func (p Policy) String() string {
+	pairs := make([]string, 0, len(p.Countries)+1)
+	pairs = append(pairs, "*="+strconv.Itoa(p.Default))
+	for country, years := range p.Countries {
+		pairs = append(pairs, country+"="+strconv.Itoa(years))
+	}
+	sort.Strings(pairs[1:])
+
+	return strings.Join(pairs, ",")
+}
+
+// Of returns the age in completed years of a person born on the birthday.
+// A person born on 29 February becomes a year older on 1 March of common years.
+func Of(birthday, now time.Time) int {
+	years := now.Year() - birthday.Year()
+
+	bm, bd := birthday.Month(), birthday.Day()
+	if bm == time.February && bd == 29 && !isLeap(now.Year()) {
+		bm, bd = time.March, 1
+	}
+	if now.Month() < bm || (now.Month() == bm && now.Day() < bd) {
+		years--
+	}
+
+	return years
+}
+
+func isLeap(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
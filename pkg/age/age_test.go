@@ -0,0 +1,77 @@
+package age_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/chutommy/user-microservice/pkg/age"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestOf(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		birthday time.Time
+		now      time.Time
+		exp      int
+	}{
+		{"day before birthday", date(2000, 6, 15), date(2016, 6, 14), 15},
+		{"on birthday", date(2000, 6, 15), date(2016, 6, 15), 16},
+		{"after birthday", date(2000, 6, 15), date(2016, 12, 1), 16},
+		{"leap day in leap year", date(2004, 2, 29), date(2020, 2, 29), 16},
+		{"leap day before march", date(2004, 2, 29), date(2021, 2, 28), 16},
+		{"leap day on march", date(2004, 2, 29), date(2021, 3, 1), 17},
+		{"newborn", date(2021, 3, 1), date(2021, 3, 1), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.exp, age.Of(tt.birthday, tt.now))
+		})
+	}
+}
+
+func TestParsePolicy(t *testing.T) {
+	t.Parallel()
+
+	p, err := age.ParsePolicy("*=16, us=13,KR=14")
+	require.NoError(t, err)
+	require.Equal(t, 16, p.Default)
+	require.Equal(t, 13, p.MinimumAge("US"))
+	require.Equal(t, 14, p.MinimumAge("kr"))
+	require.Equal(t, 16, p.MinimumAge("CZ"))
+	require.Equal(t, 16, p.MinimumAge(""))
+	require.Equal(t, "*=16,KR=14,US=13", p.String())
+
+	p, err = age.ParsePolicy("")
+	require.NoError(t, err)
+	require.Equal(t, 0, p.MinimumAge("US"))
+
+	for _, s := range []string{"16", "US=x", "US=-1", "XX=13", "USA=13"} {
+		_, err = age.ParsePolicy(s)
+		require.ErrorIs(t, err, age.ErrInvalidPolicy, s)
+	}
+}
+
+func TestPolicy_RequiresConsent(t *testing.T) {
+	t.Parallel()
+
+	p := age.Policy{Default: 16, Countries: map[string]int{"US": 13}}
+	now := date(2021, 3, 1)
+
+	require.True(t, p.RequiresConsent(date(2006, 3, 2), "CZ", now))
+	require.False(t, p.RequiresConsent(date(2005, 3, 1), "CZ", now))
+	require.False(t, p.RequiresConsent(date(2006, 3, 2), "US", now))
+	require.True(t, p.RequiresConsent(date(2008, 3, 2), "us", now))
+	require.False(t, age.Policy{}.RequiresConsent(date(2021, 1, 1), "CZ", now))
+	require.False(t, p.IsZero())
+	require.True(t, age.Policy{}.IsZero())
+	require.False(t, age.Policy{Countries: map[string]int{"US": 13}}.IsZero())
+}
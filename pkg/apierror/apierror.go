@@ -0,0 +1,230 @@
+// Package apierror builds the errors returned to the clients: gRPC statuses
+// with google.rpc error details, which never contain internal error text, and
+// their RFC 7807 problem details on the gateway.
+package apierror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Domain is the domain of the ErrorInfo reasons.
+const Domain = "user-service"
+
+// Reasons of the ErrorInfo details, they are stable and may be relied on by
+// the clients.
+const (
+	// ReasonEmailTaken is the reason of an email address used by another user.
+	ReasonEmailTaken = "EMAIL_TAKEN"
+	// ReasonPhoneTaken is the reason of a phone number used by another user.
+	ReasonPhoneTaken = "PHONE_TAKEN"
+	// ReasonIdempotencyKeyReused is the reason of an idempotency key sent
+	// with a different request than the first one.
+	ReasonIdempotencyKeyReused = "IDEMPOTENCY_KEY_REUSED"
+	// ReasonIdempotencyKeyInUse is the reason of a retry sent before the
+	// first call with the idempotency key completed.
+	ReasonIdempotencyKeyInUse = "IDEMPOTENCY_KEY_IN_USE"
+)
+
+// internalMessage is the message of the errors which are not statuses.
+const internalMessage = "internal error"
+
+// uniqueViolation is the code of the Postgres unique_violation error.
+const uniqueViolation = "23505"
+
+// conflict is a unique constraint mapped to a reason.
+type conflict struct {
+	reason  string
+	field   string
+	message string
+}
+
+var (
+	emailTaken = conflict{reason: ReasonEmailTaken, field: "email", message: "email address is already taken"}
+	phoneTaken = conflict{reason: ReasonPhoneTaken, field: "phone", message: "phone number is already taken"}
+)
+
+// constraints maps the names of the unique constraints to the conflicts.
+var constraints = map[string]conflict{
+	"users_email_key":                 emailTaken,
+	"user_emails_pkey":                emailTaken,
+	"user_emails_email_canonical_key": emailTaken,
+	"users_phone_number_key":          phoneTaken,
+}
+
+// InvalidField returns an InvalidArgument status with the message and a
+// BadRequest violation of the field.
+func InvalidField(field, format string, args ...interface{}) error {
+	msg := fmt.Sprintf(format, args...)
+
+	st := status.New(codes.InvalidArgument, msg)
+	if ds, err := st.WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: field, Description: msg}},
+	}); err == nil {
+		st = ds
+	}
+
+	return st.Err()
+}
+
+// Conflict maps the violation of a unique constraint to an AlreadyExists
+// status. The known constraints carry the ErrorInfo of their reason. It
+// returns nil if the error is not such a violation.
+func Conflict(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code != uniqueViolation {
+		return nil
+	}
+
+	c, ok := constraints[pqErr.Constraint]
+	if !ok {
+		return status.Error(codes.AlreadyExists, "resource already exists")
+	}
+
+	return WithReason(codes.AlreadyExists, c.reason, map[string]string{"field": c.field}, c.message)
+}
+
+// WithReason returns a status with the message and the ErrorInfo of the
+// reason and the metadata.
+func WithReason(code codes.Code, reason string, metadata map[string]string, msg string) error {
+	st := status.New(code, msg)
+	if ds, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   reason,
+		Domain:   Domain,
+		Metadata: metadata,
+	}); err == nil {
+		st = ds
+	}
+
+	return st.Err()
+}
+
+// fieldError is a validation error generated by protoc-gen-validate.
+type fieldError interface {
+	Field() string
+	Reason() string
+	Cause() error
+}
+
+// multiError is the error of all the violations of a message generated by
+// protoc-gen-validate.
+type multiError interface {
+	AllErrors() []error
+}
+
+// snakeCase converts the Go name of a field to its proto name.
+func snakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// Validation converts the error of a generated ValidateAll method to an
+// InvalidArgument status with a BadRequest violation of each invalid field,
+// e.g. "user.email". Other errors are returned unchanged.
+func Validation(err error) error {
+	violations := fieldViolations(nil, err)
+	if len(violations) == 0 {
+		return err
+	}
+
+	msgs := make([]string, 0, len(violations))
+	for _, v := range violations {
+		msgs = append(msgs, v.Description)
+	}
+
+	st := status.New(codes.InvalidArgument, strings.Join(msgs, "; "))
+	if ds, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations}); err == nil {
+		st = ds
+	}
+
+	return st.Err()
+}
+
+// fieldViolations returns the violations of the validation error of the
+// message at the path. The errors of the embedded messages are nested in
+// their causes.
+func fieldViolations(path []string, err error) []*errdetails.BadRequest_FieldViolation {
+	var me multiError
+	if errors.As(err, &me) {
+		var violations []*errdetails.BadRequest_FieldViolation
+		for _, e := range me.AllErrors() {
+			violations = append(violations, fieldViolations(path, e)...)
+		}
+
+		return violations
+	}
+
+	var fe fieldError
+	if !errors.As(err, &fe) {
+		return nil
+	}
+
+	path = append(path[:len(path):len(path)], snakeCase(fe.Field()))
+	if nested := fieldViolations(path, fe.Cause()); len(nested) > 0 {
+		return nested
+	}
+
+	field := strings.Join(path, ".")
+	return []*errdetails.BadRequest_FieldViolation{{
+		Field:       field,
+		Description: fmt.Sprintf("invalid %s: %s", field, fe.Reason()),
+	}}
+}
+
+// sanitize replaces the errors which are not statuses, their text is
+// internal. The context errors keep their codes.
+func sanitize(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, context.Canceled.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, context.DeadlineExceeded.Error())
+	}
+
+	ctxzap.Extract(ctx).Error("internal error", zap.Error(err))
+	return status.Error(codes.Internal, internalMessage)
+}
+
+// UnaryServerInterceptor replaces the errors of the handlers which are not
+// statuses by an Internal status, so their text is never sent to the clients.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		return resp, sanitize(ctx, err)
+	}
+}
+
+// StreamServerInterceptor replaces the errors of the streaming handlers, see
+// UnaryServerInterceptor.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return sanitize(ss.Context(), handler(srv, ss))
+	}
+}
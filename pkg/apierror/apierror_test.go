@@ -0,0 +1,182 @@
+package apierror_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/chutommy/user-microservice/pkg/apierror"
+	"github.com/chutommy/user-microservice/pkg/grpc/userpb"
+)
+
+func TestInvalidField(t *testing.T) {
+	t.Parallel()
+
+	err := apierror.InvalidField("user.email", "invalid %s", "email")
+	st := status.Convert(err)
+	require.Equal(t, codes.InvalidArgument, st.Code())
+	require.Equal(t, "invalid email", st.Message())
+
+	require.Len(t, st.Details(), 1)
+	violations := st.Details()[0].(*errdetails.BadRequest).GetFieldViolations()
+	require.Len(t, violations, 1)
+	require.Equal(t, "user.email", violations[0].GetField())
+	require.Equal(t, "invalid email", violations[0].GetDescription())
+}
+
+func TestConflict(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		err    error
+		code   codes.Code
+		reason string
+	}{
+		{
+			name:   "email",
+			err:    &pq.Error{Code: "23505", Constraint: "users_email_key", Detail: "Key (email)=(alice@example.org) already exists."},
+			code:   codes.AlreadyExists,
+			reason: apierror.ReasonEmailTaken,
+		},
+		{
+			name:   "secondary email",
+			err:    fmt.Errorf("add email: %w", &pq.Error{Code: "23505", Constraint: "user_emails_email_canonical_key"}),
+			code:   codes.AlreadyExists,
+			reason: apierror.ReasonEmailTaken,
+		},
+		{
+			name:   "phone",
+			err:    &pq.Error{Code: "23505", Constraint: "users_phone_number_key"},
+			code:   codes.AlreadyExists,
+			reason: apierror.ReasonPhoneTaken,
+		},
+		{
+			name: "unknown constraint",
+			err:  &pq.Error{Code: "23505", Constraint: "addresses_pkey"},
+			code: codes.AlreadyExists,
+		},
+		{
+			name: "foreign key violation",
+			err:  &pq.Error{Code: "23503", Constraint: "users_email_key"},
+			code: codes.OK,
+		},
+		{
+			name: "other error",
+			err:  errors.New("connection refused"),
+			code: codes.OK,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := apierror.Conflict(tt.err)
+			require.Equal(t, tt.code, status.Code(err))
+			if err == nil {
+				return
+			}
+
+			st := status.Convert(err)
+			require.NotContains(t, st.Message(), "alice@example.org")
+			if tt.reason == "" {
+				require.Empty(t, st.Details())
+				return
+			}
+
+			require.Len(t, st.Details(), 1)
+			info := st.Details()[0].(*errdetails.ErrorInfo)
+			require.Equal(t, tt.reason, info.GetReason())
+			require.Equal(t, apierror.Domain, info.GetDomain())
+		})
+	}
+}
+
+func TestValidation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		msg    interface{ ValidateAll() error }
+		fields []string
+	}{
+		{"nested", &userpb.RegisterUserRequest{User: &userpb.User{Email: strings.Repeat("a", 255)}}, []string{"user.email"}},
+		{"required", &userpb.RegisterUserRequest{}, []string{"user"}},
+		{"top level", &userpb.RegisterUserRequest{User: &userpb.User{}, PhoneRegion: "USA"}, []string{"phone_region"}},
+		{
+			"multiple",
+			&userpb.RegisterUserRequest{User: &userpb.User{Email: strings.Repeat("a", 255), Country: "USA"}, PhoneRegion: "USA"},
+			[]string{"user.email", "user.country", "phone_region"},
+		},
+		{
+			"repeated",
+			&userpb.RegisterUserRequest{User: &userpb.User{}, AcceptedPolicies: []*userpb.PolicyVersion{{Kind: "tos", Version: 1}, {}}},
+			[]string{"accepted_policies[1].kind", "accepted_policies[1].version"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			st := status.Convert(apierror.Validation(tt.msg.ValidateAll()))
+			require.Equal(t, codes.InvalidArgument, st.Code())
+			require.Len(t, st.Details(), 1)
+
+			var fields []string
+			for _, v := range st.Details()[0].(*errdetails.BadRequest).GetFieldViolations() {
+				fields = append(fields, v.GetField())
+				require.Contains(t, st.Message(), v.GetDescription())
+			}
+			require.Equal(t, tt.fields, fields)
+		})
+	}
+
+	err := errors.New("plain")
+	require.Equal(t, err, apierror.Validation(err))
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		code codes.Code
+		msg  string
+	}{
+		{"ok", nil, codes.OK, ""},
+		{"status", status.Error(codes.NotFound, "user not found"), codes.NotFound, "user not found"},
+		{"internal", errors.New("pq: password authentication failed"), codes.Internal, "internal error"},
+		{"canceled", fmt.Errorf("query: %w", context.Canceled), codes.Canceled, "context canceled"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := apierror.UnaryServerInterceptor()(context.Background(), nil, &grpc.UnaryServerInfo{},
+				func(context.Context, interface{}) (interface{}, error) {
+					return nil, tt.err
+				})
+			require.Equal(t, tt.code, status.Code(err))
+			require.Equal(t, tt.msg, status.Convert(err).Message())
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package apierror
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ProblemContentType is the media type of the RFC 7807 problem details.
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details object. The extension members carry
+// the gRPC code and the error details of the status.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+
+	Code          string            `json:"code,omitempty"`
+	Reason        string            `json:"reason,omitempty"`
+	Domain        string            `json:"domain,omitempty"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+	InvalidParams []InvalidParam    `json:"invalid-params,omitempty"`
+}
+
+// InvalidParam is a request field which violates its rules.
+type InvalidParam struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// newProblem returns the problem of the HTTP status code.
+func newProblem(r *http.Request, code int) *Problem {
+	return &Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(code),
+		Status:   code,
+		Instance: r.URL.Path,
+	}
+}
+
+// NewProblem converts the error to the problem details. Errors which are not
+// statuses are reported as internal without their text.
+func NewProblem(r *http.Request, err error) *Problem {
+	st, ok := status.FromError(err)
+	if !ok {
+		st = status.New(codes.Internal, internalMessage)
+	}
+
+	p := newProblem(r, runtime.HTTPStatusFromCode(st.Code()))
+	p.Detail = st.Message()
+	p.Code = st.Code().String()
+
+	for _, d := range st.Details() {
+		switch d := d.(type) {
+		case *errdetails.ErrorInfo:
+			p.Reason = d.GetReason()
+			p.Domain = d.GetDomain()
+			p.Metadata = d.GetMetadata()
+		case *errdetails.BadRequest:
+			for _, v := range d.GetFieldViolations() {
+				p.InvalidParams = append(p.InvalidParams, InvalidParam{Name: v.GetField(), Reason: v.GetDescription()})
+			}
+		}
+	}
+
+	return p
+}
+
+// Write writes the problem as the response.
+func (p *Problem) Write(w http.ResponseWriter) {
+	w.Header().Del("Trailer")
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
+
+// WriteProblem writes the error as the problem details response.
+func WriteProblem(w http.ResponseWriter, r *http.Request, err error) {
+	NewProblem(r, err).Write(w)
+}
+
+// ErrorHandler is the gateway error handler writing the problem details.
+func ErrorHandler(_ context.Context, _ *runtime.ServeMux, _ runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	st := status.Convert(err)
+	if st.Code() == codes.Unauthenticated {
+		w.Header().Set("WWW-Authenticate", st.Message())
+	}
+
+	WriteProblem(w, r, err)
+}
+
+// RoutingErrorHandler is the gateway routing error handler writing the
+// problem details of the HTTP status, e.g. 405 for a wrong method.
+func RoutingErrorHandler(_ context.Context, _ *runtime.ServeMux, _ runtime.Marshaler, w http.ResponseWriter, r *http.Request, code int) {
+	newProblem(r, code).Write(w)
+}
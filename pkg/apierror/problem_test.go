@@ -0,0 +1,111 @@
+package apierror_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/chutommy/user-microservice/pkg/apierror"
+)
+
+func TestErrorHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want apierror.Problem
+	}{
+		{
+			name: "conflict",
+			err:  apierror.Conflict(&pq.Error{Code: "23505", Constraint: "users_phone_number_key"}),
+			want: apierror.Problem{
+				Type:     "about:blank",
+				Title:    "Conflict",
+				Status:   http.StatusConflict,
+				Detail:   "phone number is already taken",
+				Instance: "/v1/user",
+				Code:     "AlreadyExists",
+				Reason:   apierror.ReasonPhoneTaken,
+				Domain:   apierror.Domain,
+				Metadata: map[string]string{"field": "phone"},
+			},
+		},
+		{
+			name: "invalid field",
+			err:  apierror.InvalidField("id", "invalid id"),
+			want: apierror.Problem{
+				Type:          "about:blank",
+				Title:         "Bad Request",
+				Status:        http.StatusBadRequest,
+				Detail:        "invalid id",
+				Instance:      "/v1/user",
+				Code:          "InvalidArgument",
+				InvalidParams: []apierror.InvalidParam{{Name: "id", Reason: "invalid id"}},
+			},
+		},
+		{
+			name: "not found",
+			err:  status.Error(codes.NotFound, "user not found"),
+			want: apierror.Problem{
+				Type:     "about:blank",
+				Title:    "Not Found",
+				Status:   http.StatusNotFound,
+				Detail:   "user not found",
+				Instance: "/v1/user",
+				Code:     "NotFound",
+			},
+		},
+		{
+			name: "internal",
+			err:  errors.New("dial tcp 10.0.0.3:5432: connection refused"),
+			want: apierror.Problem{
+				Type:     "about:blank",
+				Title:    "Internal Server Error",
+				Status:   http.StatusInternalServerError,
+				Detail:   "internal error",
+				Instance: "/v1/user",
+				Code:     "Internal",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/v1/user", nil)
+			apierror.ErrorHandler(context.Background(), nil, nil, w, r, tt.err)
+
+			require.Equal(t, tt.want.Status, w.Code)
+			require.Equal(t, apierror.ProblemContentType, w.Header().Get("Content-Type"))
+
+			var got apierror.Problem
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRoutingErrorHandler(t *testing.T) {
+	t.Parallel()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPut, "/v1/user/export", nil)
+	apierror.RoutingErrorHandler(context.Background(), nil, nil, w, r, http.StatusMethodNotAllowed)
+
+	require.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	require.Equal(t, apierror.ProblemContentType, w.Header().Get("Content-Type"))
+	require.JSONEq(t, `{"type":"about:blank","title":"Method Not Allowed","status":405,"instance":"/v1/user/export"}`, w.Body.String())
+}
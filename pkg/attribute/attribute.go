@@ -0,0 +1,128 @@
+package attribute
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+var (
+	// ErrInvalidAttributes is returned if the attributes do not conform to the schema.
+	ErrInvalidAttributes = errors.New("invalid attributes")
+
+	// ErrPathNotIndexed is returned if a filter uses a path which is not indexed.
+	ErrPathNotIndexed = errors.New("attribute path is not indexed")
+)
+
+// schemaURL identifies the configured schema in the validation errors.
+const schemaURL = "attributes.schema.json"
+
+// Validator checks custom profile attributes against a JSON Schema.
+type Validator struct {
+	schema *jsonschema.Schema
+}
+
+// NewValidator compiles the JSON Schema read from r.
+func NewValidator(r io.Reader) (*Validator, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := jsonschema.CompileString(schemaURL, string(data))
+	if err != nil {
+		return nil, fmt.Errorf("compile attributes schema: %w", err)
+	}
+
+	return &Validator{
+		schema: schema,
+	}, nil
+}
+
+// Validate checks the attributes. A nil Validator accepts any JSON object.
+func (v *Validator) Validate(attrs map[string]interface{}) error {
+	if v == nil {
+		return nil
+	}
+
+	// the schema validates the generic JSON representation
+	data, err := json.Marshal(attrs)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidAttributes, err)
+	}
+	var doc interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err = dec.Decode(&doc); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidAttributes, err)
+	}
+
+	if err = v.schema.Validate(doc); err != nil {
+		var ve *jsonschema.ValidationError
+		if errors.As(err, &ve) {
+			return fmt.Errorf("%w: %s", ErrInvalidAttributes, describe(ve))
+		}
+		return fmt.Errorf("%w: %v", ErrInvalidAttributes, err)
+	}
+
+	return nil
+}
+
+// describe returns the messages of the innermost validation errors.
+func describe(ve *jsonschema.ValidationError) string {
+	if len(ve.Causes) == 0 {
+		loc := ve.InstanceLocation
+		if loc == "" {
+			loc = "/"
+		}
+		return fmt.Sprintf("%s: %s", loc, ve.Message)
+	}
+
+	msgs := make([]string, 0, len(ve.Causes))
+	for _, c := range ve.Causes {
+		msgs = append(msgs, describe(c))
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// pathPattern matches dot separated attribute paths such as "company.department".
+var pathPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+// Filter builds a JSON containment document which matches users whose attributes
+// hold the given values at the dot separated paths. Only the indexed paths may be used.
+func Filter(values map[string]interface{}, indexed []string) (json.RawMessage, error) {
+	allowed := make(map[string]bool, len(indexed))
+	for _, p := range indexed {
+		allowed[p] = true
+	}
+
+	doc := map[string]interface{}{}
+	for path, value := range values {
+		if !pathPattern.MatchString(path) || !allowed[path] {
+			return nil, fmt.Errorf("%w: %q", ErrPathNotIndexed, path)
+		}
+
+		// build the nested objects of the path
+		node := doc
+		parts := strings.Split(path, ".")
+		for _, p := range parts[:len(parts)-1] {
+			next, ok := node[p].(map[string]interface{})
+			if !ok {
+				next = map[string]interface{}{}
+				node[p] = next
+			}
+			node = next
+		}
+		node[parts[len(parts)-1]] = value
+	}
+
+	return json.Marshal(doc)
+}
@@ -0,0 +1,115 @@
+package attribute_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/chutommy/user-microservice/pkg/attribute"
+)
+
+const schema = `{
+	"type": "object",
+	"properties": {
+		"company": {
+			"type": "object",
+			"properties": {
+				"name": {"type": "string", "maxLength": 64},
+				"department": {"type": "string"}
+			},
+			"required": ["name"]
+		},
+		"employee_number": {"type": "integer", "minimum": 1}
+	},
+	"additionalProperties": false
+}`
+
+func TestValidator_Validate(t *testing.T) {
+	t.Parallel()
+
+	v, err := attribute.NewValidator(strings.NewReader(schema))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		attrs map[string]interface{}
+		valid bool
+	}{
+		{
+			name:  "empty",
+			attrs: map[string]interface{}{},
+			valid: true,
+		},
+		{
+			name: "valid",
+			attrs: map[string]interface{}{
+				"company":         map[string]interface{}{"name": "ACME", "department": "sales"},
+				"employee_number": 42.0,
+			},
+			valid: true,
+		},
+		{
+			name:  "missing required",
+			attrs: map[string]interface{}{"company": map[string]interface{}{"department": "sales"}},
+			valid: false,
+		},
+		{
+			name:  "wrong type",
+			attrs: map[string]interface{}{"employee_number": "42"},
+			valid: false,
+		},
+		{
+			name:  "not an integer",
+			attrs: map[string]interface{}{"employee_number": 4.2},
+			valid: false,
+		},
+		{
+			name:  "unknown attribute",
+			attrs: map[string]interface{}{"nickname": "joe"},
+			valid: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.Validate(tt.attrs)
+			if tt.valid {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, attribute.ErrInvalidAttributes)
+			}
+		})
+	}
+
+	// no schema accepts anything
+	var nilValidator *attribute.Validator
+	require.NoError(t, nilValidator.Validate(map[string]interface{}{"nickname": "joe"}))
+
+	_, err = attribute.NewValidator(strings.NewReader(`{"type": 42}`))
+	require.Error(t, err)
+}
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+
+	indexed := []string{"company.department", "company.name", "tier"}
+
+	f, err := attribute.Filter(map[string]interface{}{
+		"company.department": "sales",
+		"company.name":       "ACME",
+		"tier":               2.0,
+	}, indexed)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"company": {"department": "sales", "name": "ACME"}, "tier": 2}`, string(f))
+
+	f, err = attribute.Filter(nil, indexed)
+	require.NoError(t, err)
+	require.JSONEq(t, `{}`, string(f))
+
+	_, err = attribute.Filter(map[string]interface{}{"employee_number": 1.0}, indexed)
+	require.ErrorIs(t, err, attribute.ErrPathNotIndexed)
+
+	_, err = attribute.Filter(map[string]interface{}{"company..name": "x"}, []string{"company..name"})
+	require.ErrorIs(t, err, attribute.ErrPathNotIndexed)
+}
@@ -0,0 +1,171 @@
+package avatar
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"path"
+	"strconv"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+var (
+	// ErrUnsupportedType is returned for images of other types than JPEG and PNG.
+	ErrUnsupportedType = errors.New("unsupported image type")
+
+	// ErrTooLarge is returned if the image exceeds the size or dimension limits.
+	ErrTooLarge = errors.New("image too large")
+
+	// ErrInvalidImage is returned if the image can not be decoded.
+	ErrInvalidImage = errors.New("invalid image")
+)
+
+const (
+	// MaxSize is the maximal size of an uploaded image in bytes.
+	MaxSize = 5 << 20
+
+	// MaxDimension is the maximal width and height of an uploaded image in pixels.
+	MaxDimension = 4096
+
+	// jpegQuality is the quality of the re-encoded JPEG images.
+	jpegQuality = 90
+)
+
+// ThumbnailSizes are the edge lengths of the square thumbnails in pixels.
+var ThumbnailSizes = []int{64, 256}
+
+// Image is an encoded image.
+type Image struct {
+	ContentType string
+	Data        []byte
+}
+
+// Ext returns the file extension of the image type.
+func (i Image) Ext() string {
+	if i.ContentType == "image/png" {
+		return "png"
+	}
+
+	return "jpg"
+}
+
+// Avatar is a processed avatar, the original and its thumbnails keyed by size.
+type Avatar struct {
+	Original   Image
+	Thumbnails map[int]Image
+}
+
+// Process validates the uploaded image and re-encodes it without any metadata.
+// JPEG images are rotated according to their EXIF orientation first. The
+// thumbnails are center-cropped squares encoded in the type of the original.
+func Process(data []byte) (*Avatar, error) {
+	if len(data) > MaxSize {
+		return nil, fmt.Errorf("%w: exceeds %d bytes", ErrTooLarge, MaxSize)
+	}
+
+	// the content is sniffed, a declared type can not be trusted
+	contentType := http.DetectContentType(data)
+	if contentType != "image/jpeg" && contentType != "image/png" {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, contentType)
+	}
+
+	// check the dimensions before the pixels are allocated
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	if cfg.Width > MaxDimension || cfg.Height > MaxDimension {
+		return nil, fmt.Errorf("%w: %dx%d exceeds %dx%d pixels", ErrTooLarge, cfg.Width, cfg.Height, MaxDimension, MaxDimension)
+	}
+	if cfg.Width == 0 || cfg.Height == 0 {
+		return nil, fmt.Errorf("%w: empty image", ErrInvalidImage)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	if contentType == "image/jpeg" {
+		img = orient(img, exifOrientation(data))
+	}
+
+	a := &Avatar{
+		Thumbnails: make(map[int]Image, len(ThumbnailSizes)),
+	}
+	if a.Original, err = encode(img, contentType); err != nil {
+		return nil, err
+	}
+	for _, size := range ThumbnailSizes {
+		if a.Thumbnails[size], err = encode(thumbnail(img, size), contentType); err != nil {
+			return nil, err
+		}
+	}
+
+	return a, nil
+}
+
+// encode encodes the image. The encoders write no metadata, which strips EXIF.
+func encode(img image.Image, contentType string) (Image, error) {
+	var buf bytes.Buffer
+
+	var err error
+	if contentType == "image/png" {
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality})
+	}
+	if err != nil {
+		return Image{}, fmt.Errorf("encode image: %w", err)
+	}
+
+	return Image{
+		ContentType: contentType,
+		Data:        buf.Bytes(),
+	}, nil
+}
+
+// thumbnail crops the center square of the image and scales it to size x size.
+func thumbnail(img image.Image, size int) image.Image {
+	b := img.Bounds()
+	edge := b.Dx()
+	if b.Dy() < edge {
+		edge = b.Dy()
+	}
+	x0 := b.Min.X + (b.Dx()-edge)/2
+	y0 := b.Min.Y + (b.Dy()-edge)/2
+	crop := image.Rect(x0, y0, x0+edge, y0+edge)
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), img, crop, draw.Src, nil)
+
+	return dst
+}
+
+// KeyPrefix is the prefix of the blob keys of all avatars.
+const KeyPrefix = "avatars/"
+
+// OriginalKey returns the blob key of the full sized image of an upload.
+func OriginalKey(userID, uploadID, ext string) string {
+	return path.Join(KeyPrefix, userID, uploadID, "original."+ext)
+}
+
+// ThumbnailKey derives the blob key of a thumbnail from the key of the original.
+func ThumbnailKey(originalKey string, size int) string {
+	return path.Join(path.Dir(originalKey), strconv.Itoa(size)+path.Ext(originalKey))
+}
+
+// Keys returns the blob keys of the original and all its thumbnails.
+func Keys(originalKey string) []string {
+	keys := []string{originalKey}
+	for _, size := range ThumbnailSizes {
+		keys = append(keys, ThumbnailKey(originalKey, size))
+	}
+
+	return keys
+}
@@ -0,0 +1,139 @@
+package avatar_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/chutommy/user-microservice/pkg/avatar"
+)
+
+// testImage returns an image with a red top left corner.
+func testImage(w, h int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := color.RGBA{B: 255, A: 255}
+			if x < w/4 && y < h/4 {
+				c = color.RGBA{R: 255, A: 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+
+	return img
+}
+
+// withOrientation inserts an EXIF segment with the orientation after the SOI marker.
+func withOrientation(t *testing.T, data []byte, orientation uint16) []byte {
+	t.Helper()
+
+	var tiff bytes.Buffer
+	tiff.WriteString("MM")
+	for _, v := range []interface{}{
+		uint16(42), uint32(8), // header
+		uint16(1),                                                    // entries
+		uint16(0x0112), uint16(3), uint32(1), orientation, uint16(0), // orientation
+		uint32(0), // next IFD
+	} {
+		require.NoError(t, binary.Write(&tiff, binary.BigEndian, v))
+	}
+
+	payload := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+	seg := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(seg[2:], uint16(len(payload)+2))
+	seg = append(seg, payload...)
+
+	out := append([]byte{}, data[:2]...)
+	out = append(out, seg...)
+	return append(out, data[2:]...)
+}
+
+func TestProcess(t *testing.T) {
+	t.Parallel()
+
+	var jpg bytes.Buffer
+	require.NoError(t, jpeg.Encode(&jpg, testImage(200, 100), nil))
+	rotated := withOrientation(t, jpg.Bytes(), 6)
+
+	a, err := avatar.Process(rotated)
+	require.NoError(t, err)
+	require.Equal(t, "image/jpeg", a.Original.ContentType)
+	require.Equal(t, "jpg", a.Original.Ext())
+
+	// EXIF is stripped and the orientation applied
+	require.NotContains(t, string(a.Original.Data), "Exif")
+	img, err := jpeg.Decode(bytes.NewReader(a.Original.Data))
+	require.NoError(t, err)
+	require.Equal(t, 100, img.Bounds().Dx())
+	require.Equal(t, 200, img.Bounds().Dy())
+	// the red corner moved to the top right
+	r, _, b, _ := img.At(95, 5).RGBA()
+	require.Greater(t, r, b)
+
+	// thumbnails
+	require.Len(t, a.Thumbnails, len(avatar.ThumbnailSizes))
+	for _, size := range avatar.ThumbnailSizes {
+		thumb, err := jpeg.Decode(bytes.NewReader(a.Thumbnails[size].Data))
+		require.NoError(t, err)
+		require.Equal(t, image.Rect(0, 0, size, size), thumb.Bounds())
+	}
+}
+
+func TestProcess_PNG(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, testImage(64, 80)))
+
+	a, err := avatar.Process(buf.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, "image/png", a.Original.ContentType)
+	require.Equal(t, "png", a.Thumbnails[64].Ext())
+}
+
+func TestProcess_Invalid(t *testing.T) {
+	t.Parallel()
+
+	var gifData bytes.Buffer
+	require.NoError(t, gif.Encode(&gifData, testImage(8, 8), nil))
+
+	var huge bytes.Buffer
+	require.NoError(t, png.Encode(&huge, image.NewGray(image.Rect(0, 0, avatar.MaxDimension+1, 1))))
+
+	tests := []struct {
+		name   string
+		data   []byte
+		expErr error
+	}{
+		{"text", []byte("definitely not an image"), avatar.ErrUnsupportedType},
+		{"gif", gifData.Bytes(), avatar.ErrUnsupportedType},
+		{"truncated png", huge.Bytes()[:16], avatar.ErrInvalidImage},
+		{"too many pixels", huge.Bytes(), avatar.ErrTooLarge},
+		{"too many bytes", make([]byte, avatar.MaxSize+1), avatar.ErrTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			_, err := avatar.Process(tt.data)
+			require.ErrorIs(t, err, tt.expErr)
+		})
+	}
+}
+
+func TestKeys(t *testing.T) {
+	t.Parallel()
+
+	key := avatar.OriginalKey("user", "upload", "png")
+	require.Equal(t, "avatars/user/upload/original.png", key)
+	require.Equal(t, "avatars/user/upload/64.png", avatar.ThumbnailKey(key, 64))
+	require.Len(t, avatar.Keys(key), len(avatar.ThumbnailSizes)+1)
+}
@@ -0,0 +1,116 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	// ErrNotFound is returned if no blob is stored under the key.
+	ErrNotFound = errors.New("blob not found")
+
+	// ErrInvalidKey is returned for keys which are empty or escape the store.
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+// Store persists binary objects under slash-separated keys.
+type Store interface {
+	// Put stores the content of r under the key, replacing any previous blob.
+	Put(ctx context.Context, key string, r io.Reader) error
+	// Get opens the blob stored under the key.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the blob. Deleting a missing blob is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// FileStore is a Store which keeps blobs as files in a local directory.
+type FileStore struct {
+	root string
+}
+
+// NewFileStore constructs a FileStore rooted at dir. The directory is created if needed.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("create blob directory: %w", err)
+	}
+
+	return &FileStore{
+		root: dir,
+	}, nil
+}
+
+// path converts the key into a file path inside the root directory.
+func (s *FileStore) path(key string) (string, error) {
+	clean := path.Clean("/" + key)
+	if key == "" || strings.HasSuffix(key, "/") || clean != "/"+key {
+		return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+
+	return filepath.Join(s.root, filepath.FromSlash(clean)), nil
+}
+
+// Put implements Store. The blob is written into a temporary file first
+// so readers never see partial content.
+func (s *FileStore) Put(_ context.Context, key string, r io.Reader) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(p)
+	if err = os.MkdirAll(dir, 0o750); err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = io.Copy(tmp, r); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), p)
+}
+
+// Get implements Store.
+func (s *FileStore) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %q", ErrNotFound, key)
+	}
+
+	return f, err
+}
+
+// Delete implements Store.
+func (s *FileStore) Delete(_ context.Context, key string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err = os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package blob_test
+
+import (
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/chutommy/user-microservice/pkg/blob"
+)
+
+func TestFileStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "blob")
+	require.NoError(t, err)
+
+	store, err := blob.NewFileStore(dir)
+	require.NoError(t, err)
+
+	// put and get
+	require.NoError(t, store.Put(ctx, "a/b/c.json", strings.NewReader("first")))
+	require.NoError(t, store.Put(ctx, "a/b/c.json", strings.NewReader("second")))
+
+	rc, err := store.Get(ctx, "a/b/c.json")
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(rc)
+	require.NoError(t, err)
+	require.NoError(t, rc.Close())
+	require.Equal(t, "second", string(data))
+
+	// delete
+	require.NoError(t, store.Delete(ctx, "a/b/c.json"))
+	require.NoError(t, store.Delete(ctx, "a/b/c.json"))
+
+	_, err = store.Get(ctx, "a/b/c.json")
+	require.ErrorIs(t, err, blob.ErrNotFound)
+
+	// invalid keys
+	for _, key := range []string{"", "../escape", "a/../../b", "/abs", "dir/"} {
+		require.ErrorIs(t, store.Put(ctx, key, strings.NewReader("x")), blob.ErrInvalidKey, key)
+	}
+}
@@ -0,0 +1,237 @@
+// Package cache decorates a repo.Querier with an in-process cache of the
+// users, which are read far more often than they are written.
+package cache
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/chutommy/user-microservice/pkg/repo"
+)
+
+// entry is a cached user, or a user which does not exist.
+type entry struct {
+	user     repo.User
+	notFound bool
+	expires  time.Time
+}
+
+// Querier decorates a repo.Querier with a bounded LRU cache of GetUser. The
+// users are cached for the TTL and the missing users for the negative TTL.
+// The writes of the users through the Querier invalidate their entries.
+type Querier struct {
+	repo.Querier
+
+	users       *lru.Cache
+	ttl         time.Duration
+	negativeTTL time.Duration
+
+	// generation counts the invalidations, the users read before one are not
+	// cached as they may be stale
+	mu         sync.Mutex
+	generation uint64
+
+	notifier *notifier
+	hits     prometheus.Counter
+	misses   prometheus.Counter
+}
+
+var _ repo.Querier = (*Querier)(nil)
+
+// Option configures the Querier.
+type Option func(*Querier)
+
+// WithCounters sets the counters of the cache hits and misses.
+func WithCounters(hits, misses prometheus.Counter) Option {
+	return func(q *Querier) {
+		q.hits = hits
+		q.misses = misses
+	}
+}
+
+// NewQuerier constructs a Querier caching up to size users of the next
+// querier. Missing users are not cached if the negative TTL is 0.
+func NewQuerier(next repo.Querier, size int, ttl, negativeTTL time.Duration, opts ...Option) (*Querier, error) {
+	users, err := lru.New(size)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &Querier{
+		Querier:     next,
+		users:       users,
+		ttl:         ttl,
+		negativeTTL: negativeTTL,
+		hits:        prometheus.NewCounter(prometheus.CounterOpts{Name: "hits"}),
+		misses:      prometheus.NewCounter(prometheus.CounterOpts{Name: "misses"}),
+	}
+	for _, opt := range opts {
+		opt(q)
+	}
+
+	return q, nil
+}
+
+// lookup returns the cached entry of the user.
+func (q *Querier) lookup(id uuid.UUID) (entry, bool) {
+	v, ok := q.users.Get(id)
+	if !ok {
+		return entry{}, false
+	}
+
+	e := v.(entry)
+	if time.Now().After(e.expires) {
+		q.users.Remove(id)
+		return entry{}, false
+	}
+
+	return e, true
+}
+
+// store caches the entry unless the cache was invalidated since the
+// generation.
+func (q *Querier) store(generation uint64, id uuid.UUID, e entry) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.generation == generation {
+		q.users.Add(id, e)
+	}
+}
+
+// currentGeneration returns the count of the invalidations.
+func (q *Querier) currentGeneration() uint64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.generation
+}
+
+// Invalidate removes the users from the cache of this replica.
+func (q *Querier) Invalidate(ids ...uuid.UUID) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.generation++
+	for _, id := range ids {
+		q.users.Remove(id)
+	}
+}
+
+// Purge removes all users from the cache of this replica.
+func (q *Querier) Purge() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.generation++
+	q.users.Purge()
+}
+
+// invalidate removes the users from the caches of all replicas.
+func (q *Querier) invalidate(ctx context.Context, ids ...uuid.UUID) {
+	q.Invalidate(ids...)
+	q.notifier.notify(ctx, ids...)
+}
+
+// purge removes all users from the caches of all replicas.
+func (q *Querier) purge(ctx context.Context) {
+	q.Purge()
+	q.notifier.notify(ctx)
+}
+
+func (q *Querier) GetUser(ctx context.Context, id uuid.UUID) (repo.User, error) {
+	if e, ok := q.lookup(id); ok {
+		q.hits.Inc()
+		if e.notFound {
+			return repo.User{}, sql.ErrNoRows
+		}
+		return e.user, nil
+	}
+	q.misses.Inc()
+
+	generation := q.currentGeneration()
+	user, err := q.Querier.GetUser(ctx, id)
+	switch {
+	case err == nil:
+		q.store(generation, id, entry{user: user, expires: time.Now().Add(q.ttl)})
+	case errors.Is(err, sql.ErrNoRows) && q.negativeTTL > 0:
+		q.store(generation, id, entry{notFound: true, expires: time.Now().Add(q.negativeTTL)})
+	}
+
+	return user, err
+}
+
+func (q *Querier) CreateUser(ctx context.Context, arg repo.CreateUserParams) (repo.User, error) {
+	r, err := q.Querier.CreateUser(ctx, arg)
+	q.invalidate(ctx, arg.ID)
+
+	return r, err
+}
+
+func (q *Querier) UpdateUser(ctx context.Context, arg repo.UpdateUserParams) (repo.User, error) {
+	r, err := q.Querier.UpdateUser(ctx, arg)
+	q.invalidate(ctx, arg.ID)
+
+	return r, err
+}
+
+func (q *Querier) DeleteUser(ctx context.Context, id uuid.UUID) (int64, error) {
+	r, err := q.Querier.DeleteUser(ctx, id)
+	q.invalidate(ctx, id)
+
+	return r, err
+}
+
+func (q *Querier) EraseUser(ctx context.Context, arg repo.EraseUserParams) (repo.ErasureReceipt, error) {
+	r, err := q.Querier.EraseUser(ctx, arg)
+	q.invalidate(ctx, arg.UserID)
+
+	return r, err
+}
+
+func (q *Querier) SetUserAvatar(ctx context.Context, arg repo.SetUserAvatarParams) (sql.NullString, error) {
+	r, err := q.Querier.SetUserAvatar(ctx, arg)
+	q.invalidate(ctx, arg.ID)
+
+	return r, err
+}
+
+func (q *Querier) SetUserPhone(ctx context.Context, arg repo.SetUserPhoneParams) error {
+	err := q.Querier.SetUserPhone(ctx, arg)
+	q.invalidate(ctx, arg.ID)
+
+	return err
+}
+
+func (q *Querier) SetPrimaryEmail(ctx context.Context, arg repo.SetPrimaryEmailParams) (repo.User, error) {
+	r, err := q.Querier.SetPrimaryEmail(ctx, arg)
+	q.invalidate(ctx, arg.ID)
+
+	return r, err
+}
+
+// SetEmailCanonical updates the user of the email, which is not known, so the
+// whole cache is purged.
+func (q *Querier) SetEmailCanonical(ctx context.Context, arg repo.SetEmailCanonicalParams) error {
+	err := q.Querier.SetEmailCanonical(ctx, arg)
+	q.purge(ctx)
+
+	return err
+}
+
+// ConfirmGuardianConsent activates the minor.
+func (q *Querier) ConfirmGuardianConsent(ctx context.Context, arg repo.ConfirmGuardianConsentParams) (repo.ConfirmGuardianConsentRow, error) {
+	r, err := q.Querier.ConfirmGuardianConsent(ctx, arg)
+	if err == nil {
+		q.invalidate(ctx, r.MinorID)
+	}
+
+	return r, err
+}
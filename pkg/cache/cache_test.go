@@ -0,0 +1,304 @@
+package cache_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/chutommy/user-microservice/pkg/cache"
+	"github.com/chutommy/user-microservice/pkg/mocks"
+	"github.com/chutommy/user-microservice/pkg/repo"
+)
+
+// counter returns the value of the counter.
+func counter(t *testing.T, c prometheus.Counter) float64 {
+	t.Helper()
+
+	var m dto.Metric
+	require.NoError(t, c.Write(&m))
+
+	return m.GetCounter().GetValue()
+}
+
+func newQuerier(t *testing.T, q *mocks.Querier, ttl, negativeTTL time.Duration, opts ...cache.Option) *cache.Querier {
+	t.Helper()
+
+	cached, err := cache.NewQuerier(q, 10, ttl, negativeTTL, opts...)
+	require.NoError(t, err)
+
+	return cached
+}
+
+func TestQuerier_GetUser(t *testing.T) {
+	t.Parallel()
+
+	alice := repo.User{ID: uuid.New(), Email: "alice@example.org"}
+	missing := uuid.New()
+
+	tests := []struct {
+		name        string
+		id          uuid.UUID
+		ttl         time.Duration
+		negativeTTL time.Duration
+		err         error
+		queries     int
+	}{
+		{"cached", alice.ID, time.Minute, time.Minute, nil, 1},
+		{"expired", alice.ID, time.Nanosecond, time.Minute, nil, 2},
+		{"not found", missing, time.Minute, time.Minute, sql.ErrNoRows, 1},
+		{"negative caching disabled", missing, time.Minute, 0, sql.ErrNoRows, 2},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			q := &mocks.Querier{}
+			q.On("GetUser", mock.Anything, alice.ID).Return(alice, nil)
+			q.On("GetUser", mock.Anything, missing).Return(repo.User{}, sql.ErrNoRows)
+
+			hits := prometheus.NewCounter(prometheus.CounterOpts{Name: "hits"})
+			misses := prometheus.NewCounter(prometheus.CounterOpts{Name: "misses"})
+			cached := newQuerier(t, q, tt.ttl, tt.negativeTTL, cache.WithCounters(hits, misses))
+
+			for i := 0; i < 2; i++ {
+				user, err := cached.GetUser(context.Background(), tt.id)
+				require.ErrorIs(t, err, tt.err)
+				if err == nil {
+					require.Equal(t, alice, user)
+				}
+			}
+
+			q.AssertNumberOfCalls(t, "GetUser", tt.queries)
+			require.Equal(t, float64(2-tt.queries), counter(t, hits))
+			require.Equal(t, float64(tt.queries), counter(t, misses))
+		})
+	}
+}
+
+func TestQuerier_Invalidation(t *testing.T) {
+	t.Parallel()
+
+	alice := repo.User{ID: uuid.New(), Email: "alice@example.org"}
+
+	tests := []struct {
+		name  string
+		write func(ctx context.Context, q *cache.Querier) error
+	}{
+		{
+			name: "update",
+			write: func(ctx context.Context, q *cache.Querier) error {
+				_, err := q.UpdateUser(ctx, repo.UpdateUserParams{ID: alice.ID})
+				return err
+			},
+		},
+		{
+			name: "delete",
+			write: func(ctx context.Context, q *cache.Querier) error {
+				_, err := q.DeleteUser(ctx, alice.ID)
+				return err
+			},
+		},
+		{
+			name: "set avatar",
+			write: func(ctx context.Context, q *cache.Querier) error {
+				_, err := q.SetUserAvatar(ctx, repo.SetUserAvatarParams{ID: alice.ID})
+				return err
+			},
+		},
+		{
+			name: "confirm guardian consent",
+			write: func(ctx context.Context, q *cache.Querier) error {
+				_, err := q.ConfirmGuardianConsent(ctx, repo.ConfirmGuardianConsentParams{ID: uuid.New()})
+				return err
+			},
+		},
+		{
+			name: "set email canonical",
+			write: func(ctx context.Context, q *cache.Querier) error {
+				return q.SetEmailCanonical(ctx, repo.SetEmailCanonicalParams{Email: "a.lice@example.org"})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			q := &mocks.Querier{}
+			q.On("GetUser", mock.Anything, alice.ID).Return(alice, nil)
+			q.On("UpdateUser", mock.Anything, mock.Anything).Return(alice, nil)
+			q.On("DeleteUser", mock.Anything, alice.ID).Return(int64(1), nil)
+			q.On("SetUserAvatar", mock.Anything, mock.Anything).Return(sql.NullString{}, nil)
+			q.On("ConfirmGuardianConsent", mock.Anything, mock.Anything).
+				Return(repo.ConfirmGuardianConsentRow{MinorID: alice.ID}, nil)
+			q.On("SetEmailCanonical", mock.Anything, mock.Anything).Return(nil)
+
+			var payloads []string
+			cached := newQuerier(t, q, time.Minute, time.Minute,
+				cache.WithNotifications(execFunc(func(args ...interface{}) {
+					payloads = append(payloads, args[1].(string))
+				}), "user_cache", zap.NewNop()))
+
+			ctx := context.Background()
+			_, err := cached.GetUser(ctx, alice.ID)
+			require.NoError(t, err)
+			require.NoError(t, tt.write(ctx, cached))
+			_, err = cached.GetUser(ctx, alice.ID)
+			require.NoError(t, err)
+
+			q.AssertNumberOfCalls(t, "GetUser", 2)
+			require.Len(t, payloads, 1)
+		})
+	}
+}
+
+func TestQuerier_Invalidation_Canceled(t *testing.T) {
+	t.Parallel()
+
+	alice := repo.User{ID: uuid.New(), Email: "alice@example.org"}
+
+	// the call is canceled once the user is updated
+	ctx, cancel := context.WithCancel(context.Background())
+	q := &mocks.Querier{}
+	q.On("UpdateUser", mock.Anything, mock.Anything).
+		Run(func(mock.Arguments) { cancel() }).
+		Return(alice, nil)
+
+	var errs []error
+	cached := newQuerier(t, q, time.Minute, time.Minute,
+		cache.WithNotifications(ctxExecFunc(func(ctx context.Context) {
+			errs = append(errs, ctx.Err())
+		}), "user_cache", zap.NewNop()))
+
+	_, err := cached.UpdateUser(ctx, repo.UpdateUserParams{ID: alice.ID})
+	require.NoError(t, err)
+
+	require.Len(t, errs, 1)
+	require.NoError(t, errs[0])
+}
+
+func TestQuerier_GetUser_Stale(t *testing.T) {
+	t.Parallel()
+
+	alice := repo.User{ID: uuid.New(), Email: "alice@example.org"}
+
+	q := &mocks.Querier{}
+	cached := newQuerier(t, q, time.Minute, time.Minute)
+
+	// the user is updated while it is read
+	q.On("GetUser", mock.Anything, alice.ID).
+		Run(func(mock.Arguments) { cached.Invalidate(alice.ID) }).
+		Return(alice, nil).Once()
+	q.On("GetUser", mock.Anything, alice.ID).Return(alice, nil)
+
+	for i := 0; i < 3; i++ {
+		_, err := cached.GetUser(context.Background(), alice.ID)
+		require.NoError(t, err)
+	}
+
+	q.AssertNumberOfCalls(t, "GetUser", 2)
+}
+
+func TestQuerier_Run(t *testing.T) {
+	t.Parallel()
+
+	alice := repo.User{ID: uuid.New(), Email: "alice@example.org"}
+	bob := repo.User{ID: uuid.New(), Email: "bob@example.org"}
+
+	tests := []struct {
+		name         string
+		notification *pq.Notification
+		queries      int
+	}{
+		{"user", &pq.Notification{Extra: alice.ID.String()}, 3},
+		{"users", &pq.Notification{Extra: alice.ID.String() + "," + bob.ID.String()}, 4},
+		{"other user", &pq.Notification{Extra: uuid.New().String()}, 2},
+		{"purge", &pq.Notification{Extra: "*"}, 4},
+		{"reconnected", nil, 4},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			q := &mocks.Querier{}
+			q.On("GetUser", mock.Anything, alice.ID).Return(alice, nil)
+			q.On("GetUser", mock.Anything, bob.ID).Return(bob, nil)
+			cached := newQuerier(t, q, time.Minute, time.Minute)
+
+			read := func() {
+				for _, id := range []uuid.UUID{alice.ID, bob.ID} {
+					_, err := cached.GetUser(context.Background(), id)
+					require.NoError(t, err)
+				}
+			}
+			read()
+
+			notifications := make(chan *pq.Notification, 1)
+			notifications <- tt.notification
+			close(notifications)
+
+			// returns once the channel is drained
+			cached.Run(context.Background(), notifications)
+
+			read()
+			q.AssertNumberOfCalls(t, "GetUser", tt.queries)
+		})
+	}
+}
+
+func TestQuerier_Listen(t *testing.T) {
+	t.Parallel()
+
+	cached := newQuerier(t, &mocks.Querier{}, time.Minute, time.Minute,
+		cache.WithNotifications(execFunc(func(...interface{}) {}), "user_cache", zap.NewNop()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	// the database is unreachable, the listener keeps retrying until the
+	// context is done
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cached.Listen(ctx, "postgres://127.0.0.1:1/users?sslmode=disable&connect_timeout=1")
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Listen did not return after the context was done")
+	}
+}
+
+// execFunc records the arguments of the executed queries.
+type execFunc func(args ...interface{})
+
+func (f execFunc) ExecContext(_ context.Context, _ string, args ...interface{}) (sql.Result, error) {
+	f(args...)
+	return nil, nil
+}
+
+// ctxExecFunc records the contexts of the executed queries.
+type ctxExecFunc func(ctx context.Context)
+
+func (f ctxExecFunc) ExecContext(ctx context.Context, _ string, _ ...interface{}) (sql.Result, error) {
+	f(ctx)
+	return nil, nil
+}
@@ -0,0 +1,197 @@
+package cache
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"github.com/chutommy/user-microservice/pkg/util"
+)
+
+// purgeAll is the notification payload purging the whole cache.
+const purgeAll = "*"
+
+const (
+	// minReconnectInterval and maxReconnectInterval bound the backoff of the
+	// reconnection of the listener.
+	minReconnectInterval = 100 * time.Millisecond
+	maxReconnectInterval = time.Minute
+
+	// pingInterval is the interval of the checks of an idle listener.
+	pingInterval = 90 * time.Second
+
+	// notifyTimeout bounds the sending of a notification.
+	notifyTimeout = 5 * time.Second
+)
+
+// Execer executes a query without returning any rows.
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// notifier tells the other replicas to invalidate their caches.
+type notifier struct {
+	db      Execer
+	channel string
+	logger  *zap.Logger
+}
+
+// WithNotifications sends the invalidations to the other replicas as the
+// notifications of the Postgres channel, see Listen.
+func WithNotifications(db Execer, channel string, logger *zap.Logger) Option {
+	return func(q *Querier) {
+		q.notifier = &notifier{
+			db:      db,
+			channel: channel,
+			logger:  logger,
+		}
+	}
+}
+
+// notify sends the IDs of the invalidated users, all users are invalidated
+// if none is given. A nil notifier sends nothing.
+func (n *notifier) notify(ctx context.Context, ids ...uuid.UUID) {
+	if n == nil {
+		return
+	}
+
+	payload := purgeAll
+	if len(ids) > 0 {
+		s := make([]string, len(ids))
+		for i, id := range ids {
+			s[i] = id.String()
+		}
+		payload = strings.Join(s, ",")
+	}
+
+	// the change is committed, so the notification is sent even if the
+	// call is canceled; the entries of the other replicas expire with the
+	// TTL anyway
+	ctx, cancel := context.WithTimeout(util.WithoutCancel(ctx), notifyTimeout)
+	defer cancel()
+
+	if _, err := n.db.ExecContext(ctx, "select pg_notify($1, $2)", n.channel, payload); err != nil {
+		n.logger.Warn("failed to notify the cache invalidation", zap.String("payload", payload), zap.Error(err))
+	}
+}
+
+// Run invalidates the users of the notifications until the context is done
+// or the channel is closed. A nil notification, sent by a reconnected
+// listener, purges the cache as the notifications may have been missed.
+func (q *Querier) Run(ctx context.Context, notifications <-chan *pq.Notification) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case n, ok := <-notifications:
+			if !ok {
+				return
+			}
+			q.handle(n)
+		}
+	}
+}
+
+// handle invalidates the users of the notification.
+func (q *Querier) handle(n *pq.Notification) {
+	if n == nil || n.Extra == purgeAll {
+		q.Purge()
+		return
+	}
+
+	var ids []uuid.UUID
+	for _, s := range strings.Split(n.Extra, ",") {
+		if id, err := uuid.Parse(s); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	q.Invalidate(ids...)
+}
+
+// Listen listens to the invalidations of the other replicas on the channel
+// of WithNotifications until the context is done. A failed listener is
+// created again with an exponential backoff, the cache is purged as the
+// notifications may have been missed meanwhile.
+func (q *Querier) Listen(ctx context.Context, dsn string) {
+	n := q.notifier
+	if n == nil {
+		return
+	}
+
+	backoff := minReconnectInterval
+	for {
+		err := q.listen(ctx, dsn)
+		if ctx.Err() != nil {
+			return
+		}
+
+		n.logger.Warn("failed to listen to the cache invalidations", zap.Duration("retry_in", backoff), zap.Error(err))
+		q.Purge()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > maxReconnectInterval {
+			backoff = maxReconnectInterval
+		}
+	}
+}
+
+// listen runs a listener of the channel until the context is done. It
+// returns the error of the LISTEN command.
+func (q *Querier) listen(ctx context.Context, dsn string) error {
+	n := q.notifier
+
+	l := pq.NewListener(dsn, minReconnectInterval, maxReconnectInterval, func(event pq.ListenerEventType, err error) {
+		switch {
+		case err != nil:
+			n.logger.Warn("cache invalidation listener failed", zap.Error(err))
+		case event == pq.ListenerEventReconnected:
+			n.logger.Info("cache invalidation listener reconnected")
+		}
+	})
+
+	// closing the listener stops a LISTEN waiting for the connection
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+		_ = l.Close()
+	}()
+
+	if err := l.Listen(n.channel); err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(pingInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-done:
+				return
+			case <-ticker.C:
+				// a failed ping makes the listener reconnect
+				_ = l.Ping()
+			}
+		}
+	}()
+
+	q.Run(ctx, l.Notify)
+	return nil
+}
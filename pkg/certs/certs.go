@@ -0,0 +1,252 @@
+// Package certs provides the TLS configuration of the servers with the
+// certificates reloaded on rotation, and the identity of the callers
+// authenticated by their client certificates.
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ErrNoCertificates is returned if the CA bundle contains no certificate.
+var ErrNoCertificates = errors.New("no certificates in the CA bundle")
+
+// Reloader holds the certificate of the server and the CA bundle verifying
+// the client certificates, and reloads them when the files change.
+type Reloader struct {
+	certFile string
+	keyFile  string
+	caFile   string
+	gateway  string
+	logger   *zap.Logger
+
+	mu     sync.RWMutex
+	cert   *tls.Certificate
+	leaf   *x509.Certificate
+	pool   *x509.CertPool
+	stamps map[string]time.Time
+}
+
+// Option configures the Reloader.
+type Option func(*Reloader)
+
+// WithGatewayIdentity sets the identity of the client certificate of the
+// gateway, whose forwarded caller identities are trusted. The gateway presents
+// the certificate of the server by default.
+func WithGatewayIdentity(identity string) Option {
+	return func(r *Reloader) {
+		r.gateway = identity
+	}
+}
+
+// NewReloader loads the certificate, the key and the CA bundle. The client
+// certificates are required and verified only if the CA bundle is set.
+func NewReloader(certFile, keyFile, caFile string, logger *zap.Logger, opts ...Option) (*Reloader, error) {
+	r := &Reloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		caFile:   caFile,
+		logger:   logger,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if _, err := r.Reload(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// files lists the files watched for changes.
+func (r *Reloader) files() []string {
+	files := []string{r.certFile, r.keyFile}
+	if r.caFile != "" {
+		files = append(files, r.caFile)
+	}
+
+	return files
+}
+
+// Reload loads the files again if any of them was modified since the last
+// load and reports whether the certificates were replaced. The current
+// certificates are kept if the new ones are invalid.
+func (r *Reloader) Reload() (bool, error) {
+	stamps := make(map[string]time.Time)
+	for _, name := range r.files() {
+		fi, err := os.Stat(name)
+		if err != nil {
+			return false, err
+		}
+		stamps[name] = fi.ModTime()
+	}
+
+	r.mu.RLock()
+	changed := r.stamps == nil
+	for name, t := range stamps {
+		if !r.stamps[name].Equal(t) {
+			changed = true
+		}
+	}
+	r.mu.RUnlock()
+	if !changed {
+		return false, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return false, fmt.Errorf("failed to load the certificate: %w", err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return false, fmt.Errorf("failed to parse the certificate: %w", err)
+	}
+	cert.Leaf = leaf
+
+	var pool *x509.CertPool
+	if r.caFile != "" {
+		pem, err := ioutil.ReadFile(r.caFile)
+		if err != nil {
+			return false, fmt.Errorf("failed to read the CA bundle: %w", err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return false, fmt.Errorf("%w: %s", ErrNoCertificates, r.caFile)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cert = &cert
+	r.leaf = leaf
+	r.pool = pool
+	r.stamps = stamps
+
+	return true, nil
+}
+
+// Run checks the files for changes every interval until the context is done.
+func (r *Reloader) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		reloaded, err := r.Reload()
+		switch {
+		case err != nil:
+			r.logger.Error("failed to reload the certificates, keeping the current ones", zap.Error(err))
+		case reloaded:
+			r.logger.Info("certificates reloaded", zap.Time("not_after", r.Leaf().NotAfter))
+		}
+	}
+}
+
+// Leaf returns the current certificate of the server.
+func (r *Reloader) Leaf() *x509.Certificate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.leaf
+}
+
+// MutualTLS reports whether the client certificates are required.
+func (r *Reloader) MutualTLS() bool {
+	return r.caFile != ""
+}
+
+// isGateway reports whether the certificate is the client certificate of the
+// gateway. The identity is compared, so the gateway is recognized with any
+// certificate issued to it, also across the rotations.
+func (r *Reloader) isGateway(cert *x509.Certificate) bool {
+	if r.gateway != "" {
+		return Identity(cert) == r.gateway
+	}
+
+	leaf := r.Leaf()
+	return leaf != nil && Identity(cert) == Identity(leaf)
+}
+
+// ServerConfig returns the TLS configuration of the servers. Each handshake
+// uses the current certificates.
+func (r *Reloader) ServerConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+
+			return r.cert, nil
+		},
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+
+			cfg := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*r.cert},
+				NextProtos:   []string{"h2", "http/1.1"},
+			}
+			if r.pool != nil {
+				cfg.ClientAuth = tls.RequireAndVerifyClientCert
+				cfg.ClientCAs = r.pool
+			}
+
+			return cfg, nil
+		},
+	}
+}
+
+// ClientConfig returns the TLS configuration of the gateway connecting to the
+// gRPC server under the name. The server is verified against the CA bundle,
+// or against the system roots if there is none, and the gateway presents the
+// certificate of the server as its client certificate.
+func (r *Reloader) ClientConfig(serverName string) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: serverName,
+		// the server is verified against the current CA bundle by VerifyConnection
+		InsecureSkipVerify: true, //nolint:gosec
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			if len(cs.PeerCertificates) == 0 {
+				return errors.New("no server certificate")
+			}
+
+			r.mu.RLock()
+			opts := x509.VerifyOptions{
+				DNSName:       serverName,
+				Roots:         r.pool,
+				Intermediates: x509.NewCertPool(),
+			}
+			r.mu.RUnlock()
+			for _, c := range cs.PeerCertificates[1:] {
+				opts.Intermediates.AddCert(c)
+			}
+
+			_, err := cs.PeerCertificates[0].Verify(opts)
+			return err
+		},
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+
+			return r.cert, nil
+		},
+	}
+}
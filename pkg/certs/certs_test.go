@@ -0,0 +1,256 @@
+package certs_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/chutommy/user-microservice/pkg/certs"
+)
+
+// authority issues the certificates of the tests.
+type authority struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newAuthority(t *testing.T) *authority {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &authority{
+		cert: cert,
+		key:  key,
+		pem:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+// issue returns the PEM certificate and key of a leaf certificate.
+func (a *authority) issue(t *testing.T, serial int64, modify func(*x509.Certificate)) (certPEM, keyPEM []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	modify(tmpl)
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, a.cert, &key.PublicKey, a.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func server(c *x509.Certificate) {
+	c.DNSNames = []string{"localhost"}
+}
+
+func client(c *x509.Certificate) {
+	c.URIs = []*url.URL{{Scheme: "spiffe", Host: "example.org", Path: "/billing"}}
+}
+
+// files writes the certificate, the key and the CA bundle of a server.
+type files struct {
+	cert, key, ca string
+}
+
+func writeFiles(t *testing.T, ca *authority, serial int64) files {
+	t.Helper()
+
+	dir := t.TempDir()
+	f := files{
+		cert: filepath.Join(dir, "server.pem"),
+		key:  filepath.Join(dir, "server-key.pem"),
+		ca:   filepath.Join(dir, "ca.pem"),
+	}
+	f.rotate(t, ca, serial)
+	require.NoError(t, ioutil.WriteFile(f.ca, ca.pem, 0o600))
+
+	return f
+}
+
+// rotate replaces the server certificate, with a later modification time.
+func (f files) rotate(t *testing.T, ca *authority, serial int64) {
+	t.Helper()
+
+	certPEM, keyPEM := ca.issue(t, serial, server)
+	require.NoError(t, ioutil.WriteFile(f.cert, certPEM, 0o600))
+	require.NoError(t, ioutil.WriteFile(f.key, keyPEM, 0o600))
+
+	later := time.Now().Add(time.Duration(serial) * time.Second)
+	require.NoError(t, os.Chtimes(f.cert, later, later))
+	require.NoError(t, os.Chtimes(f.key, later, later))
+}
+
+// handshake connects the client to the server over a pipe and returns the
+// state of the server side.
+func handshake(serverCfg, clientCfg *tls.Config) (tls.ConnectionState, error) {
+	sc, cc := net.Pipe()
+	defer sc.Close()
+	defer cc.Close()
+
+	errc := make(chan error, 1)
+	go func() {
+		c := tls.Client(cc, clientCfg)
+		err := c.Handshake()
+		if err == nil {
+			// the server verifies the client certificate after the client handshake
+			_, err = c.Read(make([]byte, 1))
+		}
+		errc <- err
+	}()
+
+	s := tls.Server(sc, serverCfg)
+	if err := s.Handshake(); err != nil {
+		return tls.ConnectionState{}, err
+	}
+	_, _ = s.Write([]byte{0})
+
+	return s.ConnectionState(), <-errc
+}
+
+func TestReloader_MutualTLS(t *testing.T) {
+	t.Parallel()
+
+	ca := newAuthority(t)
+	f := writeFiles(t, ca, 2)
+	r, err := certs.NewReloader(f.cert, f.key, f.ca, zap.NewNop())
+	require.NoError(t, err)
+	require.True(t, r.MutualTLS())
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	clientPEM, clientKey := ca.issue(t, 3, client)
+	clientCert, err := tls.X509KeyPair(clientPEM, clientKey)
+	require.NoError(t, err)
+
+	t.Run("client certificate", func(t *testing.T) {
+		t.Parallel()
+
+		state, err := handshake(r.ServerConfig(), &tls.Config{
+			ServerName:   "localhost",
+			RootCAs:      roots,
+			Certificates: []tls.Certificate{clientCert},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "spiffe://example.org/billing", certs.Identity(state.VerifiedChains[0][0]))
+	})
+
+	t.Run("no client certificate", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := handshake(r.ServerConfig(), &tls.Config{
+			ServerName: "localhost",
+			RootCAs:    roots,
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("gateway", func(t *testing.T) {
+		t.Parallel()
+
+		state, err := handshake(r.ServerConfig(), r.ClientConfig("localhost"))
+		require.NoError(t, err)
+		require.Equal(t, "localhost", certs.Identity(state.VerifiedChains[0][0]))
+	})
+
+	t.Run("gateway with wrong server name", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := handshake(r.ServerConfig(), r.ClientConfig("users.internal"))
+		require.Error(t, err)
+	})
+}
+
+func TestReloader_Reload(t *testing.T) {
+	t.Parallel()
+
+	ca := newAuthority(t)
+	f := writeFiles(t, ca, 2)
+	r, err := certs.NewReloader(f.cert, f.key, "", zap.NewNop())
+	require.NoError(t, err)
+	require.False(t, r.MutualTLS())
+	require.Equal(t, int64(2), r.Leaf().SerialNumber.Int64())
+
+	// unchanged
+	reloaded, err := r.Reload()
+	require.NoError(t, err)
+	require.False(t, reloaded)
+
+	// rotated
+	f.rotate(t, ca, 3)
+	reloaded, err = r.Reload()
+	require.NoError(t, err)
+	require.True(t, reloaded)
+	require.Equal(t, int64(3), r.Leaf().SerialNumber.Int64())
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	cs, err := handshake(r.ServerConfig(), &tls.Config{ServerName: "localhost", RootCAs: roots})
+	require.NoError(t, err)
+	require.Empty(t, cs.PeerCertificates)
+
+	// broken, the current certificate is kept
+	require.NoError(t, ioutil.WriteFile(f.key, []byte("garbage"), 0o600))
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(f.key, later, later))
+	_, err = r.Reload()
+	require.Error(t, err)
+	require.Equal(t, int64(3), r.Leaf().SerialNumber.Int64())
+}
+
+func TestNewReloader_Errors(t *testing.T) {
+	t.Parallel()
+
+	ca := newAuthority(t)
+	f := writeFiles(t, ca, 2)
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+	require.NoError(t, ioutil.WriteFile(empty, nil, 0o600))
+
+	_, err := certs.NewReloader(f.cert, f.key, empty, zap.NewNop())
+	require.ErrorIs(t, err, certs.ErrNoCertificates)
+
+	_, err = certs.NewReloader(f.cert, filepath.Join(t.TempDir(), "missing.pem"), "", zap.NewNop())
+	require.Error(t, err)
+}
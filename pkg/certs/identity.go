@@ -0,0 +1,156 @@
+package certs
+
+import (
+	"context"
+	"crypto/x509"
+	"net/http"
+	"net/textproto"
+
+	gmdw "github.com/grpc-ecosystem/go-grpc-middleware"
+	gtags "github.com/grpc-ecosystem/go-grpc-middleware/tags"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// CallerMetadataKey is the metadata key by which the gateway forwards the
+// identity of its HTTP clients to the gRPC server.
+const CallerMetadataKey = "x-caller-identity"
+
+// CallerField is the log field of the caller identity.
+const CallerField = "grpc.caller"
+
+type callerKey struct{}
+
+// Identity returns the identity of the certificate's owner: the first URI
+// SAN (e.g. a SPIFFE ID), the first DNS SAN, the first email SAN, or the
+// common name, in this order.
+func Identity(cert *x509.Certificate) string {
+	switch {
+	case len(cert.URIs) > 0:
+		return cert.URIs[0].String()
+	case len(cert.DNSNames) > 0:
+		return cert.DNSNames[0]
+	case len(cert.EmailAddresses) > 0:
+		return cert.EmailAddresses[0]
+	}
+
+	return cert.Subject.CommonName
+}
+
+// WithCaller returns a copy of the context carrying the caller identity.
+func WithCaller(ctx context.Context, caller string) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+// CallerFromContext returns the identity of the authenticated caller.
+func CallerFromContext(ctx context.Context) (string, bool) {
+	caller, ok := ctx.Value(callerKey{}).(string)
+	return caller, ok
+}
+
+// caller returns the identity of the verified client certificate of the call.
+// The identity forwarded by the gateway is trusted only if the peer is the
+// gateway, see WithGatewayIdentity. The calls of the gateway without a single
+// forwarded identity authenticate no caller, the gateway does not call on its
+// own behalf. A nil Reloader, i.e. a server without TLS, authenticates no
+// caller.
+func (r *Reloader) caller(ctx context.Context) (string, bool) {
+	if r == nil {
+		return "", false
+	}
+
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "", false
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return "", false
+	}
+	cert := info.State.VerifiedChains[0][0]
+
+	if r.isGateway(cert) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if forwarded := md.Get(CallerMetadataKey); len(forwarded) == 1 {
+			return forwarded[0], true
+		}
+		return "", false
+	}
+
+	return Identity(cert), true
+}
+
+// authenticate stores the caller identity in the context and in the log tags.
+func (r *Reloader) authenticate(ctx context.Context) context.Context {
+	caller, ok := r.caller(ctx)
+	if !ok {
+		return ctx
+	}
+
+	gtags.Extract(ctx).Set(CallerField, caller)
+	return WithCaller(ctx, caller)
+}
+
+// UnaryServerInterceptor stores the identity of the caller in the context of
+// the unary calls, see CallerFromContext. It must be chained after the tags
+// interceptor.
+func (r *Reloader) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(r.authenticate(ctx), req)
+	}
+}
+
+// StreamServerInterceptor stores the identity of the caller in the context of
+// the streaming calls, see UnaryServerInterceptor.
+func (r *Reloader) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		wrapped := gmdw.WrapServerStream(ss)
+		wrapped.WrappedContext = r.authenticate(ss.Context())
+
+		return handler(srv, wrapped)
+	}
+}
+
+// clientIdentity returns the identity of the verified client certificate of the HTTP request.
+func clientIdentity(r *http.Request) (string, bool) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return "", false
+	}
+
+	return Identity(r.TLS.VerifiedChains[0][0]), true
+}
+
+// GatewayMetadata is a runtime.WithMetadata option forwarding the identity of
+// the HTTP client to the gRPC server.
+func GatewayMetadata(_ context.Context, r *http.Request) metadata.MD {
+	caller, ok := clientIdentity(r)
+	if !ok {
+		return nil
+	}
+
+	return metadata.Pairs(CallerMetadataKey, caller)
+}
+
+// ForwardCaller forwards the identity of the HTTP client of a custom gateway
+// route calling the gRPC server.
+func ForwardCaller(h runtime.HandlerFunc) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		if caller, ok := clientIdentity(r); ok {
+			r = r.WithContext(metadata.AppendToOutgoingContext(r.Context(), CallerMetadataKey, caller))
+		}
+		h(w, r, params)
+	}
+}
+
+// HeaderMatcher is a runtime.WithIncomingHeaderMatcher option which prevents
+// the HTTP clients from setting the forwarded identity themselves.
+func HeaderMatcher(key string) (string, bool) {
+	if textproto.CanonicalMIMEHeaderKey(key) == textproto.CanonicalMIMEHeaderKey(runtime.MetadataHeaderPrefix+CallerMetadataKey) {
+		return "", false
+	}
+
+	return runtime.DefaultHeaderMatcher(key)
+}
@@ -0,0 +1,233 @@
+package certs_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	gtags "github.com/grpc-ecosystem/go-grpc-middleware/tags"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"github.com/chutommy/user-microservice/pkg/certs"
+)
+
+func TestIdentity(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cert *x509.Certificate
+		want string
+	}{
+		{
+			name: "URI",
+			cert: &x509.Certificate{
+				URIs:     []*url.URL{{Scheme: "spiffe", Host: "example.org", Path: "/billing"}},
+				DNSNames: []string{"billing.internal"},
+			},
+			want: "spiffe://example.org/billing",
+		},
+		{
+			name: "DNS",
+			cert: &x509.Certificate{
+				DNSNames:       []string{"billing.internal", "billing"},
+				EmailAddresses: []string{"billing@example.org"},
+			},
+			want: "billing.internal",
+		},
+		{
+			name: "email",
+			cert: &x509.Certificate{EmailAddresses: []string{"billing@example.org"}},
+			want: "billing@example.org",
+		},
+		{
+			name: "common name",
+			cert: &x509.Certificate{Subject: pkix.Name{CommonName: "billing"}},
+			want: "billing",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, certs.Identity(tt.cert))
+		})
+	}
+}
+
+func parse(t *testing.T, certPEM []byte) *x509.Certificate {
+	t.Helper()
+
+	block, _ := pem.Decode(certPEM)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+
+	return cert
+}
+
+func TestReloader_UnaryServerInterceptor(t *testing.T) {
+	t.Parallel()
+
+	ca := newAuthority(t)
+	f := writeFiles(t, ca, 2)
+	r, err := certs.NewReloader(f.cert, f.key, f.ca, zap.NewNop())
+	require.NoError(t, err)
+
+	pinned, err := certs.NewReloader(f.cert, f.key, f.ca, zap.NewNop(),
+		certs.WithGatewayIdentity("spiffe://example.org/billing"))
+	require.NoError(t, err)
+
+	clientPEM, _ := ca.issue(t, 3, client)
+	billing := parse(t, clientPEM)
+	self := r.Leaf()
+	// the certificate of the gateway which reloaded a rotated one first
+	rotatedPEM, _ := ca.issue(t, 4, server)
+	rotated := parse(t, rotatedPEM)
+
+	tlsPeer := func(cert *x509.Certificate) *peer.Peer {
+		return &peer.Peer{AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			VerifiedChains: [][]*x509.Certificate{{cert, ca.cert}},
+		}}}
+	}
+
+	tests := []struct {
+		name      string
+		reloader  *certs.Reloader
+		peer      *peer.Peer
+		forwarded []string
+		caller    string
+	}{
+		{
+			name:     "client",
+			reloader: r,
+			peer:     tlsPeer(billing),
+			caller:   "spiffe://example.org/billing",
+		},
+		{
+			name:      "client forging the identity",
+			reloader:  r,
+			peer:      tlsPeer(billing),
+			forwarded: []string{"spiffe://example.org/admin"},
+			caller:    "spiffe://example.org/billing",
+		},
+		{
+			name:      "gateway",
+			reloader:  r,
+			peer:      tlsPeer(self),
+			forwarded: []string{"spiffe://example.org/billing"},
+			caller:    "spiffe://example.org/billing",
+		},
+		{
+			name:     "gateway without a client",
+			reloader: r,
+			peer:     tlsPeer(self),
+		},
+		{
+			name:      "gateway with ambiguous clients",
+			reloader:  r,
+			peer:      tlsPeer(self),
+			forwarded: []string{"spiffe://example.org/billing", "spiffe://example.org/admin"},
+		},
+		{
+			name:      "gateway with a rotated certificate",
+			reloader:  r,
+			peer:      tlsPeer(rotated),
+			forwarded: []string{"spiffe://example.org/billing"},
+			caller:    "spiffe://example.org/billing",
+		},
+		{
+			name:      "pinned gateway",
+			reloader:  pinned,
+			peer:      tlsPeer(billing),
+			forwarded: []string{"spiffe://example.org/admin"},
+			caller:    "spiffe://example.org/admin",
+		},
+		{
+			name:      "server with a pinned gateway",
+			reloader:  pinned,
+			peer:      tlsPeer(self),
+			forwarded: []string{"spiffe://example.org/billing"},
+			caller:    "localhost",
+		},
+		{
+			name:     "plaintext",
+			reloader: r,
+			peer:     &peer.Peer{},
+		},
+		{
+			name: "no TLS",
+			peer: tlsPeer(billing),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := gtags.SetInContext(context.Background(), gtags.NewTags())
+			ctx = peer.NewContext(ctx, tt.peer)
+			md := metadata.MD{}
+			for _, f := range tt.forwarded {
+				md.Append(certs.CallerMetadataKey, f)
+			}
+			ctx = metadata.NewIncomingContext(ctx, md)
+
+			var caller string
+			var ok bool
+			_, err := tt.reloader.UnaryServerInterceptor()(ctx, nil, &grpc.UnaryServerInfo{},
+				func(ctx context.Context, _ interface{}) (interface{}, error) {
+					caller, ok = certs.CallerFromContext(ctx)
+					return nil, nil
+				})
+			require.NoError(t, err)
+
+			require.Equal(t, tt.caller, caller)
+			require.Equal(t, tt.caller != "", ok)
+			if ok {
+				require.Equal(t, tt.caller, gtags.Extract(ctx).Values()[certs.CallerField])
+			}
+		})
+	}
+}
+
+func TestGatewayMetadata(t *testing.T) {
+	t.Parallel()
+
+	ca := newAuthority(t)
+	clientPEM, _ := ca.issue(t, 3, client)
+
+	req := httptest.NewRequest("GET", "/v1/user/1", nil)
+	require.Nil(t, certs.GatewayMetadata(context.Background(), req))
+
+	req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{parse(t, clientPEM), ca.cert}}}
+	md := certs.GatewayMetadata(context.Background(), req)
+	require.Equal(t, []string{"spiffe://example.org/billing"}, md.Get(certs.CallerMetadataKey))
+}
+
+func TestHeaderMatcher(t *testing.T) {
+	t.Parallel()
+
+	_, ok := certs.HeaderMatcher("Grpc-Metadata-X-Caller-Identity")
+	require.False(t, ok)
+	_, ok = certs.HeaderMatcher("grpc-metadata-x-caller-identity")
+	require.False(t, ok)
+
+	key, ok := certs.HeaderMatcher("Grpc-Metadata-X-Request-Id")
+	require.True(t, ok)
+	require.Equal(t, "X-Request-Id", key)
+}
@@ -0,0 +1,324 @@
+// Package config defines the configuration of the user service and loads it
+// from a YAML or TOML file, the environment and the command line flags.
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chutommy/user-microservice/pkg/age"
+	"github.com/chutommy/user-microservice/pkg/phone"
+	"github.com/chutommy/user-microservice/pkg/tracing"
+)
+
+// mask replaces the values of the secrets in the printed configuration.
+const mask = "********"
+
+// Secret is a configuration value which is never printed, e.g. a password.
+type Secret string
+
+// String masks the secret.
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+
+	return mask
+}
+
+// MarshalYAML masks the secret.
+func (s Secret) MarshalYAML() (interface{}, error) {
+	return s.String(), nil
+}
+
+// Config is the configuration of the user service. Every field has a key in
+// the config file, an environment variable and a command line flag, see Load.
+type Config struct {
+	Debug bool `yaml:"debug" flag:"debug" usage:"enable development level logging"`
+
+	Server      Server      `yaml:"server"`
+	TLS         TLS         `yaml:"tls"`
+	Database    Database    `yaml:"database"`
+	Storage     Storage     `yaml:"storage"`
+	Exports     Exports     `yaml:"exports"`
+	Idempotency Idempotency `yaml:"idempotency"`
+	Cache       Cache       `yaml:"cache"`
+	Profile     Profile     `yaml:"profile"`
+	Tracing     Tracing     `yaml:"tracing"`
+}
+
+// Server configures the listeners.
+type Server struct {
+	HTTPPort       string        `yaml:"http_port" flag:"http-port" usage:"HTTP listen address port"`
+	GRPCPort       string        `yaml:"grpc_port" flag:"grpc-port" usage:"gRPC listen address port"`
+	AdminPort      string        `yaml:"admin_port" flag:"admin-port" usage:"admin HTTP listen address port serving the metrics and the health probes"`
+	HealthInterval time.Duration `yaml:"health_interval" flag:"health-interval" usage:"interval of the database health checks"`
+}
+
+// TLS configures the TLS of the gRPC server and the gateway, both serve
+// plaintext if no certificate is set.
+type TLS struct {
+	CertFile        string        `yaml:"cert_file" flag:"tls-cert" usage:"PEM certificate file of the servers, enables TLS"`
+	KeyFile         string        `yaml:"key_file" flag:"tls-key" usage:"PEM private key file of the certificate"`
+	ClientCAFile    string        `yaml:"client_ca_file" flag:"tls-client-ca" usage:"PEM bundle of the CAs verifying the required client certificates, enables mutual TLS"`
+	ServerName      string        `yaml:"server_name" flag:"tls-server-name" usage:"name of the gRPC server verified by the gateway"`
+	ReloadInterval  time.Duration `yaml:"reload_interval" flag:"tls-reload-interval" usage:"interval of the checks for rotated certificates"`
+	AdminIdentities []string      `yaml:"admin_identities" flag:"tls-admin-identities" usage:"comma separated identities of the client certificates which may add verified emails"`
+	GatewayIdentity string        `yaml:"gateway_identity" flag:"tls-gateway-identity" usage:"identity of the client certificate of the gateway whose forwarded caller identities are trusted, the identity of the server certificate if empty"`
+}
+
+// Enabled reports whether the servers use TLS.
+func (t TLS) Enabled() bool {
+	return t.CertFile != ""
+}
+
+// Database configures the connection to Postgres.
+type Database struct {
+	URL                    Secret        `yaml:"url" flag:"db_url" usage:"database URL of the user service"`
+	EventsChannel          string        `yaml:"events_channel" flag:"events-channel" usage:"Postgres notification channel of the domain events"`
+	EventsRetryInterval    time.Duration `yaml:"events_retry_interval" flag:"events-retry-interval" usage:"interval of the republishing of the erasure events which failed to be delivered"`
+	ConnectTimeout         time.Duration `yaml:"connect_timeout" flag:"db-connect-timeout" usage:"total time of the connection attempts to the database at the start"`
+	MaxOpenConns           int           `yaml:"max_open_conns" flag:"db-max-open-conns" usage:"maximum number of open connections to the database, unlimited if 0"`
+	MaxIdleConns           int           `yaml:"max_idle_conns" flag:"db-max-idle-conns" usage:"maximum number of idle connections to the database"`
+	ConnMaxLifetime        time.Duration `yaml:"conn_max_lifetime" flag:"db-conn-max-lifetime" usage:"maximum time a connection to the database may be reused, unlimited if 0"`
+	ConnMaxIdleTime        time.Duration `yaml:"conn_max_idle_time" flag:"db-conn-max-idle-time" usage:"maximum time a connection to the database may be idle, unlimited if 0"`
+	AutoMigrate            bool          `yaml:"auto_migrate" flag:"db-auto-migrate" usage:"apply the pending schema migrations on start, one replica at a time"`
+	MigrateTimeout         time.Duration `yaml:"migrate_timeout" flag:"db-migrate-timeout" usage:"total time of the schema migrations on start, including the wait for other replicas"`
+	AllowUncanonicalEmails bool          `yaml:"allow_uncanonical_emails" flag:"db-allow-uncanonical-emails" usage:"start even if some email addresses have no canonical form, they are not found by email until cmd/emailmigrate stores it"`
+}
+
+// Storage configures the blob store of the data exports and the avatars.
+type Storage struct {
+	BlobDir       string `yaml:"blob_dir" flag:"blob-dir" usage:"directory of the local blob store"`
+	AvatarBaseURL string `yaml:"avatar_base_url" flag:"avatar-base-url" usage:"URL prefix of the avatar links"`
+}
+
+// Exports configures the personal data exports.
+type Exports struct {
+	SigningKey Secret `yaml:"signing_key" flag:"signing-key" usage:"key used to sign the personal data exports"`
+}
+
+// Idempotency configures the replay of the responses to the retries of the
+// mutating calls with the same idempotency key.
+type Idempotency struct {
+	TTL             time.Duration `yaml:"ttl" flag:"idempotency-ttl" usage:"time the responses are kept for the retries with the same idempotency key, disabled if 0"`
+	LockTimeout     time.Duration `yaml:"lock_timeout" flag:"idempotency-lock-timeout" usage:"time after which the key of a call which did not complete may be used again"`
+	CleanupInterval time.Duration `yaml:"cleanup_interval" flag:"idempotency-cleanup-interval" usage:"interval of the deletion of the expired idempotency keys"`
+}
+
+// Cache configures the in-process cache of the users.
+type Cache struct {
+	Size                int           `yaml:"size" flag:"cache-size" usage:"maximum number of cached users, disabled if 0"`
+	TTL                 time.Duration `yaml:"ttl" flag:"cache-ttl" usage:"time a user is cached, the longest a replica may serve a user changed by another one without invalidation_channel"`
+	NegativeTTL         time.Duration `yaml:"negative_ttl" flag:"cache-negative-ttl" usage:"time a missing user is cached, disabled if 0"`
+	InvalidationChannel string        `yaml:"invalidation_channel" flag:"cache-invalidation-channel" usage:"Postgres notification channel of the invalidations of the other replicas, disabled if empty"`
+}
+
+// Profile configures the validation and the policies of the user profiles.
+type Profile struct {
+	PreferenceDefaults string   `yaml:"preference_defaults" flag:"preference-defaults" usage:"JSON file with the default preferences per namespace"`
+	AttributesSchema   string   `yaml:"attributes_schema" flag:"attributes-schema" usage:"JSON Schema file of the custom profile attributes"`
+	IndexedAttributes  []string `yaml:"indexed_attributes" flag:"indexed-attributes" usage:"comma separated attribute paths which may be used in filters"`
+	PhoneRegion        string   `yaml:"phone_region" flag:"phone-region" usage:"region of phone numbers given without the international prefix, e.g. US"`
+	EmailProviderRules bool     `yaml:"email_provider_rules" flag:"email-provider-rules" usage:"ignore provider aliases of email addresses, e.g. dots and plus-tags of Gmail"`
+	MinimumAge         string   `yaml:"minimum_age" flag:"minimum-age" usage:"minimum age of registration without a guardian consent per country, e.g. *=16,US=13"`
+}
+
+// Tracing configures the export of the traces.
+type Tracing struct {
+	Exporter     string `yaml:"exporter" flag:"trace-exporter" usage:"exporter of the traces: otlp, stdout or none if empty"`
+	OTLPEndpoint string `yaml:"otlp_endpoint" flag:"otlp-endpoint" usage:"address of the OpenTelemetry collector receiving the traces"`
+}
+
+// Default returns the default configuration.
+func Default() *Config {
+	return &Config{
+		Server: Server{
+			HTTPPort:       "8081",
+			GRPCPort:       "8082",
+			AdminPort:      "9090",
+			HealthInterval: 10 * time.Second,
+		},
+		TLS: TLS{
+			ServerName:     "localhost",
+			ReloadInterval: time.Minute,
+		},
+		Database: Database{
+			EventsChannel:       "user_events",
+			EventsRetryInterval: time.Minute,
+			ConnectTimeout:      time.Minute,
+			MaxIdleConns:        2,
+			MigrateTimeout:      5 * time.Minute,
+		},
+		Storage: Storage{
+			BlobDir:       "data/blobs",
+			AvatarBaseURL: "/v1/avatars",
+		},
+		Idempotency: Idempotency{
+			TTL:             24 * time.Hour,
+			LockTimeout:     time.Minute,
+			CleanupInterval: 10 * time.Minute,
+		},
+		Cache: Cache{
+			TTL:         time.Minute,
+			NegativeTTL: 5 * time.Second,
+		},
+		Tracing: Tracing{
+			Exporter:     tracing.ExporterNone,
+			OTLPEndpoint: tracing.DefaultOTLPEndpoint,
+		},
+	}
+}
+
+// ValidationError lists all problems of a configuration.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  " + strings.Join(e.Problems, "\n  ")
+}
+
+// Validate checks the configuration and reports all problems at once.
+func (c *Config) Validate() error {
+	var problems []string
+	add := func(key, format string, args ...interface{}) {
+		problems = append(problems, key+": "+fmt.Sprintf(format, args...))
+	}
+
+	ports := map[string]string{}
+	for _, p := range []struct{ key, port string }{
+		{"server.http_port", c.Server.HTTPPort},
+		{"server.grpc_port", c.Server.GRPCPort},
+		{"server.admin_port", c.Server.AdminPort},
+	} {
+		if n, err := strconv.Atoi(p.port); err != nil || n < 1 || n > 65535 {
+			add(p.key, "%q is not a port number", p.port)
+			continue
+		}
+		if other, ok := ports[p.port]; ok {
+			add(p.key, "port %s is already used by %s", p.port, other)
+		}
+		ports[p.port] = p.key
+	}
+	if c.Server.HealthInterval <= 0 {
+		add("server.health_interval", "must be positive, got %s", c.Server.HealthInterval)
+	}
+
+	switch {
+	case c.TLS.CertFile != "" && c.TLS.KeyFile == "":
+		add("tls.key_file", "is required by tls.cert_file")
+	case c.TLS.CertFile == "" && c.TLS.KeyFile != "":
+		add("tls.cert_file", "is required by tls.key_file")
+	case c.TLS.CertFile == "" && c.TLS.ClientCAFile != "":
+		add("tls.client_ca_file", "requires tls.cert_file and tls.key_file")
+	}
+	if len(c.TLS.AdminIdentities) > 0 && c.TLS.ClientCAFile == "" {
+		add("tls.admin_identities", "requires tls.client_ca_file")
+	}
+	if c.TLS.GatewayIdentity != "" && c.TLS.ClientCAFile == "" {
+		add("tls.gateway_identity", "requires tls.client_ca_file")
+	}
+	if c.TLS.Enabled() && c.TLS.ReloadInterval <= 0 {
+		add("tls.reload_interval", "must be positive, got %s", c.TLS.ReloadInterval)
+	}
+
+	if c.Database.URL == "" {
+		add("database.url", "is required, set it or database.url_file")
+	} else if u, err := url.Parse(string(c.Database.URL)); err != nil || (u.Scheme != "postgres" && u.Scheme != "postgresql") {
+		// the parse error would contain the password
+		add("database.url", "must be a postgres:// URL")
+	}
+	if c.Database.EventsChannel == "" {
+		add("database.events_channel", "is required")
+	}
+	if c.Database.EventsRetryInterval <= 0 {
+		add("database.events_retry_interval", "must be positive, got %s", c.Database.EventsRetryInterval)
+	}
+	if c.Database.ConnectTimeout <= 0 {
+		add("database.connect_timeout", "must be positive, got %s", c.Database.ConnectTimeout)
+	}
+	for _, p := range []struct {
+		key   string
+		value int
+	}{
+		{"database.max_open_conns", c.Database.MaxOpenConns},
+		{"database.max_idle_conns", c.Database.MaxIdleConns},
+	} {
+		if p.value < 0 {
+			add(p.key, "must not be negative, got %d", p.value)
+		}
+	}
+	if c.Database.AutoMigrate && c.Database.MigrateTimeout <= 0 {
+		add("database.migrate_timeout", "must be positive, got %s", c.Database.MigrateTimeout)
+	}
+	if c.Database.MaxOpenConns > 0 && c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		add("database.max_idle_conns", "must not exceed database.max_open_conns (%d), got %d",
+			c.Database.MaxOpenConns, c.Database.MaxIdleConns)
+	}
+	for _, p := range []struct {
+		key   string
+		value time.Duration
+	}{
+		{"database.conn_max_lifetime", c.Database.ConnMaxLifetime},
+		{"database.conn_max_idle_time", c.Database.ConnMaxIdleTime},
+	} {
+		if p.value < 0 {
+			add(p.key, "must not be negative, got %s", p.value)
+		}
+	}
+
+	if c.Idempotency.TTL < 0 {
+		add("idempotency.ttl", "must not be negative, got %s", c.Idempotency.TTL)
+	}
+	if c.Idempotency.TTL > 0 {
+		if c.Idempotency.LockTimeout <= 0 {
+			add("idempotency.lock_timeout", "must be positive, got %s", c.Idempotency.LockTimeout)
+		}
+		if c.Idempotency.CleanupInterval <= 0 {
+			add("idempotency.cleanup_interval", "must be positive, got %s", c.Idempotency.CleanupInterval)
+		}
+	}
+
+	if c.Cache.Size < 0 {
+		add("cache.size", "must not be negative, got %d", c.Cache.Size)
+	}
+	if c.Cache.Size > 0 {
+		if c.Cache.TTL <= 0 {
+			add("cache.ttl", "must be positive, got %s", c.Cache.TTL)
+		}
+		if c.Cache.NegativeTTL < 0 {
+			add("cache.negative_ttl", "must not be negative, got %s", c.Cache.NegativeTTL)
+		}
+	}
+
+	if c.Storage.BlobDir == "" {
+		add("storage.blob_dir", "is required")
+	}
+
+	if err := phone.ValidateRegion(c.Profile.PhoneRegion); err != nil {
+		add("profile.phone_region", "%v", err)
+	}
+	if _, err := age.ParsePolicy(c.Profile.MinimumAge); err != nil {
+		add("profile.minimum_age", "%v", err)
+	}
+
+	switch c.Tracing.Exporter {
+	case tracing.ExporterNone, tracing.ExporterStdout:
+	case tracing.ExporterOTLP:
+		if c.Tracing.OTLPEndpoint == "" {
+			add("tracing.otlp_endpoint", "is required by the otlp exporter")
+		}
+	default:
+		add("tracing.exporter", "%q is not one of otlp, stdout or empty", c.Tracing.Exporter)
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+
+	return nil
+}
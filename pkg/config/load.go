@@ -0,0 +1,411 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v2"
+)
+
+// EnvPrefix prefixes the environment variables of the configuration, e.g.
+// server.http_port is read from USER_SERVICE_SERVER_HTTP_PORT.
+const EnvPrefix = "USER_SERVICE_"
+
+// FileFlag is the flag of the config file. The file may also be set by the
+// USER_SERVICE_CONFIG environment variable.
+const FileFlag = "config"
+
+var (
+	// ErrUnknownFormat is returned if the config file is neither YAML nor TOML.
+	ErrUnknownFormat = errors.New("unknown config file format")
+
+	// ErrUnknownKey is returned if the config file contains an unknown key.
+	ErrUnknownKey = errors.New("unknown config key")
+
+	// ErrConflict is returned if a secret and its file are set by the same source.
+	ErrConflict = errors.New("both the value and the file of a secret are set")
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+var secretType = reflect.TypeOf(Secret(""))
+
+// field is a configurable field of the Config.
+type field struct {
+	key   string
+	flag  string
+	usage string
+	value reflect.Value
+}
+
+// env returns the environment variable of the field.
+func (f field) env() string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(f.key, ".", "_"))
+}
+
+// secret reports whether the field may be read from a file.
+func (f field) secret() bool {
+	return f.value.Type() == secretType
+}
+
+// fileFlag returns the flag of the secret's file, following the naming of the flag.
+func (f field) fileFlag() string {
+	if strings.Contains(f.flag, "_") {
+		return f.flag + "_file"
+	}
+
+	return f.flag + "-file"
+}
+
+// fields lists the fields of the struct, the keys of the nested structs are
+// joined by dots.
+func fields(v reflect.Value, prefix string) []field {
+	var fs []field
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		key := prefix + sf.Tag.Get("yaml")
+
+		if sf.Type.Kind() == reflect.Struct {
+			fs = append(fs, fields(v.Field(i), key+".")...)
+			continue
+		}
+
+		fs = append(fs, field{
+			key:   key,
+			flag:  sf.Tag.Get("flag"),
+			usage: sf.Tag.Get("usage"),
+			value: v.Field(i),
+		})
+	}
+
+	return fs
+}
+
+// set parses the value of the field.
+func (f field) set(s string) error {
+	v := f.value
+
+	switch {
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+	case v.Kind() == reflect.String:
+		v.SetString(s)
+	case v.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case v.Kind() == reflect.Int:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(n))
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String:
+		var items []string
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		panic("config: unsupported type " + v.Type().String())
+	}
+
+	return nil
+}
+
+// format formats the value of the field as accepted by set.
+func (f field) format() string {
+	v := f.value
+
+	switch {
+	case v.Type() == durationType:
+		return time.Duration(v.Int()).String()
+	case v.Type() == secretType:
+		return ""
+	case v.Kind() == reflect.Slice:
+		return strings.Join(v.Interface().([]string), ",")
+	}
+
+	return fmt.Sprint(v.Interface())
+}
+
+// layer holds the values of one source by the keys, the files of the
+// secrets are keyed by the key with the "_file" suffix.
+type layer struct {
+	source string
+	values map[string]string
+	names  map[string]string
+}
+
+func newLayer(source string) *layer {
+	return &layer{
+		source: source,
+		values: make(map[string]string),
+		names:  make(map[string]string),
+	}
+}
+
+func (l *layer) add(key, name, value string) {
+	l.values[key] = value
+	l.names[key] = name
+}
+
+// has reports whether the layer sets the field.
+func (l *layer) has(f field) bool {
+	_, ok := l.values[f.key]
+	_, fileOK := l.values[f.key+"_file"]
+
+	return ok || (fileOK && f.secret())
+}
+
+// apply sets the field from the layer. The file of a secret is read only if
+// the layer wins, so a missing file of an overridden source is harmless.
+func (l *layer) apply(f field) error {
+	s, ok := l.values[f.key]
+	name := l.names[f.key]
+
+	if f.secret() {
+		fileKey := f.key + "_file"
+		if file, fileOK := l.values[fileKey]; fileOK {
+			if ok {
+				return fmt.Errorf("%s: %w: %s and %s", l.source, ErrConflict, name, l.names[fileKey])
+			}
+
+			data, err := ioutil.ReadFile(file)
+			if err != nil {
+				return fmt.Errorf("%s: %s: failed to read the secret: %w", l.source, l.names[fileKey], err)
+			}
+			s, name = strings.TrimSpace(string(data)), l.names[fileKey]
+		}
+	}
+
+	if err := f.set(s); err != nil {
+		return fmt.Errorf("%s: %s: %w", l.source, name, err)
+	}
+
+	return nil
+}
+
+// flagValue records the value of a flag, the flags which were set are applied
+// over the other sources.
+type flagValue struct {
+	value  string
+	isBool bool
+}
+
+func (v *flagValue) String() string {
+	if v == nil {
+		return ""
+	}
+
+	return v.value
+}
+
+func (v *flagValue) Set(s string) error {
+	v.value = s
+	return nil
+}
+
+func (v *flagValue) IsBoolFlag() bool {
+	return v.isBool
+}
+
+// Load builds the configuration from the defaults, the config file, the
+// environment variables and the command line flags, each overriding the
+// previous ones. The configuration is not validated, see Config.Validate.
+//
+// The config file is given by the -config flag or the USER_SERVICE_CONFIG
+// variable, its format is chosen by the extension: .yaml, .yml or .toml.
+// The secrets may be read from files, given by the key with the "_file"
+// suffix, the variable with the "_FILE" suffix or the flag with the "file"
+// suffix, e.g. database.url_file, USER_SERVICE_DATABASE_URL_FILE or
+// -db_url_file.
+func Load(name string, args []string, lookupEnv func(string) (string, bool), output io.Writer) (*Config, error) {
+	cfg := Default()
+	fs := fields(reflect.ValueOf(cfg).Elem(), "")
+
+	// command line
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(output)
+	configFile := flags.String(FileFlag, "", "YAML or TOML config file, also read from "+EnvPrefix+"CONFIG")
+	values := make(map[string]*flagValue)
+	for _, f := range fs {
+		v := &flagValue{value: f.format(), isBool: f.value.Kind() == reflect.Bool}
+		flags.Var(v, f.flag, f.usage+", also read from "+f.env())
+		values[f.flag] = v
+
+		if f.secret() {
+			fv := &flagValue{}
+			flags.Var(fv, f.fileFlag(), "file with the "+f.flag+" secret, also read from "+f.env()+"_FILE")
+			values[f.fileFlag()] = fv
+		}
+	}
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	if flags.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(flags.Args(), " "))
+	}
+
+	flagLayer := newLayer("command line")
+	set := make(map[string]bool)
+	flags.Visit(func(fl *flag.Flag) {
+		set[fl.Name] = true
+	})
+	for _, f := range fs {
+		if set[f.flag] {
+			flagLayer.add(f.key, "-"+f.flag, values[f.flag].value)
+		}
+		if f.secret() && set[f.fileFlag()] {
+			flagLayer.add(f.key+"_file", "-"+f.fileFlag(), values[f.fileFlag()].value)
+		}
+	}
+
+	// environment
+	envLayer := newLayer("environment")
+	for _, f := range fs {
+		if s, ok := lookupEnv(f.env()); ok {
+			envLayer.add(f.key, f.env(), s)
+		}
+		if s, ok := lookupEnv(f.env() + "_FILE"); ok && f.secret() {
+			envLayer.add(f.key+"_file", f.env()+"_FILE", s)
+		}
+	}
+
+	// config file
+	if *configFile == "" {
+		*configFile, _ = lookupEnv(EnvPrefix + "CONFIG")
+	}
+	layers := []*layer{envLayer, flagLayer}
+	if *configFile != "" {
+		fileLayer, err := readFile(*configFile, fs)
+		if err != nil {
+			return nil, err
+		}
+		layers = append([]*layer{fileLayer}, layers...)
+	}
+
+	// the last source setting a field wins
+	for _, f := range fs {
+		for i := len(layers) - 1; i >= 0; i-- {
+			if !layers[i].has(f) {
+				continue
+			}
+			if err := layers[i].apply(f); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+
+	return cfg, nil
+}
+
+// readFile reads the layer of the config file.
+func readFile(name string, fs []field) (*layer, error) {
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the config file: %w", err)
+	}
+
+	tree := make(map[string]interface{})
+	switch ext := strings.ToLower(filepath.Ext(name)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &tree)
+	case ".toml":
+		_, err = toml.Decode(string(data), &tree)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the config file %s: %w", name, err)
+	}
+
+	known := make(map[string]bool)
+	for _, f := range fs {
+		known[f.key] = true
+		if f.secret() {
+			known[f.key+"_file"] = true
+		}
+	}
+
+	l := newLayer("config file " + name)
+	flat := make(map[string]string)
+	flatten(tree, "", flat)
+
+	keys := make([]string, 0, len(flat))
+	for key := range flat {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !known[key] {
+			return nil, fmt.Errorf("%s: %w: %s", l.source, ErrUnknownKey, key)
+		}
+		l.add(key, key, flat[key])
+	}
+
+	return l, nil
+}
+
+// flatten joins the keys of the nested tables by dots, the lists are joined by commas.
+func flatten(v interface{}, key string, out map[string]string) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, item := range v {
+			flatten(item, join(key, k), out)
+		}
+	case map[interface{}]interface{}:
+		for k, item := range v {
+			flatten(item, join(key, fmt.Sprint(k)), out)
+		}
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = fmt.Sprint(item)
+		}
+		out[key] = strings.Join(items, ",")
+	case nil:
+		out[key] = ""
+	default:
+		out[key] = fmt.Sprint(v)
+	}
+}
+
+func join(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+
+	return prefix + "." + key
+}
+
+// Print writes the configuration as YAML with the secrets masked.
+func Print(w io.Writer, c *Config) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(data)
+	return err
+}
@@ -0,0 +1,310 @@
+package dataexport
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/chutommy/user-microservice/pkg/repo"
+)
+
+// Version is the version of the archive format.
+const Version = 1
+
+// Archive holds all data stored about a single user.
+type Archive struct {
+	Version     int       `json:"version"`
+	ExportID    uuid.UUID `json:"exportId"`
+	GeneratedAt time.Time `json:"generatedAt"`
+
+	Profile      Profile      `json:"profile"`
+	AuditEntries []AuditEntry `json:"auditEntries"`
+	Consents     []Consent    `json:"consents"`
+	Preferences  []Preference `json:"preferences"`
+	Addresses    []Address    `json:"addresses"`
+	Emails       []Email      `json:"emails"`
+
+	GuardianConsents []GuardianConsent `json:"guardianConsents"`
+}
+
+// Profile is the user record without the credentials.
+type Profile struct {
+	ID           uuid.UUID       `json:"id"`
+	Email        string          `json:"email"`
+	PhoneNumber  *string         `json:"phoneNumber"`
+	PhoneCountry *string         `json:"phoneCountry"`
+	FirstName    string          `json:"firstName"`
+	LastName     string          `json:"lastName"`
+	Gender       int16           `json:"gender"`
+	BirthDay     *string         `json:"birthDay"`
+	Country      *string         `json:"country"`
+	State        int16           `json:"state"`
+	Attributes   json.RawMessage `json:"attributes"`
+	AvatarKey    *string         `json:"avatarKey"`
+	UpdatedAt    *time.Time      `json:"updatedAt"`
+	CreatedAt    time.Time       `json:"createdAt"`
+	ErasedAt     *time.Time      `json:"erasedAt"`
+}
+
+// AuditEntry is a recorded operation on the personal data of the user.
+type AuditEntry struct {
+	ID         uuid.UUID `json:"id"`
+	Action     string    `json:"action"`
+	Reason     string    `json:"reason,omitempty"`
+	OccurredAt time.Time `json:"occurredAt"`
+}
+
+// Consent is an accepted version of a policy.
+type Consent struct {
+	PolicyKind    string     `json:"policyKind"`
+	PolicyVersion int32      `json:"policyVersion"`
+	AcceptedAt    time.Time  `json:"acceptedAt"`
+	WithdrawnAt   *time.Time `json:"withdrawnAt"`
+}
+
+// Preference is a preference set by the user.
+type Preference struct {
+	Namespace string          `json:"namespace"`
+	Key       string          `json:"key"`
+	Value     json.RawMessage `json:"value"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+}
+
+// Address is a postal address of the user.
+type Address struct {
+	ID          uuid.UUID `json:"id"`
+	Kind        int16     `json:"kind"`
+	Default     bool      `json:"default"`
+	Recipient   string    `json:"recipient"`
+	Line1       string    `json:"line1"`
+	Line2       string    `json:"line2"`
+	City        string    `json:"city"`
+	Region      string    `json:"region"`
+	PostalCode  string    `json:"postalCode"`
+	CountryCode string    `json:"countryCode"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// Email is an email address of the user.
+type Email struct {
+	Email     string    `json:"email"`
+	Verified  bool      `json:"verified"`
+	Primary   bool      `json:"primary"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// GuardianConsent is a consent of a guardian to the account of a minor, the
+// user is either of them.
+type GuardianConsent struct {
+	ID          uuid.UUID  `json:"id"`
+	MinorID     uuid.UUID  `json:"minorId"`
+	GuardianID  uuid.UUID  `json:"guardianId"`
+	ExpiresAt   time.Time  `json:"expiresAt"`
+	ConfirmedAt *time.Time `json:"confirmedAt"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
+// Builder assembles archives from the repo.
+type Builder struct {
+	repo repo.Querier
+}
+
+// NewBuilder constructs a Builder.
+func NewBuilder(repo repo.Querier) *Builder {
+	return &Builder{
+		repo: repo,
+	}
+}
+
+// Build collects the data of the user into an archive.
+func (b *Builder) Build(ctx context.Context, exportID, userID uuid.UUID) (*Archive, error) {
+	user, err := b.repo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("retrieve user: %w", err)
+	}
+
+	receipts, err := b.repo.ListErasureReceipts(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list erasure receipts: %w", err)
+	}
+
+	consents, err := b.repo.ListConsents(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list consents: %w", err)
+	}
+
+	prefs, err := b.repo.ListPreferences(ctx, repo.ListPreferencesParams{UserID: userID})
+	if err != nil {
+		return nil, fmt.Errorf("list preferences: %w", err)
+	}
+
+	addrs, err := b.repo.ListAddresses(ctx, repo.ListAddressesParams{UserID: userID})
+	if err != nil {
+		return nil, fmt.Errorf("list addresses: %w", err)
+	}
+
+	emails, err := b.repo.ListEmails(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list emails: %w", err)
+	}
+
+	guardians, err := b.repo.ListGuardianConsents(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list guardian consents: %w", err)
+	}
+
+	a := &Archive{
+		Version:      Version,
+		ExportID:     exportID,
+		GeneratedAt:  time.Now().UTC(),
+		Profile:      newProfile(user),
+		AuditEntries: make([]AuditEntry, 0, len(receipts)),
+		Consents:     make([]Consent, 0, len(consents)),
+		Preferences:  make([]Preference, 0, len(prefs)),
+		Addresses:    make([]Address, 0, len(addrs)),
+		Emails:       make([]Email, 0, len(emails)),
+
+		GuardianConsents: make([]GuardianConsent, 0, len(guardians)),
+	}
+	for _, r := range receipts {
+		a.AuditEntries = append(a.AuditEntries, AuditEntry{
+			ID:         r.ID,
+			Action:     "erase",
+			Reason:     r.Reason,
+			OccurredAt: r.ErasedAt,
+		})
+	}
+
+	for _, c := range consents {
+		consent := Consent{
+			PolicyKind:    c.PolicyKind,
+			PolicyVersion: c.PolicyVersion,
+			AcceptedAt:    c.AcceptedAt,
+		}
+		if c.WithdrawnAt.Valid {
+			consent.WithdrawnAt = &c.WithdrawnAt.Time
+		}
+		a.Consents = append(a.Consents, consent)
+	}
+	for _, p := range prefs {
+		a.Preferences = append(a.Preferences, Preference{
+			Namespace: p.Namespace,
+			Key:       p.Key,
+			Value:     p.Value,
+			UpdatedAt: p.UpdatedAt,
+		})
+	}
+	for _, ad := range addrs {
+		a.Addresses = append(a.Addresses, Address{
+			ID:          ad.ID,
+			Kind:        ad.Kind,
+			Default:     ad.IsDefault,
+			Recipient:   ad.Recipient,
+			Line1:       ad.Line1,
+			Line2:       ad.Line2,
+			City:        ad.City,
+			Region:      ad.Region,
+			PostalCode:  ad.PostalCode,
+			CountryCode: ad.CountryCode,
+			CreatedAt:   ad.CreatedAt,
+		})
+	}
+	for _, e := range emails {
+		a.Emails = append(a.Emails, Email{
+			Email:     e.Email,
+			Verified:  e.Verified,
+			Primary:   e.IsPrimary,
+			CreatedAt: e.CreatedAt,
+		})
+	}
+
+	for _, g := range guardians {
+		consent := GuardianConsent{
+			ID:         g.ID,
+			MinorID:    g.MinorID,
+			GuardianID: g.GuardianID,
+			ExpiresAt:  g.ExpiresAt,
+			CreatedAt:  g.CreatedAt,
+		}
+		if g.ConfirmedAt.Valid {
+			consent.ConfirmedAt = &g.ConfirmedAt.Time
+		}
+		a.GuardianConsents = append(a.GuardianConsents, consent)
+	}
+
+	return a, nil
+}
+
+func newProfile(u repo.User) Profile {
+	p := Profile{
+		ID:         u.ID,
+		Email:      u.Email,
+		FirstName:  u.FirstName,
+		LastName:   u.LastName,
+		Gender:     u.Gender,
+		State:      u.State,
+		Attributes: u.Attributes,
+		CreatedAt:  u.CreatedAt,
+	}
+	if u.PhoneNumber.Valid {
+		p.PhoneNumber = &u.PhoneNumber.String
+	}
+	if u.PhoneCountry.Valid {
+		p.PhoneCountry = &u.PhoneCountry.String
+	}
+	if u.Country.Valid {
+		p.Country = &u.Country.String
+	}
+	if u.AvatarKey.Valid {
+		p.AvatarKey = &u.AvatarKey.String
+	}
+	if u.BirthDay.Valid {
+		bd := u.BirthDay.Time.Format("2006-01-02")
+		p.BirthDay = &bd
+	}
+	if u.UpdatedAt.Valid {
+		p.UpdatedAt = &u.UpdatedAt.Time
+	}
+	if u.ErasedAt.Valid {
+		p.ErasedAt = &u.ErasedAt.Time
+	}
+
+	return p
+}
+
+// Marshal encodes the archive and signs the encoded data with the key.
+func Marshal(a *Archive, key []byte) (data []byte, signature string, err error) {
+	data, err = json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return nil, "", err
+	}
+
+	return data, Sign(data, key), nil
+}
+
+// Sign returns a hex encoded HMAC-SHA256 signature of the data.
+func Sign(data, key []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether the signature of the data is valid.
+func Verify(data, key []byte, signature string) bool {
+	sig, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+
+	return hmac.Equal(sig, mac.Sum(nil))
+}
@@ -0,0 +1,119 @@
+package dataexport_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chutommy/user-microservice/pkg/dataexport"
+	"github.com/chutommy/user-microservice/pkg/mocks"
+	"github.com/chutommy/user-microservice/pkg/repo"
+	"github.com/chutommy/user-microservice/pkg/util"
+)
+
+func TestBuilder_Build(t *testing.T) {
+	t.Parallel()
+
+	user := repo.User{
+		ID:             uuid.New(),
+		Email:          util.RandomEmail(),
+		HashedPassword: "hashed",
+		FirstName:      util.RandomName(),
+		LastName:       util.RandomName(),
+		BirthDay:       sql.NullTime{Time: time.Date(1990, 4, 16, 0, 0, 0, 0, time.UTC), Valid: true},
+		PhoneCountry:   sql.NullString{String: "CZ", Valid: true},
+		Country:        sql.NullString{String: "CZ", Valid: true},
+		State:          1,
+		Attributes:     json.RawMessage(`{"team":"core"}`),
+		AvatarKey:      sql.NullString{String: "avatars/1/original.png", Valid: true},
+		UpdatedAt:      sql.NullTime{Time: time.Now(), Valid: true},
+		CreatedAt:      time.Now().Add(-time.Hour),
+	}
+	receipt := repo.ErasureReceipt{
+		ID:       uuid.New(),
+		UserID:   user.ID,
+		ErasedAt: time.Now(),
+	}
+
+	q := new(mocks.Querier)
+	q.On("GetUser", mock.Anything, user.ID).Return(user, nil).Once()
+	q.On("ListErasureReceipts", mock.Anything, user.ID).Return([]repo.ErasureReceipt{receipt}, nil).Once()
+	q.On("ListConsents", mock.Anything, user.ID).Return([]repo.UserConsent{{
+		UserID:        user.ID,
+		PolicyKind:    "terms_of_service",
+		PolicyVersion: 2,
+		AcceptedAt:    time.Now(),
+	}}, nil).Once()
+	q.On("ListPreferences", mock.Anything, repo.ListPreferencesParams{UserID: user.ID}).Return([]repo.UserPreference{}, nil).Once()
+	q.On("ListAddresses", mock.Anything, repo.ListAddressesParams{UserID: user.ID}).Return([]repo.UserAddress{{
+		ID:          uuid.New(),
+		UserID:      user.ID,
+		Kind:        1,
+		IsDefault:   true,
+		Recipient:   user.FirstName,
+		Line1:       "Vodickova 1",
+		City:        "Prague",
+		PostalCode:  "110 00",
+		CountryCode: "CZ",
+	}}, nil).Once()
+	q.On("ListEmails", mock.Anything, user.ID).Return([]repo.UserEmail{{Email: "home@example.com", UserID: user.ID, IsPrimary: true}}, nil).Once()
+	q.On("ListGuardianConsents", mock.Anything, user.ID).Return([]repo.GuardianConsent{{
+		ID:          uuid.New(),
+		MinorID:     user.ID,
+		GuardianID:  uuid.New(),
+		ExpiresAt:   time.Now().Add(time.Hour),
+		ConfirmedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	}}, nil).Once()
+
+	exportID := uuid.New()
+	a, err := dataexport.NewBuilder(q).Build(context.Background(), exportID, user.ID)
+	require.NoError(t, err)
+	require.Equal(t, exportID, a.ExportID)
+	require.Equal(t, user.Email, a.Profile.Email)
+	require.Nil(t, a.Profile.PhoneNumber)
+	require.Equal(t, "1990-04-16", *a.Profile.BirthDay)
+	require.Equal(t, user.CreatedAt, a.Profile.CreatedAt)
+	require.Equal(t, "CZ", *a.Profile.Country)
+	require.Equal(t, "CZ", *a.Profile.PhoneCountry)
+	require.Equal(t, user.State, a.Profile.State)
+	require.JSONEq(t, `{"team":"core"}`, string(a.Profile.Attributes))
+	require.Equal(t, user.AvatarKey.String, *a.Profile.AvatarKey)
+	require.Len(t, a.AuditEntries, 1)
+	require.Equal(t, receipt.ID, a.AuditEntries[0].ID)
+	require.Len(t, a.Consents, 1)
+	require.Equal(t, int32(2), a.Consents[0].PolicyVersion)
+	require.Nil(t, a.Consents[0].WithdrawnAt)
+	require.Len(t, a.Addresses, 1)
+	require.Equal(t, "CZ", a.Addresses[0].CountryCode)
+	require.Len(t, a.GuardianConsents, 1)
+	require.Equal(t, user.ID, a.GuardianConsents[0].MinorID)
+	require.NotNil(t, a.GuardianConsents[0].ConfirmedAt)
+
+	// the credentials are never exported
+	data, sig, err := dataexport.Marshal(a, []byte("key"))
+	require.NoError(t, err)
+	require.NotContains(t, string(data), user.HashedPassword)
+	require.True(t, json.Valid(data))
+	require.NotEmpty(t, sig)
+
+	q.AssertExpectations(t)
+}
+
+func TestSign(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`{"version":1}`)
+	key := []byte("secret")
+
+	sig := dataexport.Sign(data, key)
+	require.True(t, dataexport.Verify(data, key, sig))
+	require.False(t, dataexport.Verify(data, []byte("other"), sig))
+	require.False(t, dataexport.Verify([]byte(`{"version":2}`), key, sig))
+	require.False(t, dataexport.Verify(data, key, "not hex"))
+}
@@ -0,0 +1,113 @@
+// Package dbconn connects to the database, retrying until it is reachable.
+package dbconn
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Pool configures the connection pool of the database, the zero values keep
+// the defaults of database/sql.
+type Pool struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
+// apply configures the pool of the database.
+func (p Pool) apply(db *sql.DB) {
+	if p.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(p.MaxOpenConns)
+	}
+	if p.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(p.MaxIdleConns)
+	}
+	if p.ConnMaxLifetime > 0 {
+		db.SetConnMaxLifetime(p.ConnMaxLifetime)
+	}
+	if p.ConnMaxIdleTime > 0 {
+		db.SetConnMaxIdleTime(p.ConnMaxIdleTime)
+	}
+}
+
+// Backoff computes the delays between the connection attempts.
+type Backoff struct {
+	// Initial is the delay after the first failed attempt.
+	Initial time.Duration
+	// Max caps the delays.
+	Max time.Duration
+	// Multiplier grows the delay after each failed attempt.
+	Multiplier float64
+	// Jitter is the fraction of the delay which is randomized, in [0, 1].
+	Jitter float64
+}
+
+// DefaultBackoff starts at 250ms and doubles the delays up to 10s, each
+// randomly shortened by up to a half to spread the reconnecting replicas.
+var DefaultBackoff = Backoff{
+	Initial:    250 * time.Millisecond,
+	Max:        10 * time.Second,
+	Multiplier: 2,
+	Jitter:     0.5,
+}
+
+// Delay returns the delay after the failed attempt, numbered from 1. The
+// random number r in [0, 1) shortens the delay by the jitter.
+func (b Backoff) Delay(attempt int, r float64) time.Duration {
+	d := float64(b.Initial) * math.Pow(b.Multiplier, float64(attempt-1))
+	if d > float64(b.Max) {
+		d = float64(b.Max)
+	}
+
+	return time.Duration(d * (1 - b.Jitter*r))
+}
+
+// Open opens the database and pings it until it responds, waiting by the
+// backoff between the attempts. It gives up once the context is done and
+// returns the last error of the ping which was not caused by the context.
+func Open(ctx context.Context, driver, dsn string, pool Pool, backoff Backoff, logger *zap.Logger) (*sql.DB, error) {
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	pool.apply(db)
+
+	var cause error
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		err = db.PingContext(ctx)
+		if err == nil {
+			logger.Info(
+				"connected to the database",
+				zap.Int("attempts", attempt),
+				zap.Duration("elapsed", time.Since(start)),
+			)
+			return db, nil
+		}
+		if ctx.Err() == nil || cause == nil {
+			cause = err
+		}
+
+		delay := backoff.Delay(attempt, rand.Float64()) //nolint:gosec
+		logger.Warn(
+			"failed to connect to the database",
+			zap.Int("attempt", attempt),
+			zap.Duration("retry_in", delay),
+			zap.Error(err),
+		)
+
+		select {
+		case <-ctx.Done():
+			_ = db.Close()
+			return nil, fmt.Errorf("failed to connect to the database after %d attempts: %w", attempt, cause)
+		case <-time.After(delay):
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package dbconn_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/chutommy/user-microservice/pkg/dbconn"
+)
+
+var errDown = errors.New("connection refused")
+
+// flakyDriver refuses the connections to the DSNs until they have been
+// attempted the given number of times.
+type flakyDriver struct {
+	mu       sync.Mutex
+	failures map[string]int
+}
+
+var flaky = &flakyDriver{failures: map[string]int{}}
+
+func init() {
+	sql.Register("flaky", flaky)
+}
+
+func (d *flakyDriver) fail(dsn string, n int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.failures[dsn] = n
+}
+
+func (d *flakyDriver) Open(dsn string) (driver.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.failures[dsn] != 0 {
+		d.failures[dsn]--
+		return nil, errDown
+	}
+
+	return conn{}, nil
+}
+
+type conn struct{}
+
+func (conn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not implemented") }
+func (conn) Close() error                        { return nil }
+func (conn) Begin() (driver.Tx, error)           { return nil, errors.New("not implemented") }
+
+var fastBackoff = dbconn.Backoff{
+	Initial:    time.Millisecond,
+	Max:        5 * time.Millisecond,
+	Multiplier: 2,
+	Jitter:     0.5,
+}
+
+func TestBackoff_Delay(t *testing.T) {
+	t.Parallel()
+
+	b := dbconn.Backoff{Initial: 100 * time.Millisecond, Max: time.Second, Multiplier: 2, Jitter: 0.5}
+
+	tests := []struct {
+		name    string
+		attempt int
+		r       float64
+		want    time.Duration
+	}{
+		{"first", 1, 0, 100 * time.Millisecond},
+		{"second", 2, 0, 200 * time.Millisecond},
+		{"third", 3, 0, 400 * time.Millisecond},
+		{"capped", 10, 0, time.Second},
+		{"jitter", 2, 0.5, 150 * time.Millisecond},
+		{"full jitter", 10, 1, 500 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, b.Delay(tt.attempt, tt.r))
+		})
+	}
+}
+
+func TestOpen(t *testing.T) {
+	t.Parallel()
+
+	t.Run("retries until reachable", func(t *testing.T) {
+		t.Parallel()
+
+		flaky.fail("retry", 3)
+		db, err := dbconn.Open(context.Background(), "flaky", "retry", dbconn.Pool{
+			MaxOpenConns: 4,
+			MaxIdleConns: 2,
+		}, fastBackoff, zap.NewNop())
+		require.NoError(t, err)
+		defer db.Close()
+
+		require.Equal(t, 4, db.Stats().MaxOpenConnections)
+	})
+
+	t.Run("gives up after the timeout", func(t *testing.T) {
+		t.Parallel()
+
+		flaky.fail("down", -1)
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		_, err := dbconn.Open(ctx, "flaky", "down", dbconn.Pool{}, fastBackoff, zap.NewNop())
+		require.ErrorIs(t, err, errDown)
+	})
+
+	t.Run("unknown driver", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := dbconn.Open(context.Background(), "unknown", "", dbconn.Pool{}, fastBackoff, zap.NewNop())
+		require.Error(t, err)
+	})
+}
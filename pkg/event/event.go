@@ -0,0 +1,96 @@
+package event
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// TypeUserErased is emitted once the personal data of a user are anonymized.
+	TypeUserErased = "user.erased"
+
+	// TypeGuardianConsentRequested is emitted to the guardian asked to consent
+	// to the account of a minor.
+	TypeGuardianConsentRequested = "user.guardian_consent_requested"
+)
+
+// Event is a domain event emitted by the user service.
+type Event struct {
+	Type       string            `json:"type"`
+	UserID     uuid.UUID         `json:"userId"`
+	OccurredAt time.Time         `json:"occurredAt"`
+	Data       map[string]string `json:"data,omitempty"`
+}
+
+// Publisher delivers events to their consumers.
+type Publisher interface {
+	Publish(ctx context.Context, e Event) error
+}
+
+// NopPublisher discards all events.
+type NopPublisher struct{}
+
+// Publish implements Publisher.
+func (NopPublisher) Publish(context.Context, Event) error {
+	return nil
+}
+
+// LogPublisher writes events to the logger.
+type LogPublisher struct {
+	logger *zap.Logger
+}
+
+// NewLogPublisher constructs a LogPublisher.
+func NewLogPublisher(logger *zap.Logger) *LogPublisher {
+	return &LogPublisher{
+		logger: logger,
+	}
+}
+
+// Publish implements Publisher.
+func (p *LogPublisher) Publish(_ context.Context, e Event) error {
+	p.logger.Info(
+		"event published",
+		zap.String("event_type", e.Type),
+		zap.String("user_id", e.UserID.String()),
+		zap.Time("occurred_at", e.OccurredAt),
+		zap.Any("data", e.Data),
+	)
+
+	return nil
+}
+
+// Execer executes a query without returning any rows.
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// PostgresPublisher sends events as JSON payloads of Postgres notifications.
+type PostgresPublisher struct {
+	db      Execer
+	channel string
+}
+
+// NewPostgresPublisher constructs a PostgresPublisher which notifies the given channel.
+func NewPostgresPublisher(db Execer, channel string) *PostgresPublisher {
+	return &PostgresPublisher{
+		db:      db,
+		channel: channel,
+	}
+}
+
+// Publish implements Publisher.
+func (p *PostgresPublisher) Publish(ctx context.Context, e Event) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.db.ExecContext(ctx, "select pg_notify($1, $2)", p.channel, string(payload))
+	return err
+}
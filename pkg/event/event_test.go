@@ -0,0 +1,46 @@
+package event_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chutommy/user-microservice/pkg/event"
+)
+
+type execFunc func(query string, args ...interface{}) error
+
+func (f execFunc) ExecContext(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return nil, f(query, args...)
+}
+
+func TestPostgresPublisher_Publish(t *testing.T) {
+	t.Parallel()
+
+	e := event.Event{
+		Type:       event.TypeUserErased,
+		UserID:     uuid.New(),
+		OccurredAt: time.Now().UTC().Truncate(time.Second),
+		Data:       map[string]string{"receipt_id": uuid.New().String()},
+	}
+
+	var channel, payload string
+	p := event.NewPostgresPublisher(execFunc(func(query string, args ...interface{}) error {
+		require.Contains(t, query, "pg_notify")
+		require.Len(t, args, 2)
+		channel, payload = args[0].(string), args[1].(string)
+		return nil
+	}), "user_events")
+
+	require.NoError(t, p.Publish(context.Background(), e))
+	require.Equal(t, "user_events", channel)
+
+	var got event.Event
+	require.NoError(t, json.Unmarshal([]byte(payload), &got))
+	require.Equal(t, e, got)
+}
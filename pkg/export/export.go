@@ -0,0 +1,406 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+
+	"github.com/chutommy/user-microservice/pkg/repo"
+)
+
+// Format is an encoding of the exported users.
+type Format int
+
+const (
+	// CSV encodes users as comma-separated values with a header line.
+	CSV Format = iota
+	// JSONL encodes every user as a JSON object on a separate line.
+	JSONL
+	// Parquet encodes users into an Apache Parquet file.
+	Parquet
+)
+
+var (
+	// ErrUnknownColumn is returned if a requested column does not exist
+	// or must not be exported.
+	ErrUnknownColumn = errors.New("unknown column")
+
+	// ErrUnknownFormat is returned for an unsupported Format.
+	ErrUnknownFormat = errors.New("unknown export format")
+)
+
+// Encoder writes users to the underlying writer.
+type Encoder interface {
+	// Encode writes a single user.
+	Encode(u repo.User) error
+	// Close flushes buffered data. It does not close the underlying writer.
+	Close() error
+}
+
+// column describes a single exportable column of the users table.
+type column struct {
+	name string
+	// parquet schema definition of the column
+	parquet string
+	// value returns the column's value; nil represents NULL
+	value func(u repo.User) interface{}
+}
+
+func nullString(s string, valid bool) interface{} {
+	if !valid {
+		return nil
+	}
+	return s
+}
+
+func nullTime(t time.Time, valid bool) interface{} {
+	if !valid {
+		return nil
+	}
+	return t
+}
+
+// attributes returns the custom attributes as a JSON document.
+func attributes(raw json.RawMessage) json.RawMessage {
+	if len(raw) == 0 {
+		return json.RawMessage("{}")
+	}
+	return raw
+}
+
+// date is a calendar date without a time of day.
+type date time.Time
+
+func nullDate(t time.Time, valid bool) interface{} {
+	if !valid {
+		return nil
+	}
+	return date(t)
+}
+
+// days returns the number of days since the Unix epoch.
+func (d date) days() int64 {
+	const day = 24 * 60 * 60
+	s := time.Time(d).Unix()
+	if s < 0 && s%day != 0 {
+		return s/day - 1
+	}
+	return s / day
+}
+
+// columns are all exportable columns. The hashed password is left out on purpose.
+var columns = []column{
+	{
+		name:    "id",
+		parquet: "name=id, type=BYTE_ARRAY, convertedtype=UTF8",
+		value:   func(u repo.User) interface{} { return u.ID.String() },
+	},
+	{
+		name:    "email",
+		parquet: "name=email, type=BYTE_ARRAY, convertedtype=UTF8",
+		value:   func(u repo.User) interface{} { return u.Email },
+	},
+	{
+		name:    "email_canonical",
+		parquet: "name=email_canonical, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL",
+		value:   func(u repo.User) interface{} { return nullString(u.EmailCanonical.String, u.EmailCanonical.Valid) },
+	},
+	{
+		name:    "phone_number",
+		parquet: "name=phone_number, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL",
+		value:   func(u repo.User) interface{} { return nullString(u.PhoneNumber.String, u.PhoneNumber.Valid) },
+	},
+	{
+		name:    "phone_country",
+		parquet: "name=phone_country, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL",
+		value:   func(u repo.User) interface{} { return nullString(u.PhoneCountry.String, u.PhoneCountry.Valid) },
+	},
+	{
+		name:    "first_name",
+		parquet: "name=first_name, type=BYTE_ARRAY, convertedtype=UTF8",
+		value:   func(u repo.User) interface{} { return u.FirstName },
+	},
+	{
+		name:    "last_name",
+		parquet: "name=last_name, type=BYTE_ARRAY, convertedtype=UTF8",
+		value:   func(u repo.User) interface{} { return u.LastName },
+	},
+	{
+		name:    "gender",
+		parquet: "name=gender, type=INT32",
+		value:   func(u repo.User) interface{} { return int32(u.Gender) },
+	},
+	{
+		name:    "birth_day",
+		parquet: "name=birth_day, type=INT32, convertedtype=DATE, repetitiontype=OPTIONAL",
+		value:   func(u repo.User) interface{} { return nullDate(u.BirthDay.Time, u.BirthDay.Valid) },
+	},
+	{
+		name:    "country",
+		parquet: "name=country, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL",
+		value:   func(u repo.User) interface{} { return nullString(u.Country.String, u.Country.Valid) },
+	},
+	{
+		name:    "state",
+		parquet: "name=state, type=INT32",
+		value:   func(u repo.User) interface{} { return int32(u.State) },
+	},
+	{
+		name:    "avatar_key",
+		parquet: "name=avatar_key, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL",
+		value:   func(u repo.User) interface{} { return nullString(u.AvatarKey.String, u.AvatarKey.Valid) },
+	},
+	{
+		name:    "updated_at",
+		parquet: "name=updated_at, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL",
+		value:   func(u repo.User) interface{} { return nullTime(u.UpdatedAt.Time, u.UpdatedAt.Valid) },
+	},
+	{
+		name:    "created_at",
+		parquet: "name=created_at, type=INT64, convertedtype=TIMESTAMP_MILLIS",
+		value:   func(u repo.User) interface{} { return u.CreatedAt },
+	},
+	{
+		name:    "attributes",
+		parquet: "name=attributes, type=BYTE_ARRAY, convertedtype=UTF8",
+		value:   func(u repo.User) interface{} { return attributes(u.Attributes) },
+	},
+	{
+		name:    "erased_at",
+		parquet: "name=erased_at, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL",
+		value:   func(u repo.User) interface{} { return nullTime(u.ErasedAt.Time, u.ErasedAt.Valid) },
+	},
+}
+
+// Columns returns the names of all exportable columns in the default order.
+func Columns() []string {
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.name
+	}
+
+	return names
+}
+
+// selectColumns resolves the column names. All columns are selected if names is empty.
+func selectColumns(names []string) ([]column, error) {
+	if len(names) == 0 {
+		return columns, nil
+	}
+
+	selected := make([]column, 0, len(names))
+	for _, n := range names {
+		var found bool
+		for _, c := range columns {
+			if c.name == n {
+				selected = append(selected, c)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownColumn, n)
+		}
+	}
+
+	return selected, nil
+}
+
+// NewEncoder constructs an Encoder of the given format which writes the selected
+// columns into w. All columns are exported if no columns are given.
+func NewEncoder(format Format, w io.Writer, columnNames []string) (Encoder, error) {
+	cols, err := selectColumns(columnNames)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case CSV:
+		return newCSVEncoder(w, cols)
+	case JSONL:
+		return newJSONLEncoder(w, cols)
+	case Parquet:
+		return newParquetEncoder(w, cols)
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrUnknownFormat, format)
+	}
+}
+
+// formatValue formats a column value as a text. NULL is encoded as an empty string.
+func formatValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int32:
+		return strconv.FormatInt(int64(v), 10)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	case date:
+		return time.Time(v).Format("2006-01-02")
+	case json.RawMessage:
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+type csvEncoder struct {
+	w    *csv.Writer
+	cols []column
+	rec  []string
+}
+
+func newCSVEncoder(w io.Writer, cols []column) (*csvEncoder, error) {
+	e := &csvEncoder{
+		w:    csv.NewWriter(w),
+		cols: cols,
+		rec:  make([]string, len(cols)),
+	}
+
+	// header
+	for i, c := range cols {
+		e.rec[i] = c.name
+	}
+	if err := e.w.Write(e.rec); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+func (e *csvEncoder) Encode(u repo.User) error {
+	for i, c := range e.cols {
+		e.rec[i] = formatValue(c.value(u))
+	}
+
+	return e.w.Write(e.rec)
+}
+
+func (e *csvEncoder) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type jsonlEncoder struct {
+	w    io.Writer
+	cols []column
+
+	// keys are the encoded column names
+	keys [][]byte
+	buf  bytes.Buffer
+}
+
+func newJSONLEncoder(w io.Writer, cols []column) (*jsonlEncoder, error) {
+	e := &jsonlEncoder{
+		w:    w,
+		cols: cols,
+		keys: make([][]byte, len(cols)),
+	}
+	for i, c := range cols {
+		key, err := json.Marshal(c.name)
+		if err != nil {
+			return nil, err
+		}
+		e.keys[i] = key
+	}
+
+	return e, nil
+}
+
+// Encode writes the columns of the user in their order, which a map would
+// not keep.
+func (e *jsonlEncoder) Encode(u repo.User) error {
+	e.buf.Reset()
+	e.buf.WriteByte('{')
+	for i, c := range e.cols {
+		v := c.value(u)
+		switch v.(type) {
+		case time.Time, date:
+			v = formatValue(v)
+		}
+
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+
+		if i > 0 {
+			e.buf.WriteByte(',')
+		}
+		e.buf.Write(e.keys[i])
+		e.buf.WriteByte(':')
+		e.buf.Write(data)
+	}
+	e.buf.WriteString("}\n")
+
+	_, err := e.w.Write(e.buf.Bytes())
+	return err
+}
+
+func (e *jsonlEncoder) Close() error {
+	return nil
+}
+
+// parquetRowGroupSize bounds the memory of the buffered rows, a row group
+// is flushed to the writer once it reaches the size.
+const parquetRowGroupSize = 8 * 1024 * 1024
+
+type parquetEncoder struct {
+	pw   *writer.CSVWriter
+	cols []column
+	rec  []*string
+}
+
+func newParquetEncoder(w io.Writer, cols []column) (*parquetEncoder, error) {
+	md := make([]string, len(cols))
+	for i, c := range cols {
+		md[i] = c.parquet
+	}
+
+	pw, err := writer.NewCSVWriterFromWriter(md, w, 1)
+	if err != nil {
+		return nil, fmt.Errorf("create parquet writer: %w", err)
+	}
+	pw.RowGroupSize = parquetRowGroupSize
+	pw.CompressionType = parquet.CompressionCodec_SNAPPY
+
+	return &parquetEncoder{
+		pw:   pw,
+		cols: cols,
+		rec:  make([]*string, len(cols)),
+	}, nil
+}
+
+func (e *parquetEncoder) Encode(u repo.User) error {
+	for i, c := range e.cols {
+		var s string
+		switch v := c.value(u).(type) {
+		case nil:
+			e.rec[i] = nil
+			continue
+		case date:
+			s = strconv.FormatInt(v.days(), 10)
+		case time.Time:
+			s = strconv.FormatInt(v.UnixNano()/int64(time.Millisecond), 10)
+		default:
+			s = formatValue(v)
+		}
+		e.rec[i] = &s
+	}
+
+	return e.pw.WriteString(e.rec)
+}
+
+func (e *parquetEncoder) Close() error {
+	return e.pw.WriteStop()
+}
@@ -0,0 +1,153 @@
+package export_test
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chutommy/user-microservice/pkg/export"
+	"github.com/chutommy/user-microservice/pkg/repo"
+	"github.com/chutommy/user-microservice/pkg/util"
+)
+
+func randomRepoUser() repo.User {
+	return repo.User{
+		ID:             uuid.New(),
+		Email:          util.RandomEmail(),
+		PhoneNumber:    sql.NullString{String: util.RandomPhoneNumber(), Valid: true},
+		HashedPassword: util.RandomPassword(),
+		FirstName:      util.RandomName(),
+		LastName:       util.RandomName(),
+		Gender:         int16(util.RandomInt(0, 3)),
+		BirthDay: sql.NullTime{
+			Time:  time.Date(1990, 4, 16, 0, 0, 0, 0, time.UTC),
+			Valid: true,
+		},
+		CreatedAt: time.Date(2021, 3, 20, 12, 30, 0, 0, time.UTC),
+	}
+}
+
+func TestNewEncoder(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		format  export.Format
+		columns []string
+		ok      bool
+	}{
+		{name: "csv", format: export.CSV, ok: true},
+		{name: "jsonl", format: export.JSONL, ok: true},
+		{name: "parquet", format: export.Parquet, ok: true},
+		{name: "selected columns", format: export.CSV, columns: []string{"email", "id"}, ok: true},
+		{name: "hashed password", format: export.CSV, columns: []string{"id", "hashed_password"}, ok: false},
+		{name: "unknown column", format: export.JSONL, columns: []string{"nickname"}, ok: false},
+		{name: "unknown format", format: export.Format(42), ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			enc, err := export.NewEncoder(tt.format, &buf, tt.columns)
+			if tt.ok {
+				require.NoError(t, err)
+				require.NotNil(t, enc)
+			} else {
+				require.Error(t, err)
+				require.Nil(t, enc)
+			}
+		})
+	}
+}
+
+func TestEncoder_CSV(t *testing.T) {
+	t.Parallel()
+
+	u1 := randomRepoUser()
+	u2 := randomRepoUser()
+	u2.PhoneNumber = sql.NullString{}
+
+	var buf bytes.Buffer
+	enc, err := export.NewEncoder(export.CSV, &buf, []string{"id", "phone_number", "birth_day"})
+	require.NoError(t, err)
+	require.NoError(t, enc.Encode(u1))
+	require.NoError(t, enc.Encode(u2))
+	require.NoError(t, enc.Close())
+
+	recs, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, [][]string{
+		{"id", "phone_number", "birth_day"},
+		{u1.ID.String(), u1.PhoneNumber.String, "1990-04-16"},
+		{u2.ID.String(), "", "1990-04-16"},
+	}, recs)
+}
+
+func TestEncoder_JSONL(t *testing.T) {
+	t.Parallel()
+
+	u1 := randomRepoUser()
+	u1.Country = sql.NullString{String: "CZ", Valid: true}
+	u1.State = 1
+
+	var buf bytes.Buffer
+	enc, err := export.NewEncoder(export.JSONL, &buf, nil)
+	require.NoError(t, err)
+	require.NoError(t, enc.Encode(u1))
+	require.NoError(t, enc.Encode(u1))
+	require.NoError(t, enc.Close())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var obj map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &obj))
+	require.Equal(t, u1.Email, obj["email"])
+	require.Equal(t, "2021-03-20T12:30:00Z", obj["created_at"])
+	require.Nil(t, obj["updated_at"])
+	require.Equal(t, "CZ", obj["country"])
+	require.Equal(t, float64(1), obj["state"])
+	require.Nil(t, obj["avatar_key"])
+	require.NotContains(t, obj, "hashed_password")
+	require.Len(t, obj, len(export.Columns()))
+}
+
+func TestEncoder_JSONL_Order(t *testing.T) {
+	t.Parallel()
+
+	u := randomRepoUser()
+
+	var buf bytes.Buffer
+	enc, err := export.NewEncoder(export.JSONL, &buf, []string{"last_name", "id", "email"})
+	require.NoError(t, err)
+	require.NoError(t, enc.Encode(u))
+	require.NoError(t, enc.Close())
+
+	// the keys follow the requested columns, not the alphabet
+	want := fmt.Sprintf(`{"last_name":%q,"id":%q,"email":%q}`+"\n", u.LastName, u.ID, u.Email)
+	require.Equal(t, want, buf.String())
+}
+
+func TestEncoder_Parquet(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	enc, err := export.NewEncoder(export.Parquet, &buf, nil)
+	require.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		require.NoError(t, enc.Encode(randomRepoUser()))
+	}
+	require.NoError(t, enc.Close())
+
+	// a parquet file starts and ends with the magic number
+	require.True(t, bytes.HasPrefix(buf.Bytes(), []byte("PAR1")))
+	require.True(t, bytes.HasSuffix(buf.Bytes(), []byte("PAR1")))
+}
@@ -0,0 +1,141 @@
+package gateway
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"path"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/chutommy/user-microservice/pkg/apierror"
+	"github.com/chutommy/user-microservice/pkg/avatar"
+	"github.com/chutommy/user-microservice/pkg/blob"
+	"github.com/chutommy/user-microservice/pkg/grpc/userpb"
+)
+
+const (
+	// AvatarUploadPath is the gateway route of the multipart avatar upload.
+	AvatarUploadPath = "/v1/user/{id}/avatar"
+
+	// AvatarPath is the gateway route serving the avatar images.
+	AvatarPath = "/v1/avatars/{user_id}/{upload_id}/{name}"
+
+	// avatarFormField is the name of the multipart field carrying the image.
+	avatarFormField = "avatar"
+
+	// avatarChunkSize is the size of the chunks streamed to the service.
+	avatarChunkSize = 32 << 10
+
+	// multipartOverhead bounds the size of the multipart headers and boundaries.
+	multipartOverhead = 64 << 10
+)
+
+// AvatarUploadHandler returns a gateway handler which streams the image of
+// the "avatar" field of a multipart form to the UploadAvatar RPC.
+func AvatarUploadHandler(client userpb.UserServiceClient) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		r.Body = http.MaxBytesReader(w, r.Body, avatar.MaxSize+multipartOverhead)
+
+		mr, err := r.MultipartReader()
+		if err != nil {
+			apierror.WriteProblem(w, r, status.Error(codes.InvalidArgument, err.Error()))
+			return
+		}
+
+		// find the image part
+		var part io.Reader
+		for part == nil {
+			p, err := mr.NextPart()
+			if errors.Is(err, io.EOF) {
+				apierror.WriteProblem(w, r, apierror.InvalidField(avatarFormField, "missing form field %s", avatarFormField))
+				return
+			}
+			if err != nil {
+				apierror.WriteProblem(w, r, status.Error(codes.InvalidArgument, err.Error()))
+				return
+			}
+
+			if p.FormName() == avatarFormField {
+				part = p
+			}
+		}
+
+		stream, err := client.UploadAvatar(r.Context())
+		if err != nil {
+			apierror.WriteProblem(w, r, err)
+			return
+		}
+
+		err = stream.Send(&userpb.UploadAvatarRequest{
+			Data: &userpb.UploadAvatarRequest_Id{Id: params["id"]},
+		})
+		buf := make([]byte, avatarChunkSize)
+		for err == nil {
+			n, rerr := part.Read(buf)
+			if n > 0 {
+				err = stream.Send(&userpb.UploadAvatarRequest{
+					Data: &userpb.UploadAvatarRequest_Chunk{Chunk: buf[:n]},
+				})
+			}
+			if errors.Is(rerr, io.EOF) {
+				break
+			}
+			if rerr != nil {
+				apierror.WriteProblem(w, r, status.Error(codes.InvalidArgument, rerr.Error()))
+				return
+			}
+		}
+		// io.EOF of Send means the service ended the stream, the status
+		// is reported by CloseAndRecv
+		if err != nil && !errors.Is(err, io.EOF) {
+			apierror.WriteProblem(w, r, err)
+			return
+		}
+
+		resp, err := stream.CloseAndRecv()
+		if err != nil {
+			apierror.WriteProblem(w, r, err)
+			return
+		}
+
+		data, err := protojson.Marshal(resp)
+		if err != nil {
+			apierror.WriteProblem(w, r, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(data)
+	}
+}
+
+// AvatarHandler returns a gateway handler serving the avatar images from the blob store.
+func AvatarHandler(blobs blob.Store) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		key := path.Join(avatar.KeyPrefix, params["user_id"], params["upload_id"], params["name"])
+
+		rc, err := blobs.Get(r.Context(), key)
+		if err != nil {
+			if errors.Is(err, blob.ErrNotFound) || errors.Is(err, blob.ErrInvalidKey) {
+				apierror.WriteProblem(w, r, status.Error(codes.NotFound, "avatar not found"))
+				return
+			}
+
+			apierror.WriteProblem(w, r, status.Error(codes.Internal, "failed to retrieve avatar"))
+			return
+		}
+		defer rc.Close()
+
+		contentType := "image/jpeg"
+		if path.Ext(key) == ".png" {
+			contentType = "image/png"
+		}
+		w.Header().Set("Content-Type", contentType)
+		// every upload is stored under new keys
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		_, _ = io.Copy(w, rc)
+	}
+}
@@ -0,0 +1,218 @@
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// dateName is the full name of the google.type.Date message.
+const dateName protoreflect.FullName = "google.type.Date"
+
+// isoDate matches the ISO 8601 calendar dates.
+var isoDate = regexp.MustCompile(`^(\d{4})-(\d{2})-(\d{2})$`)
+
+// DateMarshaler is a JSON marshaler of the gateway which represents the
+// google.type.Date fields as ISO 8601 calendar dates, e.g. "1990-04-16".
+// The object form of the dates is still accepted. Messages without any date
+// field are passed to the embedded marshaler unchanged.
+type DateMarshaler struct {
+	runtime.JSONPb
+}
+
+func (m *DateMarshaler) Marshal(v interface{}) ([]byte, error) {
+	data, err := m.JSONPb.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, ok := v.(proto.Message)
+	if !ok || !hasDate(msg.ProtoReflect().Descriptor(), nil) {
+		return data, nil
+	}
+
+	return rewriteDates(data, msg.ProtoReflect().Descriptor(), dateToISO)
+}
+
+func (m *DateMarshaler) Unmarshal(data []byte, v interface{}) error {
+	msg, ok := v.(proto.Message)
+	if ok && hasDate(msg.ProtoReflect().Descriptor(), nil) {
+		var err error
+		data, err = rewriteDates(data, msg.ProtoReflect().Descriptor(), isoToDate)
+		if err != nil {
+			return err
+		}
+	}
+
+	return m.JSONPb.Unmarshal(data, v)
+}
+
+func (m *DateMarshaler) NewDecoder(r io.Reader) runtime.Decoder {
+	return runtime.DecoderFunc(func(v interface{}) error {
+		data, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+
+		return m.Unmarshal(data, v)
+	})
+}
+
+func (m *DateMarshaler) NewEncoder(w io.Writer) runtime.Encoder {
+	return runtime.EncoderFunc(func(v interface{}) error {
+		data, err := m.Marshal(v)
+		if err != nil {
+			return err
+		}
+
+		_, err = w.Write(data)
+		return err
+	})
+}
+
+// hasDate reports whether the message has a date field at any depth.
+func hasDate(md protoreflect.MessageDescriptor, visited map[protoreflect.FullName]bool) bool {
+	if md.FullName() == dateName {
+		return true
+	}
+	if visited == nil {
+		visited = make(map[protoreflect.FullName]bool)
+	}
+	if visited[md.FullName()] {
+		return false
+	}
+	visited[md.FullName()] = true
+
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		if sub := fieldMessage(fields.Get(i)); sub != nil && hasDate(sub, visited) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fieldMessage returns the message type of the field, or of the values of a map field.
+func fieldMessage(fd protoreflect.FieldDescriptor) protoreflect.MessageDescriptor {
+	if fd.IsMap() {
+		return fd.MapValue().Message()
+	}
+
+	return fd.Message()
+}
+
+// rewriteDates applies the conversion to the JSON values of all date fields of the message.
+func rewriteDates(data []byte, md protoreflect.MessageDescriptor, conv func(interface{}) interface{}) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var tree interface{}
+	if err := dec.Decode(&tree); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(walkDates(tree, md, conv))
+}
+
+func walkDates(v interface{}, md protoreflect.MessageDescriptor, conv func(interface{}) interface{}) interface{} {
+	if md.FullName() == dateName {
+		return conv(v)
+	}
+
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		sub := fieldMessage(fd)
+		if sub == nil {
+			continue
+		}
+
+		// both the JSON and the proto names are accepted
+		for _, key := range []string{fd.JSONName(), string(fd.Name())} {
+			val, ok := obj[key]
+			if !ok {
+				continue
+			}
+
+			switch {
+			case fd.IsMap():
+				if m, ok := val.(map[string]interface{}); ok {
+					for k, item := range m {
+						m[k] = walkDates(item, sub, conv)
+					}
+				}
+			case fd.IsList():
+				if l, ok := val.([]interface{}); ok {
+					for j, item := range l {
+						l[j] = walkDates(item, sub, conv)
+					}
+				}
+			default:
+				obj[key] = walkDates(val, sub, conv)
+			}
+		}
+	}
+
+	return obj
+}
+
+// dateToISO formats a complete date object as an ISO 8601 date. Partial
+// dates are kept as objects.
+func dateToISO(v interface{}) interface{} {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+
+	var parts [3]int64
+	for i, key := range []string{"year", "month", "day"} {
+		n, ok := obj[key].(json.Number)
+		if !ok {
+			return v
+		}
+		p, err := n.Int64()
+		if err != nil || p == 0 {
+			return v
+		}
+		parts[i] = p
+	}
+
+	return fmt.Sprintf("%04d-%02d-%02d", parts[0], parts[1], parts[2])
+}
+
+// isoToDate converts an ISO 8601 date into a date object. The calendar
+// validity is checked by the service; other strings are left to fail
+// the decoding.
+func isoToDate(v interface{}) interface{} {
+	s, ok := v.(string)
+	if !ok {
+		return v
+	}
+
+	match := isoDate.FindStringSubmatch(s)
+	if match == nil {
+		return v
+	}
+
+	obj := make(map[string]interface{}, 3)
+	for i, key := range []string{"year", "month", "day"} {
+		n, _ := strconv.Atoi(match[i+1])
+		obj[key] = n
+	}
+
+	return obj
+}
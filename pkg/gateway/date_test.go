@@ -0,0 +1,94 @@
+package gateway_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/type/date"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/chutommy/user-microservice/pkg/gateway"
+	"github.com/chutommy/user-microservice/pkg/grpc/userpb"
+)
+
+func newDateMarshaler() *gateway.DateMarshaler {
+	return &gateway.DateMarshaler{
+		JSONPb: runtime.JSONPb{
+			MarshalOptions:   protojson.MarshalOptions{EmitUnpopulated: true},
+			UnmarshalOptions: protojson.UnmarshalOptions{DiscardUnknown: true},
+		},
+	}
+}
+
+func TestDateMarshaler_Marshal(t *testing.T) {
+	t.Parallel()
+
+	m := newDateMarshaler()
+
+	data, err := m.Marshal(&userpb.GetUserResponse{
+		User: &userpb.User{
+			Id:        "42",
+			BirthDate: &date.Date{Year: 1990, Month: 4, Day: 16},
+		},
+	})
+	require.NoError(t, err)
+
+	var out struct {
+		User struct {
+			ID        string `json:"id"`
+			BirthDate string `json:"birthDate"`
+		} `json:"user"`
+	}
+	require.NoError(t, json.Unmarshal(data, &out))
+	require.Equal(t, "42", out.User.ID)
+	require.Equal(t, "1990-04-16", out.User.BirthDate)
+
+	// partial dates are kept as objects
+	data, err = m.Marshal(&userpb.User{BirthDate: &date.Date{Month: 4, Day: 16}})
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"birthDate":{"day":16,"month":4,"year":0}`)
+
+	// unset dates stay null
+	data, err = m.Marshal(&userpb.User{})
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"birthDate":null`)
+}
+
+func TestDateMarshaler_Unmarshal(t *testing.T) {
+	t.Parallel()
+
+	m := newDateMarshaler()
+
+	tests := []struct {
+		name   string
+		body   string
+		expErr bool
+		exp    *date.Date
+	}{
+		{"iso", `{"user": {"email": "a@example.com", "birthDate": "1990-04-16"}}`, false, &date.Date{Year: 1990, Month: 4, Day: 16}},
+		{"proto name", `{"user": {"birth_date": "1990-04-16"}}`, false, &date.Date{Year: 1990, Month: 4, Day: 16}},
+		{"object", `{"user": {"birthDate": {"year": 1990, "month": 4, "day": 16}}}`, false, &date.Date{Year: 1990, Month: 4, Day: 16}},
+		{"impossible day is left to the service", `{"user": {"birthDate": "1990-02-31"}}`, false, &date.Date{Year: 1990, Month: 2, Day: 31}},
+		{"unset", `{"user": {"email": "a@example.com"}}`, false, nil},
+		{"invalid format", `{"user": {"birthDate": "16/04/1990"}}`, true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			var req userpb.RegisterUserRequest
+			err := m.NewDecoder(bytes.NewBufferString(tt.body)).Decode(&req)
+			if tt.expErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.True(t, proto.Equal(tt.exp, req.GetUser().GetBirthDate()), "got %v", req.GetUser().GetBirthDate())
+		})
+	}
+}
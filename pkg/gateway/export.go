@@ -0,0 +1,140 @@
+package gateway
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/chutommy/user-microservice/pkg/apierror"
+	"github.com/chutommy/user-microservice/pkg/grpc/userpb"
+)
+
+// ExportPath is the gateway route of the users export download.
+const ExportPath = "/v1/user/export"
+
+// exportContentTypes maps the export formats to the MIME types and file extensions.
+var exportContentTypes = map[userpb.ExportUsersRequest_Format][2]string{
+	userpb.ExportUsersRequest_CSV:     {"text/csv", "csv"},
+	userpb.ExportUsersRequest_JSONL:   {"application/x-ndjson", "jsonl"},
+	userpb.ExportUsersRequest_PARQUET: {"application/vnd.apache.parquet", "parquet"},
+}
+
+// attributeParamPrefix prefixes the query parameters of the attribute filters.
+const attributeParamPrefix = "attribute."
+
+// parseExportRequest builds the export request from the query parameters:
+// format, columns (comma separated), created_after, created_before (RFC 3339),
+// gender and attribute.<path> for the string attribute filters.
+func parseExportRequest(r *http.Request) (*userpb.ExportUsersRequest, error) {
+	q := r.URL.Query()
+	req := &userpb.ExportUsersRequest{}
+
+	if f := q.Get("format"); f != "" {
+		v, ok := userpb.ExportUsersRequest_Format_value[strings.ToUpper(f)]
+		if !ok {
+			return nil, fmt.Errorf("unsupported format %q", f)
+		}
+		req.Format = userpb.ExportUsersRequest_Format(v)
+	}
+
+	for _, c := range q["columns"] {
+		for _, name := range strings.Split(c, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				req.Columns = append(req.Columns, name)
+			}
+		}
+	}
+
+	for key, dst := range map[string]**timestamppb.Timestamp{
+		"created_after":  &req.CreatedAfter,
+		"created_before": &req.CreatedBefore,
+	} {
+		if v := q.Get(key); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", key, err)
+			}
+			*dst = timestamppb.New(t)
+		}
+	}
+
+	if g := q.Get("gender"); g != "" {
+		v, ok := userpb.User_Gender_value[strings.ToUpper(g)]
+		if !ok {
+			return nil, fmt.Errorf("unsupported gender %q", g)
+		}
+		req.Gender = userpb.User_Gender(v)
+	}
+
+	for key := range q {
+		if path := strings.TrimPrefix(key, attributeParamPrefix); path != key {
+			if req.AttributeFilters == nil {
+				req.AttributeFilters = map[string]*structpb.Value{}
+			}
+			req.AttributeFilters[path] = structpb.NewStringValue(q.Get(key))
+		}
+	}
+
+	return req, nil
+}
+
+// ExportHandler returns a gateway handler which streams the users export
+// as a file download.
+func ExportHandler(client userpb.UserServiceClient) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		req, err := parseExportRequest(r)
+		if err != nil {
+			apierror.WriteProblem(w, r, status.Error(codes.InvalidArgument, err.Error()))
+			return
+		}
+
+		stream, err := client.ExportUsers(r.Context(), req)
+		if err != nil {
+			apierror.WriteProblem(w, r, err)
+			return
+		}
+
+		// the first message reports errors of an invalid request
+		first, err := stream.Recv()
+		if err != nil && !errors.Is(err, io.EOF) {
+			apierror.WriteProblem(w, r, err)
+			return
+		}
+
+		ct := exportContentTypes[req.GetFormat()]
+		w.Header().Set("Content-Type", ct[0])
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="users.%s"`, ct[1]))
+		w.WriteHeader(http.StatusOK)
+
+		if first == nil {
+			return
+		}
+		if _, err = w.Write(first.GetChunk()); err != nil {
+			return
+		}
+
+		for {
+			resp, err := stream.Recv()
+			if err != nil {
+				// the response is already committed, the client sees a truncated body
+				return
+			}
+
+			if _, err = w.Write(resp.GetChunk()); err != nil {
+				return
+			}
+			if f, ok := w.(http.Flusher); ok {
+				f.Flush()
+			}
+		}
+	}
+}
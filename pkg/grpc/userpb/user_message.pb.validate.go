@@ -0,0 +1,1558 @@
+// Code generated by protoc-gen-validate. DO NOT EDIT.
+// source: user_message.proto
+
+package userpb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// ensure the imports are used
+var (
+	_ = bytes.MinRead
+	_ = errors.New("")
+	_ = fmt.Print
+	_ = utf8.UTFMax
+	_ = (*regexp.Regexp)(nil)
+	_ = (*strings.Reader)(nil)
+	_ = net.IPv4len
+	_ = time.Duration(0)
+	_ = (*url.URL)(nil)
+	_ = (*mail.Address)(nil)
+	_ = anypb.Any{}
+	_ = sort.Sort
+)
+
+// Validate checks the field values on User with the rules defined in the proto
+// definition for this message. If any rules are violated, the first error
+// encountered is returned, or nil if there are no violations.
+func (m *User) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on User with the rules defined in the
+// proto definition for this message. If any rules are violated, the result is
+// a list of violation errors wrapped in UserMultiError, or nil if none found.
+func (m *User) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *User) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Id
+
+	if utf8.RuneCountInString(m.GetEmail()) > 254 {
+		err := UserValidationError{
+			field:  "Email",
+			reason: "value length must be at most 254 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetPhone()) > 32 {
+		err := UserValidationError{
+			field:  "Phone",
+			reason: "value length must be at most 32 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(m.GetPassword()) > 72 {
+		err := UserValidationError{
+			field:  "Password",
+			reason: "value length must be at most 72 bytes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetFirstName()) > 64 {
+		err := UserValidationError{
+			field:  "FirstName",
+			reason: "value length must be at most 64 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetLastName()) > 64 {
+		err := UserValidationError{
+			field:  "LastName",
+			reason: "value length must be at most 64 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if _, ok := User_Gender_name[int32(m.GetGender())]; !ok {
+		err := UserValidationError{
+			field:  "Gender",
+			reason: "value must be one of the defined enum values",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for Birthday
+
+	if all {
+		switch v := interface{}(m.GetAttributes()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, UserValidationError{
+					field:  "Attributes",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, UserValidationError{
+					field:  "Attributes",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetAttributes()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return UserValidationError{
+				field:  "Attributes",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if all {
+		switch v := interface{}(m.GetAvatar()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, UserValidationError{
+					field:  "Avatar",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, UserValidationError{
+					field:  "Avatar",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetAvatar()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return UserValidationError{
+				field:  "Avatar",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for PhoneCountry
+
+	if all {
+		switch v := interface{}(m.GetBirthDate()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, UserValidationError{
+					field:  "BirthDate",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, UserValidationError{
+					field:  "BirthDate",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetBirthDate()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return UserValidationError{
+				field:  "BirthDate",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if m.GetCountry() != "" {
+
+		if utf8.RuneCountInString(m.GetCountry()) != 2 {
+			err := UserValidationError{
+				field:  "Country",
+				reason: "value length must be 2 runes",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+
+		}
+
+	}
+
+	// no validation rules for State
+
+	if len(errors) > 0 {
+		return UserMultiError(errors)
+	}
+	return nil
+}
+
+// UserMultiError is an error wrapping multiple validation errors returned by
+// User.ValidateAll() if the designated constraints aren't met.
+type UserMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m UserMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m UserMultiError) AllErrors() []error { return m }
+
+// UserValidationError is the validation error returned by User.Validate if the
+// designated constraints aren't met.
+type UserValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e UserValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e UserValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e UserValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e UserValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e UserValidationError) ErrorName() string { return "UserValidationError" }
+
+// Error satisfies the builtin error interface
+func (e UserValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sUser.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = UserValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = UserValidationError{}
+
+// Validate checks the field values on Avatar with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *Avatar) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on Avatar with the rules defined in the
+// proto definition for this message. If any rules are violated, the result is
+// a list of violation errors wrapped in AvatarMultiError, or nil if none found.
+func (m *Avatar) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *Avatar) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Url
+
+	// no validation rules for ThumbnailUrls
+
+	if len(errors) > 0 {
+		return AvatarMultiError(errors)
+	}
+	return nil
+}
+
+// AvatarMultiError is an error wrapping multiple validation errors returned by
+// Avatar.ValidateAll() if the designated constraints aren't met.
+type AvatarMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m AvatarMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m AvatarMultiError) AllErrors() []error { return m }
+
+// AvatarValidationError is the validation error returned by Avatar.Validate if
+// the designated constraints aren't met.
+type AvatarValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e AvatarValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e AvatarValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e AvatarValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e AvatarValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e AvatarValidationError) ErrorName() string { return "AvatarValidationError" }
+
+// Error satisfies the builtin error interface
+func (e AvatarValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sAvatar.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = AvatarValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = AvatarValidationError{}
+
+// Validate checks the field values on DataExport with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *DataExport) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on DataExport with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in DataExportMultiError, or
+// nil if none found.
+func (m *DataExport) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *DataExport) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Id
+
+	// no validation rules for UserId
+
+	// no validation rules for Status
+
+	// no validation rules for Signature
+
+	if all {
+		switch v := interface{}(m.GetCreatedAt()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, DataExportValidationError{
+					field:  "CreatedAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, DataExportValidationError{
+					field:  "CreatedAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetCreatedAt()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return DataExportValidationError{
+				field:  "CreatedAt",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if all {
+		switch v := interface{}(m.GetCompletedAt()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, DataExportValidationError{
+					field:  "CompletedAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, DataExportValidationError{
+					field:  "CompletedAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetCompletedAt()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return DataExportValidationError{
+				field:  "CompletedAt",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return DataExportMultiError(errors)
+	}
+	return nil
+}
+
+// DataExportMultiError is an error wrapping multiple validation errors
+// returned by DataExport.ValidateAll() if the designated constraints aren't met.
+type DataExportMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m DataExportMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m DataExportMultiError) AllErrors() []error { return m }
+
+// DataExportValidationError is the validation error returned by
+// DataExport.Validate if the designated constraints aren't met.
+type DataExportValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e DataExportValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e DataExportValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e DataExportValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e DataExportValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e DataExportValidationError) ErrorName() string { return "DataExportValidationError" }
+
+// Error satisfies the builtin error interface
+func (e DataExportValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sDataExport.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = DataExportValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = DataExportValidationError{}
+
+// Validate checks the field values on PolicyVersion with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *PolicyVersion) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on PolicyVersion with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in PolicyVersionMultiError, or
+// nil if none found.
+func (m *PolicyVersion) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *PolicyVersion) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if l := utf8.RuneCountInString(m.GetKind()); l < 1 || l > 32 {
+		err := PolicyVersionValidationError{
+			field:  "Kind",
+			reason: "value length must be between 1 and 32 runes, inclusive",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if m.GetVersion() <= 0 {
+		err := PolicyVersionValidationError{
+			field:  "Version",
+			reason: "value must be greater than 0",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return PolicyVersionMultiError(errors)
+	}
+	return nil
+}
+
+// PolicyVersionMultiError is an error wrapping multiple validation errors
+// returned by PolicyVersion.ValidateAll() if the designated constraints
+// aren't met.
+type PolicyVersionMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m PolicyVersionMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m PolicyVersionMultiError) AllErrors() []error { return m }
+
+// PolicyVersionValidationError is the validation error returned by
+// PolicyVersion.Validate if the designated constraints aren't met.
+type PolicyVersionValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e PolicyVersionValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e PolicyVersionValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e PolicyVersionValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e PolicyVersionValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e PolicyVersionValidationError) ErrorName() string { return "PolicyVersionValidationError" }
+
+// Error satisfies the builtin error interface
+func (e PolicyVersionValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sPolicyVersion.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = PolicyVersionValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = PolicyVersionValidationError{}
+
+// Validate checks the field values on ConsentPolicy with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *ConsentPolicy) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ConsentPolicy with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in ConsentPolicyMultiError, or
+// nil if none found.
+func (m *ConsentPolicy) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ConsentPolicy) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if all {
+		switch v := interface{}(m.GetPolicy()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, ConsentPolicyValidationError{
+					field:  "Policy",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, ConsentPolicyValidationError{
+					field:  "Policy",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetPolicy()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ConsentPolicyValidationError{
+				field:  "Policy",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for Required
+
+	if all {
+		switch v := interface{}(m.GetPublishedAt()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, ConsentPolicyValidationError{
+					field:  "PublishedAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, ConsentPolicyValidationError{
+					field:  "PublishedAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetPublishedAt()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ConsentPolicyValidationError{
+				field:  "PublishedAt",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return ConsentPolicyMultiError(errors)
+	}
+	return nil
+}
+
+// ConsentPolicyMultiError is an error wrapping multiple validation errors
+// returned by ConsentPolicy.ValidateAll() if the designated constraints
+// aren't met.
+type ConsentPolicyMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ConsentPolicyMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ConsentPolicyMultiError) AllErrors() []error { return m }
+
+// ConsentPolicyValidationError is the validation error returned by
+// ConsentPolicy.Validate if the designated constraints aren't met.
+type ConsentPolicyValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ConsentPolicyValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ConsentPolicyValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ConsentPolicyValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ConsentPolicyValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ConsentPolicyValidationError) ErrorName() string { return "ConsentPolicyValidationError" }
+
+// Error satisfies the builtin error interface
+func (e ConsentPolicyValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sConsentPolicy.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ConsentPolicyValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ConsentPolicyValidationError{}
+
+// Validate checks the field values on Consent with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *Consent) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on Consent with the rules defined in the
+// proto definition for this message. If any rules are violated, the result is
+// a list of violation errors wrapped in ConsentMultiError, or nil if none found.
+func (m *Consent) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *Consent) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if all {
+		switch v := interface{}(m.GetPolicy()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, ConsentValidationError{
+					field:  "Policy",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, ConsentValidationError{
+					field:  "Policy",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetPolicy()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ConsentValidationError{
+				field:  "Policy",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if all {
+		switch v := interface{}(m.GetAcceptedAt()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, ConsentValidationError{
+					field:  "AcceptedAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, ConsentValidationError{
+					field:  "AcceptedAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetAcceptedAt()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ConsentValidationError{
+				field:  "AcceptedAt",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if all {
+		switch v := interface{}(m.GetWithdrawnAt()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, ConsentValidationError{
+					field:  "WithdrawnAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, ConsentValidationError{
+					field:  "WithdrawnAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetWithdrawnAt()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ConsentValidationError{
+				field:  "WithdrawnAt",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return ConsentMultiError(errors)
+	}
+	return nil
+}
+
+// ConsentMultiError is an error wrapping multiple validation errors returned
+// by Consent.ValidateAll() if the designated constraints aren't met.
+type ConsentMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ConsentMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ConsentMultiError) AllErrors() []error { return m }
+
+// ConsentValidationError is the validation error returned by Consent.Validate
+// if the designated constraints aren't met.
+type ConsentValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ConsentValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ConsentValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ConsentValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ConsentValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ConsentValidationError) ErrorName() string { return "ConsentValidationError" }
+
+// Error satisfies the builtin error interface
+func (e ConsentValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sConsent.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ConsentValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ConsentValidationError{}
+
+// Validate checks the field values on Preference with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *Preference) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on Preference with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in PreferenceMultiError, or
+// nil if none found.
+func (m *Preference) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *Preference) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if !_Preference_Namespace_Pattern.MatchString(m.GetNamespace()) {
+		err := PreferenceValidationError{
+			field:  "Namespace",
+			reason: "value does not match regex pattern \"^[a-z][a-z0-9_.-]{0,63}$\"",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if !_Preference_Key_Pattern.MatchString(m.GetKey()) {
+		err := PreferenceValidationError{
+			field:  "Key",
+			reason: "value does not match regex pattern \"^[a-z][a-z0-9_.-]{0,63}$\"",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for Default
+
+	switch m.Value.(type) {
+
+	case *Preference_StringValue:
+		// no validation rules for StringValue
+
+	case *Preference_BoolValue:
+		// no validation rules for BoolValue
+
+	case *Preference_IntValue:
+		// no validation rules for IntValue
+
+	case *Preference_JsonValue:
+		// no validation rules for JsonValue
+
+	}
+
+	if len(errors) > 0 {
+		return PreferenceMultiError(errors)
+	}
+	return nil
+}
+
+// PreferenceMultiError is an error wrapping multiple validation errors
+// returned by Preference.ValidateAll() if the designated constraints aren't met.
+type PreferenceMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m PreferenceMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m PreferenceMultiError) AllErrors() []error { return m }
+
+// PreferenceValidationError is the validation error returned by
+// Preference.Validate if the designated constraints aren't met.
+type PreferenceValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e PreferenceValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e PreferenceValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e PreferenceValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e PreferenceValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e PreferenceValidationError) ErrorName() string { return "PreferenceValidationError" }
+
+// Error satisfies the builtin error interface
+func (e PreferenceValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sPreference.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = PreferenceValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = PreferenceValidationError{}
+
+var _Preference_Namespace_Pattern = regexp.MustCompile("^[a-z][a-z0-9_.-]{0,63}$")
+
+var _Preference_Key_Pattern = regexp.MustCompile("^[a-z][a-z0-9_.-]{0,63}$")
+
+// Validate checks the field values on Address with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *Address) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on Address with the rules defined in the
+// proto definition for this message. If any rules are violated, the result is
+// a list of violation errors wrapped in AddressMultiError, or nil if none found.
+func (m *Address) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *Address) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Id
+
+	// no validation rules for UserId
+
+	// no validation rules for Kind
+
+	// no validation rules for Default
+
+	if utf8.RuneCountInString(m.GetRecipient()) > 128 {
+		err := AddressValidationError{
+			field:  "Recipient",
+			reason: "value length must be at most 128 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetLine1()) > 128 {
+		err := AddressValidationError{
+			field:  "Line1",
+			reason: "value length must be at most 128 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetLine2()) > 128 {
+		err := AddressValidationError{
+			field:  "Line2",
+			reason: "value length must be at most 128 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetCity()) > 64 {
+		err := AddressValidationError{
+			field:  "City",
+			reason: "value length must be at most 64 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetRegion()) > 64 {
+		err := AddressValidationError{
+			field:  "Region",
+			reason: "value length must be at most 64 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetPostalCode()) > 16 {
+		err := AddressValidationError{
+			field:  "PostalCode",
+			reason: "value length must be at most 16 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if m.GetCountryCode() != "" {
+
+		if utf8.RuneCountInString(m.GetCountryCode()) != 2 {
+			err := AddressValidationError{
+				field:  "CountryCode",
+				reason: "value length must be 2 runes",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+
+		}
+
+	}
+
+	if all {
+		switch v := interface{}(m.GetUpdatedAt()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, AddressValidationError{
+					field:  "UpdatedAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, AddressValidationError{
+					field:  "UpdatedAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetUpdatedAt()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return AddressValidationError{
+				field:  "UpdatedAt",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if all {
+		switch v := interface{}(m.GetCreatedAt()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, AddressValidationError{
+					field:  "CreatedAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, AddressValidationError{
+					field:  "CreatedAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetCreatedAt()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return AddressValidationError{
+				field:  "CreatedAt",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return AddressMultiError(errors)
+	}
+	return nil
+}
+
+// AddressMultiError is an error wrapping multiple validation errors returned
+// by Address.ValidateAll() if the designated constraints aren't met.
+type AddressMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m AddressMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m AddressMultiError) AllErrors() []error { return m }
+
+// AddressValidationError is the validation error returned by Address.Validate
+// if the designated constraints aren't met.
+type AddressValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e AddressValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e AddressValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e AddressValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e AddressValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e AddressValidationError) ErrorName() string { return "AddressValidationError" }
+
+// Error satisfies the builtin error interface
+func (e AddressValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sAddress.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = AddressValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = AddressValidationError{}
+
+// Validate checks the field values on Email with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *Email) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on Email with the rules defined in the
+// proto definition for this message. If any rules are violated, the result is
+// a list of violation errors wrapped in EmailMultiError, or nil if none found.
+func (m *Email) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *Email) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Email
+
+	// no validation rules for Verified
+
+	// no validation rules for Primary
+
+	if all {
+		switch v := interface{}(m.GetCreatedAt()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, EmailValidationError{
+					field:  "CreatedAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, EmailValidationError{
+					field:  "CreatedAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetCreatedAt()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return EmailValidationError{
+				field:  "CreatedAt",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return EmailMultiError(errors)
+	}
+	return nil
+}
+
+// EmailMultiError is an error wrapping multiple validation errors returned by
+// Email.ValidateAll() if the designated constraints aren't met.
+type EmailMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m EmailMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m EmailMultiError) AllErrors() []error { return m }
+
+// EmailValidationError is the validation error returned by Email.Validate if
+// the designated constraints aren't met.
+type EmailValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e EmailValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e EmailValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e EmailValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e EmailValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e EmailValidationError) ErrorName() string { return "EmailValidationError" }
+
+// Error satisfies the builtin error interface
+func (e EmailValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sEmail.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = EmailValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = EmailValidationError{}
@@ -0,0 +1,6979 @@
+// Code generated by protoc-gen-validate. DO NOT EDIT.
+// source: user_service.proto
+
+package userpb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// ensure the imports are used
+var (
+	_ = bytes.MinRead
+	_ = errors.New("")
+	_ = fmt.Print
+	_ = utf8.UTFMax
+	_ = (*regexp.Regexp)(nil)
+	_ = (*strings.Reader)(nil)
+	_ = net.IPv4len
+	_ = time.Duration(0)
+	_ = (*url.URL)(nil)
+	_ = (*mail.Address)(nil)
+	_ = anypb.Any{}
+	_ = sort.Sort
+)
+
+// define the regex for a UUID once up-front
+var _user_service_uuidPattern = regexp.MustCompile("^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$")
+
+// Validate checks the field values on RegisterUserRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *RegisterUserRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on RegisterUserRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// RegisterUserRequestMultiError, or nil if none found.
+func (m *RegisterUserRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *RegisterUserRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if m.GetUser() == nil {
+		err := RegisterUserRequestValidationError{
+			field:  "User",
+			reason: "value is required",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if all {
+		switch v := interface{}(m.GetUser()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, RegisterUserRequestValidationError{
+					field:  "User",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, RegisterUserRequestValidationError{
+					field:  "User",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetUser()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return RegisterUserRequestValidationError{
+				field:  "User",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	for idx, item := range m.GetAcceptedPolicies() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, RegisterUserRequestValidationError{
+						field:  fmt.Sprintf("AcceptedPolicies[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, RegisterUserRequestValidationError{
+						field:  fmt.Sprintf("AcceptedPolicies[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return RegisterUserRequestValidationError{
+					field:  fmt.Sprintf("AcceptedPolicies[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if m.GetPhoneRegion() != "" {
+
+		if utf8.RuneCountInString(m.GetPhoneRegion()) != 2 {
+			err := RegisterUserRequestValidationError{
+				field:  "PhoneRegion",
+				reason: "value length must be 2 runes",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return RegisterUserRequestMultiError(errors)
+	}
+	return nil
+}
+
+// RegisterUserRequestMultiError is an error wrapping multiple validation
+// errors returned by RegisterUserRequest.ValidateAll() if the designated
+// constraints aren't met.
+type RegisterUserRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m RegisterUserRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m RegisterUserRequestMultiError) AllErrors() []error { return m }
+
+// RegisterUserRequestValidationError is the validation error returned by
+// RegisterUserRequest.Validate if the designated constraints aren't met.
+type RegisterUserRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e RegisterUserRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e RegisterUserRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e RegisterUserRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e RegisterUserRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e RegisterUserRequestValidationError) ErrorName() string {
+	return "RegisterUserRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e RegisterUserRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sRegisterUserRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = RegisterUserRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = RegisterUserRequestValidationError{}
+
+// Validate checks the field values on RegisterUserResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *RegisterUserResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on RegisterUserResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// RegisterUserResponseMultiError, or nil if none found.
+func (m *RegisterUserResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *RegisterUserResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Id
+
+	// no validation rules for State
+
+	if len(errors) > 0 {
+		return RegisterUserResponseMultiError(errors)
+	}
+	return nil
+}
+
+// RegisterUserResponseMultiError is an error wrapping multiple validation
+// errors returned by RegisterUserResponse.ValidateAll() if the designated
+// constraints aren't met.
+type RegisterUserResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m RegisterUserResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m RegisterUserResponseMultiError) AllErrors() []error { return m }
+
+// RegisterUserResponseValidationError is the validation error returned by
+// RegisterUserResponse.Validate if the designated constraints aren't met.
+type RegisterUserResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e RegisterUserResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e RegisterUserResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e RegisterUserResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e RegisterUserResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e RegisterUserResponseValidationError) ErrorName() string {
+	return "RegisterUserResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e RegisterUserResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sRegisterUserResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = RegisterUserResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = RegisterUserResponseValidationError{}
+
+// Validate checks the field values on GetUserRequest with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *GetUserRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on GetUserRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in GetUserRequestMultiError,
+// or nil if none found.
+func (m *GetUserRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *GetUserRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if m.GetId() != "" {
+
+		if err := m._validateUuid(m.GetId()); err != nil {
+			err = GetUserRequestValidationError{
+				field:  "Id",
+				reason: "value must be a valid UUID",
+				cause:  err,
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	}
+
+	if utf8.RuneCountInString(m.GetEmail()) > 254 {
+		err := GetUserRequestValidationError{
+			field:  "Email",
+			reason: "value length must be at most 254 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return GetUserRequestMultiError(errors)
+	}
+	return nil
+}
+
+func (m *GetUserRequest) _validateUuid(uuid string) error {
+	if matched := _user_service_uuidPattern.MatchString(uuid); !matched {
+		return errors.New("invalid uuid format")
+	}
+
+	return nil
+}
+
+// GetUserRequestMultiError is an error wrapping multiple validation errors
+// returned by GetUserRequest.ValidateAll() if the designated constraints
+// aren't met.
+type GetUserRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m GetUserRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m GetUserRequestMultiError) AllErrors() []error { return m }
+
+// GetUserRequestValidationError is the validation error returned by
+// GetUserRequest.Validate if the designated constraints aren't met.
+type GetUserRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e GetUserRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e GetUserRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e GetUserRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e GetUserRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e GetUserRequestValidationError) ErrorName() string { return "GetUserRequestValidationError" }
+
+// Error satisfies the builtin error interface
+func (e GetUserRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sGetUserRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = GetUserRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = GetUserRequestValidationError{}
+
+// Validate checks the field values on GetUserResponse with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *GetUserResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on GetUserResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// GetUserResponseMultiError, or nil if none found.
+func (m *GetUserResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *GetUserResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if all {
+		switch v := interface{}(m.GetUser()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, GetUserResponseValidationError{
+					field:  "User",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, GetUserResponseValidationError{
+					field:  "User",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetUser()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return GetUserResponseValidationError{
+				field:  "User",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return GetUserResponseMultiError(errors)
+	}
+	return nil
+}
+
+// GetUserResponseMultiError is an error wrapping multiple validation errors
+// returned by GetUserResponse.ValidateAll() if the designated constraints
+// aren't met.
+type GetUserResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m GetUserResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m GetUserResponseMultiError) AllErrors() []error { return m }
+
+// GetUserResponseValidationError is the validation error returned by
+// GetUserResponse.Validate if the designated constraints aren't met.
+type GetUserResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e GetUserResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e GetUserResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e GetUserResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e GetUserResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e GetUserResponseValidationError) ErrorName() string { return "GetUserResponseValidationError" }
+
+// Error satisfies the builtin error interface
+func (e GetUserResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sGetUserResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = GetUserResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = GetUserResponseValidationError{}
+
+// Validate checks the field values on UpdateUserRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *UpdateUserRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on UpdateUserRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// UpdateUserRequestMultiError, or nil if none found.
+func (m *UpdateUserRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *UpdateUserRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if err := m._validateUuid(m.GetId()); err != nil {
+		err = UpdateUserRequestValidationError{
+			field:  "Id",
+			reason: "value must be a valid UUID",
+			cause:  err,
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if all {
+		switch v := interface{}(m.GetUser()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, UpdateUserRequestValidationError{
+					field:  "User",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, UpdateUserRequestValidationError{
+					field:  "User",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetUser()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return UpdateUserRequestValidationError{
+				field:  "User",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if m.GetPhoneRegion() != "" {
+
+		if utf8.RuneCountInString(m.GetPhoneRegion()) != 2 {
+			err := UpdateUserRequestValidationError{
+				field:  "PhoneRegion",
+				reason: "value length must be 2 runes",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return UpdateUserRequestMultiError(errors)
+	}
+	return nil
+}
+
+func (m *UpdateUserRequest) _validateUuid(uuid string) error {
+	if matched := _user_service_uuidPattern.MatchString(uuid); !matched {
+		return errors.New("invalid uuid format")
+	}
+
+	return nil
+}
+
+// UpdateUserRequestMultiError is an error wrapping multiple validation errors
+// returned by UpdateUserRequest.ValidateAll() if the designated constraints
+// aren't met.
+type UpdateUserRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m UpdateUserRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m UpdateUserRequestMultiError) AllErrors() []error { return m }
+
+// UpdateUserRequestValidationError is the validation error returned by
+// UpdateUserRequest.Validate if the designated constraints aren't met.
+type UpdateUserRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e UpdateUserRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e UpdateUserRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e UpdateUserRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e UpdateUserRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e UpdateUserRequestValidationError) ErrorName() string {
+	return "UpdateUserRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e UpdateUserRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sUpdateUserRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = UpdateUserRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = UpdateUserRequestValidationError{}
+
+// Validate checks the field values on UpdateUserResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *UpdateUserResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on UpdateUserResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// UpdateUserResponseMultiError, or nil if none found.
+func (m *UpdateUserResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *UpdateUserResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Id
+
+	if len(errors) > 0 {
+		return UpdateUserResponseMultiError(errors)
+	}
+	return nil
+}
+
+// UpdateUserResponseMultiError is an error wrapping multiple validation errors
+// returned by UpdateUserResponse.ValidateAll() if the designated constraints
+// aren't met.
+type UpdateUserResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m UpdateUserResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m UpdateUserResponseMultiError) AllErrors() []error { return m }
+
+// UpdateUserResponseValidationError is the validation error returned by
+// UpdateUserResponse.Validate if the designated constraints aren't met.
+type UpdateUserResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e UpdateUserResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e UpdateUserResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e UpdateUserResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e UpdateUserResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e UpdateUserResponseValidationError) ErrorName() string {
+	return "UpdateUserResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e UpdateUserResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sUpdateUserResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = UpdateUserResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = UpdateUserResponseValidationError{}
+
+// Validate checks the field values on DeleteUserRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *DeleteUserRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on DeleteUserRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// DeleteUserRequestMultiError, or nil if none found.
+func (m *DeleteUserRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *DeleteUserRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if err := m._validateUuid(m.GetId()); err != nil {
+		err = DeleteUserRequestValidationError{
+			field:  "Id",
+			reason: "value must be a valid UUID",
+			cause:  err,
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return DeleteUserRequestMultiError(errors)
+	}
+	return nil
+}
+
+func (m *DeleteUserRequest) _validateUuid(uuid string) error {
+	if matched := _user_service_uuidPattern.MatchString(uuid); !matched {
+		return errors.New("invalid uuid format")
+	}
+
+	return nil
+}
+
+// DeleteUserRequestMultiError is an error wrapping multiple validation errors
+// returned by DeleteUserRequest.ValidateAll() if the designated constraints
+// aren't met.
+type DeleteUserRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m DeleteUserRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m DeleteUserRequestMultiError) AllErrors() []error { return m }
+
+// DeleteUserRequestValidationError is the validation error returned by
+// DeleteUserRequest.Validate if the designated constraints aren't met.
+type DeleteUserRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e DeleteUserRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e DeleteUserRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e DeleteUserRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e DeleteUserRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e DeleteUserRequestValidationError) ErrorName() string {
+	return "DeleteUserRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e DeleteUserRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sDeleteUserRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = DeleteUserRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = DeleteUserRequestValidationError{}
+
+// Validate checks the field values on DeleteUserResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *DeleteUserResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on DeleteUserResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// DeleteUserResponseMultiError, or nil if none found.
+func (m *DeleteUserResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *DeleteUserResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Id
+
+	if len(errors) > 0 {
+		return DeleteUserResponseMultiError(errors)
+	}
+	return nil
+}
+
+// DeleteUserResponseMultiError is an error wrapping multiple validation errors
+// returned by DeleteUserResponse.ValidateAll() if the designated constraints
+// aren't met.
+type DeleteUserResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m DeleteUserResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m DeleteUserResponseMultiError) AllErrors() []error { return m }
+
+// DeleteUserResponseValidationError is the validation error returned by
+// DeleteUserResponse.Validate if the designated constraints aren't met.
+type DeleteUserResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e DeleteUserResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e DeleteUserResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e DeleteUserResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e DeleteUserResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e DeleteUserResponseValidationError) ErrorName() string {
+	return "DeleteUserResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e DeleteUserResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sDeleteUserResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = DeleteUserResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = DeleteUserResponseValidationError{}
+
+// Validate checks the field values on EraseUserRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *EraseUserRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on EraseUserRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// EraseUserRequestMultiError, or nil if none found.
+func (m *EraseUserRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *EraseUserRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if err := m._validateUuid(m.GetId()); err != nil {
+		err = EraseUserRequestValidationError{
+			field:  "Id",
+			reason: "value must be a valid UUID",
+			cause:  err,
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for Reason
+
+	if len(errors) > 0 {
+		return EraseUserRequestMultiError(errors)
+	}
+	return nil
+}
+
+func (m *EraseUserRequest) _validateUuid(uuid string) error {
+	if matched := _user_service_uuidPattern.MatchString(uuid); !matched {
+		return errors.New("invalid uuid format")
+	}
+
+	return nil
+}
+
+// EraseUserRequestMultiError is an error wrapping multiple validation errors
+// returned by EraseUserRequest.ValidateAll() if the designated constraints
+// aren't met.
+type EraseUserRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m EraseUserRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m EraseUserRequestMultiError) AllErrors() []error { return m }
+
+// EraseUserRequestValidationError is the validation error returned by
+// EraseUserRequest.Validate if the designated constraints aren't met.
+type EraseUserRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e EraseUserRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e EraseUserRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e EraseUserRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e EraseUserRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e EraseUserRequestValidationError) ErrorName() string { return "EraseUserRequestValidationError" }
+
+// Error satisfies the builtin error interface
+func (e EraseUserRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sEraseUserRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = EraseUserRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = EraseUserRequestValidationError{}
+
+// Validate checks the field values on EraseUserResponse with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *EraseUserResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on EraseUserResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// EraseUserResponseMultiError, or nil if none found.
+func (m *EraseUserResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *EraseUserResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Id
+
+	// no validation rules for ReceiptId
+
+	if all {
+		switch v := interface{}(m.GetErasedAt()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, EraseUserResponseValidationError{
+					field:  "ErasedAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, EraseUserResponseValidationError{
+					field:  "ErasedAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetErasedAt()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return EraseUserResponseValidationError{
+				field:  "ErasedAt",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return EraseUserResponseMultiError(errors)
+	}
+	return nil
+}
+
+// EraseUserResponseMultiError is an error wrapping multiple validation errors
+// returned by EraseUserResponse.ValidateAll() if the designated constraints
+// aren't met.
+type EraseUserResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m EraseUserResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m EraseUserResponseMultiError) AllErrors() []error { return m }
+
+// EraseUserResponseValidationError is the validation error returned by
+// EraseUserResponse.Validate if the designated constraints aren't met.
+type EraseUserResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e EraseUserResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e EraseUserResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e EraseUserResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e EraseUserResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e EraseUserResponseValidationError) ErrorName() string {
+	return "EraseUserResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e EraseUserResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sEraseUserResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = EraseUserResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = EraseUserResponseValidationError{}
+
+// Validate checks the field values on RequestDataExportRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *RequestDataExportRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on RequestDataExportRequest with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// RequestDataExportRequestMultiError, or nil if none found.
+func (m *RequestDataExportRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *RequestDataExportRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if err := m._validateUuid(m.GetId()); err != nil {
+		err = RequestDataExportRequestValidationError{
+			field:  "Id",
+			reason: "value must be a valid UUID",
+			cause:  err,
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return RequestDataExportRequestMultiError(errors)
+	}
+	return nil
+}
+
+func (m *RequestDataExportRequest) _validateUuid(uuid string) error {
+	if matched := _user_service_uuidPattern.MatchString(uuid); !matched {
+		return errors.New("invalid uuid format")
+	}
+
+	return nil
+}
+
+// RequestDataExportRequestMultiError is an error wrapping multiple validation
+// errors returned by RequestDataExportRequest.ValidateAll() if the designated
+// constraints aren't met.
+type RequestDataExportRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m RequestDataExportRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m RequestDataExportRequestMultiError) AllErrors() []error { return m }
+
+// RequestDataExportRequestValidationError is the validation error returned by
+// RequestDataExportRequest.Validate if the designated constraints aren't met.
+type RequestDataExportRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e RequestDataExportRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e RequestDataExportRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e RequestDataExportRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e RequestDataExportRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e RequestDataExportRequestValidationError) ErrorName() string {
+	return "RequestDataExportRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e RequestDataExportRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sRequestDataExportRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = RequestDataExportRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = RequestDataExportRequestValidationError{}
+
+// Validate checks the field values on RequestDataExportResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *RequestDataExportResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on RequestDataExportResponse with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// RequestDataExportResponseMultiError, or nil if none found.
+func (m *RequestDataExportResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *RequestDataExportResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if all {
+		switch v := interface{}(m.GetExport()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, RequestDataExportResponseValidationError{
+					field:  "Export",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, RequestDataExportResponseValidationError{
+					field:  "Export",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetExport()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return RequestDataExportResponseValidationError{
+				field:  "Export",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return RequestDataExportResponseMultiError(errors)
+	}
+	return nil
+}
+
+// RequestDataExportResponseMultiError is an error wrapping multiple validation
+// errors returned by RequestDataExportResponse.ValidateAll() if the
+// designated constraints aren't met.
+type RequestDataExportResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m RequestDataExportResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m RequestDataExportResponseMultiError) AllErrors() []error { return m }
+
+// RequestDataExportResponseValidationError is the validation error returned by
+// RequestDataExportResponse.Validate if the designated constraints aren't met.
+type RequestDataExportResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e RequestDataExportResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e RequestDataExportResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e RequestDataExportResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e RequestDataExportResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e RequestDataExportResponseValidationError) ErrorName() string {
+	return "RequestDataExportResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e RequestDataExportResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sRequestDataExportResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = RequestDataExportResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = RequestDataExportResponseValidationError{}
+
+// Validate checks the field values on GetDataExportRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *GetDataExportRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on GetDataExportRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// GetDataExportRequestMultiError, or nil if none found.
+func (m *GetDataExportRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *GetDataExportRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if err := m._validateUuid(m.GetId()); err != nil {
+		err = GetDataExportRequestValidationError{
+			field:  "Id",
+			reason: "value must be a valid UUID",
+			cause:  err,
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return GetDataExportRequestMultiError(errors)
+	}
+	return nil
+}
+
+func (m *GetDataExportRequest) _validateUuid(uuid string) error {
+	if matched := _user_service_uuidPattern.MatchString(uuid); !matched {
+		return errors.New("invalid uuid format")
+	}
+
+	return nil
+}
+
+// GetDataExportRequestMultiError is an error wrapping multiple validation
+// errors returned by GetDataExportRequest.ValidateAll() if the designated
+// constraints aren't met.
+type GetDataExportRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m GetDataExportRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m GetDataExportRequestMultiError) AllErrors() []error { return m }
+
+// GetDataExportRequestValidationError is the validation error returned by
+// GetDataExportRequest.Validate if the designated constraints aren't met.
+type GetDataExportRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e GetDataExportRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e GetDataExportRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e GetDataExportRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e GetDataExportRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e GetDataExportRequestValidationError) ErrorName() string {
+	return "GetDataExportRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e GetDataExportRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sGetDataExportRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = GetDataExportRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = GetDataExportRequestValidationError{}
+
+// Validate checks the field values on GetDataExportResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *GetDataExportResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on GetDataExportResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// GetDataExportResponseMultiError, or nil if none found.
+func (m *GetDataExportResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *GetDataExportResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if all {
+		switch v := interface{}(m.GetExport()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, GetDataExportResponseValidationError{
+					field:  "Export",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, GetDataExportResponseValidationError{
+					field:  "Export",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetExport()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return GetDataExportResponseValidationError{
+				field:  "Export",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for Archive
+
+	if len(errors) > 0 {
+		return GetDataExportResponseMultiError(errors)
+	}
+	return nil
+}
+
+// GetDataExportResponseMultiError is an error wrapping multiple validation
+// errors returned by GetDataExportResponse.ValidateAll() if the designated
+// constraints aren't met.
+type GetDataExportResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m GetDataExportResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m GetDataExportResponseMultiError) AllErrors() []error { return m }
+
+// GetDataExportResponseValidationError is the validation error returned by
+// GetDataExportResponse.Validate if the designated constraints aren't met.
+type GetDataExportResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e GetDataExportResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e GetDataExportResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e GetDataExportResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e GetDataExportResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e GetDataExportResponseValidationError) ErrorName() string {
+	return "GetDataExportResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e GetDataExportResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sGetDataExportResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = GetDataExportResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = GetDataExportResponseValidationError{}
+
+// Validate checks the field values on RecordConsentRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *RecordConsentRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on RecordConsentRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// RecordConsentRequestMultiError, or nil if none found.
+func (m *RecordConsentRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *RecordConsentRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if err := m._validateUuid(m.GetId()); err != nil {
+		err = RecordConsentRequestValidationError{
+			field:  "Id",
+			reason: "value must be a valid UUID",
+			cause:  err,
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if m.GetPolicy() == nil {
+		err := RecordConsentRequestValidationError{
+			field:  "Policy",
+			reason: "value is required",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if all {
+		switch v := interface{}(m.GetPolicy()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, RecordConsentRequestValidationError{
+					field:  "Policy",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, RecordConsentRequestValidationError{
+					field:  "Policy",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetPolicy()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return RecordConsentRequestValidationError{
+				field:  "Policy",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return RecordConsentRequestMultiError(errors)
+	}
+	return nil
+}
+
+func (m *RecordConsentRequest) _validateUuid(uuid string) error {
+	if matched := _user_service_uuidPattern.MatchString(uuid); !matched {
+		return errors.New("invalid uuid format")
+	}
+
+	return nil
+}
+
+// RecordConsentRequestMultiError is an error wrapping multiple validation
+// errors returned by RecordConsentRequest.ValidateAll() if the designated
+// constraints aren't met.
+type RecordConsentRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m RecordConsentRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m RecordConsentRequestMultiError) AllErrors() []error { return m }
+
+// RecordConsentRequestValidationError is the validation error returned by
+// RecordConsentRequest.Validate if the designated constraints aren't met.
+type RecordConsentRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e RecordConsentRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e RecordConsentRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e RecordConsentRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e RecordConsentRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e RecordConsentRequestValidationError) ErrorName() string {
+	return "RecordConsentRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e RecordConsentRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sRecordConsentRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = RecordConsentRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = RecordConsentRequestValidationError{}
+
+// Validate checks the field values on RecordConsentResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *RecordConsentResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on RecordConsentResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// RecordConsentResponseMultiError, or nil if none found.
+func (m *RecordConsentResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *RecordConsentResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if all {
+		switch v := interface{}(m.GetConsent()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, RecordConsentResponseValidationError{
+					field:  "Consent",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, RecordConsentResponseValidationError{
+					field:  "Consent",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetConsent()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return RecordConsentResponseValidationError{
+				field:  "Consent",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return RecordConsentResponseMultiError(errors)
+	}
+	return nil
+}
+
+// RecordConsentResponseMultiError is an error wrapping multiple validation
+// errors returned by RecordConsentResponse.ValidateAll() if the designated
+// constraints aren't met.
+type RecordConsentResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m RecordConsentResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m RecordConsentResponseMultiError) AllErrors() []error { return m }
+
+// RecordConsentResponseValidationError is the validation error returned by
+// RecordConsentResponse.Validate if the designated constraints aren't met.
+type RecordConsentResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e RecordConsentResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e RecordConsentResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e RecordConsentResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e RecordConsentResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e RecordConsentResponseValidationError) ErrorName() string {
+	return "RecordConsentResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e RecordConsentResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sRecordConsentResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = RecordConsentResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = RecordConsentResponseValidationError{}
+
+// Validate checks the field values on WithdrawConsentRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *WithdrawConsentRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on WithdrawConsentRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// WithdrawConsentRequestMultiError, or nil if none found.
+func (m *WithdrawConsentRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *WithdrawConsentRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if err := m._validateUuid(m.GetId()); err != nil {
+		err = WithdrawConsentRequestValidationError{
+			field:  "Id",
+			reason: "value must be a valid UUID",
+			cause:  err,
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if l := utf8.RuneCountInString(m.GetPolicyKind()); l < 1 || l > 32 {
+		err := WithdrawConsentRequestValidationError{
+			field:  "PolicyKind",
+			reason: "value length must be between 1 and 32 runes, inclusive",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return WithdrawConsentRequestMultiError(errors)
+	}
+	return nil
+}
+
+func (m *WithdrawConsentRequest) _validateUuid(uuid string) error {
+	if matched := _user_service_uuidPattern.MatchString(uuid); !matched {
+		return errors.New("invalid uuid format")
+	}
+
+	return nil
+}
+
+// WithdrawConsentRequestMultiError is an error wrapping multiple validation
+// errors returned by WithdrawConsentRequest.ValidateAll() if the designated
+// constraints aren't met.
+type WithdrawConsentRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m WithdrawConsentRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m WithdrawConsentRequestMultiError) AllErrors() []error { return m }
+
+// WithdrawConsentRequestValidationError is the validation error returned by
+// WithdrawConsentRequest.Validate if the designated constraints aren't met.
+type WithdrawConsentRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e WithdrawConsentRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e WithdrawConsentRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e WithdrawConsentRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e WithdrawConsentRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e WithdrawConsentRequestValidationError) ErrorName() string {
+	return "WithdrawConsentRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e WithdrawConsentRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sWithdrawConsentRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = WithdrawConsentRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = WithdrawConsentRequestValidationError{}
+
+// Validate checks the field values on WithdrawConsentResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *WithdrawConsentResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on WithdrawConsentResponse with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// WithdrawConsentResponseMultiError, or nil if none found.
+func (m *WithdrawConsentResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *WithdrawConsentResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Id
+
+	if len(errors) > 0 {
+		return WithdrawConsentResponseMultiError(errors)
+	}
+	return nil
+}
+
+// WithdrawConsentResponseMultiError is an error wrapping multiple validation
+// errors returned by WithdrawConsentResponse.ValidateAll() if the designated
+// constraints aren't met.
+type WithdrawConsentResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m WithdrawConsentResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m WithdrawConsentResponseMultiError) AllErrors() []error { return m }
+
+// WithdrawConsentResponseValidationError is the validation error returned by
+// WithdrawConsentResponse.Validate if the designated constraints aren't met.
+type WithdrawConsentResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e WithdrawConsentResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e WithdrawConsentResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e WithdrawConsentResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e WithdrawConsentResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e WithdrawConsentResponseValidationError) ErrorName() string {
+	return "WithdrawConsentResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e WithdrawConsentResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sWithdrawConsentResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = WithdrawConsentResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = WithdrawConsentResponseValidationError{}
+
+// Validate checks the field values on ListConsentsRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *ListConsentsRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ListConsentsRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ListConsentsRequestMultiError, or nil if none found.
+func (m *ListConsentsRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ListConsentsRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if err := m._validateUuid(m.GetId()); err != nil {
+		err = ListConsentsRequestValidationError{
+			field:  "Id",
+			reason: "value must be a valid UUID",
+			cause:  err,
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return ListConsentsRequestMultiError(errors)
+	}
+	return nil
+}
+
+func (m *ListConsentsRequest) _validateUuid(uuid string) error {
+	if matched := _user_service_uuidPattern.MatchString(uuid); !matched {
+		return errors.New("invalid uuid format")
+	}
+
+	return nil
+}
+
+// ListConsentsRequestMultiError is an error wrapping multiple validation
+// errors returned by ListConsentsRequest.ValidateAll() if the designated
+// constraints aren't met.
+type ListConsentsRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ListConsentsRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ListConsentsRequestMultiError) AllErrors() []error { return m }
+
+// ListConsentsRequestValidationError is the validation error returned by
+// ListConsentsRequest.Validate if the designated constraints aren't met.
+type ListConsentsRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ListConsentsRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ListConsentsRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ListConsentsRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ListConsentsRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ListConsentsRequestValidationError) ErrorName() string {
+	return "ListConsentsRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ListConsentsRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sListConsentsRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ListConsentsRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ListConsentsRequestValidationError{}
+
+// Validate checks the field values on ListConsentsResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *ListConsentsResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ListConsentsResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ListConsentsResponseMultiError, or nil if none found.
+func (m *ListConsentsResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ListConsentsResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetConsents() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, ListConsentsResponseValidationError{
+						field:  fmt.Sprintf("Consents[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, ListConsentsResponseValidationError{
+						field:  fmt.Sprintf("Consents[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return ListConsentsResponseValidationError{
+					field:  fmt.Sprintf("Consents[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return ListConsentsResponseMultiError(errors)
+	}
+	return nil
+}
+
+// ListConsentsResponseMultiError is an error wrapping multiple validation
+// errors returned by ListConsentsResponse.ValidateAll() if the designated
+// constraints aren't met.
+type ListConsentsResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ListConsentsResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ListConsentsResponseMultiError) AllErrors() []error { return m }
+
+// ListConsentsResponseValidationError is the validation error returned by
+// ListConsentsResponse.Validate if the designated constraints aren't met.
+type ListConsentsResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ListConsentsResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ListConsentsResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ListConsentsResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ListConsentsResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ListConsentsResponseValidationError) ErrorName() string {
+	return "ListConsentsResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ListConsentsResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sListConsentsResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ListConsentsResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ListConsentsResponseValidationError{}
+
+// Validate checks the field values on PublishConsentPolicyRequest with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *PublishConsentPolicyRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on PublishConsentPolicyRequest with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// PublishConsentPolicyRequestMultiError, or nil if none found.
+func (m *PublishConsentPolicyRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *PublishConsentPolicyRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if m.GetPolicy() == nil {
+		err := PublishConsentPolicyRequestValidationError{
+			field:  "Policy",
+			reason: "value is required",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if all {
+		switch v := interface{}(m.GetPolicy()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, PublishConsentPolicyRequestValidationError{
+					field:  "Policy",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, PublishConsentPolicyRequestValidationError{
+					field:  "Policy",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetPolicy()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return PublishConsentPolicyRequestValidationError{
+				field:  "Policy",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for Required
+
+	if len(errors) > 0 {
+		return PublishConsentPolicyRequestMultiError(errors)
+	}
+	return nil
+}
+
+// PublishConsentPolicyRequestMultiError is an error wrapping multiple
+// validation errors returned by PublishConsentPolicyRequest.ValidateAll() if
+// the designated constraints aren't met.
+type PublishConsentPolicyRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m PublishConsentPolicyRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m PublishConsentPolicyRequestMultiError) AllErrors() []error { return m }
+
+// PublishConsentPolicyRequestValidationError is the validation error returned
+// by PublishConsentPolicyRequest.Validate if the designated constraints
+// aren't met.
+type PublishConsentPolicyRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e PublishConsentPolicyRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e PublishConsentPolicyRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e PublishConsentPolicyRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e PublishConsentPolicyRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e PublishConsentPolicyRequestValidationError) ErrorName() string {
+	return "PublishConsentPolicyRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e PublishConsentPolicyRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sPublishConsentPolicyRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = PublishConsentPolicyRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = PublishConsentPolicyRequestValidationError{}
+
+// Validate checks the field values on PublishConsentPolicyResponse with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *PublishConsentPolicyResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on PublishConsentPolicyResponse with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// PublishConsentPolicyResponseMultiError, or nil if none found.
+func (m *PublishConsentPolicyResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *PublishConsentPolicyResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if all {
+		switch v := interface{}(m.GetPolicy()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, PublishConsentPolicyResponseValidationError{
+					field:  "Policy",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, PublishConsentPolicyResponseValidationError{
+					field:  "Policy",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetPolicy()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return PublishConsentPolicyResponseValidationError{
+				field:  "Policy",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return PublishConsentPolicyResponseMultiError(errors)
+	}
+	return nil
+}
+
+// PublishConsentPolicyResponseMultiError is an error wrapping multiple
+// validation errors returned by PublishConsentPolicyResponse.ValidateAll() if
+// the designated constraints aren't met.
+type PublishConsentPolicyResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m PublishConsentPolicyResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m PublishConsentPolicyResponseMultiError) AllErrors() []error { return m }
+
+// PublishConsentPolicyResponseValidationError is the validation error returned
+// by PublishConsentPolicyResponse.Validate if the designated constraints
+// aren't met.
+type PublishConsentPolicyResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e PublishConsentPolicyResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e PublishConsentPolicyResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e PublishConsentPolicyResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e PublishConsentPolicyResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e PublishConsentPolicyResponseValidationError) ErrorName() string {
+	return "PublishConsentPolicyResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e PublishConsentPolicyResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sPublishConsentPolicyResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = PublishConsentPolicyResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = PublishConsentPolicyResponseValidationError{}
+
+// Validate checks the field values on ListUsersRequiringConsentRequest with
+// the rules defined in the proto definition for this message. If any rules
+// are violated, the first error encountered is returned, or nil if there are
+// no violations.
+func (m *ListUsersRequiringConsentRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ListUsersRequiringConsentRequest with
+// the rules defined in the proto definition for this message. If any rules
+// are violated, the result is a list of violation errors wrapped in
+// ListUsersRequiringConsentRequestMultiError, or nil if none found.
+func (m *ListUsersRequiringConsentRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ListUsersRequiringConsentRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if l := utf8.RuneCountInString(m.GetPolicyKind()); l < 1 || l > 32 {
+		err := ListUsersRequiringConsentRequestValidationError{
+			field:  "PolicyKind",
+			reason: "value length must be between 1 and 32 runes, inclusive",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if val := m.GetPageSize(); val < 0 || val > 1000 {
+		err := ListUsersRequiringConsentRequestValidationError{
+			field:  "PageSize",
+			reason: "value must be inside range [0, 1000]",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if m.GetPageToken() != "" {
+
+		if err := m._validateUuid(m.GetPageToken()); err != nil {
+			err = ListUsersRequiringConsentRequestValidationError{
+				field:  "PageToken",
+				reason: "value must be a valid UUID",
+				cause:  err,
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return ListUsersRequiringConsentRequestMultiError(errors)
+	}
+	return nil
+}
+
+func (m *ListUsersRequiringConsentRequest) _validateUuid(uuid string) error {
+	if matched := _user_service_uuidPattern.MatchString(uuid); !matched {
+		return errors.New("invalid uuid format")
+	}
+
+	return nil
+}
+
+// ListUsersRequiringConsentRequestMultiError is an error wrapping multiple
+// validation errors returned by
+// ListUsersRequiringConsentRequest.ValidateAll() if the designated
+// constraints aren't met.
+type ListUsersRequiringConsentRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ListUsersRequiringConsentRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ListUsersRequiringConsentRequestMultiError) AllErrors() []error { return m }
+
+// ListUsersRequiringConsentRequestValidationError is the validation error
+// returned by ListUsersRequiringConsentRequest.Validate if the designated
+// constraints aren't met.
+type ListUsersRequiringConsentRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ListUsersRequiringConsentRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ListUsersRequiringConsentRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ListUsersRequiringConsentRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ListUsersRequiringConsentRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ListUsersRequiringConsentRequestValidationError) ErrorName() string {
+	return "ListUsersRequiringConsentRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ListUsersRequiringConsentRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sListUsersRequiringConsentRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ListUsersRequiringConsentRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ListUsersRequiringConsentRequestValidationError{}
+
+// Validate checks the field values on ListUsersRequiringConsentResponse with
+// the rules defined in the proto definition for this message. If any rules
+// are violated, the first error encountered is returned, or nil if there are
+// no violations.
+func (m *ListUsersRequiringConsentResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ListUsersRequiringConsentResponse
+// with the rules defined in the proto definition for this message. If any
+// rules are violated, the result is a list of violation errors wrapped in
+// ListUsersRequiringConsentResponseMultiError, or nil if none found.
+func (m *ListUsersRequiringConsentResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ListUsersRequiringConsentResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for NextPageToken
+
+	if len(errors) > 0 {
+		return ListUsersRequiringConsentResponseMultiError(errors)
+	}
+	return nil
+}
+
+// ListUsersRequiringConsentResponseMultiError is an error wrapping multiple
+// validation errors returned by
+// ListUsersRequiringConsentResponse.ValidateAll() if the designated
+// constraints aren't met.
+type ListUsersRequiringConsentResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ListUsersRequiringConsentResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ListUsersRequiringConsentResponseMultiError) AllErrors() []error { return m }
+
+// ListUsersRequiringConsentResponseValidationError is the validation error
+// returned by ListUsersRequiringConsentResponse.Validate if the designated
+// constraints aren't met.
+type ListUsersRequiringConsentResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ListUsersRequiringConsentResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ListUsersRequiringConsentResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ListUsersRequiringConsentResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ListUsersRequiringConsentResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ListUsersRequiringConsentResponseValidationError) ErrorName() string {
+	return "ListUsersRequiringConsentResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ListUsersRequiringConsentResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sListUsersRequiringConsentResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ListUsersRequiringConsentResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ListUsersRequiringConsentResponseValidationError{}
+
+// Validate checks the field values on GetPreferencesRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *GetPreferencesRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on GetPreferencesRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// GetPreferencesRequestMultiError, or nil if none found.
+func (m *GetPreferencesRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *GetPreferencesRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if err := m._validateUuid(m.GetId()); err != nil {
+		err = GetPreferencesRequestValidationError{
+			field:  "Id",
+			reason: "value must be a valid UUID",
+			cause:  err,
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if m.GetNamespace() != "" {
+
+		if !_GetPreferencesRequest_Namespace_Pattern.MatchString(m.GetNamespace()) {
+			err := GetPreferencesRequestValidationError{
+				field:  "Namespace",
+				reason: "value does not match regex pattern \"^[a-z][a-z0-9_.-]{0,63}$\"",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return GetPreferencesRequestMultiError(errors)
+	}
+	return nil
+}
+
+func (m *GetPreferencesRequest) _validateUuid(uuid string) error {
+	if matched := _user_service_uuidPattern.MatchString(uuid); !matched {
+		return errors.New("invalid uuid format")
+	}
+
+	return nil
+}
+
+// GetPreferencesRequestMultiError is an error wrapping multiple validation
+// errors returned by GetPreferencesRequest.ValidateAll() if the designated
+// constraints aren't met.
+type GetPreferencesRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m GetPreferencesRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m GetPreferencesRequestMultiError) AllErrors() []error { return m }
+
+// GetPreferencesRequestValidationError is the validation error returned by
+// GetPreferencesRequest.Validate if the designated constraints aren't met.
+type GetPreferencesRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e GetPreferencesRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e GetPreferencesRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e GetPreferencesRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e GetPreferencesRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e GetPreferencesRequestValidationError) ErrorName() string {
+	return "GetPreferencesRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e GetPreferencesRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sGetPreferencesRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = GetPreferencesRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = GetPreferencesRequestValidationError{}
+
+var _GetPreferencesRequest_Namespace_Pattern = regexp.MustCompile("^[a-z][a-z0-9_.-]{0,63}$")
+
+// Validate checks the field values on GetPreferencesResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *GetPreferencesResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on GetPreferencesResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// GetPreferencesResponseMultiError, or nil if none found.
+func (m *GetPreferencesResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *GetPreferencesResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetPreferences() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, GetPreferencesResponseValidationError{
+						field:  fmt.Sprintf("Preferences[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, GetPreferencesResponseValidationError{
+						field:  fmt.Sprintf("Preferences[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return GetPreferencesResponseValidationError{
+					field:  fmt.Sprintf("Preferences[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return GetPreferencesResponseMultiError(errors)
+	}
+	return nil
+}
+
+// GetPreferencesResponseMultiError is an error wrapping multiple validation
+// errors returned by GetPreferencesResponse.ValidateAll() if the designated
+// constraints aren't met.
+type GetPreferencesResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m GetPreferencesResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m GetPreferencesResponseMultiError) AllErrors() []error { return m }
+
+// GetPreferencesResponseValidationError is the validation error returned by
+// GetPreferencesResponse.Validate if the designated constraints aren't met.
+type GetPreferencesResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e GetPreferencesResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e GetPreferencesResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e GetPreferencesResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e GetPreferencesResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e GetPreferencesResponseValidationError) ErrorName() string {
+	return "GetPreferencesResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e GetPreferencesResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sGetPreferencesResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = GetPreferencesResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = GetPreferencesResponseValidationError{}
+
+// Validate checks the field values on SetPreferencesRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *SetPreferencesRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on SetPreferencesRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// SetPreferencesRequestMultiError, or nil if none found.
+func (m *SetPreferencesRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *SetPreferencesRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if err := m._validateUuid(m.GetId()); err != nil {
+		err = SetPreferencesRequestValidationError{
+			field:  "Id",
+			reason: "value must be a valid UUID",
+			cause:  err,
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(m.GetPreferences()) < 1 {
+		err := SetPreferencesRequestValidationError{
+			field:  "Preferences",
+			reason: "value must contain at least 1 item(s)",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	for idx, item := range m.GetPreferences() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, SetPreferencesRequestValidationError{
+						field:  fmt.Sprintf("Preferences[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, SetPreferencesRequestValidationError{
+						field:  fmt.Sprintf("Preferences[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return SetPreferencesRequestValidationError{
+					field:  fmt.Sprintf("Preferences[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return SetPreferencesRequestMultiError(errors)
+	}
+	return nil
+}
+
+func (m *SetPreferencesRequest) _validateUuid(uuid string) error {
+	if matched := _user_service_uuidPattern.MatchString(uuid); !matched {
+		return errors.New("invalid uuid format")
+	}
+
+	return nil
+}
+
+// SetPreferencesRequestMultiError is an error wrapping multiple validation
+// errors returned by SetPreferencesRequest.ValidateAll() if the designated
+// constraints aren't met.
+type SetPreferencesRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m SetPreferencesRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m SetPreferencesRequestMultiError) AllErrors() []error { return m }
+
+// SetPreferencesRequestValidationError is the validation error returned by
+// SetPreferencesRequest.Validate if the designated constraints aren't met.
+type SetPreferencesRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e SetPreferencesRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e SetPreferencesRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e SetPreferencesRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e SetPreferencesRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e SetPreferencesRequestValidationError) ErrorName() string {
+	return "SetPreferencesRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e SetPreferencesRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sSetPreferencesRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = SetPreferencesRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = SetPreferencesRequestValidationError{}
+
+// Validate checks the field values on SetPreferencesResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *SetPreferencesResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on SetPreferencesResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// SetPreferencesResponseMultiError, or nil if none found.
+func (m *SetPreferencesResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *SetPreferencesResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetPreferences() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, SetPreferencesResponseValidationError{
+						field:  fmt.Sprintf("Preferences[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, SetPreferencesResponseValidationError{
+						field:  fmt.Sprintf("Preferences[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return SetPreferencesResponseValidationError{
+					field:  fmt.Sprintf("Preferences[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return SetPreferencesResponseMultiError(errors)
+	}
+	return nil
+}
+
+// SetPreferencesResponseMultiError is an error wrapping multiple validation
+// errors returned by SetPreferencesResponse.ValidateAll() if the designated
+// constraints aren't met.
+type SetPreferencesResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m SetPreferencesResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m SetPreferencesResponseMultiError) AllErrors() []error { return m }
+
+// SetPreferencesResponseValidationError is the validation error returned by
+// SetPreferencesResponse.Validate if the designated constraints aren't met.
+type SetPreferencesResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e SetPreferencesResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e SetPreferencesResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e SetPreferencesResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e SetPreferencesResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e SetPreferencesResponseValidationError) ErrorName() string {
+	return "SetPreferencesResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e SetPreferencesResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sSetPreferencesResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = SetPreferencesResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = SetPreferencesResponseValidationError{}
+
+// Validate checks the field values on DeletePreferenceRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *DeletePreferenceRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on DeletePreferenceRequest with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// DeletePreferenceRequestMultiError, or nil if none found.
+func (m *DeletePreferenceRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *DeletePreferenceRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if err := m._validateUuid(m.GetId()); err != nil {
+		err = DeletePreferenceRequestValidationError{
+			field:  "Id",
+			reason: "value must be a valid UUID",
+			cause:  err,
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if !_DeletePreferenceRequest_Namespace_Pattern.MatchString(m.GetNamespace()) {
+		err := DeletePreferenceRequestValidationError{
+			field:  "Namespace",
+			reason: "value does not match regex pattern \"^[a-z][a-z0-9_.-]{0,63}$\"",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if !_DeletePreferenceRequest_Key_Pattern.MatchString(m.GetKey()) {
+		err := DeletePreferenceRequestValidationError{
+			field:  "Key",
+			reason: "value does not match regex pattern \"^[a-z][a-z0-9_.-]{0,63}$\"",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return DeletePreferenceRequestMultiError(errors)
+	}
+	return nil
+}
+
+func (m *DeletePreferenceRequest) _validateUuid(uuid string) error {
+	if matched := _user_service_uuidPattern.MatchString(uuid); !matched {
+		return errors.New("invalid uuid format")
+	}
+
+	return nil
+}
+
+// DeletePreferenceRequestMultiError is an error wrapping multiple validation
+// errors returned by DeletePreferenceRequest.ValidateAll() if the designated
+// constraints aren't met.
+type DeletePreferenceRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m DeletePreferenceRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m DeletePreferenceRequestMultiError) AllErrors() []error { return m }
+
+// DeletePreferenceRequestValidationError is the validation error returned by
+// DeletePreferenceRequest.Validate if the designated constraints aren't met.
+type DeletePreferenceRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e DeletePreferenceRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e DeletePreferenceRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e DeletePreferenceRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e DeletePreferenceRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e DeletePreferenceRequestValidationError) ErrorName() string {
+	return "DeletePreferenceRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e DeletePreferenceRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sDeletePreferenceRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = DeletePreferenceRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = DeletePreferenceRequestValidationError{}
+
+var _DeletePreferenceRequest_Namespace_Pattern = regexp.MustCompile("^[a-z][a-z0-9_.-]{0,63}$")
+
+var _DeletePreferenceRequest_Key_Pattern = regexp.MustCompile("^[a-z][a-z0-9_.-]{0,63}$")
+
+// Validate checks the field values on DeletePreferenceResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *DeletePreferenceResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on DeletePreferenceResponse with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// DeletePreferenceResponseMultiError, or nil if none found.
+func (m *DeletePreferenceResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *DeletePreferenceResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Id
+
+	if len(errors) > 0 {
+		return DeletePreferenceResponseMultiError(errors)
+	}
+	return nil
+}
+
+// DeletePreferenceResponseMultiError is an error wrapping multiple validation
+// errors returned by DeletePreferenceResponse.ValidateAll() if the designated
+// constraints aren't met.
+type DeletePreferenceResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m DeletePreferenceResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m DeletePreferenceResponseMultiError) AllErrors() []error { return m }
+
+// DeletePreferenceResponseValidationError is the validation error returned by
+// DeletePreferenceResponse.Validate if the designated constraints aren't met.
+type DeletePreferenceResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e DeletePreferenceResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e DeletePreferenceResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e DeletePreferenceResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e DeletePreferenceResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e DeletePreferenceResponseValidationError) ErrorName() string {
+	return "DeletePreferenceResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e DeletePreferenceResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sDeletePreferenceResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = DeletePreferenceResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = DeletePreferenceResponseValidationError{}
+
+// Validate checks the field values on CreateAddressRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *CreateAddressRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on CreateAddressRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// CreateAddressRequestMultiError, or nil if none found.
+func (m *CreateAddressRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *CreateAddressRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if err := m._validateUuid(m.GetUserId()); err != nil {
+		err = CreateAddressRequestValidationError{
+			field:  "UserId",
+			reason: "value must be a valid UUID",
+			cause:  err,
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if m.GetAddress() == nil {
+		err := CreateAddressRequestValidationError{
+			field:  "Address",
+			reason: "value is required",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if all {
+		switch v := interface{}(m.GetAddress()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, CreateAddressRequestValidationError{
+					field:  "Address",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, CreateAddressRequestValidationError{
+					field:  "Address",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetAddress()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return CreateAddressRequestValidationError{
+				field:  "Address",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return CreateAddressRequestMultiError(errors)
+	}
+	return nil
+}
+
+func (m *CreateAddressRequest) _validateUuid(uuid string) error {
+	if matched := _user_service_uuidPattern.MatchString(uuid); !matched {
+		return errors.New("invalid uuid format")
+	}
+
+	return nil
+}
+
+// CreateAddressRequestMultiError is an error wrapping multiple validation
+// errors returned by CreateAddressRequest.ValidateAll() if the designated
+// constraints aren't met.
+type CreateAddressRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m CreateAddressRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m CreateAddressRequestMultiError) AllErrors() []error { return m }
+
+// CreateAddressRequestValidationError is the validation error returned by
+// CreateAddressRequest.Validate if the designated constraints aren't met.
+type CreateAddressRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e CreateAddressRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e CreateAddressRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e CreateAddressRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e CreateAddressRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e CreateAddressRequestValidationError) ErrorName() string {
+	return "CreateAddressRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e CreateAddressRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sCreateAddressRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = CreateAddressRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = CreateAddressRequestValidationError{}
+
+// Validate checks the field values on CreateAddressResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *CreateAddressResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on CreateAddressResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// CreateAddressResponseMultiError, or nil if none found.
+func (m *CreateAddressResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *CreateAddressResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if all {
+		switch v := interface{}(m.GetAddress()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, CreateAddressResponseValidationError{
+					field:  "Address",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, CreateAddressResponseValidationError{
+					field:  "Address",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetAddress()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return CreateAddressResponseValidationError{
+				field:  "Address",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return CreateAddressResponseMultiError(errors)
+	}
+	return nil
+}
+
+// CreateAddressResponseMultiError is an error wrapping multiple validation
+// errors returned by CreateAddressResponse.ValidateAll() if the designated
+// constraints aren't met.
+type CreateAddressResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m CreateAddressResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m CreateAddressResponseMultiError) AllErrors() []error { return m }
+
+// CreateAddressResponseValidationError is the validation error returned by
+// CreateAddressResponse.Validate if the designated constraints aren't met.
+type CreateAddressResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e CreateAddressResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e CreateAddressResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e CreateAddressResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e CreateAddressResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e CreateAddressResponseValidationError) ErrorName() string {
+	return "CreateAddressResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e CreateAddressResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sCreateAddressResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = CreateAddressResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = CreateAddressResponseValidationError{}
+
+// Validate checks the field values on ListAddressesRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *ListAddressesRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ListAddressesRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ListAddressesRequestMultiError, or nil if none found.
+func (m *ListAddressesRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ListAddressesRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if err := m._validateUuid(m.GetUserId()); err != nil {
+		err = ListAddressesRequestValidationError{
+			field:  "UserId",
+			reason: "value must be a valid UUID",
+			cause:  err,
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if _, ok := Address_Kind_name[int32(m.GetKind())]; !ok {
+		err := ListAddressesRequestValidationError{
+			field:  "Kind",
+			reason: "value must be one of the defined enum values",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return ListAddressesRequestMultiError(errors)
+	}
+	return nil
+}
+
+func (m *ListAddressesRequest) _validateUuid(uuid string) error {
+	if matched := _user_service_uuidPattern.MatchString(uuid); !matched {
+		return errors.New("invalid uuid format")
+	}
+
+	return nil
+}
+
+// ListAddressesRequestMultiError is an error wrapping multiple validation
+// errors returned by ListAddressesRequest.ValidateAll() if the designated
+// constraints aren't met.
+type ListAddressesRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ListAddressesRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ListAddressesRequestMultiError) AllErrors() []error { return m }
+
+// ListAddressesRequestValidationError is the validation error returned by
+// ListAddressesRequest.Validate if the designated constraints aren't met.
+type ListAddressesRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ListAddressesRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ListAddressesRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ListAddressesRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ListAddressesRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ListAddressesRequestValidationError) ErrorName() string {
+	return "ListAddressesRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ListAddressesRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sListAddressesRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ListAddressesRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ListAddressesRequestValidationError{}
+
+// Validate checks the field values on ListAddressesResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *ListAddressesResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ListAddressesResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ListAddressesResponseMultiError, or nil if none found.
+func (m *ListAddressesResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ListAddressesResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetAddresses() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, ListAddressesResponseValidationError{
+						field:  fmt.Sprintf("Addresses[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, ListAddressesResponseValidationError{
+						field:  fmt.Sprintf("Addresses[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return ListAddressesResponseValidationError{
+					field:  fmt.Sprintf("Addresses[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return ListAddressesResponseMultiError(errors)
+	}
+	return nil
+}
+
+// ListAddressesResponseMultiError is an error wrapping multiple validation
+// errors returned by ListAddressesResponse.ValidateAll() if the designated
+// constraints aren't met.
+type ListAddressesResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ListAddressesResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ListAddressesResponseMultiError) AllErrors() []error { return m }
+
+// ListAddressesResponseValidationError is the validation error returned by
+// ListAddressesResponse.Validate if the designated constraints aren't met.
+type ListAddressesResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ListAddressesResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ListAddressesResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ListAddressesResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ListAddressesResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ListAddressesResponseValidationError) ErrorName() string {
+	return "ListAddressesResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ListAddressesResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sListAddressesResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ListAddressesResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ListAddressesResponseValidationError{}
+
+// Validate checks the field values on UpdateAddressRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *UpdateAddressRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on UpdateAddressRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// UpdateAddressRequestMultiError, or nil if none found.
+func (m *UpdateAddressRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *UpdateAddressRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if err := m._validateUuid(m.GetUserId()); err != nil {
+		err = UpdateAddressRequestValidationError{
+			field:  "UserId",
+			reason: "value must be a valid UUID",
+			cause:  err,
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if err := m._validateUuid(m.GetId()); err != nil {
+		err = UpdateAddressRequestValidationError{
+			field:  "Id",
+			reason: "value must be a valid UUID",
+			cause:  err,
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if m.GetAddress() == nil {
+		err := UpdateAddressRequestValidationError{
+			field:  "Address",
+			reason: "value is required",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if all {
+		switch v := interface{}(m.GetAddress()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, UpdateAddressRequestValidationError{
+					field:  "Address",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, UpdateAddressRequestValidationError{
+					field:  "Address",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetAddress()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return UpdateAddressRequestValidationError{
+				field:  "Address",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return UpdateAddressRequestMultiError(errors)
+	}
+	return nil
+}
+
+func (m *UpdateAddressRequest) _validateUuid(uuid string) error {
+	if matched := _user_service_uuidPattern.MatchString(uuid); !matched {
+		return errors.New("invalid uuid format")
+	}
+
+	return nil
+}
+
+// UpdateAddressRequestMultiError is an error wrapping multiple validation
+// errors returned by UpdateAddressRequest.ValidateAll() if the designated
+// constraints aren't met.
+type UpdateAddressRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m UpdateAddressRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m UpdateAddressRequestMultiError) AllErrors() []error { return m }
+
+// UpdateAddressRequestValidationError is the validation error returned by
+// UpdateAddressRequest.Validate if the designated constraints aren't met.
+type UpdateAddressRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e UpdateAddressRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e UpdateAddressRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e UpdateAddressRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e UpdateAddressRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e UpdateAddressRequestValidationError) ErrorName() string {
+	return "UpdateAddressRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e UpdateAddressRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sUpdateAddressRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = UpdateAddressRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = UpdateAddressRequestValidationError{}
+
+// Validate checks the field values on UpdateAddressResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *UpdateAddressResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on UpdateAddressResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// UpdateAddressResponseMultiError, or nil if none found.
+func (m *UpdateAddressResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *UpdateAddressResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if all {
+		switch v := interface{}(m.GetAddress()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, UpdateAddressResponseValidationError{
+					field:  "Address",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, UpdateAddressResponseValidationError{
+					field:  "Address",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetAddress()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return UpdateAddressResponseValidationError{
+				field:  "Address",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return UpdateAddressResponseMultiError(errors)
+	}
+	return nil
+}
+
+// UpdateAddressResponseMultiError is an error wrapping multiple validation
+// errors returned by UpdateAddressResponse.ValidateAll() if the designated
+// constraints aren't met.
+type UpdateAddressResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m UpdateAddressResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m UpdateAddressResponseMultiError) AllErrors() []error { return m }
+
+// UpdateAddressResponseValidationError is the validation error returned by
+// UpdateAddressResponse.Validate if the designated constraints aren't met.
+type UpdateAddressResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e UpdateAddressResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e UpdateAddressResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e UpdateAddressResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e UpdateAddressResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e UpdateAddressResponseValidationError) ErrorName() string {
+	return "UpdateAddressResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e UpdateAddressResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sUpdateAddressResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = UpdateAddressResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = UpdateAddressResponseValidationError{}
+
+// Validate checks the field values on DeleteAddressRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *DeleteAddressRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on DeleteAddressRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// DeleteAddressRequestMultiError, or nil if none found.
+func (m *DeleteAddressRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *DeleteAddressRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if err := m._validateUuid(m.GetUserId()); err != nil {
+		err = DeleteAddressRequestValidationError{
+			field:  "UserId",
+			reason: "value must be a valid UUID",
+			cause:  err,
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if err := m._validateUuid(m.GetId()); err != nil {
+		err = DeleteAddressRequestValidationError{
+			field:  "Id",
+			reason: "value must be a valid UUID",
+			cause:  err,
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return DeleteAddressRequestMultiError(errors)
+	}
+	return nil
+}
+
+func (m *DeleteAddressRequest) _validateUuid(uuid string) error {
+	if matched := _user_service_uuidPattern.MatchString(uuid); !matched {
+		return errors.New("invalid uuid format")
+	}
+
+	return nil
+}
+
+// DeleteAddressRequestMultiError is an error wrapping multiple validation
+// errors returned by DeleteAddressRequest.ValidateAll() if the designated
+// constraints aren't met.
+type DeleteAddressRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m DeleteAddressRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m DeleteAddressRequestMultiError) AllErrors() []error { return m }
+
+// DeleteAddressRequestValidationError is the validation error returned by
+// DeleteAddressRequest.Validate if the designated constraints aren't met.
+type DeleteAddressRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e DeleteAddressRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e DeleteAddressRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e DeleteAddressRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e DeleteAddressRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e DeleteAddressRequestValidationError) ErrorName() string {
+	return "DeleteAddressRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e DeleteAddressRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sDeleteAddressRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = DeleteAddressRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = DeleteAddressRequestValidationError{}
+
+// Validate checks the field values on DeleteAddressResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *DeleteAddressResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on DeleteAddressResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// DeleteAddressResponseMultiError, or nil if none found.
+func (m *DeleteAddressResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *DeleteAddressResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Id
+
+	if len(errors) > 0 {
+		return DeleteAddressResponseMultiError(errors)
+	}
+	return nil
+}
+
+// DeleteAddressResponseMultiError is an error wrapping multiple validation
+// errors returned by DeleteAddressResponse.ValidateAll() if the designated
+// constraints aren't met.
+type DeleteAddressResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m DeleteAddressResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m DeleteAddressResponseMultiError) AllErrors() []error { return m }
+
+// DeleteAddressResponseValidationError is the validation error returned by
+// DeleteAddressResponse.Validate if the designated constraints aren't met.
+type DeleteAddressResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e DeleteAddressResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e DeleteAddressResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e DeleteAddressResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e DeleteAddressResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e DeleteAddressResponseValidationError) ErrorName() string {
+	return "DeleteAddressResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e DeleteAddressResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sDeleteAddressResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = DeleteAddressResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = DeleteAddressResponseValidationError{}
+
+// Validate checks the field values on AddEmailRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *AddEmailRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on AddEmailRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// AddEmailRequestMultiError, or nil if none found.
+func (m *AddEmailRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *AddEmailRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if err := m._validateUuid(m.GetId()); err != nil {
+		err = AddEmailRequestValidationError{
+			field:  "Id",
+			reason: "value must be a valid UUID",
+			cause:  err,
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if l := utf8.RuneCountInString(m.GetEmail()); l < 1 || l > 254 {
+		err := AddEmailRequestValidationError{
+			field:  "Email",
+			reason: "value length must be between 1 and 254 runes, inclusive",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for Verified
+
+	if len(errors) > 0 {
+		return AddEmailRequestMultiError(errors)
+	}
+	return nil
+}
+
+func (m *AddEmailRequest) _validateUuid(uuid string) error {
+	if matched := _user_service_uuidPattern.MatchString(uuid); !matched {
+		return errors.New("invalid uuid format")
+	}
+
+	return nil
+}
+
+// AddEmailRequestMultiError is an error wrapping multiple validation errors
+// returned by AddEmailRequest.ValidateAll() if the designated constraints
+// aren't met.
+type AddEmailRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m AddEmailRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m AddEmailRequestMultiError) AllErrors() []error { return m }
+
+// AddEmailRequestValidationError is the validation error returned by
+// AddEmailRequest.Validate if the designated constraints aren't met.
+type AddEmailRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e AddEmailRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e AddEmailRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e AddEmailRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e AddEmailRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e AddEmailRequestValidationError) ErrorName() string { return "AddEmailRequestValidationError" }
+
+// Error satisfies the builtin error interface
+func (e AddEmailRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sAddEmailRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = AddEmailRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = AddEmailRequestValidationError{}
+
+// Validate checks the field values on AddEmailResponse with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *AddEmailResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on AddEmailResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// AddEmailResponseMultiError, or nil if none found.
+func (m *AddEmailResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *AddEmailResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetEmails() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, AddEmailResponseValidationError{
+						field:  fmt.Sprintf("Emails[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, AddEmailResponseValidationError{
+						field:  fmt.Sprintf("Emails[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return AddEmailResponseValidationError{
+					field:  fmt.Sprintf("Emails[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return AddEmailResponseMultiError(errors)
+	}
+	return nil
+}
+
+// AddEmailResponseMultiError is an error wrapping multiple validation errors
+// returned by AddEmailResponse.ValidateAll() if the designated constraints
+// aren't met.
+type AddEmailResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m AddEmailResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m AddEmailResponseMultiError) AllErrors() []error { return m }
+
+// AddEmailResponseValidationError is the validation error returned by
+// AddEmailResponse.Validate if the designated constraints aren't met.
+type AddEmailResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e AddEmailResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e AddEmailResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e AddEmailResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e AddEmailResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e AddEmailResponseValidationError) ErrorName() string { return "AddEmailResponseValidationError" }
+
+// Error satisfies the builtin error interface
+func (e AddEmailResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sAddEmailResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = AddEmailResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = AddEmailResponseValidationError{}
+
+// Validate checks the field values on RemoveEmailRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *RemoveEmailRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on RemoveEmailRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// RemoveEmailRequestMultiError, or nil if none found.
+func (m *RemoveEmailRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *RemoveEmailRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if err := m._validateUuid(m.GetId()); err != nil {
+		err = RemoveEmailRequestValidationError{
+			field:  "Id",
+			reason: "value must be a valid UUID",
+			cause:  err,
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if l := utf8.RuneCountInString(m.GetEmail()); l < 1 || l > 254 {
+		err := RemoveEmailRequestValidationError{
+			field:  "Email",
+			reason: "value length must be between 1 and 254 runes, inclusive",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return RemoveEmailRequestMultiError(errors)
+	}
+	return nil
+}
+
+func (m *RemoveEmailRequest) _validateUuid(uuid string) error {
+	if matched := _user_service_uuidPattern.MatchString(uuid); !matched {
+		return errors.New("invalid uuid format")
+	}
+
+	return nil
+}
+
+// RemoveEmailRequestMultiError is an error wrapping multiple validation errors
+// returned by RemoveEmailRequest.ValidateAll() if the designated constraints
+// aren't met.
+type RemoveEmailRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m RemoveEmailRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m RemoveEmailRequestMultiError) AllErrors() []error { return m }
+
+// RemoveEmailRequestValidationError is the validation error returned by
+// RemoveEmailRequest.Validate if the designated constraints aren't met.
+type RemoveEmailRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e RemoveEmailRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e RemoveEmailRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e RemoveEmailRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e RemoveEmailRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e RemoveEmailRequestValidationError) ErrorName() string {
+	return "RemoveEmailRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e RemoveEmailRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sRemoveEmailRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = RemoveEmailRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = RemoveEmailRequestValidationError{}
+
+// Validate checks the field values on RemoveEmailResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *RemoveEmailResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on RemoveEmailResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// RemoveEmailResponseMultiError, or nil if none found.
+func (m *RemoveEmailResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *RemoveEmailResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetEmails() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, RemoveEmailResponseValidationError{
+						field:  fmt.Sprintf("Emails[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, RemoveEmailResponseValidationError{
+						field:  fmt.Sprintf("Emails[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return RemoveEmailResponseValidationError{
+					field:  fmt.Sprintf("Emails[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return RemoveEmailResponseMultiError(errors)
+	}
+	return nil
+}
+
+// RemoveEmailResponseMultiError is an error wrapping multiple validation
+// errors returned by RemoveEmailResponse.ValidateAll() if the designated
+// constraints aren't met.
+type RemoveEmailResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m RemoveEmailResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m RemoveEmailResponseMultiError) AllErrors() []error { return m }
+
+// RemoveEmailResponseValidationError is the validation error returned by
+// RemoveEmailResponse.Validate if the designated constraints aren't met.
+type RemoveEmailResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e RemoveEmailResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e RemoveEmailResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e RemoveEmailResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e RemoveEmailResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e RemoveEmailResponseValidationError) ErrorName() string {
+	return "RemoveEmailResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e RemoveEmailResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sRemoveEmailResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = RemoveEmailResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = RemoveEmailResponseValidationError{}
+
+// Validate checks the field values on SetPrimaryEmailRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *SetPrimaryEmailRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on SetPrimaryEmailRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// SetPrimaryEmailRequestMultiError, or nil if none found.
+func (m *SetPrimaryEmailRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *SetPrimaryEmailRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if err := m._validateUuid(m.GetId()); err != nil {
+		err = SetPrimaryEmailRequestValidationError{
+			field:  "Id",
+			reason: "value must be a valid UUID",
+			cause:  err,
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if l := utf8.RuneCountInString(m.GetEmail()); l < 1 || l > 254 {
+		err := SetPrimaryEmailRequestValidationError{
+			field:  "Email",
+			reason: "value length must be between 1 and 254 runes, inclusive",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return SetPrimaryEmailRequestMultiError(errors)
+	}
+	return nil
+}
+
+func (m *SetPrimaryEmailRequest) _validateUuid(uuid string) error {
+	if matched := _user_service_uuidPattern.MatchString(uuid); !matched {
+		return errors.New("invalid uuid format")
+	}
+
+	return nil
+}
+
+// SetPrimaryEmailRequestMultiError is an error wrapping multiple validation
+// errors returned by SetPrimaryEmailRequest.ValidateAll() if the designated
+// constraints aren't met.
+type SetPrimaryEmailRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m SetPrimaryEmailRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m SetPrimaryEmailRequestMultiError) AllErrors() []error { return m }
+
+// SetPrimaryEmailRequestValidationError is the validation error returned by
+// SetPrimaryEmailRequest.Validate if the designated constraints aren't met.
+type SetPrimaryEmailRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e SetPrimaryEmailRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e SetPrimaryEmailRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e SetPrimaryEmailRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e SetPrimaryEmailRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e SetPrimaryEmailRequestValidationError) ErrorName() string {
+	return "SetPrimaryEmailRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e SetPrimaryEmailRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sSetPrimaryEmailRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = SetPrimaryEmailRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = SetPrimaryEmailRequestValidationError{}
+
+// Validate checks the field values on SetPrimaryEmailResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *SetPrimaryEmailResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on SetPrimaryEmailResponse with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// SetPrimaryEmailResponseMultiError, or nil if none found.
+func (m *SetPrimaryEmailResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *SetPrimaryEmailResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetEmails() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, SetPrimaryEmailResponseValidationError{
+						field:  fmt.Sprintf("Emails[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, SetPrimaryEmailResponseValidationError{
+						field:  fmt.Sprintf("Emails[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return SetPrimaryEmailResponseValidationError{
+					field:  fmt.Sprintf("Emails[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return SetPrimaryEmailResponseMultiError(errors)
+	}
+	return nil
+}
+
+// SetPrimaryEmailResponseMultiError is an error wrapping multiple validation
+// errors returned by SetPrimaryEmailResponse.ValidateAll() if the designated
+// constraints aren't met.
+type SetPrimaryEmailResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m SetPrimaryEmailResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m SetPrimaryEmailResponseMultiError) AllErrors() []error { return m }
+
+// SetPrimaryEmailResponseValidationError is the validation error returned by
+// SetPrimaryEmailResponse.Validate if the designated constraints aren't met.
+type SetPrimaryEmailResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e SetPrimaryEmailResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e SetPrimaryEmailResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e SetPrimaryEmailResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e SetPrimaryEmailResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e SetPrimaryEmailResponseValidationError) ErrorName() string {
+	return "SetPrimaryEmailResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e SetPrimaryEmailResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sSetPrimaryEmailResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = SetPrimaryEmailResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = SetPrimaryEmailResponseValidationError{}
+
+// Validate checks the field values on UploadAvatarRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *UploadAvatarRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on UploadAvatarRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// UploadAvatarRequestMultiError, or nil if none found.
+func (m *UploadAvatarRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *UploadAvatarRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	switch m.Data.(type) {
+
+	case *UploadAvatarRequest_Id:
+
+		if err := m._validateUuid(m.GetId()); err != nil {
+			err = UploadAvatarRequestValidationError{
+				field:  "Id",
+				reason: "value must be a valid UUID",
+				cause:  err,
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	case *UploadAvatarRequest_Chunk:
+		// no validation rules for Chunk
+
+	}
+
+	if len(errors) > 0 {
+		return UploadAvatarRequestMultiError(errors)
+	}
+	return nil
+}
+
+func (m *UploadAvatarRequest) _validateUuid(uuid string) error {
+	if matched := _user_service_uuidPattern.MatchString(uuid); !matched {
+		return errors.New("invalid uuid format")
+	}
+
+	return nil
+}
+
+// UploadAvatarRequestMultiError is an error wrapping multiple validation
+// errors returned by UploadAvatarRequest.ValidateAll() if the designated
+// constraints aren't met.
+type UploadAvatarRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m UploadAvatarRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m UploadAvatarRequestMultiError) AllErrors() []error { return m }
+
+// UploadAvatarRequestValidationError is the validation error returned by
+// UploadAvatarRequest.Validate if the designated constraints aren't met.
+type UploadAvatarRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e UploadAvatarRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e UploadAvatarRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e UploadAvatarRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e UploadAvatarRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e UploadAvatarRequestValidationError) ErrorName() string {
+	return "UploadAvatarRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e UploadAvatarRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sUploadAvatarRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = UploadAvatarRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = UploadAvatarRequestValidationError{}
+
+// Validate checks the field values on UploadAvatarResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *UploadAvatarResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on UploadAvatarResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// UploadAvatarResponseMultiError, or nil if none found.
+func (m *UploadAvatarResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *UploadAvatarResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if all {
+		switch v := interface{}(m.GetAvatar()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, UploadAvatarResponseValidationError{
+					field:  "Avatar",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, UploadAvatarResponseValidationError{
+					field:  "Avatar",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetAvatar()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return UploadAvatarResponseValidationError{
+				field:  "Avatar",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return UploadAvatarResponseMultiError(errors)
+	}
+	return nil
+}
+
+// UploadAvatarResponseMultiError is an error wrapping multiple validation
+// errors returned by UploadAvatarResponse.ValidateAll() if the designated
+// constraints aren't met.
+type UploadAvatarResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m UploadAvatarResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m UploadAvatarResponseMultiError) AllErrors() []error { return m }
+
+// UploadAvatarResponseValidationError is the validation error returned by
+// UploadAvatarResponse.Validate if the designated constraints aren't met.
+type UploadAvatarResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e UploadAvatarResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e UploadAvatarResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e UploadAvatarResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e UploadAvatarResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e UploadAvatarResponseValidationError) ErrorName() string {
+	return "UploadAvatarResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e UploadAvatarResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sUploadAvatarResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = UploadAvatarResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = UploadAvatarResponseValidationError{}
+
+// Validate checks the field values on ExportUsersRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *ExportUsersRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ExportUsersRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ExportUsersRequestMultiError, or nil if none found.
+func (m *ExportUsersRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ExportUsersRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if _, ok := ExportUsersRequest_Format_name[int32(m.GetFormat())]; !ok {
+		err := ExportUsersRequestValidationError{
+			field:  "Format",
+			reason: "value must be one of the defined enum values",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if all {
+		switch v := interface{}(m.GetCreatedAfter()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, ExportUsersRequestValidationError{
+					field:  "CreatedAfter",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, ExportUsersRequestValidationError{
+					field:  "CreatedAfter",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetCreatedAfter()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ExportUsersRequestValidationError{
+				field:  "CreatedAfter",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if all {
+		switch v := interface{}(m.GetCreatedBefore()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, ExportUsersRequestValidationError{
+					field:  "CreatedBefore",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, ExportUsersRequestValidationError{
+					field:  "CreatedBefore",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetCreatedBefore()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ExportUsersRequestValidationError{
+				field:  "CreatedBefore",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if _, ok := User_Gender_name[int32(m.GetGender())]; !ok {
+		err := ExportUsersRequestValidationError{
+			field:  "Gender",
+			reason: "value must be one of the defined enum values",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	{
+		sorted_keys := make([]string, len(m.GetAttributeFilters()))
+		i := 0
+		for key := range m.GetAttributeFilters() {
+			sorted_keys[i] = key
+			i++
+		}
+		sort.Slice(sorted_keys, func(i, j int) bool { return sorted_keys[i] < sorted_keys[j] })
+		for _, key := range sorted_keys {
+			val := m.GetAttributeFilters()[key]
+			_ = val
+
+			// no validation rules for AttributeFilters[key]
+
+			if all {
+				switch v := interface{}(val).(type) {
+				case interface{ ValidateAll() error }:
+					if err := v.ValidateAll(); err != nil {
+						errors = append(errors, ExportUsersRequestValidationError{
+							field:  fmt.Sprintf("AttributeFilters[%v]", key),
+							reason: "embedded message failed validation",
+							cause:  err,
+						})
+					}
+				case interface{ Validate() error }:
+					if err := v.Validate(); err != nil {
+						errors = append(errors, ExportUsersRequestValidationError{
+							field:  fmt.Sprintf("AttributeFilters[%v]", key),
+							reason: "embedded message failed validation",
+							cause:  err,
+						})
+					}
+				}
+			} else if v, ok := interface{}(val).(interface{ Validate() error }); ok {
+				if err := v.Validate(); err != nil {
+					return ExportUsersRequestValidationError{
+						field:  fmt.Sprintf("AttributeFilters[%v]", key),
+						reason: "embedded message failed validation",
+						cause:  err,
+					}
+				}
+			}
+
+		}
+	}
+
+	// no validation rules for IncludeErased
+
+	if len(errors) > 0 {
+		return ExportUsersRequestMultiError(errors)
+	}
+	return nil
+}
+
+// ExportUsersRequestMultiError is an error wrapping multiple validation errors
+// returned by ExportUsersRequest.ValidateAll() if the designated constraints
+// aren't met.
+type ExportUsersRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ExportUsersRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ExportUsersRequestMultiError) AllErrors() []error { return m }
+
+// ExportUsersRequestValidationError is the validation error returned by
+// ExportUsersRequest.Validate if the designated constraints aren't met.
+type ExportUsersRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ExportUsersRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ExportUsersRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ExportUsersRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ExportUsersRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ExportUsersRequestValidationError) ErrorName() string {
+	return "ExportUsersRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ExportUsersRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sExportUsersRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ExportUsersRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ExportUsersRequestValidationError{}
+
+// Validate checks the field values on ExportUsersResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *ExportUsersResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ExportUsersResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ExportUsersResponseMultiError, or nil if none found.
+func (m *ExportUsersResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ExportUsersResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Chunk
+
+	if len(errors) > 0 {
+		return ExportUsersResponseMultiError(errors)
+	}
+	return nil
+}
+
+// ExportUsersResponseMultiError is an error wrapping multiple validation
+// errors returned by ExportUsersResponse.ValidateAll() if the designated
+// constraints aren't met.
+type ExportUsersResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ExportUsersResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ExportUsersResponseMultiError) AllErrors() []error { return m }
+
+// ExportUsersResponseValidationError is the validation error returned by
+// ExportUsersResponse.Validate if the designated constraints aren't met.
+type ExportUsersResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ExportUsersResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ExportUsersResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ExportUsersResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ExportUsersResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ExportUsersResponseValidationError) ErrorName() string {
+	return "ExportUsersResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ExportUsersResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sExportUsersResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ExportUsersResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ExportUsersResponseValidationError{}
+
+// Validate checks the field values on RequestGuardianConsentRequest with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *RequestGuardianConsentRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on RequestGuardianConsentRequest with
+// the rules defined in the proto definition for this message. If any rules
+// are violated, the result is a list of violation errors wrapped in
+// RequestGuardianConsentRequestMultiError, or nil if none found.
+func (m *RequestGuardianConsentRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *RequestGuardianConsentRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if err := m._validateUuid(m.GetId()); err != nil {
+		err = RequestGuardianConsentRequestValidationError{
+			field:  "Id",
+			reason: "value must be a valid UUID",
+			cause:  err,
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if err := m._validateUuid(m.GetGuardianId()); err != nil {
+		err = RequestGuardianConsentRequestValidationError{
+			field:  "GuardianId",
+			reason: "value must be a valid UUID",
+			cause:  err,
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return RequestGuardianConsentRequestMultiError(errors)
+	}
+	return nil
+}
+
+func (m *RequestGuardianConsentRequest) _validateUuid(uuid string) error {
+	if matched := _user_service_uuidPattern.MatchString(uuid); !matched {
+		return errors.New("invalid uuid format")
+	}
+
+	return nil
+}
+
+// RequestGuardianConsentRequestMultiError is an error wrapping multiple
+// validation errors returned by RequestGuardianConsentRequest.ValidateAll()
+// if the designated constraints aren't met.
+type RequestGuardianConsentRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m RequestGuardianConsentRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m RequestGuardianConsentRequestMultiError) AllErrors() []error { return m }
+
+// RequestGuardianConsentRequestValidationError is the validation error
+// returned by RequestGuardianConsentRequest.Validate if the designated
+// constraints aren't met.
+type RequestGuardianConsentRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e RequestGuardianConsentRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e RequestGuardianConsentRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e RequestGuardianConsentRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e RequestGuardianConsentRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e RequestGuardianConsentRequestValidationError) ErrorName() string {
+	return "RequestGuardianConsentRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e RequestGuardianConsentRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sRequestGuardianConsentRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = RequestGuardianConsentRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = RequestGuardianConsentRequestValidationError{}
+
+// Validate checks the field values on RequestGuardianConsentResponse with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *RequestGuardianConsentResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on RequestGuardianConsentResponse with
+// the rules defined in the proto definition for this message. If any rules
+// are violated, the result is a list of violation errors wrapped in
+// RequestGuardianConsentResponseMultiError, or nil if none found.
+func (m *RequestGuardianConsentResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *RequestGuardianConsentResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if all {
+		switch v := interface{}(m.GetExpiresAt()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, RequestGuardianConsentResponseValidationError{
+					field:  "ExpiresAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, RequestGuardianConsentResponseValidationError{
+					field:  "ExpiresAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetExpiresAt()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return RequestGuardianConsentResponseValidationError{
+				field:  "ExpiresAt",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for ConsentId
+
+	if len(errors) > 0 {
+		return RequestGuardianConsentResponseMultiError(errors)
+	}
+	return nil
+}
+
+// RequestGuardianConsentResponseMultiError is an error wrapping multiple
+// validation errors returned by RequestGuardianConsentResponse.ValidateAll()
+// if the designated constraints aren't met.
+type RequestGuardianConsentResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m RequestGuardianConsentResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m RequestGuardianConsentResponseMultiError) AllErrors() []error { return m }
+
+// RequestGuardianConsentResponseValidationError is the validation error
+// returned by RequestGuardianConsentResponse.Validate if the designated
+// constraints aren't met.
+type RequestGuardianConsentResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e RequestGuardianConsentResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e RequestGuardianConsentResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e RequestGuardianConsentResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e RequestGuardianConsentResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e RequestGuardianConsentResponseValidationError) ErrorName() string {
+	return "RequestGuardianConsentResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e RequestGuardianConsentResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sRequestGuardianConsentResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = RequestGuardianConsentResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = RequestGuardianConsentResponseValidationError{}
+
+// Validate checks the field values on ConfirmGuardianConsentRequest with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *ConfirmGuardianConsentRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ConfirmGuardianConsentRequest with
+// the rules defined in the proto definition for this message. If any rules
+// are violated, the result is a list of violation errors wrapped in
+// ConfirmGuardianConsentRequestMultiError, or nil if none found.
+func (m *ConfirmGuardianConsentRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ConfirmGuardianConsentRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if err := m._validateUuid(m.GetConsentId()); err != nil {
+		err = ConfirmGuardianConsentRequestValidationError{
+			field:  "ConsentId",
+			reason: "value must be a valid UUID",
+			cause:  err,
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if err := m._validateUuid(m.GetGuardianId()); err != nil {
+		err = ConfirmGuardianConsentRequestValidationError{
+			field:  "GuardianId",
+			reason: "value must be a valid UUID",
+			cause:  err,
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return ConfirmGuardianConsentRequestMultiError(errors)
+	}
+	return nil
+}
+
+func (m *ConfirmGuardianConsentRequest) _validateUuid(uuid string) error {
+	if matched := _user_service_uuidPattern.MatchString(uuid); !matched {
+		return errors.New("invalid uuid format")
+	}
+
+	return nil
+}
+
+// ConfirmGuardianConsentRequestMultiError is an error wrapping multiple
+// validation errors returned by ConfirmGuardianConsentRequest.ValidateAll()
+// if the designated constraints aren't met.
+type ConfirmGuardianConsentRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ConfirmGuardianConsentRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ConfirmGuardianConsentRequestMultiError) AllErrors() []error { return m }
+
+// ConfirmGuardianConsentRequestValidationError is the validation error
+// returned by ConfirmGuardianConsentRequest.Validate if the designated
+// constraints aren't met.
+type ConfirmGuardianConsentRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ConfirmGuardianConsentRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ConfirmGuardianConsentRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ConfirmGuardianConsentRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ConfirmGuardianConsentRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ConfirmGuardianConsentRequestValidationError) ErrorName() string {
+	return "ConfirmGuardianConsentRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ConfirmGuardianConsentRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sConfirmGuardianConsentRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ConfirmGuardianConsentRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ConfirmGuardianConsentRequestValidationError{}
+
+// Validate checks the field values on ConfirmGuardianConsentResponse with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *ConfirmGuardianConsentResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ConfirmGuardianConsentResponse with
+// the rules defined in the proto definition for this message. If any rules
+// are violated, the result is a list of violation errors wrapped in
+// ConfirmGuardianConsentResponseMultiError, or nil if none found.
+func (m *ConfirmGuardianConsentResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ConfirmGuardianConsentResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Id
+
+	if len(errors) > 0 {
+		return ConfirmGuardianConsentResponseMultiError(errors)
+	}
+	return nil
+}
+
+// ConfirmGuardianConsentResponseMultiError is an error wrapping multiple
+// validation errors returned by ConfirmGuardianConsentResponse.ValidateAll()
+// if the designated constraints aren't met.
+type ConfirmGuardianConsentResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ConfirmGuardianConsentResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ConfirmGuardianConsentResponseMultiError) AllErrors() []error { return m }
+
+// ConfirmGuardianConsentResponseValidationError is the validation error
+// returned by ConfirmGuardianConsentResponse.Validate if the designated
+// constraints aren't met.
+type ConfirmGuardianConsentResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ConfirmGuardianConsentResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ConfirmGuardianConsentResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ConfirmGuardianConsentResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ConfirmGuardianConsentResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ConfirmGuardianConsentResponseValidationError) ErrorName() string {
+	return "ConfirmGuardianConsentResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ConfirmGuardianConsentResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sConfirmGuardianConsentResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ConfirmGuardianConsentResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ConfirmGuardianConsentResponseValidationError{}
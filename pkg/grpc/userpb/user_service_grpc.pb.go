@@ -22,6 +22,57 @@ type UserServiceClient interface {
 	GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*GetUserResponse, error)
 	UpdateUser(ctx context.Context, in *UpdateUserRequest, opts ...grpc.CallOption) (*UpdateUserResponse, error)
 	DeleteUser(ctx context.Context, in *DeleteUserRequest, opts ...grpc.CallOption) (*DeleteUserResponse, error)
+	// EraseUser irreversibly anonymizes the personal data of the user. Unlike
+	// DeleteUser, the record is kept so references from other systems stay valid.
+	EraseUser(ctx context.Context, in *EraseUserRequest, opts ...grpc.CallOption) (*EraseUserResponse, error)
+	// RequestDataExport assembles all data held about the user into a signed
+	// JSON archive.
+	RequestDataExport(ctx context.Context, in *RequestDataExportRequest, opts ...grpc.CallOption) (*RequestDataExportResponse, error)
+	// GetDataExport returns the state of the export and the archive once it is ready.
+	GetDataExport(ctx context.Context, in *GetDataExportRequest, opts ...grpc.CallOption) (*GetDataExportResponse, error)
+	// RecordConsent records the user's acceptance of a policy version.
+	RecordConsent(ctx context.Context, in *RecordConsentRequest, opts ...grpc.CallOption) (*RecordConsentResponse, error)
+	// WithdrawConsent withdraws all active consents of the user to the policy kind.
+	WithdrawConsent(ctx context.Context, in *WithdrawConsentRequest, opts ...grpc.CallOption) (*WithdrawConsentResponse, error)
+	ListConsents(ctx context.Context, in *ListConsentsRequest, opts ...grpc.CallOption) (*ListConsentsResponse, error)
+	// PublishConsentPolicy publishes a new version of a policy the users
+	// consent to.
+	PublishConsentPolicy(ctx context.Context, in *PublishConsentPolicyRequest, opts ...grpc.CallOption) (*PublishConsentPolicyResponse, error)
+	// ListUsersRequiringConsent pages through the users, ordered by ID, who
+	// have not consented to the latest required version of the policy kind.
+	// No users are listed if no such version is published.
+	ListUsersRequiringConsent(ctx context.Context, in *ListUsersRequiringConsentRequest, opts ...grpc.CallOption) (*ListUsersRequiringConsentResponse, error)
+	// GetPreferences returns the user's preferences completed with the defaults.
+	GetPreferences(ctx context.Context, in *GetPreferencesRequest, opts ...grpc.CallOption) (*GetPreferencesResponse, error)
+	SetPreferences(ctx context.Context, in *SetPreferencesRequest, opts ...grpc.CallOption) (*SetPreferencesResponse, error)
+	// DeletePreference resets the preference to its default.
+	DeletePreference(ctx context.Context, in *DeletePreferenceRequest, opts ...grpc.CallOption) (*DeletePreferenceResponse, error)
+	CreateAddress(ctx context.Context, in *CreateAddressRequest, opts ...grpc.CallOption) (*CreateAddressResponse, error)
+	ListAddresses(ctx context.Context, in *ListAddressesRequest, opts ...grpc.CallOption) (*ListAddressesResponse, error)
+	// UpdateAddress replaces all fields of the address.
+	UpdateAddress(ctx context.Context, in *UpdateAddressRequest, opts ...grpc.CallOption) (*UpdateAddressResponse, error)
+	DeleteAddress(ctx context.Context, in *DeleteAddressRequest, opts ...grpc.CallOption) (*DeleteAddressResponse, error)
+	// AddEmail adds a secondary email address to the user.
+	AddEmail(ctx context.Context, in *AddEmailRequest, opts ...grpc.CallOption) (*AddEmailResponse, error)
+	// RemoveEmail removes a secondary email address. The primary address can not be removed.
+	RemoveEmail(ctx context.Context, in *RemoveEmailRequest, opts ...grpc.CallOption) (*RemoveEmailResponse, error)
+	// SetPrimaryEmail makes one of the user's addresses the primary one.
+	// The previous primary address is kept as a secondary address.
+	SetPrimaryEmail(ctx context.Context, in *SetPrimaryEmailRequest, opts ...grpc.CallOption) (*SetPrimaryEmailResponse, error)
+	// UploadAvatar sets the profile picture of the user. The first message
+	// carries the user ID, the following ones the chunks of a JPEG or PNG image.
+	// The gateway accepts multipart uploads at "/v1/user/{id}/avatar".
+	UploadAvatar(ctx context.Context, opts ...grpc.CallOption) (UserService_UploadAvatarClient, error)
+	// ExportUsers streams a dump of the users in the requested format. The
+	// gateway serves it as a file download at "/v1/user/export".
+	ExportUsers(ctx context.Context, in *ExportUsersRequest, opts ...grpc.CallOption) (UserService_ExportUsersClient, error)
+	// RequestGuardianConsent asks an adult user to consent to the account of a
+	// minor pending guardian consent. The guardian is notified by the
+	// "user.guardian_consent_requested" event carrying the consent ID.
+	RequestGuardianConsent(ctx context.Context, in *RequestGuardianConsentRequest, opts ...grpc.CallOption) (*RequestGuardianConsentResponse, error)
+	// ConfirmGuardianConsent links the guardian to the minor and activates
+	// the minor's account.
+	ConfirmGuardianConsent(ctx context.Context, in *ConfirmGuardianConsentRequest, opts ...grpc.CallOption) (*ConfirmGuardianConsentResponse, error)
 }
 
 type userServiceClient struct {
@@ -68,6 +119,252 @@ func (c *userServiceClient) DeleteUser(ctx context.Context, in *DeleteUserReques
 	return out, nil
 }
 
+func (c *userServiceClient) EraseUser(ctx context.Context, in *EraseUserRequest, opts ...grpc.CallOption) (*EraseUserResponse, error) {
+	out := new(EraseUserResponse)
+	err := c.cc.Invoke(ctx, "/user.UserService/EraseUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) RequestDataExport(ctx context.Context, in *RequestDataExportRequest, opts ...grpc.CallOption) (*RequestDataExportResponse, error) {
+	out := new(RequestDataExportResponse)
+	err := c.cc.Invoke(ctx, "/user.UserService/RequestDataExport", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetDataExport(ctx context.Context, in *GetDataExportRequest, opts ...grpc.CallOption) (*GetDataExportResponse, error) {
+	out := new(GetDataExportResponse)
+	err := c.cc.Invoke(ctx, "/user.UserService/GetDataExport", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) RecordConsent(ctx context.Context, in *RecordConsentRequest, opts ...grpc.CallOption) (*RecordConsentResponse, error) {
+	out := new(RecordConsentResponse)
+	err := c.cc.Invoke(ctx, "/user.UserService/RecordConsent", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) WithdrawConsent(ctx context.Context, in *WithdrawConsentRequest, opts ...grpc.CallOption) (*WithdrawConsentResponse, error) {
+	out := new(WithdrawConsentResponse)
+	err := c.cc.Invoke(ctx, "/user.UserService/WithdrawConsent", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ListConsents(ctx context.Context, in *ListConsentsRequest, opts ...grpc.CallOption) (*ListConsentsResponse, error) {
+	out := new(ListConsentsResponse)
+	err := c.cc.Invoke(ctx, "/user.UserService/ListConsents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) PublishConsentPolicy(ctx context.Context, in *PublishConsentPolicyRequest, opts ...grpc.CallOption) (*PublishConsentPolicyResponse, error) {
+	out := new(PublishConsentPolicyResponse)
+	err := c.cc.Invoke(ctx, "/user.UserService/PublishConsentPolicy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ListUsersRequiringConsent(ctx context.Context, in *ListUsersRequiringConsentRequest, opts ...grpc.CallOption) (*ListUsersRequiringConsentResponse, error) {
+	out := new(ListUsersRequiringConsentResponse)
+	err := c.cc.Invoke(ctx, "/user.UserService/ListUsersRequiringConsent", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetPreferences(ctx context.Context, in *GetPreferencesRequest, opts ...grpc.CallOption) (*GetPreferencesResponse, error) {
+	out := new(GetPreferencesResponse)
+	err := c.cc.Invoke(ctx, "/user.UserService/GetPreferences", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) SetPreferences(ctx context.Context, in *SetPreferencesRequest, opts ...grpc.CallOption) (*SetPreferencesResponse, error) {
+	out := new(SetPreferencesResponse)
+	err := c.cc.Invoke(ctx, "/user.UserService/SetPreferences", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) DeletePreference(ctx context.Context, in *DeletePreferenceRequest, opts ...grpc.CallOption) (*DeletePreferenceResponse, error) {
+	out := new(DeletePreferenceResponse)
+	err := c.cc.Invoke(ctx, "/user.UserService/DeletePreference", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) CreateAddress(ctx context.Context, in *CreateAddressRequest, opts ...grpc.CallOption) (*CreateAddressResponse, error) {
+	out := new(CreateAddressResponse)
+	err := c.cc.Invoke(ctx, "/user.UserService/CreateAddress", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ListAddresses(ctx context.Context, in *ListAddressesRequest, opts ...grpc.CallOption) (*ListAddressesResponse, error) {
+	out := new(ListAddressesResponse)
+	err := c.cc.Invoke(ctx, "/user.UserService/ListAddresses", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) UpdateAddress(ctx context.Context, in *UpdateAddressRequest, opts ...grpc.CallOption) (*UpdateAddressResponse, error) {
+	out := new(UpdateAddressResponse)
+	err := c.cc.Invoke(ctx, "/user.UserService/UpdateAddress", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) DeleteAddress(ctx context.Context, in *DeleteAddressRequest, opts ...grpc.CallOption) (*DeleteAddressResponse, error) {
+	out := new(DeleteAddressResponse)
+	err := c.cc.Invoke(ctx, "/user.UserService/DeleteAddress", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) AddEmail(ctx context.Context, in *AddEmailRequest, opts ...grpc.CallOption) (*AddEmailResponse, error) {
+	out := new(AddEmailResponse)
+	err := c.cc.Invoke(ctx, "/user.UserService/AddEmail", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) RemoveEmail(ctx context.Context, in *RemoveEmailRequest, opts ...grpc.CallOption) (*RemoveEmailResponse, error) {
+	out := new(RemoveEmailResponse)
+	err := c.cc.Invoke(ctx, "/user.UserService/RemoveEmail", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) SetPrimaryEmail(ctx context.Context, in *SetPrimaryEmailRequest, opts ...grpc.CallOption) (*SetPrimaryEmailResponse, error) {
+	out := new(SetPrimaryEmailResponse)
+	err := c.cc.Invoke(ctx, "/user.UserService/SetPrimaryEmail", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) UploadAvatar(ctx context.Context, opts ...grpc.CallOption) (UserService_UploadAvatarClient, error) {
+	stream, err := c.cc.NewStream(ctx, &UserService_ServiceDesc.Streams[0], "/user.UserService/UploadAvatar", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &userServiceUploadAvatarClient{stream}
+	return x, nil
+}
+
+type UserService_UploadAvatarClient interface {
+	Send(*UploadAvatarRequest) error
+	CloseAndRecv() (*UploadAvatarResponse, error)
+	grpc.ClientStream
+}
+
+type userServiceUploadAvatarClient struct {
+	grpc.ClientStream
+}
+
+func (x *userServiceUploadAvatarClient) Send(m *UploadAvatarRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *userServiceUploadAvatarClient) CloseAndRecv() (*UploadAvatarResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(UploadAvatarResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *userServiceClient) ExportUsers(ctx context.Context, in *ExportUsersRequest, opts ...grpc.CallOption) (UserService_ExportUsersClient, error) {
+	stream, err := c.cc.NewStream(ctx, &UserService_ServiceDesc.Streams[1], "/user.UserService/ExportUsers", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &userServiceExportUsersClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type UserService_ExportUsersClient interface {
+	Recv() (*ExportUsersResponse, error)
+	grpc.ClientStream
+}
+
+type userServiceExportUsersClient struct {
+	grpc.ClientStream
+}
+
+func (x *userServiceExportUsersClient) Recv() (*ExportUsersResponse, error) {
+	m := new(ExportUsersResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *userServiceClient) RequestGuardianConsent(ctx context.Context, in *RequestGuardianConsentRequest, opts ...grpc.CallOption) (*RequestGuardianConsentResponse, error) {
+	out := new(RequestGuardianConsentResponse)
+	err := c.cc.Invoke(ctx, "/user.UserService/RequestGuardianConsent", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ConfirmGuardianConsent(ctx context.Context, in *ConfirmGuardianConsentRequest, opts ...grpc.CallOption) (*ConfirmGuardianConsentResponse, error) {
+	out := new(ConfirmGuardianConsentResponse)
+	err := c.cc.Invoke(ctx, "/user.UserService/ConfirmGuardianConsent", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // UserServiceServer is the server API for UserService service.
 // All implementations must embed UnimplementedUserServiceServer
 // for forward compatibility
@@ -76,6 +373,57 @@ type UserServiceServer interface {
 	GetUser(context.Context, *GetUserRequest) (*GetUserResponse, error)
 	UpdateUser(context.Context, *UpdateUserRequest) (*UpdateUserResponse, error)
 	DeleteUser(context.Context, *DeleteUserRequest) (*DeleteUserResponse, error)
+	// EraseUser irreversibly anonymizes the personal data of the user. Unlike
+	// DeleteUser, the record is kept so references from other systems stay valid.
+	EraseUser(context.Context, *EraseUserRequest) (*EraseUserResponse, error)
+	// RequestDataExport assembles all data held about the user into a signed
+	// JSON archive.
+	RequestDataExport(context.Context, *RequestDataExportRequest) (*RequestDataExportResponse, error)
+	// GetDataExport returns the state of the export and the archive once it is ready.
+	GetDataExport(context.Context, *GetDataExportRequest) (*GetDataExportResponse, error)
+	// RecordConsent records the user's acceptance of a policy version.
+	RecordConsent(context.Context, *RecordConsentRequest) (*RecordConsentResponse, error)
+	// WithdrawConsent withdraws all active consents of the user to the policy kind.
+	WithdrawConsent(context.Context, *WithdrawConsentRequest) (*WithdrawConsentResponse, error)
+	ListConsents(context.Context, *ListConsentsRequest) (*ListConsentsResponse, error)
+	// PublishConsentPolicy publishes a new version of a policy the users
+	// consent to.
+	PublishConsentPolicy(context.Context, *PublishConsentPolicyRequest) (*PublishConsentPolicyResponse, error)
+	// ListUsersRequiringConsent pages through the users, ordered by ID, who
+	// have not consented to the latest required version of the policy kind.
+	// No users are listed if no such version is published.
+	ListUsersRequiringConsent(context.Context, *ListUsersRequiringConsentRequest) (*ListUsersRequiringConsentResponse, error)
+	// GetPreferences returns the user's preferences completed with the defaults.
+	GetPreferences(context.Context, *GetPreferencesRequest) (*GetPreferencesResponse, error)
+	SetPreferences(context.Context, *SetPreferencesRequest) (*SetPreferencesResponse, error)
+	// DeletePreference resets the preference to its default.
+	DeletePreference(context.Context, *DeletePreferenceRequest) (*DeletePreferenceResponse, error)
+	CreateAddress(context.Context, *CreateAddressRequest) (*CreateAddressResponse, error)
+	ListAddresses(context.Context, *ListAddressesRequest) (*ListAddressesResponse, error)
+	// UpdateAddress replaces all fields of the address.
+	UpdateAddress(context.Context, *UpdateAddressRequest) (*UpdateAddressResponse, error)
+	DeleteAddress(context.Context, *DeleteAddressRequest) (*DeleteAddressResponse, error)
+	// AddEmail adds a secondary email address to the user.
+	AddEmail(context.Context, *AddEmailRequest) (*AddEmailResponse, error)
+	// RemoveEmail removes a secondary email address. The primary address can not be removed.
+	RemoveEmail(context.Context, *RemoveEmailRequest) (*RemoveEmailResponse, error)
+	// SetPrimaryEmail makes one of the user's addresses the primary one.
+	// The previous primary address is kept as a secondary address.
+	SetPrimaryEmail(context.Context, *SetPrimaryEmailRequest) (*SetPrimaryEmailResponse, error)
+	// UploadAvatar sets the profile picture of the user. The first message
+	// carries the user ID, the following ones the chunks of a JPEG or PNG image.
+	// The gateway accepts multipart uploads at "/v1/user/{id}/avatar".
+	UploadAvatar(UserService_UploadAvatarServer) error
+	// ExportUsers streams a dump of the users in the requested format. The
+	// gateway serves it as a file download at "/v1/user/export".
+	ExportUsers(*ExportUsersRequest, UserService_ExportUsersServer) error
+	// RequestGuardianConsent asks an adult user to consent to the account of a
+	// minor pending guardian consent. The guardian is notified by the
+	// "user.guardian_consent_requested" event carrying the consent ID.
+	RequestGuardianConsent(context.Context, *RequestGuardianConsentRequest) (*RequestGuardianConsentResponse, error)
+	// ConfirmGuardianConsent links the guardian to the minor and activates
+	// the minor's account.
+	ConfirmGuardianConsent(context.Context, *ConfirmGuardianConsentRequest) (*ConfirmGuardianConsentResponse, error)
 	mustEmbedUnimplementedUserServiceServer()
 }
 
@@ -95,6 +443,72 @@ func (UnimplementedUserServiceServer) UpdateUser(context.Context, *UpdateUserReq
 func (UnimplementedUserServiceServer) DeleteUser(context.Context, *DeleteUserRequest) (*DeleteUserResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteUser not implemented")
 }
+func (UnimplementedUserServiceServer) EraseUser(context.Context, *EraseUserRequest) (*EraseUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EraseUser not implemented")
+}
+func (UnimplementedUserServiceServer) RequestDataExport(context.Context, *RequestDataExportRequest) (*RequestDataExportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestDataExport not implemented")
+}
+func (UnimplementedUserServiceServer) GetDataExport(context.Context, *GetDataExportRequest) (*GetDataExportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDataExport not implemented")
+}
+func (UnimplementedUserServiceServer) RecordConsent(context.Context, *RecordConsentRequest) (*RecordConsentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RecordConsent not implemented")
+}
+func (UnimplementedUserServiceServer) WithdrawConsent(context.Context, *WithdrawConsentRequest) (*WithdrawConsentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WithdrawConsent not implemented")
+}
+func (UnimplementedUserServiceServer) ListConsents(context.Context, *ListConsentsRequest) (*ListConsentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListConsents not implemented")
+}
+func (UnimplementedUserServiceServer) PublishConsentPolicy(context.Context, *PublishConsentPolicyRequest) (*PublishConsentPolicyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PublishConsentPolicy not implemented")
+}
+func (UnimplementedUserServiceServer) ListUsersRequiringConsent(context.Context, *ListUsersRequiringConsentRequest) (*ListUsersRequiringConsentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUsersRequiringConsent not implemented")
+}
+func (UnimplementedUserServiceServer) GetPreferences(context.Context, *GetPreferencesRequest) (*GetPreferencesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPreferences not implemented")
+}
+func (UnimplementedUserServiceServer) SetPreferences(context.Context, *SetPreferencesRequest) (*SetPreferencesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetPreferences not implemented")
+}
+func (UnimplementedUserServiceServer) DeletePreference(context.Context, *DeletePreferenceRequest) (*DeletePreferenceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeletePreference not implemented")
+}
+func (UnimplementedUserServiceServer) CreateAddress(context.Context, *CreateAddressRequest) (*CreateAddressResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAddress not implemented")
+}
+func (UnimplementedUserServiceServer) ListAddresses(context.Context, *ListAddressesRequest) (*ListAddressesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAddresses not implemented")
+}
+func (UnimplementedUserServiceServer) UpdateAddress(context.Context, *UpdateAddressRequest) (*UpdateAddressResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateAddress not implemented")
+}
+func (UnimplementedUserServiceServer) DeleteAddress(context.Context, *DeleteAddressRequest) (*DeleteAddressResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAddress not implemented")
+}
+func (UnimplementedUserServiceServer) AddEmail(context.Context, *AddEmailRequest) (*AddEmailResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddEmail not implemented")
+}
+func (UnimplementedUserServiceServer) RemoveEmail(context.Context, *RemoveEmailRequest) (*RemoveEmailResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveEmail not implemented")
+}
+func (UnimplementedUserServiceServer) SetPrimaryEmail(context.Context, *SetPrimaryEmailRequest) (*SetPrimaryEmailResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetPrimaryEmail not implemented")
+}
+func (UnimplementedUserServiceServer) UploadAvatar(UserService_UploadAvatarServer) error {
+	return status.Errorf(codes.Unimplemented, "method UploadAvatar not implemented")
+}
+func (UnimplementedUserServiceServer) ExportUsers(*ExportUsersRequest, UserService_ExportUsersServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportUsers not implemented")
+}
+func (UnimplementedUserServiceServer) RequestGuardianConsent(context.Context, *RequestGuardianConsentRequest) (*RequestGuardianConsentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestGuardianConsent not implemented")
+}
+func (UnimplementedUserServiceServer) ConfirmGuardianConsent(context.Context, *ConfirmGuardianConsentRequest) (*ConfirmGuardianConsentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmGuardianConsent not implemented")
+}
 func (UnimplementedUserServiceServer) mustEmbedUnimplementedUserServiceServer() {}
 
 // UnsafeUserServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -180,6 +594,413 @@ func _UserService_DeleteUser_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UserService_EraseUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EraseUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).EraseUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.UserService/EraseUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).EraseUser(ctx, req.(*EraseUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_RequestDataExport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestDataExportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RequestDataExport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.UserService/RequestDataExport",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RequestDataExport(ctx, req.(*RequestDataExportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetDataExport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDataExportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetDataExport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.UserService/GetDataExport",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetDataExport(ctx, req.(*GetDataExportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_RecordConsent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecordConsentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RecordConsent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.UserService/RecordConsent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RecordConsent(ctx, req.(*RecordConsentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_WithdrawConsent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WithdrawConsentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).WithdrawConsent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.UserService/WithdrawConsent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).WithdrawConsent(ctx, req.(*WithdrawConsentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ListConsents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListConsentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ListConsents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.UserService/ListConsents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ListConsents(ctx, req.(*ListConsentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_PublishConsentPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublishConsentPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).PublishConsentPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.UserService/PublishConsentPolicy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).PublishConsentPolicy(ctx, req.(*PublishConsentPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ListUsersRequiringConsent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUsersRequiringConsentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ListUsersRequiringConsent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.UserService/ListUsersRequiringConsent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ListUsersRequiringConsent(ctx, req.(*ListUsersRequiringConsentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetPreferences_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPreferencesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetPreferences(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.UserService/GetPreferences",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetPreferences(ctx, req.(*GetPreferencesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_SetPreferences_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetPreferencesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).SetPreferences(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.UserService/SetPreferences",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).SetPreferences(ctx, req.(*SetPreferencesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_DeletePreference_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeletePreferenceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).DeletePreference(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.UserService/DeletePreference",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).DeletePreference(ctx, req.(*DeletePreferenceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_CreateAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).CreateAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.UserService/CreateAddress",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).CreateAddress(ctx, req.(*CreateAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ListAddresses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAddressesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ListAddresses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.UserService/ListAddresses",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ListAddresses(ctx, req.(*ListAddressesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_UpdateAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).UpdateAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.UserService/UpdateAddress",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).UpdateAddress(ctx, req.(*UpdateAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_DeleteAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).DeleteAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.UserService/DeleteAddress",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).DeleteAddress(ctx, req.(*DeleteAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_AddEmail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddEmailRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).AddEmail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.UserService/AddEmail",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).AddEmail(ctx, req.(*AddEmailRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_RemoveEmail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveEmailRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RemoveEmail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.UserService/RemoveEmail",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RemoveEmail(ctx, req.(*RemoveEmailRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_SetPrimaryEmail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetPrimaryEmailRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).SetPrimaryEmail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.UserService/SetPrimaryEmail",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).SetPrimaryEmail(ctx, req.(*SetPrimaryEmailRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_UploadAvatar_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(UserServiceServer).UploadAvatar(&userServiceUploadAvatarServer{stream})
+}
+
+type UserService_UploadAvatarServer interface {
+	SendAndClose(*UploadAvatarResponse) error
+	Recv() (*UploadAvatarRequest, error)
+	grpc.ServerStream
+}
+
+type userServiceUploadAvatarServer struct {
+	grpc.ServerStream
+}
+
+func (x *userServiceUploadAvatarServer) SendAndClose(m *UploadAvatarResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *userServiceUploadAvatarServer) Recv() (*UploadAvatarRequest, error) {
+	m := new(UploadAvatarRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _UserService_ExportUsers_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportUsersRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(UserServiceServer).ExportUsers(m, &userServiceExportUsersServer{stream})
+}
+
+type UserService_ExportUsersServer interface {
+	Send(*ExportUsersResponse) error
+	grpc.ServerStream
+}
+
+type userServiceExportUsersServer struct {
+	grpc.ServerStream
+}
+
+func (x *userServiceExportUsersServer) Send(m *ExportUsersResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _UserService_RequestGuardianConsent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestGuardianConsentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RequestGuardianConsent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.UserService/RequestGuardianConsent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RequestGuardianConsent(ctx, req.(*RequestGuardianConsentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ConfirmGuardianConsent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfirmGuardianConsentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ConfirmGuardianConsent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.UserService/ConfirmGuardianConsent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ConfirmGuardianConsent(ctx, req.(*ConfirmGuardianConsentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // UserService_ServiceDesc is the grpc.ServiceDesc for UserService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -203,7 +1024,98 @@ var UserService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteUser",
 			Handler:    _UserService_DeleteUser_Handler,
 		},
+		{
+			MethodName: "EraseUser",
+			Handler:    _UserService_EraseUser_Handler,
+		},
+		{
+			MethodName: "RequestDataExport",
+			Handler:    _UserService_RequestDataExport_Handler,
+		},
+		{
+			MethodName: "GetDataExport",
+			Handler:    _UserService_GetDataExport_Handler,
+		},
+		{
+			MethodName: "RecordConsent",
+			Handler:    _UserService_RecordConsent_Handler,
+		},
+		{
+			MethodName: "WithdrawConsent",
+			Handler:    _UserService_WithdrawConsent_Handler,
+		},
+		{
+			MethodName: "ListConsents",
+			Handler:    _UserService_ListConsents_Handler,
+		},
+		{
+			MethodName: "PublishConsentPolicy",
+			Handler:    _UserService_PublishConsentPolicy_Handler,
+		},
+		{
+			MethodName: "ListUsersRequiringConsent",
+			Handler:    _UserService_ListUsersRequiringConsent_Handler,
+		},
+		{
+			MethodName: "GetPreferences",
+			Handler:    _UserService_GetPreferences_Handler,
+		},
+		{
+			MethodName: "SetPreferences",
+			Handler:    _UserService_SetPreferences_Handler,
+		},
+		{
+			MethodName: "DeletePreference",
+			Handler:    _UserService_DeletePreference_Handler,
+		},
+		{
+			MethodName: "CreateAddress",
+			Handler:    _UserService_CreateAddress_Handler,
+		},
+		{
+			MethodName: "ListAddresses",
+			Handler:    _UserService_ListAddresses_Handler,
+		},
+		{
+			MethodName: "UpdateAddress",
+			Handler:    _UserService_UpdateAddress_Handler,
+		},
+		{
+			MethodName: "DeleteAddress",
+			Handler:    _UserService_DeleteAddress_Handler,
+		},
+		{
+			MethodName: "AddEmail",
+			Handler:    _UserService_AddEmail_Handler,
+		},
+		{
+			MethodName: "RemoveEmail",
+			Handler:    _UserService_RemoveEmail_Handler,
+		},
+		{
+			MethodName: "SetPrimaryEmail",
+			Handler:    _UserService_SetPrimaryEmail_Handler,
+		},
+		{
+			MethodName: "RequestGuardianConsent",
+			Handler:    _UserService_RequestGuardianConsent_Handler,
+		},
+		{
+			MethodName: "ConfirmGuardianConsent",
+			Handler:    _UserService_ConfirmGuardianConsent_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "UploadAvatar",
+			Handler:       _UserService_UploadAvatar_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ExportUsers",
+			Handler:       _UserService_ExportUsers_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "user_service.proto",
 }
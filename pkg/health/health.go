@@ -0,0 +1,217 @@
+// Package health reports the health of the user service to the orchestrators,
+// over the standard gRPC health service and the HTTP probes.
+package health
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Routes of the HTTP probes.
+const (
+	LivenessPath  = "/healthz"
+	ReadinessPath = "/readyz"
+)
+
+var (
+	// ErrShuttingDown is reported by the readiness probe during the graceful shutdown.
+	ErrShuttingDown = errors.New("shutting down")
+
+	// ErrNotChecked is reported by the readiness probe before the first check.
+	ErrNotChecked = errors.New("not checked yet")
+
+	// ErrSchemaBehind is returned if the database schema is older than required.
+	ErrSchemaBehind = errors.New("database schema is behind")
+
+	// ErrSchemaDirty is returned if the last migration of the database failed.
+	ErrSchemaDirty = errors.New("database schema is dirty")
+)
+
+// Pinger verifies the connection to the database, e.g. *sql.DB.
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// Probe checks a condition of the readiness.
+type Probe func(ctx context.Context) error
+
+// SchemaProbe checks the version of the migrations applied to the database
+// by golang-migrate is at least the version required by the service.
+func SchemaProbe(db *sql.DB, required uint) Probe {
+	return func(ctx context.Context) error {
+		var version uint
+		var dirty bool
+		err := db.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return fmt.Errorf("%w: no migration applied, %d required", ErrSchemaBehind, required)
+		case err != nil:
+			return fmt.Errorf("failed to read the schema version: %w", err)
+		case dirty:
+			return fmt.Errorf("%w: version %d", ErrSchemaDirty, version)
+		case version < required:
+			return fmt.Errorf("%w: version %d, %d required", ErrSchemaBehind, version, required)
+		}
+
+		return nil
+	}
+}
+
+// Checker periodically pings the database and reports the result as the
+// serving status of the gRPC services. The readiness additionally requires
+// the schema probe to pass.
+type Checker struct {
+	db       Pinger
+	schema   Probe
+	logger   *zap.Logger
+	services []string
+	server   *health.Server
+
+	mu       sync.RWMutex
+	checked  bool
+	dbErr    error
+	readyErr error
+	shutdown bool
+}
+
+// NewChecker constructs a Checker of the database reporting the statuses of
+// the gRPC services and of the server as a whole. The schema probe is
+// optional. All services are NOT_SERVING until the first check.
+func NewChecker(db Pinger, schema Probe, logger *zap.Logger, services ...string) *Checker {
+	c := &Checker{
+		db:       db,
+		schema:   schema,
+		logger:   logger,
+		services: append([]string{""}, services...),
+		server:   health.NewServer(),
+	}
+	c.setStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+
+	return c
+}
+
+// Server returns the gRPC health service.
+func (c *Checker) Server() healthpb.HealthServer {
+	return c.server
+}
+
+// Run checks the database immediately and then every interval until the
+// context is done. Each check is bounded by the interval.
+func (c *Checker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		checkCtx, cancel := context.WithTimeout(ctx, interval)
+		c.Check(checkCtx)
+		cancel()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check pings the database, runs the schema probe and updates the statuses.
+func (c *Checker) Check(ctx context.Context) {
+	dbErr := c.db.PingContext(ctx)
+	readyErr := dbErr
+	if readyErr == nil && c.schema != nil {
+		readyErr = c.schema(ctx)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.shutdown {
+		return
+	}
+
+	switch {
+	case dbErr != nil && (!c.checked || c.dbErr == nil):
+		c.logger.Warn("database is unavailable", zap.Error(dbErr))
+	case dbErr == nil && c.checked && c.dbErr != nil:
+		c.logger.Info("database is available again")
+	}
+	if readyErr != nil && dbErr == nil && (!c.checked || c.readyErr == nil) {
+		c.logger.Warn("service is not ready", zap.Error(readyErr))
+	}
+
+	c.checked = true
+	c.dbErr = dbErr
+	c.readyErr = readyErr
+
+	if dbErr != nil {
+		c.setStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+	} else {
+		c.setStatus(healthpb.HealthCheckResponse_SERVING)
+	}
+}
+
+// Shutdown reports all services as NOT_SERVING and the service as not ready
+// for the rest of its lifetime, so that the traffic is drained before the
+// servers stop.
+func (c *Checker) Shutdown() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.shutdown = true
+	c.server.Shutdown()
+}
+
+// Ready returns the reason why the service is not ready, or nil.
+func (c *Checker) Ready() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	switch {
+	case c.shutdown:
+		return ErrShuttingDown
+	case !c.checked:
+		return ErrNotChecked
+	}
+
+	return c.readyErr
+}
+
+// Liveness serves the liveness probe. The process is alive as long as it
+// responds, the dependencies are not considered.
+func (c *Checker) Liveness(w http.ResponseWriter, _ *http.Request) {
+	writeStatus(w, nil)
+}
+
+// Readiness serves the readiness probe.
+func (c *Checker) Readiness(w http.ResponseWriter, _ *http.Request) {
+	writeStatus(w, c.Ready())
+}
+
+func (c *Checker) setStatus(status healthpb.HealthCheckResponse_ServingStatus) {
+	for _, s := range c.services {
+		c.server.SetServingStatus(s, status)
+	}
+}
+
+// writeStatus writes "ok", or the error with the 503 status code.
+func writeStatus(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+
+	if err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = fmt.Fprintln(w, err)
+		return
+	}
+
+	_, _ = fmt.Fprintln(w, "ok")
+}
@@ -0,0 +1,144 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/chutommy/user-microservice/pkg/health"
+)
+
+const service = "user.UserService"
+
+type pinger struct {
+	err error
+}
+
+func (p *pinger) PingContext(context.Context) error {
+	return p.err
+}
+
+func status(t *testing.T, c *health.Checker, service string) healthpb.HealthCheckResponse_ServingStatus {
+	t.Helper()
+
+	resp, err := c.Server().Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	require.NoError(t, err)
+
+	return resp.GetStatus()
+}
+
+func probe(c *health.Checker, h func(*health.Checker) http.HandlerFunc) int {
+	rec := httptest.NewRecorder()
+	h(c)(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	return rec.Code
+}
+
+func liveness(c *health.Checker) http.HandlerFunc  { return c.Liveness }
+func readiness(c *health.Checker) http.HandlerFunc { return c.Readiness }
+
+func TestChecker(t *testing.T) {
+	t.Parallel()
+
+	behind := fmt.Errorf("%w: version 11, 12 required", health.ErrSchemaBehind)
+
+	tests := []struct {
+		name      string
+		pingErr   error
+		schemaErr error
+		status    healthpb.HealthCheckResponse_ServingStatus
+		ready     error
+	}{
+		{
+			name:   "ok",
+			status: healthpb.HealthCheckResponse_SERVING,
+		},
+		{
+			name:    "database down",
+			pingErr: errors.New("connection refused"),
+			status:  healthpb.HealthCheckResponse_NOT_SERVING,
+			ready:   errors.New("connection refused"),
+		},
+		{
+			name:      "schema behind",
+			schemaErr: behind,
+			status:    healthpb.HealthCheckResponse_SERVING,
+			ready:     behind,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			schema := func(context.Context) error { return tt.schemaErr }
+			c := health.NewChecker(&pinger{err: tt.pingErr}, schema, zap.NewNop(), service)
+
+			// nothing is served before the first check
+			require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status(t, c, service))
+			require.ErrorIs(t, c.Ready(), health.ErrNotChecked)
+
+			c.Check(context.Background())
+			require.Equal(t, tt.status, status(t, c, ""))
+			require.Equal(t, tt.status, status(t, c, service))
+			require.Equal(t, tt.ready, c.Ready())
+			require.Equal(t, http.StatusOK, probe(c, liveness))
+			if tt.ready == nil {
+				require.Equal(t, http.StatusOK, probe(c, readiness))
+			} else {
+				require.Equal(t, http.StatusServiceUnavailable, probe(c, readiness))
+			}
+		})
+	}
+}
+
+func TestChecker_Recovery(t *testing.T) {
+	t.Parallel()
+
+	p := &pinger{err: errors.New("connection refused")}
+	c := health.NewChecker(p, nil, zap.NewNop(), service)
+
+	c.Check(context.Background())
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status(t, c, service))
+
+	p.err = nil
+	c.Check(context.Background())
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, status(t, c, service))
+	require.NoError(t, c.Ready())
+}
+
+func TestChecker_Shutdown(t *testing.T) {
+	t.Parallel()
+
+	c := health.NewChecker(&pinger{}, nil, zap.NewNop(), service)
+	c.Check(context.Background())
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, status(t, c, service))
+
+	c.Shutdown()
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status(t, c, service))
+	require.ErrorIs(t, c.Ready(), health.ErrShuttingDown)
+	require.Equal(t, http.StatusServiceUnavailable, probe(c, readiness))
+	require.Equal(t, http.StatusOK, probe(c, liveness))
+
+	// the later checks do not revive the service
+	c.Check(context.Background())
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status(t, c, service))
+	require.ErrorIs(t, c.Ready(), health.ErrShuttingDown)
+}
+
+func TestChecker_Unknown(t *testing.T) {
+	t.Parallel()
+
+	c := health.NewChecker(&pinger{}, nil, zap.NewNop(), service)
+	_, err := c.Server().Check(context.Background(), &healthpb.HealthCheckRequest{Service: "unknown"})
+	require.Error(t, err)
+}
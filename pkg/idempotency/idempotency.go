@@ -0,0 +1,304 @@
+// Package idempotency makes the retries of the mutating calls safe. The
+// response of the first call with an idempotency key is stored and replayed
+// to the retries with the same key and request.
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/chutommy/user-microservice/pkg/apierror"
+	"github.com/chutommy/user-microservice/pkg/certs"
+	"github.com/chutommy/user-microservice/pkg/repo"
+	"github.com/chutommy/user-microservice/pkg/util"
+)
+
+const (
+	// MetadataKey is the metadata key of the idempotency key.
+	MetadataKey = "idempotency-key"
+
+	// HeaderName is the HTTP header of the idempotency key, the gateway
+	// forwards it as the metadata.
+	HeaderName = "Idempotency-Key"
+
+	// ReplayedMetadataKey is the header metadata set on the replayed
+	// responses.
+	ReplayedMetadataKey = "idempotent-replayed"
+
+	// MaxKeyLength is the maximum length of an idempotency key.
+	MaxKeyLength = 255
+
+	// writeTimeout bounds the storing and releasing of a key, which must
+	// not be canceled with the call.
+	writeTimeout = 5 * time.Second
+)
+
+// Keys stores the idempotency keys with the responses of their calls.
+type Keys struct {
+	repo        repo.Querier
+	ttl         time.Duration
+	lockTimeout time.Duration
+	methods     map[string]bool
+	logger      *zap.Logger
+}
+
+// New returns the idempotency keys of the full methods, e.g.
+// "/user.UserService/RegisterUser". The responses are kept for the ttl. A
+// call which did not complete within the lock timeout releases its key.
+func New(q repo.Querier, ttl, lockTimeout time.Duration, logger *zap.Logger, methods ...string) *Keys {
+	k := &Keys{
+		repo:        q,
+		ttl:         ttl,
+		lockTimeout: lockTimeout,
+		methods:     make(map[string]bool, len(methods)),
+		logger:      logger,
+	}
+	for _, m := range methods {
+		k.methods[m] = true
+	}
+
+	return k
+}
+
+// keyFromContext returns the idempotency key of the call, an empty string if
+// the client sent none.
+func keyFromContext(ctx context.Context) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(MetadataKey)
+
+	switch {
+	case len(values) == 0:
+		return "", nil
+	case len(values) > 1:
+		return "", status.Errorf(codes.InvalidArgument, "multiple idempotency keys")
+	case values[0] == "" || len(values[0]) > MaxKeyLength:
+		return "", status.Errorf(codes.InvalidArgument, "idempotency key must have 1 to %d characters", MaxKeyLength)
+	}
+
+	return values[0], nil
+}
+
+// fingerprint returns the hash of the request of the method.
+func fingerprint(method string, req interface{}) ([]byte, error) {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "request is not a proto message")
+	}
+
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to encode the request")
+	}
+
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write(data)
+
+	return h.Sum(nil), nil
+}
+
+// replay returns the stored response of the key, or an error if the key may
+// not be used for the request.
+func replay(ctx context.Context, stored repo.IdempotencyKey, sum []byte) (interface{}, error) {
+	if !bytes.Equal(stored.Fingerprint, sum) {
+		return nil, apierror.WithReason(codes.InvalidArgument, apierror.ReasonIdempotencyKeyReused, nil,
+			"idempotency key was used with a different request")
+	}
+	if stored.Response == nil {
+		return nil, apierror.WithReason(codes.Aborted, apierror.ReasonIdempotencyKeyInUse, nil,
+			"request with the idempotency key is in progress")
+	}
+
+	var a anypb.Any
+	if err := proto.Unmarshal(stored.Response, &a); err != nil {
+		return nil, err
+	}
+	resp, err := a.UnmarshalNew()
+	if err != nil {
+		return nil, err
+	}
+
+	_ = grpc.SetHeader(ctx, metadata.Pairs(ReplayedMetadataKey, "true"))
+	return resp, nil
+}
+
+// UnaryServerInterceptor replays the stored responses to the calls of the
+// methods with a used idempotency key. The first call reserves the key, its
+// response is stored on success and the key is released on failure, unless
+// the reservation was taken over as stale in the meantime. It must
+// be chained after the interceptor of the caller identity, the keys of the
+// callers are distinct. The interceptor of nil Keys calls the handlers only.
+func (k *Keys) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if k == nil || !k.methods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		key, err := keyFromContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if key == "" {
+			return handler(ctx, req)
+		}
+
+		logger := ctxzap.Extract(ctx).With(zap.String("idempotency_key", key))
+		caller, _ := certs.CallerFromContext(ctx)
+
+		sum, err := fingerprint(info.FullMethod, req)
+		if err != nil {
+			return nil, err
+		}
+
+		// reserve the key
+		now := time.Now()
+		reserved, err := k.repo.ReserveIdempotencyKey(ctx, repo.ReserveIdempotencyKeyParams{
+			Caller:         caller,
+			Method:         info.FullMethod,
+			IdempotencyKey: key,
+			Fingerprint:    sum,
+			ExpiresAt:      now.Add(k.ttl),
+			StaleBefore:    now.Add(-k.lockTimeout),
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			stored, err := k.repo.GetIdempotencyKey(ctx, repo.GetIdempotencyKeyParams{
+				Caller:         caller,
+				Method:         info.FullMethod,
+				IdempotencyKey: key,
+			})
+			if errors.Is(err, sql.ErrNoRows) {
+				// the key expired or was released in the meantime
+				return nil, apierror.WithReason(codes.Aborted, apierror.ReasonIdempotencyKeyInUse, nil,
+					"request with the idempotency key is in progress")
+			}
+			if err != nil {
+				logger.Error("failed to retrieve the idempotency key", zap.Error(err))
+				return nil, status.Errorf(codes.Internal, "failed to check the idempotency key")
+			}
+
+			resp, err := replay(ctx, stored, sum)
+			if err != nil {
+				logger.Info("failed to replay the response", zap.Error(err))
+				return nil, err
+			}
+
+			logger.Info("response replayed")
+			return resp, nil
+		}
+		if err != nil {
+			logger.Error("failed to reserve the idempotency key", zap.Error(err))
+			return nil, status.Errorf(codes.Internal, "failed to check the idempotency key")
+		}
+
+		resp, err := handler(ctx, req)
+
+		// the outcome is recorded even if the client is gone, otherwise the
+		// retries are rejected until the lock timeout
+		wctx, cancel := context.WithTimeout(util.WithoutCancel(ctx), writeTimeout)
+		defer cancel()
+
+		if err != nil {
+			// the retries may succeed
+			n, rerr := k.repo.ReleaseIdempotencyKey(wctx, repo.ReleaseIdempotencyKeyParams{
+				Caller:         caller,
+				Method:         info.FullMethod,
+				IdempotencyKey: key,
+				Fingerprint:    sum,
+				ReservedAt:     reserved.CreatedAt,
+			})
+			switch {
+			case rerr != nil:
+				logger.Error("failed to release the idempotency key", zap.Error(rerr))
+			case n == 0:
+				logger.Warn("idempotency key was taken over before its release")
+			}
+			return nil, err
+		}
+
+		// the call succeeded even if the response is not stored
+		n, err := k.complete(wctx, repo.CompleteIdempotencyKeyParams{
+			Caller:         caller,
+			Method:         info.FullMethod,
+			IdempotencyKey: key,
+			Fingerprint:    sum,
+			ReservedAt:     reserved.CreatedAt,
+		}, resp)
+		switch {
+		case err != nil:
+			logger.Error("failed to store the response of the idempotency key", zap.Error(err))
+		case n == 0:
+			logger.Warn("idempotency key was taken over before the response was stored")
+		}
+
+		return resp, nil
+	}
+}
+
+// complete stores the response of the reserved key. It returns the number of
+// the updated keys, zero if the reservation was taken over.
+func (k *Keys) complete(ctx context.Context, arg repo.CompleteIdempotencyKeyParams, resp interface{}) (int64, error) {
+	msg, ok := resp.(proto.Message)
+	if !ok {
+		return 0, errors.New("response is not a proto message")
+	}
+
+	a, err := anypb.New(msg)
+	if err != nil {
+		return 0, err
+	}
+	data, err := proto.Marshal(a)
+	if err != nil {
+		return 0, err
+	}
+
+	arg.Response = data
+	return k.repo.CompleteIdempotencyKey(ctx, arg)
+}
+
+// Run deletes the expired keys every interval until the context is done.
+func (k *Keys) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		n, err := k.repo.DeleteExpiredIdempotencyKeys(ctx)
+		switch {
+		case err != nil:
+			k.logger.Error("failed to delete the expired idempotency keys", zap.Error(err))
+		case n > 0:
+			k.logger.Debug("expired idempotency keys deleted", zap.Int64("count", n))
+		}
+	}
+}
+
+// GatewayMetadata is a runtime.WithMetadata option forwarding the idempotency
+// key of the HTTP request to the gRPC server.
+func GatewayMetadata(_ context.Context, r *http.Request) metadata.MD {
+	key := r.Header.Get(HeaderName)
+	if key == "" {
+		return nil
+	}
+
+	return metadata.Pairs(MetadataKey, key)
+}
@@ -0,0 +1,357 @@
+package idempotency_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/chutommy/user-microservice/pkg/apierror"
+	"github.com/chutommy/user-microservice/pkg/grpc/userpb"
+	"github.com/chutommy/user-microservice/pkg/idempotency"
+	"github.com/chutommy/user-microservice/pkg/mocks"
+	"github.com/chutommy/user-microservice/pkg/repo"
+)
+
+const (
+	method = "/user.UserService/RegisterUser"
+	id     = "4f6d3b8a-1c1e-4e7a-9a53-7c1c2f0f6a11"
+)
+
+var (
+	request  = &userpb.RegisterUserRequest{User: &userpb.User{Email: "alice@example.org"}}
+	response = &userpb.RegisterUserResponse{Id: id}
+)
+
+// call calls the interceptor of the keys with the key in the metadata.
+func call(q *mocks.Querier, fullMethod, key string, req proto.Message, handler grpc.UnaryHandler) (interface{}, error) {
+	keys := idempotency.New(q, 24*time.Hour, time.Minute, zap.NewNop(), method)
+
+	ctx := context.Background()
+	if key != "" {
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(idempotency.MetadataKey, key))
+	}
+
+	return keys.UnaryServerInterceptor()(ctx, req, &grpc.UnaryServerInfo{FullMethod: fullMethod}, handler)
+}
+
+// first makes the first call with the key and returns the stored key.
+func first(t *testing.T) repo.IdempotencyKey {
+	t.Helper()
+
+	var stored repo.IdempotencyKey
+	q := &mocks.Querier{}
+	q.On("ReserveIdempotencyKey", mock.Anything, mock.AnythingOfType("repo.ReserveIdempotencyKeyParams")).
+		Run(func(args mock.Arguments) {
+			arg := args.Get(1).(repo.ReserveIdempotencyKeyParams)
+			stored = repo.IdempotencyKey{Caller: arg.Caller, Method: arg.Method, IdempotencyKey: arg.IdempotencyKey, Fingerprint: arg.Fingerprint}
+		}).
+		Return(repo.IdempotencyKey{}, nil).Once()
+	q.On("CompleteIdempotencyKey", mock.Anything, mock.AnythingOfType("repo.CompleteIdempotencyKeyParams")).
+		Run(func(args mock.Arguments) {
+			stored.Response = args.Get(1).(repo.CompleteIdempotencyKeyParams).Response
+		}).
+		Return(int64(1), nil).Once()
+
+	resp, err := call(q, method, "k1", request, func(context.Context, interface{}) (interface{}, error) {
+		return response, nil
+	})
+	require.NoError(t, err)
+	require.True(t, proto.Equal(response, resp.(proto.Message)))
+	q.AssertExpectations(t)
+
+	require.Equal(t, "k1", stored.IdempotencyKey)
+	require.Equal(t, method, stored.Method)
+	require.NotEmpty(t, stored.Response)
+
+	return stored
+}
+
+func TestKeys_UnaryServerInterceptor(t *testing.T) {
+	t.Parallel()
+
+	stored := first(t)
+	inProgress := stored
+	inProgress.Response = nil
+	reservedAt := time.Now()
+
+	tests := []struct {
+		name       string
+		fullMethod string
+		key        string
+		req        proto.Message
+		buildRepo  func(q *mocks.Querier)
+		handlerErr error
+		called     bool
+		code       codes.Code
+		reason     string
+	}{
+		{
+			name:       "other method",
+			fullMethod: "/user.UserService/GetUser",
+			key:        "k1",
+			req:        request,
+			buildRepo:  func(q *mocks.Querier) {},
+			called:     true,
+		},
+		{
+			name:       "without key",
+			fullMethod: method,
+			req:        request,
+			buildRepo:  func(q *mocks.Querier) {},
+			called:     true,
+		},
+		{
+			name:       "long key",
+			fullMethod: method,
+			key:        strings.Repeat("k", idempotency.MaxKeyLength+1),
+			req:        request,
+			buildRepo:  func(q *mocks.Querier) {},
+			code:       codes.InvalidArgument,
+		},
+		{
+			name:       "failed call releases the key",
+			fullMethod: method,
+			key:        "k1",
+			req:        request,
+			buildRepo: func(q *mocks.Querier) {
+				q.On("ReserveIdempotencyKey", mock.Anything, mock.AnythingOfType("repo.ReserveIdempotencyKeyParams")).
+					Return(repo.IdempotencyKey{CreatedAt: reservedAt}, nil).Once()
+				q.On("ReleaseIdempotencyKey", mock.Anything, mock.MatchedBy(func(arg repo.ReleaseIdempotencyKeyParams) bool {
+					return arg.Method == method && arg.IdempotencyKey == "k1" &&
+						len(arg.Fingerprint) > 0 && arg.ReservedAt.Equal(reservedAt)
+				})).Return(int64(1), nil).Once()
+			},
+			handlerErr: status.Error(codes.AlreadyExists, "email address is already taken"),
+			called:     true,
+			code:       codes.AlreadyExists,
+		},
+		{
+			name:       "replay",
+			fullMethod: method,
+			key:        "k1",
+			req:        request,
+			buildRepo: func(q *mocks.Querier) {
+				q.On("ReserveIdempotencyKey", mock.Anything, mock.AnythingOfType("repo.ReserveIdempotencyKeyParams")).
+					Return(repo.IdempotencyKey{}, sql.ErrNoRows).Once()
+				q.On("GetIdempotencyKey", mock.Anything, repo.GetIdempotencyKeyParams{
+					Method:         method,
+					IdempotencyKey: "k1",
+				}).Return(stored, nil).Once()
+			},
+		},
+		{
+			name:       "different request",
+			fullMethod: method,
+			key:        "k1",
+			req:        &userpb.RegisterUserRequest{User: &userpb.User{Email: "bob@example.org"}},
+			buildRepo: func(q *mocks.Querier) {
+				q.On("ReserveIdempotencyKey", mock.Anything, mock.AnythingOfType("repo.ReserveIdempotencyKeyParams")).
+					Return(repo.IdempotencyKey{}, sql.ErrNoRows).Once()
+				q.On("GetIdempotencyKey", mock.Anything, mock.AnythingOfType("repo.GetIdempotencyKeyParams")).
+					Return(stored, nil).Once()
+			},
+			code:   codes.InvalidArgument,
+			reason: apierror.ReasonIdempotencyKeyReused,
+		},
+		{
+			name:       "in progress",
+			fullMethod: method,
+			key:        "k1",
+			req:        request,
+			buildRepo: func(q *mocks.Querier) {
+				q.On("ReserveIdempotencyKey", mock.Anything, mock.AnythingOfType("repo.ReserveIdempotencyKeyParams")).
+					Return(repo.IdempotencyKey{}, sql.ErrNoRows).Once()
+				q.On("GetIdempotencyKey", mock.Anything, mock.AnythingOfType("repo.GetIdempotencyKeyParams")).
+					Return(inProgress, nil).Once()
+			},
+			code:   codes.Aborted,
+			reason: apierror.ReasonIdempotencyKeyInUse,
+		},
+		{
+			name:       "reservation failure",
+			fullMethod: method,
+			key:        "k1",
+			req:        request,
+			buildRepo: func(q *mocks.Querier) {
+				q.On("ReserveIdempotencyKey", mock.Anything, mock.AnythingOfType("repo.ReserveIdempotencyKeyParams")).
+					Return(repo.IdempotencyKey{}, errors.New("connection reset")).Once()
+			},
+			code: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			q := &mocks.Querier{}
+			tt.buildRepo(q)
+
+			called := false
+			resp, err := call(q, tt.fullMethod, tt.key, tt.req, func(context.Context, interface{}) (interface{}, error) {
+				called = true
+				if tt.handlerErr != nil {
+					return nil, tt.handlerErr
+				}
+				return response, nil
+			})
+
+			q.AssertExpectations(t)
+			require.Equal(t, tt.called, called)
+			require.Equal(t, tt.code, status.Code(err))
+			if tt.code == codes.OK {
+				require.True(t, proto.Equal(response, resp.(proto.Message)))
+				return
+			}
+
+			details := status.Convert(err).Details()
+			if tt.reason == "" {
+				require.Empty(t, details)
+				return
+			}
+			require.Len(t, details, 1)
+			require.Equal(t, tt.reason, details[0].(*errdetails.ErrorInfo).GetReason())
+		})
+	}
+}
+
+func TestKeys_UnaryServerInterceptor_Canceled(t *testing.T) {
+	t.Parallel()
+
+	live := mock.MatchedBy(func(ctx context.Context) bool { return ctx.Err() == nil })
+
+	tests := []struct {
+		name       string
+		handlerErr error
+		buildRepo  func(q *mocks.Querier)
+	}{
+		{
+			name: "completed",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("CompleteIdempotencyKey", live, mock.AnythingOfType("repo.CompleteIdempotencyKeyParams")).
+					Return(int64(1), nil).Once()
+			},
+		},
+		{
+			name:       "released",
+			handlerErr: status.Error(codes.Unavailable, "database is unavailable"),
+			buildRepo: func(q *mocks.Querier) {
+				q.On("ReleaseIdempotencyKey", live, mock.AnythingOfType("repo.ReleaseIdempotencyKeyParams")).
+					Return(int64(1), nil).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			q := &mocks.Querier{}
+			q.On("ReserveIdempotencyKey", mock.Anything, mock.AnythingOfType("repo.ReserveIdempotencyKeyParams")).
+				Return(repo.IdempotencyKey{}, nil).Once()
+			tt.buildRepo(q)
+
+			keys := idempotency.New(q, 24*time.Hour, time.Minute, zap.NewNop(), method)
+			ctx, cancel := context.WithCancel(metadata.NewIncomingContext(context.Background(),
+				metadata.Pairs(idempotency.MetadataKey, "k1")))
+			defer cancel()
+
+			// the client disconnects once the handler returns
+			_, _ = keys.UnaryServerInterceptor()(ctx, request, &grpc.UnaryServerInfo{FullMethod: method},
+				func(context.Context, interface{}) (interface{}, error) {
+					cancel()
+					if tt.handlerErr != nil {
+						return nil, tt.handlerErr
+					}
+					return response, nil
+				})
+
+			q.AssertExpectations(t)
+		})
+	}
+}
+
+func TestKeys_UnaryServerInterceptor_TakenOver(t *testing.T) {
+	t.Parallel()
+
+	reservedAt := time.Now()
+	reservation := mock.MatchedBy(func(arg repo.CompleteIdempotencyKeyParams) bool {
+		return arg.ReservedAt.Equal(reservedAt)
+	})
+
+	tests := []struct {
+		name       string
+		handlerErr error
+		buildRepo  func(q *mocks.Querier)
+	}{
+		{
+			name: "completed",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("CompleteIdempotencyKey", mock.Anything, reservation).Return(int64(0), nil).Once()
+			},
+		},
+		{
+			name:       "released",
+			handlerErr: status.Error(codes.Unavailable, "database is unavailable"),
+			buildRepo: func(q *mocks.Querier) {
+				q.On("ReleaseIdempotencyKey", mock.Anything, mock.MatchedBy(func(arg repo.ReleaseIdempotencyKeyParams) bool {
+					return arg.ReservedAt.Equal(reservedAt)
+				})).Return(int64(0), nil).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// the key is taken over while the handler runs, the call keeps
+			// its outcome and the new reservation is left intact
+			q := &mocks.Querier{}
+			q.On("ReserveIdempotencyKey", mock.Anything, mock.AnythingOfType("repo.ReserveIdempotencyKeyParams")).
+				Return(repo.IdempotencyKey{CreatedAt: reservedAt}, nil).Once()
+			tt.buildRepo(q)
+
+			_, err := call(q, method, "k1", request, func(context.Context, interface{}) (interface{}, error) {
+				if tt.handlerErr != nil {
+					return nil, tt.handlerErr
+				}
+				return response, nil
+			})
+
+			q.AssertExpectations(t)
+			require.Equal(t, status.Code(tt.handlerErr), status.Code(err))
+		})
+	}
+}
+
+func TestGatewayMetadata(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequest(http.MethodPost, "/v1/user", nil)
+	require.Nil(t, idempotency.GatewayMetadata(context.Background(), r))
+
+	r.Header.Set(idempotency.HeaderName, "k1")
+	require.Equal(t, []string{"k1"}, idempotency.GatewayMetadata(context.Background(), r).Get(idempotency.MetadataKey))
+}
@@ -0,0 +1,98 @@
+package mailaddr
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// ErrInvalidAddress is returned if the email address is not a valid RFC 5322
+// address specification or its domain is not a valid internationalized name.
+var ErrInvalidAddress = errors.New("invalid email address")
+
+const (
+	// MaxLength is the maximum length of an address in octets.
+	MaxLength = 254
+
+	// maxLocalLength is the maximum length of the local part in octets.
+	maxLocalLength = 64
+)
+
+// gmailDomains are the domains of the Gmail mailboxes, all of which deliver
+// to the same mailbox regardless of the dots and the plus-tag of the local part.
+var gmailDomains = map[string]bool{
+	"gmail.com":      true,
+	"googlemail.com": true,
+}
+
+// Address is a validated email address.
+type Address struct {
+	// Email is the address as given, without surrounding white space.
+	Email string
+
+	// Canonical is the address used to tell apart the mailboxes: the local
+	// part in lower case and the domain in its lower case ASCII (punycode) form.
+	Canonical string
+}
+
+// Normalize validates the address and derives its canonical form. Display
+// names, comments and domain literals are not accepted. If providerRules is
+// set, the provider-specific aliases are folded as well, e.g. the dots and
+// the plus-tag of the Gmail addresses.
+func Normalize(raw string, providerRules bool) (Address, error) {
+	email := strings.TrimSpace(raw)
+	if len(email) > MaxLength {
+		return Address{}, fmt.Errorf("%w: longer than %d octets", ErrInvalidAddress, MaxLength)
+	}
+	if strings.ContainsAny(email, "<>") {
+		return Address{}, fmt.Errorf("%w: %q: only the address specification is accepted", ErrInvalidAddress, raw)
+	}
+
+	addr, err := mail.ParseAddress(email)
+	if err != nil {
+		return Address{}, fmt.Errorf("%w: %q: %v", ErrInvalidAddress, raw, err)
+	}
+	if addr.Name != "" {
+		return Address{}, fmt.Errorf("%w: %q: only the address specification is accepted", ErrInvalidAddress, raw)
+	}
+
+	i := strings.LastIndexByte(addr.Address, '@')
+	local, domain := addr.Address[:i], addr.Address[i+1:]
+	if len(local) > maxLocalLength {
+		return Address{}, fmt.Errorf("%w: %q: local part longer than %d octets", ErrInvalidAddress, raw, maxLocalLength)
+	}
+
+	// domain literals are valid RFC 5322 but not deliverable in practice
+	if strings.HasPrefix(domain, "[") {
+		return Address{}, fmt.Errorf("%w: %q: domain literals are not supported", ErrInvalidAddress, raw)
+	}
+	asciiDomain, err := idna.Lookup.ToASCII(domain)
+	if err != nil {
+		return Address{}, fmt.Errorf("%w: %q: %v", ErrInvalidAddress, raw, err)
+	}
+	if !strings.Contains(asciiDomain, ".") {
+		return Address{}, fmt.Errorf("%w: %q: domain is not fully qualified", ErrInvalidAddress, raw)
+	}
+
+	local = strings.ToLower(local)
+	if providerRules && gmailDomains[asciiDomain] {
+		if j := strings.IndexByte(local, '+'); j > 0 {
+			local = local[:j]
+		}
+		local = strings.ReplaceAll(local, ".", "")
+		asciiDomain = "gmail.com"
+	}
+
+	canonical := local + "@" + asciiDomain
+	if len(canonical) > MaxLength {
+		return Address{}, fmt.Errorf("%w: longer than %d octets", ErrInvalidAddress, MaxLength)
+	}
+
+	return Address{
+		Email:     email,
+		Canonical: canonical,
+	}, nil
+}
@@ -0,0 +1,60 @@
+package mailaddr_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/chutommy/user-microservice/pkg/mailaddr"
+)
+
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		raw          string
+		rules        bool
+		expEmail     string
+		expCanonical string
+		expErr       error
+	}{
+		{"plain", "john@example.com", false, "john@example.com", "john@example.com", nil},
+		{"letter case", "John.Doe@Example.COM", false, "John.Doe@Example.COM", "john.doe@example.com", nil},
+		{"surrounding space", "  john@example.com\n", false, "john@example.com", "john@example.com", nil},
+		{"plus tag kept", "john+news@example.com", true, "john+news@example.com", "john+news@example.com", nil},
+		{"quoted local part", `"john doe"@example.com`, false, `"john doe"@example.com`, "john doe@example.com", nil},
+		{"idn domain", "jan@Bücher.de", false, "jan@Bücher.de", "jan@xn--bcher-kva.de", nil},
+		{"punycode domain", "jan@xn--bcher-kva.de", false, "jan@xn--bcher-kva.de", "jan@xn--bcher-kva.de", nil},
+		{"gmail without rules", "John.Doe+news@gmail.com", false, "John.Doe+news@gmail.com", "john.doe+news@gmail.com", nil},
+		{"gmail with rules", "John.Doe+news@gmail.com", true, "John.Doe+news@gmail.com", "johndoe@gmail.com", nil},
+		{"googlemail with rules", "johndoe@GoogleMail.com", true, "johndoe@GoogleMail.com", "johndoe@gmail.com", nil},
+		{"empty", "", false, "", "", mailaddr.ErrInvalidAddress},
+		{"missing at", "john.example.com", false, "", "", mailaddr.ErrInvalidAddress},
+		{"double dot", "john..doe@example.com", false, "", "", mailaddr.ErrInvalidAddress},
+		{"display name", "John <john@example.com>", false, "", "", mailaddr.ErrInvalidAddress},
+		{"angle brackets", "<john@example.com>", false, "", "", mailaddr.ErrInvalidAddress},
+		{"comment", "john@example.com (John)", false, "", "", mailaddr.ErrInvalidAddress},
+		{"domain literal", "john@[192.0.2.1]", false, "", "", mailaddr.ErrInvalidAddress},
+		{"dotless domain", "john@localhost", false, "", "", mailaddr.ErrInvalidAddress},
+		{"invalid label", "john@-example.com", false, "", "", mailaddr.ErrInvalidAddress},
+		{"long local part", strings.Repeat("a", 65) + "@example.com", false, "", "", mailaddr.ErrInvalidAddress},
+		{"too long", "john@" + strings.Repeat("a", 250) + ".com", false, "", "", mailaddr.ErrInvalidAddress},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			addr, err := mailaddr.Normalize(tt.raw, tt.rules)
+			if tt.expErr != nil {
+				require.ErrorIs(t, err, tt.expErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expEmail, addr.Email)
+			require.Equal(t, tt.expCanonical, addr.Canonical)
+		})
+	}
+}
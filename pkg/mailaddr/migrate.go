@@ -0,0 +1,114 @@
+package mailaddr
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/chutommy/user-microservice/pkg/repo"
+)
+
+// ErrCollision is reported for addresses whose canonical form belongs to another address.
+var ErrCollision = errors.New("canonical email address is already taken")
+
+// migrationBatchSize is the number of addresses loaded at once.
+const migrationBatchSize = 500
+
+// Problem is a stored email address which could not be canonicalized.
+type Problem struct {
+	UserID uuid.UUID
+	Email  string
+	Err    error
+}
+
+// MigrationReport summarizes a migration run.
+type MigrationReport struct {
+	Canonicalized int
+	Problems      []Problem
+}
+
+// WriteCSV writes the problems as CSV rows of the user ID, the stored
+// address and the reason.
+func (r *MigrationReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"user_id", "email", "problem"}); err != nil {
+		return err
+	}
+	for _, p := range r.Problems {
+		if err := cw.Write([]string{p.UserID.String(), p.Email, p.Err.Error()}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// Migrate stores the canonical form of every email address whose stored form
+// differs, e.g. because it was added before the canonicalization or the
+// provider rules were switched. Invalid addresses and addresses colliding
+// with another one are left untouched and reported. Nothing is written in a
+// dry run, which therefore detects only collisions among the migrated rows.
+func Migrate(ctx context.Context, q repo.Querier, providerRules, dryRun bool) (*MigrationReport, error) {
+	report := &MigrationReport{}
+
+	seen := make(map[string]string)
+
+	after := ""
+	for {
+		rows, err := q.ListEmailCanonicals(ctx, repo.ListEmailCanonicalsParams{
+			AfterEmail: after,
+			RowLimit:   migrationBatchSize,
+		})
+		if err != nil {
+			return report, fmt.Errorf("list email addresses: %w", err)
+		}
+
+		for _, row := range rows {
+			after = row.Email
+
+			addr, err := Normalize(row.Email, providerRules)
+			if err != nil {
+				report.Problems = append(report.Problems, Problem{UserID: row.UserID, Email: row.Email, Err: err})
+				continue
+			}
+			if other, ok := seen[addr.Canonical]; ok {
+				err = fmt.Errorf("%w: %s by %s", ErrCollision, addr.Canonical, other)
+				report.Problems = append(report.Problems, Problem{UserID: row.UserID, Email: row.Email, Err: err})
+				continue
+			}
+			seen[addr.Canonical] = row.Email
+
+			if row.EmailCanonical.Valid && row.EmailCanonical.String == addr.Canonical {
+				continue
+			}
+
+			if !dryRun {
+				err = q.SetEmailCanonical(ctx, repo.SetEmailCanonicalParams{
+					EmailCanonical: addr.Canonical,
+					Email:          row.Email,
+				})
+				var pqErr *pq.Error
+				if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+					err = fmt.Errorf("%w: %s", ErrCollision, addr.Canonical)
+					report.Problems = append(report.Problems, Problem{UserID: row.UserID, Email: row.Email, Err: err})
+					continue
+				}
+				if err != nil {
+					return report, fmt.Errorf("update canonical form of %s: %w", row.Email, err)
+				}
+			}
+
+			report.Canonicalized++
+		}
+
+		if len(rows) < migrationBatchSize {
+			return report, nil
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package mailaddr_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chutommy/user-microservice/pkg/mailaddr"
+	"github.com/chutommy/user-microservice/pkg/mocks"
+	"github.com/chutommy/user-microservice/pkg/repo"
+)
+
+func TestMigrate(t *testing.T) {
+	t.Parallel()
+
+	row := func(email, canonical string) repo.ListEmailCanonicalsRow {
+		return repo.ListEmailCanonicalsRow{
+			Email:          email,
+			UserID:         uuid.New(),
+			EmailCanonical: sql.NullString{String: canonical, Valid: canonical != ""},
+		}
+	}
+	ok := row("Alice@Example.com", "")
+	current := row("bob@example.com", "bob@example.com")
+	invalid := row("carol", "")
+	alias := row("j.doe@gmail.com", "")
+	collision := row("jdoe+news@gmail.com", "")
+	taken := row("zoe@EXAMPLE.com", "")
+
+	q := new(mocks.Querier)
+	q.On("ListEmailCanonicals", mock.Anything, repo.ListEmailCanonicalsParams{AfterEmail: "", RowLimit: 500}).
+		Return([]repo.ListEmailCanonicalsRow{ok, current, invalid, alias, collision, taken}, nil).Once()
+	q.On("SetEmailCanonical", mock.Anything, repo.SetEmailCanonicalParams{
+		EmailCanonical: "alice@example.com",
+		Email:          ok.Email,
+	}).Return(nil).Once()
+	q.On("SetEmailCanonical", mock.Anything, repo.SetEmailCanonicalParams{
+		EmailCanonical: "jdoe@gmail.com",
+		Email:          alias.Email,
+	}).Return(nil).Once()
+	q.On("SetEmailCanonical", mock.Anything, repo.SetEmailCanonicalParams{
+		EmailCanonical: "zoe@example.com",
+		Email:          taken.Email,
+	}).Return(&pq.Error{Code: "23505"}).Once()
+
+	report, err := mailaddr.Migrate(context.Background(), q, true, false)
+	require.NoError(t, err)
+	require.Equal(t, 2, report.Canonicalized)
+	require.Len(t, report.Problems, 3)
+	require.ErrorIs(t, report.Problems[0].Err, mailaddr.ErrInvalidAddress)
+	require.Equal(t, invalid.UserID, report.Problems[0].UserID)
+	require.ErrorIs(t, report.Problems[1].Err, mailaddr.ErrCollision)
+	require.Equal(t, collision.UserID, report.Problems[1].UserID)
+	require.ErrorIs(t, report.Problems[2].Err, mailaddr.ErrCollision)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteCSV(&buf))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 4)
+	require.Equal(t, "user_id,email,problem", lines[0])
+	require.True(t, strings.HasPrefix(lines[1], invalid.UserID.String()+",carol,"))
+
+	q.AssertExpectations(t)
+}
+
+func TestMigrate_DryRun(t *testing.T) {
+	t.Parallel()
+
+	q := new(mocks.Querier)
+	q.On("ListEmailCanonicals", mock.Anything, mock.Anything).Return([]repo.ListEmailCanonicalsRow{
+		{Email: "John@Example.com", UserID: uuid.New()},
+		{Email: "john@example.com", UserID: uuid.New()},
+	}, nil).Once()
+
+	report, err := mailaddr.Migrate(context.Background(), q, false, true)
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Canonicalized)
+	require.Len(t, report.Problems, 1)
+	require.ErrorIs(t, report.Problems[0].Err, mailaddr.ErrCollision)
+
+	// nothing is written
+	q.AssertExpectations(t)
+	q.AssertNotCalled(t, "SetEmailCanonical", mock.Anything, mock.Anything)
+}
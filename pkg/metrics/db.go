@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DBStatsCollector exports the connection pool statistics of a sql.DB.
+type DBStatsCollector struct {
+	db *sql.DB
+
+	maxOpen      *prometheus.Desc
+	open         *prometheus.Desc
+	inUse        *prometheus.Desc
+	idle         *prometheus.Desc
+	waitCount    *prometheus.Desc
+	waitDuration *prometheus.Desc
+}
+
+// NewDBStatsCollector constructs a DBStatsCollector of the database pool.
+func NewDBStatsCollector(db *sql.DB) *DBStatsCollector {
+	name := func(n string) string {
+		return prometheus.BuildFQName(namespace, "db", n)
+	}
+
+	return &DBStatsCollector{
+		db:           db,
+		maxOpen:      prometheus.NewDesc(name("max_open_connections"), "Maximum number of open connections.", nil, nil),
+		open:         prometheus.NewDesc(name("open_connections"), "Number of established connections.", nil, nil),
+		inUse:        prometheus.NewDesc(name("in_use_connections"), "Number of connections in use.", nil, nil),
+		idle:         prometheus.NewDesc(name("idle_connections"), "Number of idle connections.", nil, nil),
+		waitCount:    prometheus.NewDesc(name("wait_count_total"), "Number of connections waited for.", nil, nil),
+		waitDuration: prometheus.NewDesc(name("wait_duration_seconds_total"), "Time blocked waiting for a connection.", nil, nil),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *DBStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.maxOpen
+	ch <- c.open
+	ch <- c.inUse
+	ch <- c.idle
+	ch <- c.waitCount
+	ch <- c.waitDuration
+}
+
+// Collect implements prometheus.Collector.
+func (c *DBStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.db.Stats()
+
+	ch <- prometheus.MustNewConstMetric(c.maxOpen, prometheus.GaugeValue, float64(s.MaxOpenConnections))
+	ch <- prometheus.MustNewConstMetric(c.open, prometheus.GaugeValue, float64(s.OpenConnections))
+	ch <- prometheus.MustNewConstMetric(c.inUse, prometheus.GaugeValue, float64(s.InUse))
+	ch <- prometheus.MustNewConstMetric(c.idle, prometheus.GaugeValue, float64(s.Idle))
+	ch <- prometheus.MustNewConstMetric(c.waitCount, prometheus.CounterValue, float64(s.WaitCount))
+	ch <- prometheus.MustNewConstMetric(c.waitDuration, prometheus.CounterValue, s.WaitDuration.Seconds())
+}
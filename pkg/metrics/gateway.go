@@ -0,0 +1,76 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/metadata"
+)
+
+// unknownMethod labels the requests which were not routed to any method.
+const unknownMethod = "unknown"
+
+type methodKey struct{}
+
+// statusRecorder captures the status code written by the handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	code int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.code = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+// Flush passes the streamed chunks through.
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Gateway counts and times the requests handled by the gateway. The requests
+// are labelled by the RPC method reported by GatewayMetadata, or by the name
+// of the custom route given to Route.
+func (m *Metrics) Gateway(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		method := unknownMethod
+		rec := &statusRecorder{ResponseWriter: w, code: http.StatusOK}
+		next.ServeHTTP(rec, r.WithContext(context.WithValue(r.Context(), methodKey{}, &method)))
+
+		code := strconv.Itoa(rec.code)
+		m.gatewayRequests.WithLabelValues(method, code).Inc()
+		m.gatewayDuration.WithLabelValues(method, code).Observe(time.Since(start).Seconds())
+	})
+}
+
+// setMethod labels the request of the context by the method.
+func setMethod(ctx context.Context, method string) {
+	if p, ok := ctx.Value(methodKey{}).(*string); ok {
+		*p = method
+	}
+}
+
+// GatewayMetadata is a runtime.WithMetadata option labelling the gateway
+// requests by the RPC method they are routed to. It adds no metadata.
+func GatewayMetadata(ctx context.Context, _ *http.Request) metadata.MD {
+	if method, ok := runtime.RPCMethod(ctx); ok {
+		setMethod(ctx, method)
+	}
+
+	return nil
+}
+
+// Route labels the requests of a custom gateway route by the name.
+func Route(name string, h runtime.HandlerFunc) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		setMethod(r.Context(), name)
+		h(w, r, params)
+	}
+}
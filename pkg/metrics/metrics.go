@@ -0,0 +1,90 @@
+package metrics
+
+import (
+	"net/http"
+
+	grpcprom "github.com/grpc-ecosystem/go-grpc-prometheus"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixes the metrics of the user service.
+const namespace = "user"
+
+// Path is the route of the metrics endpoint on the admin server.
+const Path = "/metrics"
+
+// Metrics holds the collectors of the user service.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	// GRPC provides the interceptors measuring the RPCs by method and code.
+	GRPC *grpcprom.ServerMetrics
+
+	// BcryptDuration observes the duration of the password hashing.
+	BcryptDuration prometheus.Histogram
+
+	// CacheLookups counts the lookups of the user cache by the result, hit
+	// or miss.
+	CacheLookups *prometheus.CounterVec
+
+	gatewayRequests *prometheus.CounterVec
+	gatewayDuration *prometheus.HistogramVec
+}
+
+// New constructs the Metrics and registers them along with the Go runtime
+// and the process collectors in a new registry.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		GRPC:     grpcprom.NewServerMetrics(),
+		BcryptDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "bcrypt_duration_seconds",
+			Help:      "Duration of the password hashing.",
+			Buckets:   []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5},
+		}),
+		CacheLookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "cache",
+			Name:      "lookups_total",
+			Help:      "Number of the lookups of the user cache by the result.",
+		}, []string{"result"}),
+		gatewayRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "gateway",
+			Name:      "requests_total",
+			Help:      "Number of the HTTP requests handled by the gateway.",
+		}, []string{"method", "code"}),
+		gatewayDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "gateway",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of the HTTP requests handled by the gateway.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "code"}),
+	}
+	m.GRPC.EnableHandlingTimeHistogram()
+
+	m.registry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		m.GRPC,
+		m.BcryptDuration,
+		m.CacheLookups,
+		m.gatewayRequests,
+		m.gatewayDuration,
+	)
+
+	return m
+}
+
+// MustRegister registers additional collectors, e.g. NewDBStatsCollector.
+func (m *Metrics) MustRegister(cs ...prometheus.Collector) {
+	m.registry.MustRegister(cs...)
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
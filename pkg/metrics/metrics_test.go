@@ -0,0 +1,116 @@
+package metrics_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chutommy/user-microservice/pkg/metrics"
+	"github.com/chutommy/user-microservice/pkg/mocks"
+	"github.com/chutommy/user-microservice/pkg/repo"
+)
+
+// scrape returns the exposition of the metrics.
+func scrape(t *testing.T, m *metrics.Metrics) string {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, metrics.Path, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	body, err := ioutil.ReadAll(rec.Body)
+	require.NoError(t, err)
+
+	return string(body)
+}
+
+func TestMetrics_Gateway(t *testing.T) {
+	t.Parallel()
+
+	m := metrics.New()
+
+	mux := runtime.NewServeMux(runtime.WithMetadata(metrics.GatewayMetadata))
+	require.NoError(t, mux.HandlePath(http.MethodGet, "/v1/user/{id}/export",
+		metrics.Route("ExportUsers", func(w http.ResponseWriter, _ *http.Request, _ map[string]string) {
+			w.WriteHeader(http.StatusAccepted)
+		})))
+	h := m.Gateway(mux)
+
+	for _, path := range []string{"/v1/user/1/export", "/v1/user/1/export", "/unknown"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	out := scrape(t, m)
+	require.Contains(t, out, `user_gateway_requests_total{code="202",method="ExportUsers"} 2`)
+	require.Contains(t, out, `user_gateway_requests_total{code="404",method="unknown"} 1`)
+	require.Contains(t, out, `user_gateway_request_duration_seconds_count{code="202",method="ExportUsers"} 2`)
+}
+
+func TestMetrics_BcryptDuration(t *testing.T) {
+	t.Parallel()
+
+	m := metrics.New()
+	m.BcryptDuration.Observe(0.2)
+
+	out := scrape(t, m)
+	require.Contains(t, out, `user_bcrypt_duration_seconds_bucket{le="0.25"} 1`)
+	require.Contains(t, out, `user_bcrypt_duration_seconds_bucket{le="0.1"} 0`)
+}
+
+func TestUserCountCollector(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		rows     []repo.CountUsersRow
+		err      error
+		contains []string
+	}{
+		{
+			name: "ok",
+			rows: []repo.CountUsersRow{
+				{State: 0, Erased: false, Count: 5},
+				{State: 1, Erased: false, Count: 2},
+				{State: 0, Erased: true, Count: 1},
+			},
+			contains: []string{
+				`user_users{erased="false",state="ACTIVE"} 5`,
+				`user_users{erased="false",state="PENDING_GUARDIAN_CONSENT"} 2`,
+				`user_users{erased="true",state="ACTIVE"} 1`,
+				`user_users_count_failed 0`,
+			},
+		},
+		{
+			name:     "failed",
+			err:      errors.New("connection refused"),
+			contains: []string{`user_users_count_failed 1`},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			q := new(mocks.Querier)
+			q.On("CountUsers", mock.Anything).
+				Return(tt.rows, tt.err).Once()
+
+			m := metrics.New()
+			m.MustRegister(metrics.NewUserCountCollector(q))
+
+			out := scrape(t, m)
+			for _, c := range tt.contains {
+				require.Contains(t, out, c)
+			}
+			q.AssertExpectations(t)
+		})
+	}
+}
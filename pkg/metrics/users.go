@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/chutommy/user-microservice/pkg/grpc/userpb"
+	"github.com/chutommy/user-microservice/pkg/repo"
+)
+
+// userCountTimeout bounds the count query run on every scrape.
+const userCountTimeout = 5 * time.Second
+
+// UserCountCollector exports the number of users by the account state.
+type UserCountCollector struct {
+	q repo.Querier
+
+	users  *prometheus.Desc
+	failed *prometheus.Desc
+}
+
+// NewUserCountCollector constructs a UserCountCollector counting the users of the repo.
+func NewUserCountCollector(q repo.Querier) *UserCountCollector {
+	return &UserCountCollector{
+		q: q,
+		users: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "users"),
+			"Number of users by the account state, erased users included.",
+			[]string{"state", "erased"}, nil,
+		),
+		failed: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "users_count_failed"),
+			"Whether the last count of the users failed.",
+			nil, nil,
+		),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *UserCountCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.users
+	ch <- c.failed
+}
+
+// Collect implements prometheus.Collector.
+func (c *UserCountCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), userCountTimeout)
+	defer cancel()
+
+	rows, err := c.q.CountUsers(ctx)
+	if err != nil {
+		ch <- prometheus.MustNewConstMetric(c.failed, prometheus.GaugeValue, 1)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.failed, prometheus.GaugeValue, 0)
+
+	for _, r := range rows {
+		ch <- prometheus.MustNewConstMetric(c.users, prometheus.GaugeValue, float64(r.Count),
+			userpb.User_State(r.State).String(), strconv.FormatBool(r.Erased))
+	}
+}
@@ -0,0 +1,199 @@
+// Package migration applies the schema migrations embedded in the binary to
+// the database.
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/httpfs"
+	"go.uber.org/zap"
+
+	"github.com/chutommy/user-microservice/db"
+)
+
+// lockID is the key of the Postgres advisory lock held by the replica which
+// applies the migrations on start.
+const lockID int64 = 0x75736572 // "user"
+
+// ErrInvalidSteps is returned if the number of migrations to apply or to
+// revert is not positive.
+var ErrInvalidSteps = errors.New("the number of steps must be positive")
+
+// open returns the source of the embedded migrations.
+func open() (source.Driver, error) {
+	return httpfs.New(http.FS(db.Migrations), "schema")
+}
+
+// Latest returns the version of the latest embedded migration.
+func Latest() (uint, error) {
+	src, err := open()
+	if err != nil {
+		return 0, err
+	}
+	defer src.Close()
+
+	version, err := src.First()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read the first migration: %w", err)
+	}
+	for {
+		next, err := src.Next(version)
+		if errors.Is(err, os.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read the migration after %d: %w", version, err)
+		}
+		version = next
+	}
+}
+
+// Status describes the schema of the database.
+type Status struct {
+	// Version is the version of the last applied migration, 0 if none.
+	Version uint
+	// Dirty reports a failed migration which must be fixed by hand and
+	// forced, see Migrator.Force.
+	Dirty bool
+	// Latest is the version of the latest embedded migration.
+	Latest uint
+}
+
+// Behind reports whether some embedded migrations are not applied.
+func (s Status) Behind() bool {
+	return s.Version < s.Latest
+}
+
+// logger adapts the zap logger to migrate.Logger.
+type logger struct {
+	*zap.SugaredLogger
+}
+
+func (l logger) Printf(format string, v ...interface{}) {
+	l.Infof(strings.TrimSuffix(format, "\n"), v...)
+}
+
+func (l logger) Verbose() bool {
+	return false
+}
+
+// Migrator migrates the schema of a Postgres database by the embedded
+// migrations. The applied version is recorded in the schema_migrations table
+// as by the migrate CLI.
+type Migrator struct {
+	db      *sql.DB
+	migrate *migrate.Migrate
+	latest  uint
+}
+
+// New constructs a Migrator of the database. The database is closed with the
+// Migrator, so it should not be shared with the rest of the service.
+func New(sqlDB *sql.DB, log *zap.Logger) (*Migrator, error) {
+	latest, err := Latest()
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := open()
+	if err != nil {
+		return nil, err
+	}
+	driver, err := postgres.WithInstance(sqlDB, &postgres.Config{})
+	if err != nil {
+		_ = src.Close()
+		return nil, fmt.Errorf("failed to open the migrations table: %w", err)
+	}
+	m, err := migrate.NewWithInstance("httpfs", src, "postgres", driver)
+	if err != nil {
+		_ = src.Close()
+		_ = driver.Close()
+		return nil, err
+	}
+	m.Log = logger{log.Sugar()}
+
+	return &Migrator{db: sqlDB, migrate: m, latest: latest}, nil
+}
+
+// Close closes the source of the migrations and the database.
+func (m *Migrator) Close() error {
+	srcErr, dbErr := m.migrate.Close()
+	if srcErr != nil {
+		return srcErr
+	}
+
+	return dbErr
+}
+
+// Status returns the status of the schema.
+func (m *Migrator) Status() (Status, error) {
+	version, dirty, err := m.migrate.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return Status{}, err
+	}
+
+	return Status{Version: version, Dirty: dirty, Latest: m.latest}, nil
+}
+
+// Up applies the given number of pending migrations, all of them if steps
+// is 0. It does nothing if the schema is up to date.
+func (m *Migrator) Up(steps int) error {
+	var err error
+	switch {
+	case steps < 0:
+		return ErrInvalidSteps
+	case steps == 0:
+		err = m.migrate.Up()
+	default:
+		err = m.migrate.Steps(steps)
+	}
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
+	}
+
+	return err
+}
+
+// Down reverts the given number of applied migrations.
+func (m *Migrator) Down(steps int) error {
+	if steps <= 0 {
+		return ErrInvalidSteps
+	}
+
+	return m.migrate.Steps(-steps)
+}
+
+// Force sets the version of the schema and clears the dirty flag without
+// running any migration, after a failed migration was fixed by hand.
+func (m *Migrator) Force(version int) error {
+	return m.migrate.Force(version)
+}
+
+// UpLocked applies all pending migrations while holding a Postgres advisory
+// lock, so when the replicas start at once a single one migrates the schema
+// and the others wait for it and find the schema up to date. The context
+// bounds the wait for the lock.
+func (m *Migrator) UpLocked(ctx context.Context) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err = conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockID); err != nil {
+		return fmt.Errorf("failed to acquire the migration lock: %w", err)
+	}
+	defer func() {
+		_, _ = conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", lockID)
+	}()
+
+	return m.Up(0)
+}
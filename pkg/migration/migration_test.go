@@ -0,0 +1,74 @@
+package migration_test
+
+import (
+	"io/fs"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/chutommy/user-microservice/db"
+	"github.com/chutommy/user-microservice/pkg/migration"
+)
+
+func TestLatest(t *testing.T) {
+	t.Parallel()
+
+	files, err := fs.Glob(db.Migrations, "schema/*.sql")
+	require.NoError(t, err)
+
+	var latest uint
+	ups, downs := map[uint]bool{}, map[uint]bool{}
+	for _, f := range files {
+		name := strings.TrimPrefix(f, "schema/")
+		v, err := strconv.ParseUint(name[:strings.Index(name, "_")], 10, 64)
+		require.NoError(t, err, name)
+
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			ups[uint(v)] = true
+		case strings.HasSuffix(name, ".down.sql"):
+			downs[uint(v)] = true
+		}
+		if uint(v) > latest {
+			latest = uint(v)
+		}
+	}
+
+	// every migration is reversible and the versions are sequential
+	require.Equal(t, ups, downs)
+	for v := uint(1); v <= latest; v++ {
+		require.True(t, ups[v], "missing migration %d", v)
+	}
+
+	got, err := migration.Latest()
+	require.NoError(t, err)
+	require.Equal(t, latest, got)
+	require.GreaterOrEqual(t, got, uint(12))
+}
+
+func TestStatus_Behind(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		status migration.Status
+		behind bool
+	}{
+		{"empty", migration.Status{Version: 0, Latest: 12}, true},
+		{"behind", migration.Status{Version: 11, Latest: 12}, true},
+		{"current", migration.Status{Version: 12, Latest: 12}, false},
+		{"ahead", migration.Status{Version: 13, Latest: 12}, false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.behind, tt.status.Behind())
+		})
+	}
+}
@@ -4,6 +4,7 @@ package mocks
 
 import (
 	context "context"
+	sql "database/sql"
 
 	repo "github.com/chutommy/user-microservice/pkg/repo"
 	mock "github.com/stretchr/testify/mock"
@@ -16,6 +17,218 @@ type Querier struct {
 	mock.Mock
 }
 
+// AddEmail provides a mock function with given fields: ctx, arg
+func (_m *Querier) AddEmail(ctx context.Context, arg repo.AddEmailParams) (repo.UserEmail, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 repo.UserEmail
+	if rf, ok := ret.Get(0).(func(context.Context, repo.AddEmailParams) repo.UserEmail); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(repo.UserEmail)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, repo.AddEmailParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CompleteDataExport provides a mock function with given fields: ctx, arg
+func (_m *Querier) CompleteDataExport(ctx context.Context, arg repo.CompleteDataExportParams) (repo.DataExport, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 repo.DataExport
+	if rf, ok := ret.Get(0).(func(context.Context, repo.CompleteDataExportParams) repo.DataExport); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(repo.DataExport)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, repo.CompleteDataExportParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CompleteIdempotencyKey provides a mock function with given fields: ctx, arg
+func (_m *Querier) CompleteIdempotencyKey(ctx context.Context, arg repo.CompleteIdempotencyKeyParams) (int64, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, repo.CompleteIdempotencyKeyParams) int64); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, repo.CompleteIdempotencyKeyParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ConfirmGuardianConsent provides a mock function with given fields: ctx, arg
+func (_m *Querier) ConfirmGuardianConsent(ctx context.Context, arg repo.ConfirmGuardianConsentParams) (repo.ConfirmGuardianConsentRow, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 repo.ConfirmGuardianConsentRow
+	if rf, ok := ret.Get(0).(func(context.Context, repo.ConfirmGuardianConsentParams) repo.ConfirmGuardianConsentRow); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(repo.ConfirmGuardianConsentRow)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, repo.ConfirmGuardianConsentParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountUncanonicalEmails provides a mock function with given fields: ctx
+func (_m *Querier) CountUncanonicalEmails(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountUsers provides a mock function with given fields: ctx
+func (_m *Querier) CountUsers(ctx context.Context) ([]repo.CountUsersRow, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []repo.CountUsersRow
+	if rf, ok := ret.Get(0).(func(context.Context) []repo.CountUsersRow); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repo.CountUsersRow)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateAddress provides a mock function with given fields: ctx, arg
+func (_m *Querier) CreateAddress(ctx context.Context, arg repo.CreateAddressParams) (repo.UserAddress, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 repo.UserAddress
+	if rf, ok := ret.Get(0).(func(context.Context, repo.CreateAddressParams) repo.UserAddress); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(repo.UserAddress)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, repo.CreateAddressParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateConsentPolicy provides a mock function with given fields: ctx, arg
+func (_m *Querier) CreateConsentPolicy(ctx context.Context, arg repo.CreateConsentPolicyParams) (repo.ConsentPolicy, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 repo.ConsentPolicy
+	if rf, ok := ret.Get(0).(func(context.Context, repo.CreateConsentPolicyParams) repo.ConsentPolicy); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(repo.ConsentPolicy)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, repo.CreateConsentPolicyParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateDataExport provides a mock function with given fields: ctx, arg
+func (_m *Querier) CreateDataExport(ctx context.Context, arg repo.CreateDataExportParams) (repo.DataExport, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 repo.DataExport
+	if rf, ok := ret.Get(0).(func(context.Context, repo.CreateDataExportParams) repo.DataExport); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(repo.DataExport)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, repo.CreateDataExportParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateGuardianConsent provides a mock function with given fields: ctx, arg
+func (_m *Querier) CreateGuardianConsent(ctx context.Context, arg repo.CreateGuardianConsentParams) (repo.GuardianConsent, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 repo.GuardianConsent
+	if rf, ok := ret.Get(0).(func(context.Context, repo.CreateGuardianConsentParams) repo.GuardianConsent); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(repo.GuardianConsent)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, repo.CreateGuardianConsentParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateUser provides a mock function with given fields: ctx, arg
 func (_m *Querier) CreateUser(ctx context.Context, arg repo.CreateUserParams) (repo.User, error) {
 	ret := _m.Called(ctx, arg)
@@ -37,6 +250,83 @@ func (_m *Querier) CreateUser(ctx context.Context, arg repo.CreateUserParams) (r
 	return r0, r1
 }
 
+// DeleteAddress provides a mock function with given fields: ctx, arg
+func (_m *Querier) DeleteAddress(ctx context.Context, arg repo.DeleteAddressParams) (int64, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, repo.DeleteAddressParams) int64); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, repo.DeleteAddressParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteExpiredIdempotencyKeys provides a mock function with given fields: ctx
+func (_m *Querier) DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteGuardianConsent provides a mock function with given fields: ctx, id
+func (_m *Querier) DeleteGuardianConsent(ctx context.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeletePreference provides a mock function with given fields: ctx, arg
+func (_m *Querier) DeletePreference(ctx context.Context, arg repo.DeletePreferenceParams) (int64, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, repo.DeletePreferenceParams) int64); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, repo.DeletePreferenceParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteUser provides a mock function with given fields: ctx, id
 func (_m *Querier) DeleteUser(ctx context.Context, id uuid.UUID) (int64, error) {
 	ret := _m.Called(ctx, id)
@@ -58,15 +348,57 @@ func (_m *Querier) DeleteUser(ctx context.Context, id uuid.UUID) (int64, error)
 	return r0, r1
 }
 
-// GetUser provides a mock function with given fields: ctx, id
-func (_m *Querier) GetUser(ctx context.Context, id uuid.UUID) (repo.User, error) {
+// EraseUser provides a mock function with given fields: ctx, arg
+func (_m *Querier) EraseUser(ctx context.Context, arg repo.EraseUserParams) (repo.ErasureReceipt, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 repo.ErasureReceipt
+	if rf, ok := ret.Get(0).(func(context.Context, repo.EraseUserParams) repo.ErasureReceipt); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(repo.ErasureReceipt)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, repo.EraseUserParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetConsentPolicy provides a mock function with given fields: ctx, arg
+func (_m *Querier) GetConsentPolicy(ctx context.Context, arg repo.GetConsentPolicyParams) (repo.ConsentPolicy, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 repo.ConsentPolicy
+	if rf, ok := ret.Get(0).(func(context.Context, repo.GetConsentPolicyParams) repo.ConsentPolicy); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(repo.ConsentPolicy)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, repo.GetConsentPolicyParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDataExport provides a mock function with given fields: ctx, id
+func (_m *Querier) GetDataExport(ctx context.Context, id uuid.UUID) (repo.DataExport, error) {
 	ret := _m.Called(ctx, id)
 
-	var r0 repo.User
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) repo.User); ok {
+	var r0 repo.DataExport
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) repo.DataExport); ok {
 		r0 = rf(ctx, id)
 	} else {
-		r0 = ret.Get(0).(repo.User)
+		r0 = ret.Get(0).(repo.DataExport)
 	}
 
 	var r1 error
@@ -79,19 +411,612 @@ func (_m *Querier) GetUser(ctx context.Context, id uuid.UUID) (repo.User, error)
 	return r0, r1
 }
 
-// UpdateUser provides a mock function with given fields: ctx, arg
-func (_m *Querier) UpdateUser(ctx context.Context, arg repo.UpdateUserParams) (repo.User, error) {
+// GetIdempotencyKey provides a mock function with given fields: ctx, arg
+func (_m *Querier) GetIdempotencyKey(ctx context.Context, arg repo.GetIdempotencyKeyParams) (repo.IdempotencyKey, error) {
 	ret := _m.Called(ctx, arg)
 
-	var r0 repo.User
-	if rf, ok := ret.Get(0).(func(context.Context, repo.UpdateUserParams) repo.User); ok {
+	var r0 repo.IdempotencyKey
+	if rf, ok := ret.Get(0).(func(context.Context, repo.GetIdempotencyKeyParams) repo.IdempotencyKey); ok {
 		r0 = rf(ctx, arg)
 	} else {
-		r0 = ret.Get(0).(repo.User)
+		r0 = ret.Get(0).(repo.IdempotencyKey)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, repo.UpdateUserParams) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, repo.GetIdempotencyKeyParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUser provides a mock function with given fields: ctx, id
+func (_m *Querier) GetUser(ctx context.Context, id uuid.UUID) (repo.User, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 repo.User
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) repo.User); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(repo.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserByEmail provides a mock function with given fields: ctx, emailCanonical
+func (_m *Querier) GetUserByEmail(ctx context.Context, emailCanonical string) (repo.User, error) {
+	ret := _m.Called(ctx, emailCanonical)
+
+	var r0 repo.User
+	if rf, ok := ret.Get(0).(func(context.Context, string) repo.User); ok {
+		r0 = rf(ctx, emailCanonical)
+	} else {
+		r0 = ret.Get(0).(repo.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, emailCanonical)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// HasGuardianConsent provides a mock function with given fields: ctx, minorID
+func (_m *Querier) HasGuardianConsent(ctx context.Context, minorID uuid.UUID) (bool, error) {
+	ret := _m.Called(ctx, minorID)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) bool); ok {
+		r0 = rf(ctx, minorID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, minorID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListAddresses provides a mock function with given fields: ctx, arg
+func (_m *Querier) ListAddresses(ctx context.Context, arg repo.ListAddressesParams) ([]repo.UserAddress, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 []repo.UserAddress
+	if rf, ok := ret.Get(0).(func(context.Context, repo.ListAddressesParams) []repo.UserAddress); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repo.UserAddress)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, repo.ListAddressesParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListConsents provides a mock function with given fields: ctx, userID
+func (_m *Querier) ListConsents(ctx context.Context, userID uuid.UUID) ([]repo.UserConsent, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 []repo.UserConsent
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []repo.UserConsent); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repo.UserConsent)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListDataExportBlobKeys provides a mock function with given fields: ctx, userID
+func (_m *Querier) ListDataExportBlobKeys(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []string); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListEmailCanonicals provides a mock function with given fields: ctx, arg
+func (_m *Querier) ListEmailCanonicals(ctx context.Context, arg repo.ListEmailCanonicalsParams) ([]repo.ListEmailCanonicalsRow, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 []repo.ListEmailCanonicalsRow
+	if rf, ok := ret.Get(0).(func(context.Context, repo.ListEmailCanonicalsParams) []repo.ListEmailCanonicalsRow); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repo.ListEmailCanonicalsRow)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, repo.ListEmailCanonicalsParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListEmails provides a mock function with given fields: ctx, userID
+func (_m *Querier) ListEmails(ctx context.Context, userID uuid.UUID) ([]repo.UserEmail, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 []repo.UserEmail
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []repo.UserEmail); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repo.UserEmail)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListErasureReceipts provides a mock function with given fields: ctx, userID
+func (_m *Querier) ListErasureReceipts(ctx context.Context, userID uuid.UUID) ([]repo.ErasureReceipt, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 []repo.ErasureReceipt
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []repo.ErasureReceipt); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repo.ErasureReceipt)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListGuardianConsents provides a mock function with given fields: ctx, userID
+func (_m *Querier) ListGuardianConsents(ctx context.Context, userID uuid.UUID) ([]repo.GuardianConsent, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 []repo.GuardianConsent
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []repo.GuardianConsent); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repo.GuardianConsent)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPreferences provides a mock function with given fields: ctx, arg
+func (_m *Querier) ListPreferences(ctx context.Context, arg repo.ListPreferencesParams) ([]repo.UserPreference, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 []repo.UserPreference
+	if rf, ok := ret.Get(0).(func(context.Context, repo.ListPreferencesParams) []repo.UserPreference); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repo.UserPreference)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, repo.ListPreferencesParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListUnnormalizedPhones provides a mock function with given fields: ctx, arg
+func (_m *Querier) ListUnnormalizedPhones(ctx context.Context, arg repo.ListUnnormalizedPhonesParams) ([]repo.ListUnnormalizedPhonesRow, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 []repo.ListUnnormalizedPhonesRow
+	if rf, ok := ret.Get(0).(func(context.Context, repo.ListUnnormalizedPhonesParams) []repo.ListUnnormalizedPhonesRow); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repo.ListUnnormalizedPhonesRow)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, repo.ListUnnormalizedPhonesParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListUnpublishedErasureReceipts provides a mock function with given fields: ctx, arg
+func (_m *Querier) ListUnpublishedErasureReceipts(ctx context.Context, arg repo.ListUnpublishedErasureReceiptsParams) ([]repo.ErasureReceipt, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 []repo.ErasureReceipt
+	if rf, ok := ret.Get(0).(func(context.Context, repo.ListUnpublishedErasureReceiptsParams) []repo.ErasureReceipt); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repo.ErasureReceipt)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, repo.ListUnpublishedErasureReceiptsParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListUsers provides a mock function with given fields: ctx, arg
+func (_m *Querier) ListUsers(ctx context.Context, arg repo.ListUsersParams) ([]repo.User, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 []repo.User
+	if rf, ok := ret.Get(0).(func(context.Context, repo.ListUsersParams) []repo.User); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repo.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, repo.ListUsersParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListUsersRequiringConsent provides a mock function with given fields: ctx, arg
+func (_m *Querier) ListUsersRequiringConsent(ctx context.Context, arg repo.ListUsersRequiringConsentParams) ([]uuid.UUID, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 []uuid.UUID
+	if rf, ok := ret.Get(0).(func(context.Context, repo.ListUsersRequiringConsentParams) []uuid.UUID); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]uuid.UUID)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, repo.ListUsersRequiringConsentParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkErasureReceiptPublished provides a mock function with given fields: ctx, id
+func (_m *Querier) MarkErasureReceiptPublished(ctx context.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RecordConsent provides a mock function with given fields: ctx, arg
+func (_m *Querier) RecordConsent(ctx context.Context, arg repo.RecordConsentParams) (repo.UserConsent, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 repo.UserConsent
+	if rf, ok := ret.Get(0).(func(context.Context, repo.RecordConsentParams) repo.UserConsent); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(repo.UserConsent)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, repo.RecordConsentParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReleaseIdempotencyKey provides a mock function with given fields: ctx, arg
+func (_m *Querier) ReleaseIdempotencyKey(ctx context.Context, arg repo.ReleaseIdempotencyKeyParams) (int64, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, repo.ReleaseIdempotencyKeyParams) int64); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, repo.ReleaseIdempotencyKeyParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveEmail provides a mock function with given fields: ctx, arg
+func (_m *Querier) RemoveEmail(ctx context.Context, arg repo.RemoveEmailParams) (int64, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, repo.RemoveEmailParams) int64); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, repo.RemoveEmailParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReserveIdempotencyKey provides a mock function with given fields: ctx, arg
+func (_m *Querier) ReserveIdempotencyKey(ctx context.Context, arg repo.ReserveIdempotencyKeyParams) (repo.IdempotencyKey, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 repo.IdempotencyKey
+	if rf, ok := ret.Get(0).(func(context.Context, repo.ReserveIdempotencyKeyParams) repo.IdempotencyKey); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(repo.IdempotencyKey)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, repo.ReserveIdempotencyKeyParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetEmailCanonical provides a mock function with given fields: ctx, arg
+func (_m *Querier) SetEmailCanonical(ctx context.Context, arg repo.SetEmailCanonicalParams) error {
+	ret := _m.Called(ctx, arg)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, repo.SetEmailCanonicalParams) error); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetPreferences provides a mock function with given fields: ctx, arg
+func (_m *Querier) SetPreferences(ctx context.Context, arg repo.SetPreferencesParams) ([]repo.UserPreference, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 []repo.UserPreference
+	if rf, ok := ret.Get(0).(func(context.Context, repo.SetPreferencesParams) []repo.UserPreference); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repo.UserPreference)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, repo.SetPreferencesParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetPrimaryEmail provides a mock function with given fields: ctx, arg
+func (_m *Querier) SetPrimaryEmail(ctx context.Context, arg repo.SetPrimaryEmailParams) (repo.User, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 repo.User
+	if rf, ok := ret.Get(0).(func(context.Context, repo.SetPrimaryEmailParams) repo.User); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(repo.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, repo.SetPrimaryEmailParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetUserAvatar provides a mock function with given fields: ctx, arg
+func (_m *Querier) SetUserAvatar(ctx context.Context, arg repo.SetUserAvatarParams) (sql.NullString, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 sql.NullString
+	if rf, ok := ret.Get(0).(func(context.Context, repo.SetUserAvatarParams) sql.NullString); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(sql.NullString)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, repo.SetUserAvatarParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetUserPhone provides a mock function with given fields: ctx, arg
+func (_m *Querier) SetUserPhone(ctx context.Context, arg repo.SetUserPhoneParams) error {
+	ret := _m.Called(ctx, arg)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, repo.SetUserPhoneParams) error); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateAddress provides a mock function with given fields: ctx, arg
+func (_m *Querier) UpdateAddress(ctx context.Context, arg repo.UpdateAddressParams) (repo.UserAddress, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 repo.UserAddress
+	if rf, ok := ret.Get(0).(func(context.Context, repo.UpdateAddressParams) repo.UserAddress); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(repo.UserAddress)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, repo.UpdateAddressParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateUser provides a mock function with given fields: ctx, arg
+func (_m *Querier) UpdateUser(ctx context.Context, arg repo.UpdateUserParams) (repo.User, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 repo.User
+	if rf, ok := ret.Get(0).(func(context.Context, repo.UpdateUserParams) repo.User); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(repo.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, repo.UpdateUserParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WithdrawConsent provides a mock function with given fields: ctx, arg
+func (_m *Querier) WithdrawConsent(ctx context.Context, arg repo.WithdrawConsentParams) (int64, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, repo.WithdrawConsentParams) int64); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, repo.WithdrawConsentParams) error); ok {
 		r1 = rf(ctx, arg)
 	} else {
 		r1 = ret.Error(1)
@@ -0,0 +1,114 @@
+package phone
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/chutommy/user-microservice/pkg/repo"
+)
+
+// ErrDuplicate is reported for numbers whose normalized form belongs to another user.
+var ErrDuplicate = errors.New("normalized phone number is already taken")
+
+// migrationBatchSize is the number of users loaded at once.
+const migrationBatchSize = 500
+
+// Problem is a stored phone number which could not be migrated.
+type Problem struct {
+	UserID uuid.UUID
+	Raw    string
+	Err    error
+}
+
+// MigrationReport summarizes a migration run.
+type MigrationReport struct {
+	Converted int
+	Problems  []Problem
+}
+
+// WriteCSV writes the problems as CSV rows of the user ID, the stored
+// number and the reason.
+func (r *MigrationReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"user_id", "phone_number", "problem"}); err != nil {
+		return err
+	}
+	for _, p := range r.Problems {
+		if err := cw.Write([]string{p.UserID.String(), p.Raw, p.Err.Error()}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// Migrate normalizes the stored phone numbers which have no country yet.
+// Numbers which can not be parsed in the region or collide with the number
+// of another user are left untouched and reported. Nothing is written in
+// a dry run, which therefore detects only collisions among the migrated rows.
+func Migrate(ctx context.Context, q repo.Querier, region string, dryRun bool) (*MigrationReport, error) {
+	report := &MigrationReport{}
+	if err := ValidateRegion(region); err != nil {
+		return report, err
+	}
+
+	seen := make(map[string]uuid.UUID)
+
+	after := uuid.Nil
+	for {
+		rows, err := q.ListUnnormalizedPhones(ctx, repo.ListUnnormalizedPhonesParams{
+			AfterID:  after,
+			RowLimit: migrationBatchSize,
+		})
+		if err != nil {
+			return report, fmt.Errorf("list phone numbers: %w", err)
+		}
+
+		for _, row := range rows {
+			after = row.ID
+
+			n, err := Normalize(row.PhoneNumber.String, region)
+			if err != nil {
+				report.Problems = append(report.Problems, Problem{UserID: row.ID, Raw: row.PhoneNumber.String, Err: err})
+				continue
+			}
+			if other, ok := seen[n.E164]; ok {
+				err = fmt.Errorf("%w: %s by user %s", ErrDuplicate, n.E164, other)
+				report.Problems = append(report.Problems, Problem{UserID: row.ID, Raw: row.PhoneNumber.String, Err: err})
+				continue
+			}
+			seen[n.E164] = row.ID
+
+			if !dryRun {
+				err = q.SetUserPhone(ctx, repo.SetUserPhoneParams{
+					PhoneNumber:  sql.NullString{String: n.E164, Valid: true},
+					PhoneCountry: sql.NullString{String: n.Country, Valid: true},
+					ID:           row.ID,
+				})
+				var pqErr *pq.Error
+				if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+					err = fmt.Errorf("%w: %s", ErrDuplicate, n.E164)
+					report.Problems = append(report.Problems, Problem{UserID: row.ID, Raw: row.PhoneNumber.String, Err: err})
+					continue
+				}
+				if err != nil {
+					return report, fmt.Errorf("update phone number of user %s: %w", row.ID, err)
+				}
+			}
+
+			report.Converted++
+		}
+
+		if len(rows) < migrationBatchSize {
+			return report, nil
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package phone_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chutommy/user-microservice/pkg/mocks"
+	"github.com/chutommy/user-microservice/pkg/phone"
+	"github.com/chutommy/user-microservice/pkg/repo"
+)
+
+func TestMigrate(t *testing.T) {
+	t.Parallel()
+
+	row := func(raw string) repo.ListUnnormalizedPhonesRow {
+		return repo.ListUnnormalizedPhonesRow{ID: uuid.New(), PhoneNumber: sql.NullString{String: raw, Valid: true}}
+	}
+	ok := row("(201) 555-0123")
+	invalid := row("555")
+	duplicate := row("+1 201 555 0123")
+	taken := row("+420 601 123 456")
+
+	q := new(mocks.Querier)
+	q.On("ListUnnormalizedPhones", mock.Anything, repo.ListUnnormalizedPhonesParams{AfterID: uuid.Nil, RowLimit: 500}).
+		Return([]repo.ListUnnormalizedPhonesRow{ok, invalid, duplicate, taken}, nil).Once()
+	q.On("SetUserPhone", mock.Anything, repo.SetUserPhoneParams{
+		PhoneNumber:  sql.NullString{String: "+12015550123", Valid: true},
+		PhoneCountry: sql.NullString{String: "US", Valid: true},
+		ID:           ok.ID,
+	}).Return(nil).Once()
+	q.On("SetUserPhone", mock.Anything, mock.MatchedBy(func(arg repo.SetUserPhoneParams) bool {
+		return arg.ID == taken.ID
+	})).Return(&pq.Error{Code: "23505"}).Once()
+
+	report, err := phone.Migrate(context.Background(), q, "US", false)
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Converted)
+	require.Len(t, report.Problems, 3)
+	require.ErrorIs(t, report.Problems[0].Err, phone.ErrInvalidNumber)
+	require.Equal(t, invalid.ID, report.Problems[0].UserID)
+	require.ErrorIs(t, report.Problems[1].Err, phone.ErrDuplicate)
+	require.ErrorIs(t, report.Problems[2].Err, phone.ErrDuplicate)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteCSV(&buf))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 4)
+	require.Equal(t, "user_id,phone_number,problem", lines[0])
+	require.True(t, strings.HasPrefix(lines[1], invalid.ID.String()+",555,"))
+
+	q.AssertExpectations(t)
+}
+
+func TestMigrate_DryRun(t *testing.T) {
+	t.Parallel()
+
+	q := new(mocks.Querier)
+	q.On("ListUnnormalizedPhones", mock.Anything, mock.Anything).Return([]repo.ListUnnormalizedPhonesRow{{
+		ID:          uuid.New(),
+		PhoneNumber: sql.NullString{String: "601123456", Valid: true},
+	}}, nil).Once()
+
+	report, err := phone.Migrate(context.Background(), q, "CZ", true)
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Converted)
+	require.Empty(t, report.Problems)
+
+	// nothing is written
+	q.AssertExpectations(t)
+	q.AssertNotCalled(t, "SetUserPhone", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,63 @@
+package phone
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nyaruka/phonenumbers"
+)
+
+var (
+	// ErrInvalidNumber is returned if the phone number can not be parsed or
+	// is not a valid number of its region.
+	ErrInvalidNumber = errors.New("invalid phone number")
+
+	// ErrUnknownRegion is returned for region codes without numbering metadata.
+	ErrUnknownRegion = errors.New("unknown phone region")
+)
+
+// Number is a normalized phone number.
+type Number struct {
+	// E164 is the number in the E.164 format, e.g. "+15550100000".
+	E164 string
+
+	// Country is the ISO 3166-1 alpha-2 code of the number's region, e.g. "US".
+	// It is "001" for non-geographic numbers.
+	Country string
+}
+
+// ValidateRegion reports an error if the region code is not known to the
+// numbering metadata. An empty region is valid.
+func ValidateRegion(region string) error {
+	if region == "" {
+		return nil
+	}
+	if phonenumbers.GetCountryCodeForRegion(strings.ToUpper(region)) == 0 {
+		return fmt.Errorf("%w: %q", ErrUnknownRegion, region)
+	}
+
+	return nil
+}
+
+// Normalize parses the phone number and formats it as E.164. Numbers without
+// the international prefix are parsed as numbers of the region; if the region
+// is empty, the number must start with "+".
+func Normalize(raw, region string) (Number, error) {
+	if err := ValidateRegion(region); err != nil {
+		return Number{}, err
+	}
+
+	num, err := phonenumbers.Parse(raw, strings.ToUpper(region))
+	if err != nil {
+		return Number{}, fmt.Errorf("%w: %q: %v", ErrInvalidNumber, raw, err)
+	}
+	if !phonenumbers.IsValidNumber(num) {
+		return Number{}, fmt.Errorf("%w: %q is not a valid number", ErrInvalidNumber, raw)
+	}
+
+	return Number{
+		E164:    phonenumbers.Format(num, phonenumbers.E164),
+		Country: phonenumbers.GetRegionCodeForNumber(num),
+	}, nil
+}
@@ -0,0 +1,56 @@
+package phone_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/chutommy/user-microservice/pkg/phone"
+)
+
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		raw        string
+		region     string
+		expE164    string
+		expCountry string
+		expErr     error
+	}{
+		{"international", "+1 (201) 555-0123", "", "+12015550123", "US", nil},
+		{"national with region", "(201) 555-0123", "US", "+12015550123", "US", nil},
+		{"lower case region", "201 555 0123", "us", "+12015550123", "US", nil},
+		{"international ignores region", "+420 601 123 456", "US", "+420601123456", "CZ", nil},
+		{"national czech", "601 123 456", "CZ", "+420601123456", "CZ", nil},
+		{"uk with trunk prefix", "020 7946 0018", "GB", "+442079460018", "GB", nil},
+		{"national without region", "201 555 0123", "", "", "", phone.ErrInvalidNumber},
+		{"garbage", "call me maybe", "US", "", "", phone.ErrInvalidNumber},
+		{"too short", "+1 555", "", "", "", phone.ErrInvalidNumber},
+		{"unknown region", "601 123 456", "XX", "", "", phone.ErrUnknownRegion},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			n, err := phone.Normalize(tt.raw, tt.region)
+			if tt.expErr != nil {
+				require.ErrorIs(t, err, tt.expErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expE164, n.E164)
+			require.Equal(t, tt.expCountry, n.Country)
+		})
+	}
+}
+
+func TestValidateRegion(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, phone.ValidateRegion(""))
+	require.NoError(t, phone.ValidateRegion("CZ"))
+	require.ErrorIs(t, phone.ValidateRegion("ZZ"), phone.ErrUnknownRegion)
+}
@@ -0,0 +1,226 @@
+package preference
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/chutommy/user-microservice/pkg/repo"
+)
+
+// Type is a type of a preference value.
+type Type int16
+
+const (
+	TypeString Type = iota + 1
+	TypeBool
+	TypeInt
+	TypeJSON
+)
+
+var (
+	// ErrInvalidName is returned for a namespace or key which does not follow the naming rules.
+	ErrInvalidName = errors.New("invalid name")
+
+	// ErrInvalidValue is returned if a value does not match its type.
+	ErrInvalidValue = errors.New("invalid value")
+)
+
+// namePattern restricts namespaces and keys to lowercase identifiers
+// which fit into the varchar(64) columns.
+var namePattern = regexp.MustCompile(`^[a-z][a-z0-9_.-]{0,63}$`)
+
+// ValidateName checks a namespace or a key.
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("%w: %q", ErrInvalidName, name)
+	}
+
+	return nil
+}
+
+// Value is a typed preference value. Raw holds its JSON encoding.
+type Value struct {
+	Type Type
+	Raw  json.RawMessage
+}
+
+// StringValue constructs a string Value.
+func StringValue(s string) Value {
+	raw, _ := json.Marshal(s)
+	return Value{Type: TypeString, Raw: raw}
+}
+
+// BoolValue constructs a bool Value.
+func BoolValue(b bool) Value {
+	return Value{Type: TypeBool, Raw: json.RawMessage(strconv.FormatBool(b))}
+}
+
+// IntValue constructs an int Value.
+func IntValue(i int64) Value {
+	return Value{Type: TypeInt, Raw: json.RawMessage(strconv.FormatInt(i, 10))}
+}
+
+// JSONValue constructs a JSON Value from an encoded JSON document.
+func JSONValue(doc string) (Value, error) {
+	if !json.Valid([]byte(doc)) {
+		return Value{}, fmt.Errorf("%w: malformed JSON", ErrInvalidValue)
+	}
+
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, []byte(doc)); err != nil {
+		return Value{}, fmt.Errorf("%w: %v", ErrInvalidValue, err)
+	}
+
+	return Value{Type: TypeJSON, Raw: buf.Bytes()}, nil
+}
+
+// String returns the value of a string Value.
+func (v Value) String() (string, error) {
+	var s string
+	err := v.decode(TypeString, &s)
+	return s, err
+}
+
+// Bool returns the value of a bool Value.
+func (v Value) Bool() (bool, error) {
+	var b bool
+	err := v.decode(TypeBool, &b)
+	return b, err
+}
+
+// Int returns the value of an int Value.
+func (v Value) Int() (int64, error) {
+	var i int64
+	err := v.decode(TypeInt, &i)
+	return i, err
+}
+
+func (v Value) decode(t Type, dst interface{}) error {
+	if v.Type != t {
+		return fmt.Errorf("%w: type %d instead of %d", ErrInvalidValue, v.Type, t)
+	}
+	if err := json.Unmarshal(v.Raw, dst); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidValue, err)
+	}
+
+	return nil
+}
+
+// UnmarshalJSON infers the type of the value from the JSON document: strings,
+// booleans and integers keep their types, anything else is a JSON value.
+func (v *Value) UnmarshalJSON(data []byte) error {
+	var x interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&x); err != nil {
+		return err
+	}
+
+	switch x := x.(type) {
+	case string:
+		*v = StringValue(x)
+		return nil
+	case bool:
+		*v = BoolValue(x)
+		return nil
+	case json.Number:
+		if i, err := x.Int64(); err == nil {
+			*v = IntValue(i)
+			return nil
+		}
+	}
+
+	jv, err := JSONValue(string(data))
+	if err != nil {
+		return err
+	}
+	*v = jv
+
+	return nil
+}
+
+// Preference is a stored or default value of a key in a namespace.
+type Preference struct {
+	Namespace string
+	Key       string
+	Value     Value
+	// Default is set if the user did not set the value.
+	Default bool
+}
+
+// FromRepo converts a stored preference.
+func FromRepo(p repo.UserPreference) Preference {
+	return Preference{
+		Namespace: p.Namespace,
+		Key:       p.Key,
+		Value: Value{
+			Type: Type(p.ValueType),
+			Raw:  p.Value,
+		},
+	}
+}
+
+// Defaults holds the default values of the keys per namespace.
+type Defaults map[string]map[string]Value
+
+// LoadDefaults reads defaults from a JSON document of the form
+// {"namespace": {"key": value}}.
+func LoadDefaults(r io.Reader) (Defaults, error) {
+	d := Defaults{}
+	if err := json.NewDecoder(r).Decode(&d); err != nil {
+		return nil, fmt.Errorf("decode preference defaults: %w", err)
+	}
+
+	for ns, keys := range d {
+		if err := ValidateName(ns); err != nil {
+			return nil, err
+		}
+		for k := range keys {
+			if err := ValidateName(k); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return d, nil
+}
+
+// Merge completes the stored preferences with the defaults of the namespace,
+// or of all namespaces if namespace is empty. Stored values take precedence.
+// The result is sorted by namespace and key.
+func (d Defaults) Merge(namespace string, stored []Preference) []Preference {
+	type name struct{ ns, key string }
+	seen := make(map[name]bool, len(stored))
+
+	merged := make([]Preference, 0, len(stored))
+	for _, p := range stored {
+		seen[name{p.Namespace, p.Key}] = true
+		merged = append(merged, p)
+	}
+
+	for ns, keys := range d {
+		if namespace != "" && ns != namespace {
+			continue
+		}
+		for k, v := range keys {
+			if !seen[name{ns, k}] {
+				merged = append(merged, Preference{Namespace: ns, Key: k, Value: v, Default: true})
+			}
+		}
+	}
+
+	sort.Slice(merged, func(i, j int) bool {
+		if merged[i].Namespace != merged[j].Namespace {
+			return merged[i].Namespace < merged[j].Namespace
+		}
+		return merged[i].Key < merged[j].Key
+	})
+
+	return merged
+}
@@ -0,0 +1,91 @@
+package preference_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/chutommy/user-microservice/pkg/preference"
+)
+
+func TestValidateName(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{"ui", "notifications", "email_digest", "theme.dark", "a1-b2"} {
+		require.NoError(t, preference.ValidateName(name), name)
+	}
+	for _, name := range []string{"", "UI", "1st", "_key", "with space", strings.Repeat("a", 65)} {
+		require.ErrorIs(t, preference.ValidateName(name), preference.ErrInvalidName, name)
+	}
+}
+
+func TestValue(t *testing.T) {
+	t.Parallel()
+
+	s, err := preference.StringValue("dark").String()
+	require.NoError(t, err)
+	require.Equal(t, "dark", s)
+
+	b, err := preference.BoolValue(true).Bool()
+	require.NoError(t, err)
+	require.True(t, b)
+
+	i, err := preference.IntValue(-42).Int()
+	require.NoError(t, err)
+	require.Equal(t, int64(-42), i)
+
+	_, err = preference.IntValue(1).String()
+	require.ErrorIs(t, err, preference.ErrInvalidValue)
+
+	jv, err := preference.JSONValue(`{ "a": [1, 2] }`)
+	require.NoError(t, err)
+	require.Equal(t, `{"a":[1,2]}`, string(jv.Raw))
+
+	_, err = preference.JSONValue(`{"a":`)
+	require.ErrorIs(t, err, preference.ErrInvalidValue)
+}
+
+func TestLoadDefaults(t *testing.T) {
+	t.Parallel()
+
+	d, err := preference.LoadDefaults(strings.NewReader(`{
+		"ui": {"theme": "light", "font_size": 12, "scale": 1.5},
+		"notifications": {"email": true, "channels": ["email", "sms"]}
+	}`))
+	require.NoError(t, err)
+
+	require.Equal(t, preference.TypeString, d["ui"]["theme"].Type)
+	require.Equal(t, preference.TypeInt, d["ui"]["font_size"].Type)
+	require.Equal(t, preference.TypeJSON, d["ui"]["scale"].Type)
+	require.Equal(t, preference.TypeBool, d["notifications"]["email"].Type)
+	require.Equal(t, preference.TypeJSON, d["notifications"]["channels"].Type)
+	require.JSONEq(t, `["email","sms"]`, string(d["notifications"]["channels"].Raw))
+
+	_, err = preference.LoadDefaults(strings.NewReader(`{"UI": {"theme": "light"}}`))
+	require.ErrorIs(t, err, preference.ErrInvalidName)
+}
+
+func TestDefaults_Merge(t *testing.T) {
+	t.Parallel()
+
+	d := preference.Defaults{
+		"ui":            {"theme": preference.StringValue("light"), "compact": preference.BoolValue(false)},
+		"notifications": {"email": preference.BoolValue(true)},
+	}
+	stored := []preference.Preference{
+		{Namespace: "ui", Key: "theme", Value: preference.StringValue("dark")},
+	}
+
+	merged := d.Merge("ui", stored)
+	require.Len(t, merged, 2)
+	require.Equal(t, "compact", merged[0].Key)
+	require.True(t, merged[0].Default)
+	require.Equal(t, "theme", merged[1].Key)
+	require.False(t, merged[1].Default)
+	require.Equal(t, json.RawMessage(`"dark"`), merged[1].Value.Raw)
+
+	require.Len(t, d.Merge("", stored), 3)
+	require.Len(t, preference.Defaults(nil).Merge("", stored), 1)
+}
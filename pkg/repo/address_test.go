@@ -0,0 +1,199 @@
+package repo_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/chutommy/user-microservice/pkg/migration"
+	"github.com/chutommy/user-microservice/pkg/repo"
+)
+
+// DatabaseEnv is the environment variable of the URL of an empty database the
+// queries are tested against. The tests are skipped if it is not set.
+const DatabaseEnv = "TEST_DATABASE_URL"
+
+// openDB migrates the test database and opens it.
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	dsn := os.Getenv(DatabaseEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", DatabaseEnv)
+	}
+
+	// the migrator closes its connection
+	migrationDB, err := sql.Open("postgres", dsn)
+	require.NoError(t, err)
+	m, err := migration.New(migrationDB, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, m.UpLocked(context.Background()))
+	require.NoError(t, m.Close())
+
+	db, err := sql.Open("postgres", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	return db
+}
+
+// createUser stores a new user.
+func createUser(t *testing.T, q *repo.Queries) uuid.UUID {
+	t.Helper()
+
+	id := uuid.New()
+	email := id.String() + "@example.org"
+	_, err := q.CreateUser(context.Background(), repo.CreateUserParams{
+		ID:             id,
+		Email:          email,
+		EmailCanonical: email,
+		HashedPassword: "hash",
+		FirstName:      "Alice",
+		LastName:       "Smith",
+		Attributes:     []byte("{}"),
+	})
+	require.NoError(t, err)
+
+	return id
+}
+
+func TestQueries_DefaultAddress(t *testing.T) {
+	t.Parallel()
+
+	db := openDB(t)
+
+	const (
+		home    = 1
+		billing = 2
+	)
+
+	tests := []struct {
+		name string
+
+		// count of the home addresses, the first one is the default
+		addresses int
+		change    func(q *repo.Queries, uid uuid.UUID, ids []uuid.UUID) error
+
+		// indexes of the addresses which are the defaults afterwards
+		defaults []int
+	}{
+		{
+			name:      "delete default",
+			addresses: 3,
+			change: func(q *repo.Queries, uid uuid.UUID, ids []uuid.UUID) error {
+				_, err := q.DeleteAddress(context.Background(), repo.DeleteAddressParams{ID: ids[0], UserID: uid})
+				return err
+			},
+			defaults: []int{1},
+		},
+		{
+			name:      "delete other",
+			addresses: 2,
+			change: func(q *repo.Queries, uid uuid.UUID, ids []uuid.UUID) error {
+				_, err := q.DeleteAddress(context.Background(), repo.DeleteAddressParams{ID: ids[1], UserID: uid})
+				return err
+			},
+			defaults: []int{0},
+		},
+		{
+			name:      "unset default",
+			addresses: 3,
+			change: func(q *repo.Queries, uid uuid.UUID, ids []uuid.UUID) error {
+				return updateAddress(q, uid, ids[0], home, false)
+			},
+			defaults: []int{1},
+		},
+		{
+			name:      "unset the only default",
+			addresses: 1,
+			change: func(q *repo.Queries, uid uuid.UUID, ids []uuid.UUID) error {
+				return updateAddress(q, uid, ids[0], home, false)
+			},
+			defaults: []int{0},
+		},
+		{
+			name:      "move default to another kind",
+			addresses: 2,
+			change: func(q *repo.Queries, uid uuid.UUID, ids []uuid.UUID) error {
+				return updateAddress(q, uid, ids[0], billing, true)
+			},
+			defaults: []int{0, 1},
+		},
+		{
+			name:      "set another default",
+			addresses: 2,
+			change: func(q *repo.Queries, uid uuid.UUID, ids []uuid.UUID) error {
+				return updateAddress(q, uid, ids[1], home, true)
+			},
+			defaults: []int{1},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			q := repo.New(db)
+			uid := createUser(t, q)
+
+			ids := make([]uuid.UUID, tt.addresses)
+			for i := range ids {
+				created, err := q.CreateAddress(context.Background(), repo.CreateAddressParams{
+					ID:          uuid.New(),
+					UserID:      uid,
+					Kind:        home,
+					Recipient:   "Alice Smith",
+					Line1:       "1600 Amphitheatre Parkway",
+					City:        "Mountain View",
+					CountryCode: "US",
+				})
+				require.NoError(t, err)
+				require.Equal(t, i == 0, created.IsDefault)
+				ids[i] = created.ID
+
+				// the addresses are promoted in the order of their creation
+				time.Sleep(time.Millisecond)
+			}
+
+			require.NoError(t, tt.change(q, uid, ids))
+
+			addrs, err := q.ListAddresses(context.Background(), repo.ListAddressesParams{UserID: uid})
+			require.NoError(t, err)
+
+			var defaults []int
+			for i, id := range ids {
+				for _, a := range addrs {
+					if a.ID == id && a.IsDefault {
+						defaults = append(defaults, i)
+					}
+				}
+			}
+			require.Equal(t, tt.defaults, defaults)
+		})
+	}
+}
+
+// updateAddress changes the kind and the default flag of the address.
+func updateAddress(q *repo.Queries, uid, id uuid.UUID, kind int16, isDefault bool) error {
+	_, err := q.UpdateAddress(context.Background(), repo.UpdateAddressParams{
+		ID:          id,
+		UserID:      uid,
+		Kind:        kind,
+		IsDefault:   isDefault,
+		Recipient:   "Alice Smith",
+		Line1:       "1600 Amphitheatre Parkway",
+		City:        "Mountain View",
+		CountryCode: "US",
+	})
+
+	return err
+}
@@ -0,0 +1,138 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chutommy/user-microservice/pkg/repo"
+)
+
+// preceding returns the ID right before the given one, so the listing starting
+// after it begins with the given ID if it is listed.
+func preceding(id uuid.UUID) uuid.UUID {
+	for i := len(id) - 1; i >= 0; i-- {
+		id[i]--
+		if id[i] != 0xff {
+			break
+		}
+	}
+
+	return id
+}
+
+func TestQueries_ListUsersRequiringConsent(t *testing.T) {
+	t.Parallel()
+
+	db := openDB(t)
+
+	type policy struct {
+		version  int32
+		required bool
+	}
+
+	tests := []struct {
+		name string
+
+		policies []policy
+		// versions the user consents to
+		consents []int32
+		withdraw bool
+
+		pending bool
+	}{
+		{
+			name:    "no policy",
+			pending: false,
+		},
+		{
+			name:     "only optional policies",
+			policies: []policy{{version: 1}, {version: 2}},
+			pending:  false,
+		},
+		{
+			name:     "no consent",
+			policies: []policy{{version: 1, required: true}},
+			pending:  true,
+		},
+		{
+			name:     "consented to the latest",
+			policies: []policy{{version: 1, required: true}, {version: 2, required: true}},
+			consents: []int32{2},
+			pending:  false,
+		},
+		{
+			name:     "consented to an outdated one",
+			policies: []policy{{version: 1, required: true}, {version: 2, required: true}},
+			consents: []int32{1},
+			pending:  true,
+		},
+		{
+			name:     "newer optional version",
+			policies: []policy{{version: 1, required: true}, {version: 2}},
+			consents: []int32{1},
+			pending:  false,
+		},
+		{
+			name:     "withdrawn",
+			policies: []policy{{version: 1, required: true}},
+			consents: []int32{1},
+			withdraw: true,
+			pending:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			q := repo.New(db)
+			uid := createUser(t, q)
+
+			// the kind is unique so the tests do not share the policies
+			kind := "test_" + uuid.New().String()[:8]
+			for _, p := range tt.policies {
+				_, err := q.CreateConsentPolicy(ctx, repo.CreateConsentPolicyParams{
+					Kind:     kind,
+					Version:  p.version,
+					Required: p.required,
+				})
+				require.NoError(t, err)
+			}
+			for _, v := range tt.consents {
+				_, err := q.RecordConsent(ctx, repo.RecordConsentParams{
+					UserID:        uid,
+					PolicyKind:    kind,
+					PolicyVersion: v,
+				})
+				require.NoError(t, err)
+			}
+			if tt.withdraw {
+				_, err := q.WithdrawConsent(ctx, repo.WithdrawConsentParams{UserID: uid, PolicyKind: kind})
+				require.NoError(t, err)
+			}
+
+			ids, err := q.ListUsersRequiringConsent(ctx, repo.ListUsersRequiringConsentParams{
+				PolicyKind: kind,
+				AfterID:    preceding(uid),
+				RowLimit:   1,
+			})
+			require.NoError(t, err)
+			require.Equal(t, tt.pending, len(ids) == 1 && ids[0] == uid)
+
+			// nobody is listed without a required version
+			required := false
+			for _, p := range tt.policies {
+				required = required || p.required
+			}
+			if !required {
+				require.Empty(t, ids)
+			}
+		})
+	}
+}
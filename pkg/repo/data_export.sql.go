@@ -0,0 +1,161 @@
+// Code generated by sqlc. DO NOT EDIT.
+// source: data_export.sql
+
+package repo
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const completeDataExport = `-- name: CompleteDataExport :one
+update data_exports
+set status       = $1,
+    blob_key     = $2,
+    signature    = $3,
+    completed_at = now()
+where id = $4
+returning id, user_id, status, blob_key, signature, created_at, completed_at
+`
+
+type CompleteDataExportParams struct {
+	Status    int16     `json:"status"`
+	BlobKey   string    `json:"blobKey"`
+	Signature string    `json:"signature"`
+	ID        uuid.UUID `json:"id"`
+}
+
+func (q *Queries) CompleteDataExport(ctx context.Context, arg CompleteDataExportParams) (DataExport, error) {
+	row := q.db.QueryRowContext(ctx, completeDataExport,
+		arg.Status,
+		arg.BlobKey,
+		arg.Signature,
+		arg.ID,
+	)
+	var i DataExport
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Status,
+		&i.BlobKey,
+		&i.Signature,
+		&i.CreatedAt,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const createDataExport = `-- name: CreateDataExport :one
+insert into data_exports (id, user_id)
+values ($1, $2)
+returning id, user_id, status, blob_key, signature, created_at, completed_at
+`
+
+type CreateDataExportParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"userId"`
+}
+
+func (q *Queries) CreateDataExport(ctx context.Context, arg CreateDataExportParams) (DataExport, error) {
+	row := q.db.QueryRowContext(ctx, createDataExport, arg.ID, arg.UserID)
+	var i DataExport
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Status,
+		&i.BlobKey,
+		&i.Signature,
+		&i.CreatedAt,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const getDataExport = `-- name: GetDataExport :one
+select id, user_id, status, blob_key, signature, created_at, completed_at
+from data_exports
+where id = $1
+limit 1
+`
+
+func (q *Queries) GetDataExport(ctx context.Context, id uuid.UUID) (DataExport, error) {
+	row := q.db.QueryRowContext(ctx, getDataExport, id)
+	var i DataExport
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Status,
+		&i.BlobKey,
+		&i.Signature,
+		&i.CreatedAt,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const listDataExportBlobKeys = `-- name: ListDataExportBlobKeys :many
+select blob_key
+from data_exports
+where user_id = $1
+  and blob_key <> ''
+`
+
+func (q *Queries) ListDataExportBlobKeys(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listDataExportBlobKeys, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var blob_key string
+		if err := rows.Scan(&blob_key); err != nil {
+			return nil, err
+		}
+		items = append(items, blob_key)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listErasureReceipts = `-- name: ListErasureReceipts :many
+select id, user_id, reason, erased_at, published_at
+from erasure_receipts
+where user_id = $1
+order by erased_at
+`
+
+func (q *Queries) ListErasureReceipts(ctx context.Context, userID uuid.UUID) ([]ErasureReceipt, error) {
+	rows, err := q.db.QueryContext(ctx, listErasureReceipts, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ErasureReceipt{}
+	for rows.Next() {
+		var i ErasureReceipt
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Reason,
+			&i.ErasedAt,
+			&i.PublishedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,178 @@
+// Code generated by sqlc. DO NOT EDIT.
+// source: email.sql
+
+package repo
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const addEmail = `-- name: AddEmail :one
+insert into user_emails (email, email_canonical, user_id, verified)
+select $1, $2::varchar(254), users.id, $3
+from users
+where users.id = $4
+  and users.erased_at is null
+returning email, user_id, verified, is_primary, created_at, email_canonical
+`
+
+type AddEmailParams struct {
+	Email          string    `json:"email"`
+	EmailCanonical string    `json:"emailCanonical"`
+	Verified       bool      `json:"verified"`
+	UserID         uuid.UUID `json:"userId"`
+}
+
+func (q *Queries) AddEmail(ctx context.Context, arg AddEmailParams) (UserEmail, error) {
+	row := q.db.QueryRowContext(ctx, addEmail,
+		arg.Email,
+		arg.EmailCanonical,
+		arg.Verified,
+		arg.UserID,
+	)
+	var i UserEmail
+	err := row.Scan(
+		&i.Email,
+		&i.UserID,
+		&i.Verified,
+		&i.IsPrimary,
+		&i.CreatedAt,
+		&i.EmailCanonical,
+	)
+	return i, err
+}
+
+const getUserByEmail = `-- name: GetUserByEmail :one
+select users.id, users.email, users.phone_number, users.hashed_password, users.first_name, users.last_name, users.gender, users.birth_day, users.updated_at, users.created_at, users.erased_at, users.attributes, users.avatar_key, users.phone_country, users.email_canonical, users.country, users.state
+from users
+         join user_emails on user_emails.user_id = users.id
+where user_emails.email_canonical = $1::varchar(254)
+limit 1
+`
+
+func (q *Queries) GetUserByEmail(ctx context.Context, emailCanonical string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByEmail, emailCanonical)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.PhoneNumber,
+		&i.HashedPassword,
+		&i.FirstName,
+		&i.LastName,
+		&i.Gender,
+		&i.BirthDay,
+		&i.UpdatedAt,
+		&i.CreatedAt,
+		&i.ErasedAt,
+		&i.Attributes,
+		&i.AvatarKey,
+		&i.PhoneCountry,
+		&i.EmailCanonical,
+		&i.Country,
+		&i.State,
+	)
+	return i, err
+}
+
+const listEmails = `-- name: ListEmails :many
+select email, user_id, verified, is_primary, created_at, email_canonical
+from user_emails
+where user_id = $1
+order by is_primary desc, created_at
+`
+
+func (q *Queries) ListEmails(ctx context.Context, userID uuid.UUID) ([]UserEmail, error) {
+	rows, err := q.db.QueryContext(ctx, listEmails, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []UserEmail{}
+	for rows.Next() {
+		var i UserEmail
+		if err := rows.Scan(
+			&i.Email,
+			&i.UserID,
+			&i.Verified,
+			&i.IsPrimary,
+			&i.CreatedAt,
+			&i.EmailCanonical,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const removeEmail = `-- name: RemoveEmail :execrows
+delete
+from user_emails
+where user_id = $1
+  and email_canonical = $2::varchar(254)
+  and not is_primary
+`
+
+type RemoveEmailParams struct {
+	UserID         uuid.UUID `json:"userId"`
+	EmailCanonical string    `json:"emailCanonical"`
+}
+
+func (q *Queries) RemoveEmail(ctx context.Context, arg RemoveEmailParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, removeEmail, arg.UserID, arg.EmailCanonical)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setPrimaryEmail = `-- name: SetPrimaryEmail :one
+update users
+set email           = user_emails.email,
+    email_canonical = user_emails.email_canonical
+from user_emails
+where users.id = $1
+  and users.erased_at is null
+  and user_emails.user_id = users.id
+  and user_emails.email_canonical = $2::varchar(254)
+returning users.id, users.email, users.phone_number, users.hashed_password, users.first_name, users.last_name, users.gender, users.birth_day, users.updated_at, users.created_at, users.erased_at, users.attributes, users.avatar_key, users.phone_country, users.email_canonical, users.country, users.state
+`
+
+type SetPrimaryEmailParams struct {
+	ID             uuid.UUID `json:"id"`
+	EmailCanonical string    `json:"emailCanonical"`
+}
+
+func (q *Queries) SetPrimaryEmail(ctx context.Context, arg SetPrimaryEmailParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, setPrimaryEmail, arg.ID, arg.EmailCanonical)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.PhoneNumber,
+		&i.HashedPassword,
+		&i.FirstName,
+		&i.LastName,
+		&i.Gender,
+		&i.BirthDay,
+		&i.UpdatedAt,
+		&i.CreatedAt,
+		&i.ErasedAt,
+		&i.Attributes,
+		&i.AvatarKey,
+		&i.PhoneCountry,
+		&i.EmailCanonical,
+		&i.Country,
+		&i.State,
+	)
+	return i, err
+}
@@ -0,0 +1,164 @@
+// Code generated by sqlc. DO NOT EDIT.
+// source: guardian.sql
+
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const confirmGuardianConsent = `-- name: ConfirmGuardianConsent :one
+with confirmed as
+         (
+             update guardian_consents
+                 set confirmed_at = now()
+                 where guardian_consents.id = $1
+                     and guardian_consents.guardian_id = $2
+                     and guardian_consents.confirmed_at is null
+                     and guardian_consents.expires_at > now()
+                 returning id, minor_id, guardian_id, expires_at, confirmed_at, created_at
+         ),
+     activated as
+         (
+             -- the minor's account becomes active once a guardian consents
+             update users
+                 set state = 0
+                 from confirmed
+                 where users.id = confirmed.minor_id
+                     and users.state = 1
+         )
+select id, minor_id, guardian_id, expires_at, confirmed_at, created_at
+from confirmed
+`
+
+type ConfirmGuardianConsentParams struct {
+	ID         uuid.UUID `json:"id"`
+	GuardianID uuid.UUID `json:"guardianId"`
+}
+
+type ConfirmGuardianConsentRow struct {
+	ID          uuid.UUID    `json:"id"`
+	MinorID     uuid.UUID    `json:"minorId"`
+	GuardianID  uuid.UUID    `json:"guardianId"`
+	ExpiresAt   time.Time    `json:"expiresAt"`
+	ConfirmedAt sql.NullTime `json:"confirmedAt"`
+	CreatedAt   time.Time    `json:"createdAt"`
+}
+
+func (q *Queries) ConfirmGuardianConsent(ctx context.Context, arg ConfirmGuardianConsentParams) (ConfirmGuardianConsentRow, error) {
+	row := q.db.QueryRowContext(ctx, confirmGuardianConsent, arg.ID, arg.GuardianID)
+	var i ConfirmGuardianConsentRow
+	err := row.Scan(
+		&i.ID,
+		&i.MinorID,
+		&i.GuardianID,
+		&i.ExpiresAt,
+		&i.ConfirmedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createGuardianConsent = `-- name: CreateGuardianConsent :one
+insert into guardian_consents (id, minor_id, guardian_id, expires_at)
+select $1, users.id, $2, $3
+from users
+where users.id = $4
+  and users.erased_at is null
+returning id, minor_id, guardian_id, expires_at, confirmed_at, created_at
+`
+
+type CreateGuardianConsentParams struct {
+	ID         uuid.UUID `json:"id"`
+	GuardianID uuid.UUID `json:"guardianId"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	MinorID    uuid.UUID `json:"minorId"`
+}
+
+func (q *Queries) CreateGuardianConsent(ctx context.Context, arg CreateGuardianConsentParams) (GuardianConsent, error) {
+	row := q.db.QueryRowContext(ctx, createGuardianConsent,
+		arg.ID,
+		arg.GuardianID,
+		arg.ExpiresAt,
+		arg.MinorID,
+	)
+	var i GuardianConsent
+	err := row.Scan(
+		&i.ID,
+		&i.MinorID,
+		&i.GuardianID,
+		&i.ExpiresAt,
+		&i.ConfirmedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteGuardianConsent = `-- name: DeleteGuardianConsent :exec
+delete
+from guardian_consents
+where id = $1
+  and confirmed_at is null
+`
+
+// withdraws a request the guardian was not notified about
+func (q *Queries) DeleteGuardianConsent(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteGuardianConsent, id)
+	return err
+}
+
+const hasGuardianConsent = `-- name: HasGuardianConsent :one
+select exists(select 1
+              from guardian_consents
+              where minor_id = $1
+                and confirmed_at is not null)
+`
+
+func (q *Queries) HasGuardianConsent(ctx context.Context, minorID uuid.UUID) (bool, error) {
+	row := q.db.QueryRowContext(ctx, hasGuardianConsent, minorID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const listGuardianConsents = `-- name: ListGuardianConsents :many
+select id, minor_id, guardian_id, expires_at, confirmed_at, created_at
+from guardian_consents
+where minor_id = $1
+   or guardian_id = $1
+order by created_at
+`
+
+func (q *Queries) ListGuardianConsents(ctx context.Context, userID uuid.UUID) ([]GuardianConsent, error) {
+	rows, err := q.db.QueryContext(ctx, listGuardianConsents, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GuardianConsent{}
+	for rows.Next() {
+		var i GuardianConsent
+		if err := rows.Scan(
+			&i.ID,
+			&i.MinorID,
+			&i.GuardianID,
+			&i.ExpiresAt,
+			&i.ConfirmedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
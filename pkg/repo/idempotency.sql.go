@@ -0,0 +1,169 @@
+// Code generated by sqlc. DO NOT EDIT.
+// source: idempotency.sql
+
+package repo
+
+import (
+	"context"
+	"time"
+)
+
+const completeIdempotencyKey = `-- name: CompleteIdempotencyKey :execrows
+update idempotency_keys
+set response = $1
+where caller = $2
+  and method = $3
+  and idempotency_key = $4
+  and fingerprint = $5
+  and created_at = $6
+  and response is null
+`
+
+type CompleteIdempotencyKeyParams struct {
+	Response       []byte    `json:"response"`
+	Caller         string    `json:"caller"`
+	Method         string    `json:"method"`
+	IdempotencyKey string    `json:"idempotencyKey"`
+	Fingerprint    []byte    `json:"fingerprint"`
+	ReservedAt     time.Time `json:"reservedAt"`
+}
+
+// the created_at of the reservation tells it apart from a later takeover
+func (q *Queries) CompleteIdempotencyKey(ctx context.Context, arg CompleteIdempotencyKeyParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, completeIdempotencyKey,
+		arg.Response,
+		arg.Caller,
+		arg.Method,
+		arg.IdempotencyKey,
+		arg.Fingerprint,
+		arg.ReservedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteExpiredIdempotencyKeys = `-- name: DeleteExpiredIdempotencyKeys :execrows
+delete
+from idempotency_keys
+where expires_at <= now()
+`
+
+func (q *Queries) DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteExpiredIdempotencyKeys)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getIdempotencyKey = `-- name: GetIdempotencyKey :one
+select caller, method, idempotency_key, fingerprint, response, created_at, expires_at
+from idempotency_keys
+where caller = $1
+  and method = $2
+  and idempotency_key = $3
+  and expires_at > now()
+limit 1
+`
+
+type GetIdempotencyKeyParams struct {
+	Caller         string `json:"caller"`
+	Method         string `json:"method"`
+	IdempotencyKey string `json:"idempotencyKey"`
+}
+
+func (q *Queries) GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error) {
+	row := q.db.QueryRowContext(ctx, getIdempotencyKey, arg.Caller, arg.Method, arg.IdempotencyKey)
+	var i IdempotencyKey
+	err := row.Scan(
+		&i.Caller,
+		&i.Method,
+		&i.IdempotencyKey,
+		&i.Fingerprint,
+		&i.Response,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const releaseIdempotencyKey = `-- name: ReleaseIdempotencyKey :execrows
+delete
+from idempotency_keys
+where caller = $1
+  and method = $2
+  and idempotency_key = $3
+  and fingerprint = $4
+  and created_at = $5
+  and response is null
+`
+
+type ReleaseIdempotencyKeyParams struct {
+	Caller         string    `json:"caller"`
+	Method         string    `json:"method"`
+	IdempotencyKey string    `json:"idempotencyKey"`
+	Fingerprint    []byte    `json:"fingerprint"`
+	ReservedAt     time.Time `json:"reservedAt"`
+}
+
+func (q *Queries) ReleaseIdempotencyKey(ctx context.Context, arg ReleaseIdempotencyKeyParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, releaseIdempotencyKey,
+		arg.Caller,
+		arg.Method,
+		arg.IdempotencyKey,
+		arg.Fingerprint,
+		arg.ReservedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const reserveIdempotencyKey = `-- name: ReserveIdempotencyKey :one
+insert into idempotency_keys (caller, method, idempotency_key, fingerprint, expires_at)
+values ($1, $2, $3, $4, $5)
+on conflict (caller, method, idempotency_key) do update
+    set fingerprint = excluded.fingerprint,
+        response    = null,
+        created_at  = now(),
+        expires_at  = excluded.expires_at
+where idempotency_keys.expires_at <= now()
+   or (idempotency_keys.response is null and idempotency_keys.created_at <= $6)
+returning caller, method, idempotency_key, fingerprint, response, created_at, expires_at
+`
+
+type ReserveIdempotencyKeyParams struct {
+	Caller         string    `json:"caller"`
+	Method         string    `json:"method"`
+	IdempotencyKey string    `json:"idempotencyKey"`
+	Fingerprint    []byte    `json:"fingerprint"`
+	ExpiresAt      time.Time `json:"expiresAt"`
+	StaleBefore    time.Time `json:"staleBefore"`
+}
+
+// reserves an unused key, a key which expired or whose call was abandoned
+// before @stale_before is taken over
+func (q *Queries) ReserveIdempotencyKey(ctx context.Context, arg ReserveIdempotencyKeyParams) (IdempotencyKey, error) {
+	row := q.db.QueryRowContext(ctx, reserveIdempotencyKey,
+		arg.Caller,
+		arg.Method,
+		arg.IdempotencyKey,
+		arg.Fingerprint,
+		arg.ExpiresAt,
+		arg.StaleBefore,
+	)
+	var i IdempotencyKey
+	err := row.Scan(
+		&i.Caller,
+		&i.Method,
+		&i.IdempotencyKey,
+		&i.Fingerprint,
+		&i.Response,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
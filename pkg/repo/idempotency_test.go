@@ -0,0 +1,114 @@
+package repo_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chutommy/user-microservice/pkg/repo"
+)
+
+func TestQueries_IdempotencyKeyTakeover(t *testing.T) {
+	t.Parallel()
+
+	db := openDB(t)
+
+	tests := []struct {
+		name string
+
+		// finish completes or releases the first reservation after the
+		// takeover
+		finish func(q *repo.Queries, first repo.IdempotencyKey) (int64, error)
+	}{
+		{
+			name: "complete",
+			finish: func(q *repo.Queries, first repo.IdempotencyKey) (int64, error) {
+				return q.CompleteIdempotencyKey(context.Background(), repo.CompleteIdempotencyKeyParams{
+					Response:       []byte("first"),
+					Caller:         first.Caller,
+					Method:         first.Method,
+					IdempotencyKey: first.IdempotencyKey,
+					Fingerprint:    first.Fingerprint,
+					ReservedAt:     first.CreatedAt,
+				})
+			},
+		},
+		{
+			name: "release",
+			finish: func(q *repo.Queries, first repo.IdempotencyKey) (int64, error) {
+				return q.ReleaseIdempotencyKey(context.Background(), repo.ReleaseIdempotencyKeyParams{
+					Caller:         first.Caller,
+					Method:         first.Method,
+					IdempotencyKey: first.IdempotencyKey,
+					Fingerprint:    first.Fingerprint,
+					ReservedAt:     first.CreatedAt,
+				})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			q := repo.New(db)
+
+			key := uuid.New().String()
+			reserve := func(fingerprint string, staleBefore time.Time) (repo.IdempotencyKey, error) {
+				return q.ReserveIdempotencyKey(ctx, repo.ReserveIdempotencyKeyParams{
+					Caller:         "test",
+					Method:         "/user.UserService/RegisterUser",
+					IdempotencyKey: key,
+					Fingerprint:    []byte(fingerprint),
+					ExpiresAt:      time.Now().Add(time.Hour),
+					StaleBefore:    staleBefore,
+				})
+			}
+
+			first, err := reserve("a", time.Now().Add(-time.Minute))
+			require.NoError(t, err)
+
+			// the in-progress reservation is kept
+			_, err = reserve("b", time.Now().Add(-time.Minute))
+			require.ErrorIs(t, err, sql.ErrNoRows)
+
+			// the reservation is stale for the second call
+			time.Sleep(time.Millisecond)
+			second, err := reserve("a", time.Now().Add(time.Minute))
+			require.NoError(t, err)
+			require.NotEqual(t, first.CreatedAt, second.CreatedAt)
+
+			n, err := tt.finish(q, first)
+			require.NoError(t, err)
+			require.Zero(t, n)
+
+			stored, err := q.GetIdempotencyKey(ctx, repo.GetIdempotencyKeyParams{
+				Caller:         second.Caller,
+				Method:         second.Method,
+				IdempotencyKey: second.IdempotencyKey,
+			})
+			require.NoError(t, err)
+			require.Nil(t, stored.Response)
+			require.True(t, stored.CreatedAt.Equal(second.CreatedAt))
+
+			// the second call completes its own reservation
+			n, err = q.CompleteIdempotencyKey(ctx, repo.CompleteIdempotencyKeyParams{
+				Response:       []byte("second"),
+				Caller:         second.Caller,
+				Method:         second.Method,
+				IdempotencyKey: second.IdempotencyKey,
+				Fingerprint:    second.Fingerprint,
+				ReservedAt:     second.CreatedAt,
+			})
+			require.NoError(t, err)
+			require.Equal(t, int64(1), n)
+		})
+	}
+}
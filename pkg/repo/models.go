@@ -4,20 +4,114 @@ package repo
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+type ConsentPolicy struct {
+	Kind        string    `json:"kind"`
+	Version     int32     `json:"version"`
+	Required    bool      `json:"required"`
+	PublishedAt time.Time `json:"publishedAt"`
+}
+
+type DataExport struct {
+	ID          uuid.UUID    `json:"id"`
+	UserID      uuid.UUID    `json:"userId"`
+	Status      int16        `json:"status"`
+	BlobKey     string       `json:"blobKey"`
+	Signature   string       `json:"signature"`
+	CreatedAt   time.Time    `json:"createdAt"`
+	CompletedAt sql.NullTime `json:"completedAt"`
+}
+
+type ErasureReceipt struct {
+	ID          uuid.UUID    `json:"id"`
+	UserID      uuid.UUID    `json:"userId"`
+	Reason      string       `json:"reason"`
+	ErasedAt    time.Time    `json:"erasedAt"`
+	PublishedAt sql.NullTime `json:"publishedAt"`
+}
+
+type GuardianConsent struct {
+	ID          uuid.UUID    `json:"id"`
+	MinorID     uuid.UUID    `json:"minorId"`
+	GuardianID  uuid.UUID    `json:"guardianId"`
+	ExpiresAt   time.Time    `json:"expiresAt"`
+	ConfirmedAt sql.NullTime `json:"confirmedAt"`
+	CreatedAt   time.Time    `json:"createdAt"`
+}
+
+type IdempotencyKey struct {
+	Caller         string    `json:"caller"`
+	Method         string    `json:"method"`
+	IdempotencyKey string    `json:"idempotencyKey"`
+	Fingerprint    []byte    `json:"fingerprint"`
+	Response       []byte    `json:"response"`
+	CreatedAt      time.Time `json:"createdAt"`
+	ExpiresAt      time.Time `json:"expiresAt"`
+}
+
 type User struct {
-	ID             uuid.UUID      `json:"id"`
+	ID             uuid.UUID       `json:"id"`
+	Email          string          `json:"email"`
+	PhoneNumber    sql.NullString  `json:"phoneNumber"`
+	HashedPassword string          `json:"hashedPassword"`
+	FirstName      string          `json:"firstName"`
+	LastName       string          `json:"lastName"`
+	Gender         int16           `json:"gender"`
+	BirthDay       sql.NullTime    `json:"birthDay"`
+	UpdatedAt      sql.NullTime    `json:"updatedAt"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	ErasedAt       sql.NullTime    `json:"erasedAt"`
+	Attributes     json.RawMessage `json:"attributes"`
+	AvatarKey      sql.NullString  `json:"avatarKey"`
+	PhoneCountry   sql.NullString  `json:"phoneCountry"`
+	EmailCanonical sql.NullString  `json:"emailCanonical"`
+	Country        sql.NullString  `json:"country"`
+	State          int16           `json:"state"`
+}
+
+type UserAddress struct {
+	ID          uuid.UUID    `json:"id"`
+	UserID      uuid.UUID    `json:"userId"`
+	Kind        int16        `json:"kind"`
+	IsDefault   bool         `json:"isDefault"`
+	Recipient   string       `json:"recipient"`
+	Line1       string       `json:"line1"`
+	Line2       string       `json:"line2"`
+	City        string       `json:"city"`
+	Region      string       `json:"region"`
+	PostalCode  string       `json:"postalCode"`
+	CountryCode string       `json:"countryCode"`
+	UpdatedAt   sql.NullTime `json:"updatedAt"`
+	CreatedAt   time.Time    `json:"createdAt"`
+}
+
+type UserConsent struct {
+	UserID        uuid.UUID    `json:"userId"`
+	PolicyKind    string       `json:"policyKind"`
+	PolicyVersion int32        `json:"policyVersion"`
+	AcceptedAt    time.Time    `json:"acceptedAt"`
+	WithdrawnAt   sql.NullTime `json:"withdrawnAt"`
+}
+
+type UserEmail struct {
 	Email          string         `json:"email"`
-	PhoneNumber    sql.NullString `json:"phoneNumber"`
-	HashedPassword string         `json:"hashedPassword"`
-	FirstName      string         `json:"firstName"`
-	LastName       string         `json:"lastName"`
-	Gender         int16          `json:"gender"`
-	BirthDay       sql.NullTime   `json:"birthDay"`
-	UpdatedAt      sql.NullTime   `json:"updatedAt"`
+	UserID         uuid.UUID      `json:"userId"`
+	Verified       bool           `json:"verified"`
+	IsPrimary      bool           `json:"isPrimary"`
 	CreatedAt      time.Time      `json:"createdAt"`
+	EmailCanonical sql.NullString `json:"emailCanonical"`
+}
+
+type UserPreference struct {
+	UserID    uuid.UUID       `json:"userId"`
+	Namespace string          `json:"namespace"`
+	Key       string          `json:"key"`
+	ValueType int16           `json:"valueType"`
+	Value     json.RawMessage `json:"value"`
+	UpdatedAt time.Time       `json:"updatedAt"`
 }
@@ -0,0 +1,134 @@
+// Code generated by sqlc. DO NOT EDIT.
+// source: preference.sql
+
+package repo
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const deletePreference = `-- name: DeletePreference :execrows
+delete
+from user_preferences
+where user_id = $1
+  and namespace = $2
+  and key = $3
+`
+
+type DeletePreferenceParams struct {
+	UserID    uuid.UUID `json:"userId"`
+	Namespace string    `json:"namespace"`
+	Key       string    `json:"key"`
+}
+
+func (q *Queries) DeletePreference(ctx context.Context, arg DeletePreferenceParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deletePreference, arg.UserID, arg.Namespace, arg.Key)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const listPreferences = `-- name: ListPreferences :many
+select user_id, namespace, key, value_type, value, updated_at
+from user_preferences
+where user_id = $1
+  and (coalesce($2::varchar(64), '') = '' or namespace = $2)
+order by namespace, key
+`
+
+type ListPreferencesParams struct {
+	UserID    uuid.UUID `json:"userId"`
+	Namespace string    `json:"namespace"`
+}
+
+func (q *Queries) ListPreferences(ctx context.Context, arg ListPreferencesParams) ([]UserPreference, error) {
+	rows, err := q.db.QueryContext(ctx, listPreferences, arg.UserID, arg.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []UserPreference{}
+	for rows.Next() {
+		var i UserPreference
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Namespace,
+			&i.Key,
+			&i.ValueType,
+			&i.Value,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setPreferences = `-- name: SetPreferences :many
+insert into user_preferences (user_id, namespace, key, value_type, value)
+select $1,
+       unnest($2::varchar[]),
+       unnest($3::varchar[]),
+       unnest($4::smallint[]),
+       unnest($5::text[])::jsonb
+on conflict (user_id, namespace, key) do update
+    set value_type = excluded.value_type,
+        value      = excluded.value,
+        updated_at = now()
+returning user_id, namespace, key, value_type, value, updated_at
+`
+
+type SetPreferencesParams struct {
+	UserID           uuid.UUID `json:"userId"`
+	Namespaces       []string  `json:"namespaces"`
+	Keys             []string  `json:"keys"`
+	ValueTypes       []int16   `json:"valueTypes"`
+	PreferenceValues []string  `json:"preferenceValues"`
+}
+
+func (q *Queries) SetPreferences(ctx context.Context, arg SetPreferencesParams) ([]UserPreference, error) {
+	rows, err := q.db.QueryContext(ctx, setPreferences,
+		arg.UserID,
+		pq.Array(arg.Namespaces),
+		pq.Array(arg.Keys),
+		pq.Array(arg.ValueTypes),
+		pq.Array(arg.PreferenceValues),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []UserPreference{}
+	for rows.Next() {
+		var i UserPreference
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Namespace,
+			&i.Key,
+			&i.ValueType,
+			&i.Value,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
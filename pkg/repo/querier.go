@@ -4,15 +4,64 @@ package repo
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/google/uuid"
 )
 
 type Querier interface {
+	AddEmail(ctx context.Context, arg AddEmailParams) (UserEmail, error)
+	CompleteDataExport(ctx context.Context, arg CompleteDataExportParams) (DataExport, error)
+	// the created_at of the reservation tells it apart from a later takeover
+	CompleteIdempotencyKey(ctx context.Context, arg CompleteIdempotencyKeyParams) (int64, error)
+	ConfirmGuardianConsent(ctx context.Context, arg ConfirmGuardianConsentParams) (ConfirmGuardianConsentRow, error)
+	CountUncanonicalEmails(ctx context.Context) (int64, error)
+	CountUsers(ctx context.Context) ([]CountUsersRow, error)
+	CreateAddress(ctx context.Context, arg CreateAddressParams) (UserAddress, error)
+	CreateConsentPolicy(ctx context.Context, arg CreateConsentPolicyParams) (ConsentPolicy, error)
+	CreateDataExport(ctx context.Context, arg CreateDataExportParams) (DataExport, error)
+	CreateGuardianConsent(ctx context.Context, arg CreateGuardianConsentParams) (GuardianConsent, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	DeleteAddress(ctx context.Context, arg DeleteAddressParams) (int64, error)
+	DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error)
+	// withdraws a request the guardian was not notified about
+	DeleteGuardianConsent(ctx context.Context, id uuid.UUID) error
+	DeletePreference(ctx context.Context, arg DeletePreferenceParams) (int64, error)
 	DeleteUser(ctx context.Context, id uuid.UUID) (int64, error)
+	EraseUser(ctx context.Context, arg EraseUserParams) (ErasureReceipt, error)
+	GetConsentPolicy(ctx context.Context, arg GetConsentPolicyParams) (ConsentPolicy, error)
+	GetDataExport(ctx context.Context, id uuid.UUID) (DataExport, error)
+	GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error)
 	GetUser(ctx context.Context, id uuid.UUID) (User, error)
+	GetUserByEmail(ctx context.Context, emailCanonical string) (User, error)
+	HasGuardianConsent(ctx context.Context, minorID uuid.UUID) (bool, error)
+	ListAddresses(ctx context.Context, arg ListAddressesParams) ([]UserAddress, error)
+	ListConsents(ctx context.Context, userID uuid.UUID) ([]UserConsent, error)
+	ListDataExportBlobKeys(ctx context.Context, userID uuid.UUID) ([]string, error)
+	ListEmailCanonicals(ctx context.Context, arg ListEmailCanonicalsParams) ([]ListEmailCanonicalsRow, error)
+	ListEmails(ctx context.Context, userID uuid.UUID) ([]UserEmail, error)
+	ListErasureReceipts(ctx context.Context, userID uuid.UUID) ([]ErasureReceipt, error)
+	ListGuardianConsents(ctx context.Context, userID uuid.UUID) ([]GuardianConsent, error)
+	ListPreferences(ctx context.Context, arg ListPreferencesParams) ([]UserPreference, error)
+	ListUnnormalizedPhones(ctx context.Context, arg ListUnnormalizedPhonesParams) ([]ListUnnormalizedPhonesRow, error)
+	ListUnpublishedErasureReceipts(ctx context.Context, arg ListUnpublishedErasureReceiptsParams) ([]ErasureReceipt, error)
+	ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error)
+	ListUsersRequiringConsent(ctx context.Context, arg ListUsersRequiringConsentParams) ([]uuid.UUID, error)
+	MarkErasureReceiptPublished(ctx context.Context, id uuid.UUID) error
+	RecordConsent(ctx context.Context, arg RecordConsentParams) (UserConsent, error)
+	ReleaseIdempotencyKey(ctx context.Context, arg ReleaseIdempotencyKeyParams) (int64, error)
+	RemoveEmail(ctx context.Context, arg RemoveEmailParams) (int64, error)
+	// reserves an unused key, a key which expired or whose call was abandoned
+	// before @stale_before is taken over
+	ReserveIdempotencyKey(ctx context.Context, arg ReserveIdempotencyKeyParams) (IdempotencyKey, error)
+	SetEmailCanonical(ctx context.Context, arg SetEmailCanonicalParams) error
+	SetPreferences(ctx context.Context, arg SetPreferencesParams) ([]UserPreference, error)
+	SetPrimaryEmail(ctx context.Context, arg SetPrimaryEmailParams) (User, error)
+	SetUserAvatar(ctx context.Context, arg SetUserAvatarParams) (sql.NullString, error)
+	SetUserPhone(ctx context.Context, arg SetUserPhoneParams) error
+	UpdateAddress(ctx context.Context, arg UpdateAddressParams) (UserAddress, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	WithdrawConsent(ctx context.Context, arg WithdrawConsentParams) (int64, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -6,38 +6,114 @@ package repo
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+const countUncanonicalEmails = `-- name: CountUncanonicalEmails :one
+select count(*)
+from user_emails
+where email_canonical is null
+`
+
+func (q *Queries) CountUncanonicalEmails(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countUncanonicalEmails)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countUsers = `-- name: CountUsers :many
+select state, (erased_at is not null)::boolean as erased, count(*) as count
+from users
+group by state, erased
+order by state, erased
+`
+
+type CountUsersRow struct {
+	State  int16 `json:"state"`
+	Erased bool  `json:"erased"`
+	Count  int64 `json:"count"`
+}
+
+func (q *Queries) CountUsers(ctx context.Context) ([]CountUsersRow, error) {
+	rows, err := q.db.QueryContext(ctx, countUsers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []CountUsersRow{}
+	for rows.Next() {
+		var i CountUsersRow
+		if err := rows.Scan(&i.State, &i.Erased, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const createUser = `-- name: CreateUser :one
-insert into users (id, email, phone_number, hashed_password, first_name, last_name, gender, birth_day)
-values ($1, $2, $3, $4, $5, $6, $7, $8)
-returning id, email, phone_number, hashed_password, first_name, last_name, gender, birth_day, updated_at, created_at
+with consents as
+         (
+             -- the foreign keys are checked once the user is inserted
+             insert into user_consents (user_id, policy_kind, policy_version)
+                 select $1::uuid,
+                        unnest($14::varchar[]),
+                        unnest($15::integer[])
+         )
+insert
+into users (id, email, email_canonical, phone_number, phone_country, hashed_password, first_name, last_name,
+                   gender, birth_day, attributes, country, state)
+values ($1, $2, $3::varchar(254), $4, $5, $6, $7,
+        $8, $9, $10, $11, $12, $13)
+returning id, email, phone_number, hashed_password, first_name, last_name, gender, birth_day, updated_at, created_at, erased_at, attributes, avatar_key, phone_country, email_canonical, country, state
 `
 
 type CreateUserParams struct {
-	ID             uuid.UUID      `json:"id"`
-	Email          string         `json:"email"`
-	PhoneNumber    sql.NullString `json:"phoneNumber"`
-	HashedPassword string         `json:"hashedPassword"`
-	FirstName      string         `json:"firstName"`
-	LastName       string         `json:"lastName"`
-	Gender         int16          `json:"gender"`
-	BirthDay       sql.NullTime   `json:"birthDay"`
+	ID             uuid.UUID       `json:"id"`
+	Email          string          `json:"email"`
+	EmailCanonical string          `json:"emailCanonical"`
+	PhoneNumber    sql.NullString  `json:"phoneNumber"`
+	PhoneCountry   sql.NullString  `json:"phoneCountry"`
+	HashedPassword string          `json:"hashedPassword"`
+	FirstName      string          `json:"firstName"`
+	LastName       string          `json:"lastName"`
+	Gender         int16           `json:"gender"`
+	BirthDay       sql.NullTime    `json:"birthDay"`
+	Attributes     json.RawMessage `json:"attributes"`
+	Country        sql.NullString  `json:"country"`
+	State          int16           `json:"state"`
+	PolicyKinds    []string        `json:"policyKinds"`
+	PolicyVersions []int32         `json:"policyVersions"`
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
 	row := q.db.QueryRowContext(ctx, createUser,
 		arg.ID,
 		arg.Email,
+		arg.EmailCanonical,
 		arg.PhoneNumber,
+		arg.PhoneCountry,
 		arg.HashedPassword,
 		arg.FirstName,
 		arg.LastName,
 		arg.Gender,
 		arg.BirthDay,
+		arg.Attributes,
+		arg.Country,
+		arg.State,
+		pq.Array(arg.PolicyKinds),
+		pq.Array(arg.PolicyVersions),
 	)
 	var i User
 	err := row.Scan(
@@ -51,6 +127,13 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.BirthDay,
 		&i.UpdatedAt,
 		&i.CreatedAt,
+		&i.ErasedAt,
+		&i.Attributes,
+		&i.AvatarKey,
+		&i.PhoneCountry,
+		&i.EmailCanonical,
+		&i.Country,
+		&i.State,
 	)
 	return i, err
 }
@@ -60,7 +143,7 @@ with deleted as
          (
              delete from users
                  where id = $1
-                 returning id, email, phone_number, hashed_password, first_name, last_name, gender, birth_day, updated_at, created_at
+                 returning id, email, phone_number, hashed_password, first_name, last_name, gender, birth_day, updated_at, created_at, erased_at, attributes, avatar_key, phone_country, email_canonical, country, state
          )
 select count(*)
 from deleted
@@ -73,8 +156,99 @@ func (q *Queries) DeleteUser(ctx context.Context, id uuid.UUID) (int64, error) {
 	return count, err
 }
 
+const eraseUser = `-- name: EraseUser :one
+with erased as
+         (
+             update users
+                 set email = 'erased-' || users.id || '@erased.invalid',
+                     email_canonical = 'erased-' || users.id || '@erased.invalid',
+                     phone_number = null,
+                     phone_country = null,
+                     hashed_password = '',
+                     first_name = '',
+                     last_name = '',
+                     birth_day = null,
+                     attributes = '{}',
+                     avatar_key = null,
+                     country = null,
+                     erased_at = now()
+                 where users.id = $3
+                     and users.erased_at is null
+                 returning users.id, users.erased_at
+         ),
+     addresses as
+         (
+             delete
+                 from user_addresses
+                     using erased
+                 where user_addresses.user_id = erased.id
+         ),
+     emails as
+         (
+             -- the primary address is replaced by the trigger on users
+             delete
+                 from user_emails
+                     using erased
+                 where user_emails.user_id = erased.id
+                     and not user_emails.is_primary
+         ),
+     exports as
+         (
+             delete
+                 from data_exports
+                     using erased
+                 where data_exports.user_id = erased.id
+         ),
+     guardians as
+         (
+             delete
+                 from guardian_consents
+                     using erased
+                 where guardian_consents.minor_id = erased.id
+                     or guardian_consents.guardian_id = erased.id
+         ),
+     preferences as
+         (
+             delete
+                 from user_preferences
+                     using erased
+                 where user_preferences.user_id = erased.id
+         ),
+     consents as
+         (
+             delete
+                 from user_consents
+                     using erased
+                 where user_consents.user_id = erased.id
+         )
+insert
+into erasure_receipts (id, user_id, reason, erased_at)
+select $1::uuid, erased.id, $2::varchar, erased.erased_at
+from erased
+returning id, user_id, reason, erased_at, published_at
+`
+
+type EraseUserParams struct {
+	ID     uuid.UUID `json:"id"`
+	Reason string    `json:"reason"`
+	UserID uuid.UUID `json:"userId"`
+}
+
+func (q *Queries) EraseUser(ctx context.Context, arg EraseUserParams) (ErasureReceipt, error) {
+	row := q.db.QueryRowContext(ctx, eraseUser, arg.ID, arg.Reason, arg.UserID)
+	var i ErasureReceipt
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Reason,
+		&i.ErasedAt,
+		&i.PublishedAt,
+	)
+	return i, err
+}
+
 const getUser = `-- name: GetUser :one
-select id, email, phone_number, hashed_password, first_name, last_name, gender, birth_day, updated_at, created_at
+select id, email, phone_number, hashed_password, first_name, last_name, gender, birth_day, updated_at, created_at, erased_at, attributes, avatar_key, phone_country, email_canonical, country, state
 from users
 where id = $1
 limit 1
@@ -94,45 +268,353 @@ func (q *Queries) GetUser(ctx context.Context, id uuid.UUID) (User, error) {
 		&i.BirthDay,
 		&i.UpdatedAt,
 		&i.CreatedAt,
+		&i.ErasedAt,
+		&i.Attributes,
+		&i.AvatarKey,
+		&i.PhoneCountry,
+		&i.EmailCanonical,
+		&i.Country,
+		&i.State,
 	)
 	return i, err
 }
 
+const listEmailCanonicals = `-- name: ListEmailCanonicals :many
+select email, user_id, email_canonical
+from user_emails
+where email > $1
+order by email
+limit $2
+`
+
+type ListEmailCanonicalsParams struct {
+	AfterEmail string `json:"afterEmail"`
+	RowLimit   int32  `json:"rowLimit"`
+}
+
+type ListEmailCanonicalsRow struct {
+	Email          string         `json:"email"`
+	UserID         uuid.UUID      `json:"userId"`
+	EmailCanonical sql.NullString `json:"emailCanonical"`
+}
+
+func (q *Queries) ListEmailCanonicals(ctx context.Context, arg ListEmailCanonicalsParams) ([]ListEmailCanonicalsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listEmailCanonicals, arg.AfterEmail, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListEmailCanonicalsRow{}
+	for rows.Next() {
+		var i ListEmailCanonicalsRow
+		if err := rows.Scan(&i.Email, &i.UserID, &i.EmailCanonical); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnnormalizedPhones = `-- name: ListUnnormalizedPhones :many
+select id, phone_number
+from users
+where phone_number is not null
+  and phone_country is null
+  and id > $1
+order by id
+limit $2
+`
+
+type ListUnnormalizedPhonesParams struct {
+	AfterID  uuid.UUID `json:"afterId"`
+	RowLimit int32     `json:"rowLimit"`
+}
+
+type ListUnnormalizedPhonesRow struct {
+	ID          uuid.UUID      `json:"id"`
+	PhoneNumber sql.NullString `json:"phoneNumber"`
+}
+
+func (q *Queries) ListUnnormalizedPhones(ctx context.Context, arg ListUnnormalizedPhonesParams) ([]ListUnnormalizedPhonesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listUnnormalizedPhones, arg.AfterID, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListUnnormalizedPhonesRow{}
+	for rows.Next() {
+		var i ListUnnormalizedPhonesRow
+		if err := rows.Scan(&i.ID, &i.PhoneNumber); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnpublishedErasureReceipts = `-- name: ListUnpublishedErasureReceipts :many
+select id, user_id, reason, erased_at, published_at
+from erasure_receipts
+where published_at is null
+  and erased_at < $1
+order by erased_at
+limit $2
+`
+
+type ListUnpublishedErasureReceiptsParams struct {
+	ErasedBefore time.Time `json:"erasedBefore"`
+	RowLimit     int32     `json:"rowLimit"`
+}
+
+func (q *Queries) ListUnpublishedErasureReceipts(ctx context.Context, arg ListUnpublishedErasureReceiptsParams) ([]ErasureReceipt, error) {
+	rows, err := q.db.QueryContext(ctx, listUnpublishedErasureReceipts, arg.ErasedBefore, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ErasureReceipt{}
+	for rows.Next() {
+		var i ErasureReceipt
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Reason,
+			&i.ErasedAt,
+			&i.PublishedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsers = `-- name: ListUsers :many
+select id, email, phone_number, hashed_password, first_name, last_name, gender, birth_day, updated_at, created_at, erased_at, attributes, avatar_key, phone_country, email_canonical, country, state
+from users
+where id > $1
+  and ($2::timestamptz is null or created_at >= $2)
+  and ($3::timestamptz is null or created_at < $3)
+  and (coalesce($4::smallint, 0) = 0 or gender = $4)
+  and attributes @> $5::jsonb
+  and ($6::boolean or erased_at is null)
+order by id
+limit $7
+`
+
+type ListUsersParams struct {
+	AfterID          uuid.UUID       `json:"afterId"`
+	CreatedAfter     sql.NullTime    `json:"createdAfter"`
+	CreatedBefore    sql.NullTime    `json:"createdBefore"`
+	Gender           int16           `json:"gender"`
+	AttributesFilter json.RawMessage `json:"attributesFilter"`
+	IncludeErased    bool            `json:"includeErased"`
+	RowLimit         int32           `json:"rowLimit"`
+}
+
+func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listUsers,
+		arg.AfterID,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.Gender,
+		arg.AttributesFilter,
+		arg.IncludeErased,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []User{}
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.PhoneNumber,
+			&i.HashedPassword,
+			&i.FirstName,
+			&i.LastName,
+			&i.Gender,
+			&i.BirthDay,
+			&i.UpdatedAt,
+			&i.CreatedAt,
+			&i.ErasedAt,
+			&i.Attributes,
+			&i.AvatarKey,
+			&i.PhoneCountry,
+			&i.EmailCanonical,
+			&i.Country,
+			&i.State,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markErasureReceiptPublished = `-- name: MarkErasureReceiptPublished :exec
+update erasure_receipts
+set published_at = now()
+where id = $1
+`
+
+func (q *Queries) MarkErasureReceiptPublished(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, markErasureReceiptPublished, id)
+	return err
+}
+
+const setEmailCanonical = `-- name: SetEmailCanonical :exec
+with updated as
+         (
+             update user_emails
+                 set email_canonical = $1::varchar(254)
+                 where user_emails.email = $2
+                 returning user_id, is_primary
+         )
+update users
+set email_canonical = $1::varchar(254)
+from updated
+where users.id = updated.user_id
+  and updated.is_primary
+`
+
+type SetEmailCanonicalParams struct {
+	EmailCanonical string `json:"emailCanonical"`
+	Email          string `json:"email"`
+}
+
+func (q *Queries) SetEmailCanonical(ctx context.Context, arg SetEmailCanonicalParams) error {
+	_, err := q.db.ExecContext(ctx, setEmailCanonical, arg.EmailCanonical, arg.Email)
+	return err
+}
+
+const setUserAvatar = `-- name: SetUserAvatar :one
+with previous as
+         (
+             select users.id, users.avatar_key
+             from users
+             where users.id = $2
+               and users.erased_at is null
+                 for update
+         )
+update users
+set avatar_key = $1,
+    updated_at = now()
+from previous
+where users.id = previous.id
+returning previous.avatar_key
+`
+
+type SetUserAvatarParams struct {
+	AvatarKey sql.NullString `json:"avatarKey"`
+	ID        uuid.UUID      `json:"id"`
+}
+
+func (q *Queries) SetUserAvatar(ctx context.Context, arg SetUserAvatarParams) (sql.NullString, error) {
+	row := q.db.QueryRowContext(ctx, setUserAvatar, arg.AvatarKey, arg.ID)
+	var avatar_key sql.NullString
+	err := row.Scan(&avatar_key)
+	return avatar_key, err
+}
+
+const setUserPhone = `-- name: SetUserPhone :exec
+update users
+set phone_number  = $1,
+    phone_country = $2
+where id = $3
+`
+
+type SetUserPhoneParams struct {
+	PhoneNumber  sql.NullString `json:"phoneNumber"`
+	PhoneCountry sql.NullString `json:"phoneCountry"`
+	ID           uuid.UUID      `json:"id"`
+}
+
+func (q *Queries) SetUserPhone(ctx context.Context, arg SetUserPhoneParams) error {
+	_, err := q.db.ExecContext(ctx, setUserPhone, arg.PhoneNumber, arg.PhoneCountry, arg.ID)
+	return err
+}
+
 const updateUser = `-- name: UpdateUser :one
 update users
-set email           = case when coalesce($1::varchar(64), '') = '' then email else $1 end,
-    phone_number    = case when coalesce($2::varchar(32), '') = '' then phone_number else $2 end,
+set email           = case when coalesce($1::varchar(254), '') = '' then email else $1 end,
+    email_canonical = case
+                          when coalesce($1::varchar(254), '') = '' then email_canonical
+                          else $2::varchar(254) end,
+    phone_number    = case when coalesce($3::varchar(32), '') = '' then phone_number else $3 end,
+    phone_country   = case when coalesce($3::varchar(32), '') = '' then phone_country else $4 end,
     hashed_password = case
-                          when coalesce($3::varchar, '') = '' then hashed_password
-                          else $3 end,
-    first_name      = case when coalesce($4::varchar(64), '') = '' then first_name else $4 end,
-    last_name       = case when coalesce($5::varchar(64), '') = '' then last_name else $5 end,
-    gender          = case when coalesce($6::smallint, 0) = 0 then gender else $6 end,
-    birth_day       = case when $7::date = '0001-01-01' then birth_day else $7 end
-where id = $8
-returning id, email, phone_number, hashed_password, first_name, last_name, gender, birth_day, updated_at, created_at
+                          when coalesce($5::varchar, '') = '' then hashed_password
+                          else $5 end,
+    first_name      = case when coalesce($6::varchar(64), '') = '' then first_name else $6 end,
+    last_name       = case when coalesce($7::varchar(64), '') = '' then last_name else $7 end,
+    gender          = case when coalesce($8::smallint, 0) = 0 then gender else $8 end,
+    birth_day       = case when $9::date = '0001-01-01' then birth_day else $9 end,
+    attributes      = case when $10::text = '' then attributes else ($10::text)::jsonb end,
+    country         = case when coalesce($11::varchar(2), '') = '' then country else $11 end,
+    state           = coalesce($12::smallint, state)
+where id = $13
+  and erased_at is null
+returning id, email, phone_number, hashed_password, first_name, last_name, gender, birth_day, updated_at, created_at, erased_at, attributes, avatar_key, phone_country, email_canonical, country, state
 `
 
 type UpdateUserParams struct {
-	Email          string    `json:"email"`
-	PhoneNumber    string    `json:"phoneNumber"`
-	HashedPassword string    `json:"hashedPassword"`
-	FirstName      string    `json:"firstName"`
-	LastName       string    `json:"lastName"`
-	Gender         int16     `json:"gender"`
-	BirthDay       time.Time `json:"birthDay"`
-	ID             uuid.UUID `json:"id"`
+	Email          string         `json:"email"`
+	EmailCanonical string         `json:"emailCanonical"`
+	PhoneNumber    string         `json:"phoneNumber"`
+	PhoneCountry   sql.NullString `json:"phoneCountry"`
+	HashedPassword string         `json:"hashedPassword"`
+	FirstName      string         `json:"firstName"`
+	LastName       string         `json:"lastName"`
+	Gender         int16          `json:"gender"`
+	BirthDay       time.Time      `json:"birthDay"`
+	Attributes     string         `json:"attributes"`
+	Country        string         `json:"country"`
+	State          sql.NullInt16  `json:"state"`
+	ID             uuid.UUID      `json:"id"`
 }
 
 func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
 	row := q.db.QueryRowContext(ctx, updateUser,
 		arg.Email,
+		arg.EmailCanonical,
 		arg.PhoneNumber,
+		arg.PhoneCountry,
 		arg.HashedPassword,
 		arg.FirstName,
 		arg.LastName,
 		arg.Gender,
 		arg.BirthDay,
+		arg.Attributes,
+		arg.Country,
+		arg.State,
 		arg.ID,
 	)
 	var i User
@@ -147,6 +629,13 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.BirthDay,
 		&i.UpdatedAt,
 		&i.CreatedAt,
+		&i.ErasedAt,
+		&i.Attributes,
+		&i.AvatarKey,
+		&i.PhoneCountry,
+		&i.EmailCanonical,
+		&i.Country,
+		&i.State,
 	)
 	return i, err
 }
@@ -0,0 +1,90 @@
+package repo_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chutommy/user-microservice/pkg/preference"
+	"github.com/chutommy/user-microservice/pkg/repo"
+)
+
+func TestQueries_EraseUser(t *testing.T) {
+	t.Parallel()
+
+	db := openDB(t)
+	ctx := context.Background()
+	q := repo.New(db)
+	uid := createUser(t, q)
+
+	_, err := q.SetPreferences(ctx, repo.SetPreferencesParams{
+		UserID:           uid,
+		Namespaces:       []string{"notifications"},
+		Keys:             []string{"email_digest"},
+		ValueTypes:       []int16{int16(preference.TypeBool)},
+		PreferenceValues: []string{"true"},
+	})
+	require.NoError(t, err)
+
+	kind := "test_" + uuid.New().String()[:8]
+	_, err = q.CreateConsentPolicy(ctx, repo.CreateConsentPolicyParams{Kind: kind, Version: 1, Required: true})
+	require.NoError(t, err)
+	_, err = q.RecordConsent(ctx, repo.RecordConsentParams{UserID: uid, PolicyKind: kind, PolicyVersion: 1})
+	require.NoError(t, err)
+
+	_, err = q.EraseUser(ctx, repo.EraseUserParams{ID: uuid.New(), UserID: uid})
+	require.NoError(t, err)
+
+	preferences, err := q.ListPreferences(ctx, repo.ListPreferencesParams{UserID: uid})
+	require.NoError(t, err)
+	require.Empty(t, preferences)
+
+	consents, err := q.ListConsents(ctx, uid)
+	require.NoError(t, err)
+	require.Empty(t, consents)
+}
+
+func TestQueries_ListUsers_Erased(t *testing.T) {
+	t.Parallel()
+
+	db := openDB(t)
+	ctx := context.Background()
+	q := repo.New(db)
+	uid := createUser(t, q)
+
+	_, err := q.EraseUser(ctx, repo.EraseUserParams{ID: uuid.New(), UserID: uid})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		includeErased bool
+		listed        bool
+	}{
+		{name: "excluded", includeErased: false, listed: false},
+		{name: "included", includeErased: true, listed: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			users, err := q.ListUsers(ctx, repo.ListUsersParams{
+				AfterID:          preceding(uid),
+				AttributesFilter: json.RawMessage("{}"),
+				RowLimit:         1,
+				IncludeErased:    tt.includeErased,
+			})
+			require.NoError(t, err)
+
+			listed := len(users) > 0 && users[0].ID == uid
+			require.Equal(t, tt.listed, listed)
+			if listed {
+				require.True(t, users[0].ErasedAt.Valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/chutommy/user-microservice/pkg/address"
+	"github.com/chutommy/user-microservice/pkg/apierror"
+	"github.com/chutommy/user-microservice/pkg/grpc/userpb"
+	"github.com/chutommy/user-microservice/pkg/repo"
+)
+
+// addressFromPB validates and normalizes the address of a request.
+func addressFromPB(ctx context.Context, a *userpb.Address) (address.Kind, address.Address, error) {
+	logger := ctxzap.Extract(ctx)
+
+	if a == nil {
+		logger.Info("empty address")
+		return 0, address.Address{}, apierror.InvalidField("address", "%v: 'address' field", ErrEmptyField)
+	}
+
+	kind := address.Kind(a.GetKind())
+	if !kind.Valid() {
+		logger.Info("invalid address kind", zap.Int32("kind", int32(a.GetKind())))
+		return 0, address.Address{}, apierror.InvalidField("address.kind", "invalid address kind: %v", a.GetKind())
+	}
+
+	addr, err := address.Normalize(address.Address{
+		Recipient:   a.GetRecipient(),
+		Line1:       a.GetLine1(),
+		Line2:       a.GetLine2(),
+		City:        a.GetCity(),
+		Region:      a.GetRegion(),
+		PostalCode:  a.GetPostalCode(),
+		CountryCode: a.GetCountryCode(),
+	})
+	if err != nil {
+		logger.Info("invalid address", zap.Error(err))
+
+		field := "address"
+		var fieldErr *address.FieldError
+		if errors.As(err, &fieldErr) {
+			field += "." + fieldErr.Field
+		}
+		return 0, address.Address{}, apierror.InvalidField(field, "%v", err)
+	}
+
+	return kind, addr, nil
+}
+
+func addressToPB(a repo.UserAddress) *userpb.Address {
+	pb := &userpb.Address{
+		Id:          a.ID.String(),
+		UserId:      a.UserID.String(),
+		Kind:        userpb.Address_Kind(a.Kind),
+		Default:     a.IsDefault,
+		Recipient:   a.Recipient,
+		Line1:       a.Line1,
+		Line2:       a.Line2,
+		City:        a.City,
+		Region:      a.Region,
+		PostalCode:  a.PostalCode,
+		CountryCode: a.CountryCode,
+		CreatedAt:   timestamppb.New(a.CreatedAt),
+	}
+	if a.UpdatedAt.Valid {
+		pb.UpdatedAt = timestamppb.New(a.UpdatedAt.Time)
+	}
+
+	return pb
+}
+
+func (u *UserServer) CreateAddress(ctx context.Context, req *userpb.CreateAddressRequest) (*userpb.CreateAddressResponse, error) {
+	logger := ctxzap.Extract(ctx)
+
+	// parse ID
+	uid, err := parseID(ctx, "user_id", req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	kind, addr, err := addressFromPB(ctx, req.GetAddress())
+	if err != nil {
+		return nil, err
+	}
+
+	// store address
+	created, err := u.repo.CreateAddress(ctx, repo.CreateAddressParams{
+		ID:          uuid.New(),
+		UserID:      uid,
+		Kind:        int16(kind),
+		IsDefault:   req.GetAddress().GetDefault(),
+		Recipient:   addr.Recipient,
+		Line1:       addr.Line1,
+		Line2:       addr.Line2,
+		City:        addr.City,
+		Region:      addr.Region,
+		PostalCode:  addr.PostalCode,
+		CountryCode: addr.CountryCode,
+	})
+	if err != nil {
+		code := codes.Internal
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			code = codes.NotFound
+		}
+
+		logger.Error("failed to create address", zap.Error(err))
+		return nil, status.Errorf(code, "failed to create address of user with id: %s", uid)
+	}
+
+	// construct response
+	resp := &userpb.CreateAddressResponse{
+		Address: addressToPB(created),
+	}
+
+	return resp, nil
+}
+
+func (u *UserServer) ListAddresses(ctx context.Context, req *userpb.ListAddressesRequest) (*userpb.ListAddressesResponse, error) {
+	logger := ctxzap.Extract(ctx)
+
+	// parse ID
+	uid, err := parseID(ctx, "user_id", req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	kind := address.Kind(req.GetKind())
+	if kind != 0 && !kind.Valid() {
+		logger.Info("invalid address kind", zap.Int32("kind", int32(req.GetKind())))
+		return nil, apierror.InvalidField("kind", "invalid address kind: %v", req.GetKind())
+	}
+
+	// retrieve addresses
+	addrs, err := u.repo.ListAddresses(ctx, repo.ListAddressesParams{
+		UserID: uid,
+		Kind:   int16(kind),
+	})
+	if err != nil {
+		logger.Error("list addresses", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "failed to list addresses of user with id: %s", uid)
+	}
+
+	// construct response
+	resp := &userpb.ListAddressesResponse{
+		Addresses: make([]*userpb.Address, 0, len(addrs)),
+	}
+	for _, a := range addrs {
+		resp.Addresses = append(resp.Addresses, addressToPB(a))
+	}
+
+	return resp, nil
+}
+
+func (u *UserServer) UpdateAddress(ctx context.Context, req *userpb.UpdateAddressRequest) (*userpb.UpdateAddressResponse, error) {
+	logger := ctxzap.Extract(ctx)
+
+	// parse IDs
+	uid, err := parseID(ctx, "user_id", req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	aid, err := parseID(ctx, "id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	kind, addr, err := addressFromPB(ctx, req.GetAddress())
+	if err != nil {
+		return nil, err
+	}
+
+	// update address
+	updated, err := u.repo.UpdateAddress(ctx, repo.UpdateAddressParams{
+		ID:          aid,
+		UserID:      uid,
+		Kind:        int16(kind),
+		IsDefault:   req.GetAddress().GetDefault(),
+		Recipient:   addr.Recipient,
+		Line1:       addr.Line1,
+		Line2:       addr.Line2,
+		City:        addr.City,
+		Region:      addr.Region,
+		PostalCode:  addr.PostalCode,
+		CountryCode: addr.CountryCode,
+	})
+	if err != nil {
+		code := codes.Internal
+
+		if errors.Is(err, sql.ErrNoRows) {
+			code = codes.NotFound
+		}
+
+		logger.Info("failed to update address", zap.Error(err))
+		return nil, status.Errorf(code, "failed to update address with id: %s", aid)
+	}
+
+	// construct response
+	resp := &userpb.UpdateAddressResponse{
+		Address: addressToPB(updated),
+	}
+
+	return resp, nil
+}
+
+func (u *UserServer) DeleteAddress(ctx context.Context, req *userpb.DeleteAddressRequest) (*userpb.DeleteAddressResponse, error) {
+	logger := ctxzap.Extract(ctx)
+
+	// parse IDs
+	uid, err := parseID(ctx, "user_id", req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	aid, err := parseID(ctx, "id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	// delete address
+	affected, err := u.repo.DeleteAddress(ctx, repo.DeleteAddressParams{
+		ID:     aid,
+		UserID: uid,
+	})
+	if err != nil || affected == 0 {
+		code := codes.Internal
+
+		if affected == 0 && err == nil {
+			code = codes.NotFound
+		}
+
+		logger.Info("failed to delete address", zap.Error(err))
+		return nil, status.Errorf(code, "failed to delete address with id: %s", aid)
+	}
+
+	// construct response
+	resp := &userpb.DeleteAddressResponse{
+		Id: aid.String(),
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,354 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/chutommy/user-microservice/pkg/grpc/userpb"
+	"github.com/chutommy/user-microservice/pkg/mocks"
+	"github.com/chutommy/user-microservice/pkg/repo"
+	"github.com/chutommy/user-microservice/pkg/service"
+)
+
+func randomAddress() *userpb.Address {
+	return &userpb.Address{
+		Kind:        userpb.Address_SHIPPING,
+		Default:     true,
+		Recipient:   "Jane Doe",
+		Line1:       "1600 Amphitheatre Parkway",
+		City:        "Mountain View",
+		Region:      "CA",
+		PostalCode:  "94043",
+		CountryCode: "us",
+	}
+}
+
+func TestUserServer_CreateAddress(t *testing.T) {
+	t.Parallel()
+
+	uid := uuid.New()
+
+	tests := []struct {
+		name      string
+		buildRepo func(q *mocks.Querier)
+		inpID     string
+		addr      *userpb.Address
+		expCode   codes.Code
+		expField  string
+	}{
+		{
+			name: "ok",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("CreateAddress", mock.Anything, mock.MatchedBy(func(arg repo.CreateAddressParams) bool {
+					return arg.UserID == uid && arg.Kind == int16(userpb.Address_SHIPPING) &&
+						arg.IsDefault && arg.CountryCode == "US"
+				})).Return(repo.UserAddress{
+					ID:          uuid.New(),
+					UserID:      uid,
+					Kind:        int16(userpb.Address_SHIPPING),
+					IsDefault:   true,
+					CountryCode: "US",
+					CreatedAt:   time.Now(),
+				}, nil).Once()
+			},
+			inpID:   uid.String(),
+			addr:    randomAddress(),
+			expCode: codes.OK,
+		},
+		{
+			name:      "empty id",
+			buildRepo: func(q *mocks.Querier) {},
+			inpID:     "",
+			addr:      randomAddress(),
+			expCode:   codes.InvalidArgument,
+		},
+		{
+			name:      "empty address",
+			buildRepo: func(q *mocks.Querier) {},
+			inpID:     uid.String(),
+			addr:      nil,
+			expCode:   codes.InvalidArgument,
+		},
+		{
+			name:      "unknown kind",
+			buildRepo: func(q *mocks.Querier) {},
+			inpID:     uid.String(),
+			addr: func() *userpb.Address {
+				a := randomAddress()
+				a.Kind = userpb.Address_UNKNOWN
+				return a
+			}(),
+			expCode: codes.InvalidArgument,
+		},
+		{
+			name:      "invalid postal code",
+			buildRepo: func(q *mocks.Querier) {},
+			inpID:     uid.String(),
+			addr: func() *userpb.Address {
+				a := randomAddress()
+				a.PostalCode = "SW1A 1AA"
+				return a
+			}(),
+			expCode:  codes.InvalidArgument,
+			expField: "address.postal_code",
+		},
+		{
+			name: "user not found",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("CreateAddress", mock.Anything, mock.Anything).Return(repo.UserAddress{}, &pq.Error{Code: "23503"}).Once()
+			},
+			inpID:   uid.String(),
+			addr:    randomAddress(),
+			expCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// construct server
+			mockRepo := new(mocks.Querier)
+			tt.buildRepo(mockRepo)
+			server := service.NewUserServer(mockRepo)
+
+			// test method
+			resp, err := server.CreateAddress(context.Background(), &userpb.CreateAddressRequest{UserId: tt.inpID, Address: tt.addr})
+			if tt.expCode == codes.OK {
+				require.NoError(t, err)
+				require.Equal(t, uid.String(), resp.Address.UserId)
+				require.True(t, resp.Address.Default)
+			} else {
+				require.Error(t, err)
+				require.Nil(t, resp)
+
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, tt.expCode, st.Code())
+				if tt.expField != "" {
+					require.Len(t, st.Details(), 1)
+					br, ok := st.Details()[0].(*errdetails.BadRequest)
+					require.True(t, ok)
+					require.Equal(t, tt.expField, br.GetFieldViolations()[0].GetField())
+				}
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserServer_ListAddresses(t *testing.T) {
+	t.Parallel()
+
+	uid := uuid.New()
+
+	tests := []struct {
+		name      string
+		buildRepo func(q *mocks.Querier)
+		kind      userpb.Address_Kind
+		expLen    int
+		expCode   codes.Code
+	}{
+		{
+			name: "ok",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("ListAddresses", mock.Anything, repo.ListAddressesParams{UserID: uid}).Return([]repo.UserAddress{
+					{ID: uuid.New(), UserID: uid, Kind: int16(userpb.Address_HOME)},
+					{ID: uuid.New(), UserID: uid, Kind: int16(userpb.Address_BILLING)},
+				}, nil).Once()
+			},
+			expLen:  2,
+			expCode: codes.OK,
+		},
+		{
+			name: "kind filter",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("ListAddresses", mock.Anything, repo.ListAddressesParams{UserID: uid, Kind: int16(userpb.Address_BILLING)}).
+					Return([]repo.UserAddress{}, nil).Once()
+			},
+			kind:    userpb.Address_BILLING,
+			expLen:  0,
+			expCode: codes.OK,
+		},
+		{
+			name:      "invalid kind",
+			buildRepo: func(q *mocks.Querier) {},
+			kind:      userpb.Address_Kind(42),
+			expCode:   codes.InvalidArgument,
+		},
+		{
+			name: "connection error",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("ListAddresses", mock.Anything, mock.Anything).Return(nil, sql.ErrConnDone).Once()
+			},
+			expCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// construct server
+			mockRepo := new(mocks.Querier)
+			tt.buildRepo(mockRepo)
+			server := service.NewUserServer(mockRepo)
+
+			// test method
+			resp, err := server.ListAddresses(context.Background(), &userpb.ListAddressesRequest{UserId: uid.String(), Kind: tt.kind})
+			if tt.expCode == codes.OK {
+				require.NoError(t, err)
+				require.Len(t, resp.Addresses, tt.expLen)
+			} else {
+				require.Error(t, err)
+				require.Nil(t, resp)
+
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, tt.expCode, st.Code())
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserServer_UpdateAddress(t *testing.T) {
+	t.Parallel()
+
+	uid := uuid.New()
+	aid := uuid.New()
+
+	tests := []struct {
+		name      string
+		buildRepo func(q *mocks.Querier)
+		inpID     string
+		expCode   codes.Code
+	}{
+		{
+			name: "ok",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("UpdateAddress", mock.Anything, mock.MatchedBy(func(arg repo.UpdateAddressParams) bool {
+					return arg.ID == aid && arg.UserID == uid
+				})).Return(repo.UserAddress{ID: aid, UserID: uid, UpdatedAt: sql.NullTime{Time: time.Now(), Valid: true}}, nil).Once()
+			},
+			inpID:   aid.String(),
+			expCode: codes.OK,
+		},
+		{
+			name:      "invalid id",
+			buildRepo: func(q *mocks.Querier) {},
+			inpID:     "invalid_uuid",
+			expCode:   codes.InvalidArgument,
+		},
+		{
+			name: "not found",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("UpdateAddress", mock.Anything, mock.Anything).Return(repo.UserAddress{}, sql.ErrNoRows).Once()
+			},
+			inpID:   aid.String(),
+			expCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// construct server
+			mockRepo := new(mocks.Querier)
+			tt.buildRepo(mockRepo)
+			server := service.NewUserServer(mockRepo)
+
+			// test method
+			resp, err := server.UpdateAddress(context.Background(), &userpb.UpdateAddressRequest{
+				UserId:  uid.String(),
+				Id:      tt.inpID,
+				Address: randomAddress(),
+			})
+			if tt.expCode == codes.OK {
+				require.NoError(t, err)
+				require.Equal(t, aid.String(), resp.Address.Id)
+				require.NotNil(t, resp.Address.UpdatedAt)
+			} else {
+				require.Error(t, err)
+				require.Nil(t, resp)
+
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, tt.expCode, st.Code())
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserServer_DeleteAddress(t *testing.T) {
+	t.Parallel()
+
+	uid := uuid.New()
+	aid := uuid.New()
+
+	tests := []struct {
+		name      string
+		buildRepo func(q *mocks.Querier)
+		expCode   codes.Code
+	}{
+		{
+			name: "ok",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("DeleteAddress", mock.Anything, repo.DeleteAddressParams{ID: aid, UserID: uid}).Return(int64(1), nil).Once()
+			},
+			expCode: codes.OK,
+		},
+		{
+			name: "not found",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("DeleteAddress", mock.Anything, mock.Anything).Return(int64(0), nil).Once()
+			},
+			expCode: codes.NotFound,
+		},
+		{
+			name: "connection error",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("DeleteAddress", mock.Anything, mock.Anything).Return(int64(0), sql.ErrConnDone).Once()
+			},
+			expCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// construct server
+			mockRepo := new(mocks.Querier)
+			tt.buildRepo(mockRepo)
+			server := service.NewUserServer(mockRepo)
+
+			// test method
+			resp, err := server.DeleteAddress(context.Background(), &userpb.DeleteAddressRequest{UserId: uid.String(), Id: aid.String()})
+			if tt.expCode == codes.OK {
+				require.NoError(t, err)
+				require.Equal(t, aid.String(), resp.Id)
+			} else {
+				require.Error(t, err)
+				require.Nil(t, resp)
+
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, tt.expCode, st.Code())
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/chutommy/user-microservice/pkg/attribute"
+	"github.com/chutommy/user-microservice/pkg/grpc/userpb"
+	"github.com/chutommy/user-microservice/pkg/mocks"
+	"github.com/chutommy/user-microservice/pkg/repo"
+	"github.com/chutommy/user-microservice/pkg/service"
+)
+
+const attributesSchema = `{
+	"type": "object",
+	"properties": {
+		"company": {"type": "string"},
+		"employee_number": {"type": "integer"}
+	},
+	"additionalProperties": false
+}`
+
+func TestUserServer_RegisterUser_Attributes(t *testing.T) {
+	t.Parallel()
+
+	validator, err := attribute.NewValidator(strings.NewReader(attributesSchema))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		buildRepo func(q *mocks.Querier)
+		attrs     map[string]interface{}
+		expCode   codes.Code
+	}{
+		{
+			name: "ok",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("CreateUser", mock.Anything, mock.MatchedBy(func(arg repo.CreateUserParams) bool {
+					var attrs map[string]interface{}
+					return json.Unmarshal(arg.Attributes, &attrs) == nil &&
+						attrs["company"] == "ACME" && attrs["employee_number"] == 42.0
+				})).Return(repo.User{ID: uuid.New()}, nil).Once()
+			},
+			attrs:   map[string]interface{}{"company": "ACME", "employee_number": 42},
+			expCode: codes.OK,
+		},
+		{
+			name: "no attributes",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("CreateUser", mock.Anything, mock.MatchedBy(func(arg repo.CreateUserParams) bool {
+					return string(arg.Attributes) == "{}"
+				})).Return(repo.User{ID: uuid.New()}, nil).Once()
+			},
+			expCode: codes.OK,
+		},
+		{
+			name:      "schema violation",
+			buildRepo: func(q *mocks.Querier) {},
+			attrs:     map[string]interface{}{"employee_number": "42"},
+			expCode:   codes.InvalidArgument,
+		},
+		{
+			name:      "unknown attribute",
+			buildRepo: func(q *mocks.Querier) {},
+			attrs:     map[string]interface{}{"nickname": "joe"},
+			expCode:   codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// construct server
+			mockRepo := new(mocks.Querier)
+			tt.buildRepo(mockRepo)
+			server := service.NewUserServer(mockRepo, service.WithAttributeSchema(validator))
+
+			// build request
+			user := randomUser()
+			if tt.attrs != nil {
+				attrs, err := structpb.NewStruct(tt.attrs)
+				require.NoError(t, err)
+				user.Attributes = attrs
+			}
+
+			// test method
+			resp, err := server.RegisterUser(context.Background(), &userpb.RegisterUserRequest{User: user})
+			if tt.expCode == codes.OK {
+				require.NoError(t, err)
+				require.NotNil(t, resp)
+			} else {
+				require.Error(t, err)
+				require.Nil(t, resp)
+
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, tt.expCode, st.Code())
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserServer_GetUser_Attributes(t *testing.T) {
+	t.Parallel()
+
+	uid := uuid.New()
+	mockRepo := new(mocks.Querier)
+	mockRepo.On("GetUser", mock.Anything, uid).Return(repo.User{
+		ID:         uid,
+		Attributes: json.RawMessage(`{"company": "ACME", "employee_number": 42}`),
+	}, nil).Once()
+
+	server := service.NewUserServer(mockRepo)
+	resp, err := server.GetUser(context.Background(), &userpb.GetUserRequest{Id: uid.String()})
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"company": "ACME", "employee_number": 42.0}, resp.User.Attributes.AsMap())
+
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/chutommy/user-microservice/pkg/apierror"
+	"github.com/chutommy/user-microservice/pkg/avatar"
+	"github.com/chutommy/user-microservice/pkg/grpc/userpb"
+	"github.com/chutommy/user-microservice/pkg/repo"
+)
+
+// DefaultAvatarBaseURL is the URL prefix of the avatars served by the gateway.
+const DefaultAvatarBaseURL = "/v1/avatars"
+
+// avatarURL converts the blob key of an avatar image into its URL.
+func (u *UserServer) avatarURL(key string) string {
+	return strings.TrimSuffix(u.avatarBaseURL, "/") + "/" + strings.TrimPrefix(key, avatar.KeyPrefix)
+}
+
+func (u *UserServer) avatarToPB(originalKey string) *userpb.Avatar {
+	pb := &userpb.Avatar{
+		Url:           u.avatarURL(originalKey),
+		ThumbnailUrls: make(map[int32]string, len(avatar.ThumbnailSizes)),
+	}
+	for _, size := range avatar.ThumbnailSizes {
+		pb.ThumbnailUrls[int32(size)] = u.avatarURL(avatar.ThumbnailKey(originalKey, size))
+	}
+
+	return pb
+}
+
+// storeAvatar puts the avatar images into the blob store. Nothing is left
+// behind if any of the images fails to be stored.
+func (u *UserServer) storeAvatar(ctx context.Context, originalKey string, a *avatar.Avatar) error {
+	images := map[string]avatar.Image{
+		originalKey: a.Original,
+	}
+	for size, img := range a.Thumbnails {
+		images[avatar.ThumbnailKey(originalKey, size)] = img
+	}
+
+	for key, img := range images {
+		if err := u.blobs.Put(ctx, key, bytes.NewReader(img.Data)); err != nil {
+			u.deleteAvatar(ctx, originalKey)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteAvatar removes the avatar images from the blob store. Failures are
+// only logged, leftover blobs are not referenced anymore.
+func (u *UserServer) deleteAvatar(ctx context.Context, originalKey string) {
+	logger := ctxzap.Extract(ctx)
+
+	for _, key := range avatar.Keys(originalKey) {
+		if err := u.blobs.Delete(ctx, key); err != nil {
+			logger.Warn("failed to delete avatar image", zap.String("key", key), zap.Error(err))
+		}
+	}
+}
+
+// receiveAvatar reads the user ID and the image data of the upload stream.
+func receiveAvatar(stream userpb.UserService_UploadAvatarServer) (string, []byte, error) {
+	logger := ctxzap.Extract(stream.Context())
+
+	first, err := stream.Recv()
+	if err != nil {
+		logger.Info("failed to receive avatar upload", zap.Error(err))
+		return "", nil, status.Error(codes.InvalidArgument, "failed to receive upload")
+	}
+	id := first.GetId()
+	if id == "" {
+		logger.Info("empty id")
+		return "", nil, apierror.InvalidField("id", "%v: 'id' field", ErrEmptyField)
+	}
+
+	var buf bytes.Buffer
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			logger.Info("failed to receive avatar chunk", zap.Error(err))
+			return "", nil, status.Error(codes.Canceled, "failed to receive upload")
+		}
+
+		if _, ok := req.GetData().(*userpb.UploadAvatarRequest_Chunk); !ok {
+			logger.Info("unexpected upload message")
+			return "", nil, apierror.InvalidField("id", "only the first message may carry the id")
+		}
+		if buf.Len()+len(req.GetChunk()) > avatar.MaxSize {
+			logger.Info("avatar too large")
+			return "", nil, apierror.InvalidField("chunk", "%v: exceeds %d bytes", avatar.ErrTooLarge, avatar.MaxSize)
+		}
+		buf.Write(req.GetChunk())
+	}
+
+	return id, buf.Bytes(), nil
+}
+
+func (u *UserServer) UploadAvatar(stream userpb.UserService_UploadAvatarServer) error {
+	ctx := stream.Context()
+	logger := ctxzap.Extract(ctx)
+
+	if u.blobs == nil {
+		logger.Error("avatars are not configured")
+		return status.Errorf(codes.Unavailable, "avatars are not available")
+	}
+
+	id, data, err := receiveAvatar(stream)
+	if err != nil {
+		return err
+	}
+
+	// parse ID
+	uid, err := parseID(ctx, "id", id)
+	if err != nil {
+		return err
+	}
+
+	// validate and process the image
+	img, err := avatar.Process(data)
+	if err != nil {
+		logger.Info("invalid avatar", zap.Error(err))
+		return apierror.InvalidField("chunk", "%v", err)
+	}
+
+	// store images
+	key := avatar.OriginalKey(uid.String(), uuid.New().String(), img.Original.Ext())
+	if err = u.storeAvatar(ctx, key, img); err != nil {
+		logger.Error("failed to store avatar", zap.Error(err))
+		return status.Errorf(codes.Internal, "failed to store avatar of user with id: %s", id)
+	}
+
+	// reference the images
+	previous, err := u.repo.SetUserAvatar(ctx, repo.SetUserAvatarParams{
+		ID:        uid,
+		AvatarKey: sql.NullString{String: key, Valid: true},
+	})
+	if err != nil {
+		u.deleteAvatar(ctx, key)
+
+		code := codes.Internal
+
+		if errors.Is(err, sql.ErrNoRows) {
+			code = codes.NotFound
+		}
+
+		logger.Info("failed to set avatar", zap.Error(err))
+		return status.Errorf(code, "failed to set avatar of user with id: %s", id)
+	}
+	if previous.Valid {
+		u.deleteAvatar(ctx, previous.String)
+	}
+
+	// construct response
+	resp := &userpb.UploadAvatarResponse{
+		Avatar: u.avatarToPB(key),
+	}
+
+	return stream.SendAndClose(resp)
+}
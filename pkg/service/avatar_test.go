@@ -0,0 +1,231 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"image"
+	"image/png"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/chutommy/user-microservice/pkg/blob"
+	"github.com/chutommy/user-microservice/pkg/grpc/userpb"
+	"github.com/chutommy/user-microservice/pkg/mocks"
+	"github.com/chutommy/user-microservice/pkg/repo"
+	"github.com/chutommy/user-microservice/pkg/service"
+)
+
+// uploadStream replays the upload messages and records the response. The
+// error, io.EOF if nil, is received after the messages.
+type uploadStream struct {
+	grpc.ServerStream
+	reqs []*userpb.UploadAvatarRequest
+	err  error
+	resp *userpb.UploadAvatarResponse
+}
+
+func (s *uploadStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *uploadStream) Recv() (*userpb.UploadAvatarRequest, error) {
+	if len(s.reqs) == 0 {
+		if s.err != nil {
+			return nil, s.err
+		}
+		return nil, io.EOF
+	}
+	req := s.reqs[0]
+	s.reqs = s.reqs[1:]
+
+	return req, nil
+}
+
+func (s *uploadStream) SendAndClose(resp *userpb.UploadAvatarResponse) error {
+	s.resp = resp
+	return nil
+}
+
+// uploadRequests splits the data into an upload of the user's avatar.
+func uploadRequests(id string, data []byte) []*userpb.UploadAvatarRequest {
+	reqs := []*userpb.UploadAvatarRequest{{Data: &userpb.UploadAvatarRequest_Id{Id: id}}}
+	for len(data) > 0 {
+		n := 1 << 10
+		if n > len(data) {
+			n = len(data)
+		}
+		reqs = append(reqs, &userpb.UploadAvatarRequest{Data: &userpb.UploadAvatarRequest_Chunk{Chunk: data[:n]}})
+		data = data[n:]
+	}
+
+	return reqs
+}
+
+func TestUserServer_UploadAvatar(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "blobs")
+	require.NoError(t, err)
+	blobs, err := blob.NewFileStore(dir)
+	require.NoError(t, err)
+
+	var img bytes.Buffer
+	require.NoError(t, png.Encode(&img, image.NewGray(image.Rect(0, 0, 300, 200))))
+
+	uid := uuid.New()
+	previous := "avatars/" + uid.String() + "/old/original.png"
+	require.NoError(t, blobs.Put(context.Background(), previous, bytes.NewReader(img.Bytes())))
+
+	tests := []struct {
+		name      string
+		buildRepo func(q *mocks.Querier)
+		opts      []service.Option
+		reqs      []*userpb.UploadAvatarRequest
+		expCode   codes.Code
+	}{
+		{
+			name: "ok",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("SetUserAvatar", mock.Anything, mock.MatchedBy(func(arg repo.SetUserAvatarParams) bool {
+					return arg.ID == uid && arg.AvatarKey.Valid
+				})).Return(sql.NullString{String: previous, Valid: true}, nil).Once()
+			},
+			opts:    []service.Option{service.WithBlobStore(blobs), service.WithAvatarBaseURL("https://cdn.example.com/avatars/")},
+			reqs:    uploadRequests(uid.String(), img.Bytes()),
+			expCode: codes.OK,
+		},
+		{
+			name:      "not configured",
+			buildRepo: func(q *mocks.Querier) {},
+			reqs:      uploadRequests(uid.String(), img.Bytes()),
+			expCode:   codes.Unavailable,
+		},
+		{
+			name:      "empty id",
+			buildRepo: func(q *mocks.Querier) {},
+			opts:      []service.Option{service.WithBlobStore(blobs)},
+			reqs:      uploadRequests("", img.Bytes()),
+			expCode:   codes.InvalidArgument,
+		},
+		{
+			name:      "not an image",
+			buildRepo: func(q *mocks.Querier) {},
+			opts:      []service.Option{service.WithBlobStore(blobs)},
+			reqs:      uploadRequests(uid.String(), []byte("hello world")),
+			expCode:   codes.InvalidArgument,
+		},
+		{
+			name:      "repeated id",
+			buildRepo: func(q *mocks.Querier) {},
+			opts:      []service.Option{service.WithBlobStore(blobs)},
+			reqs:      append(uploadRequests(uid.String(), img.Bytes()), uploadRequests(uid.String(), nil)...),
+			expCode:   codes.InvalidArgument,
+		},
+		{
+			name: "user not found",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("SetUserAvatar", mock.Anything, mock.Anything).Return(sql.NullString{}, sql.ErrNoRows).Once()
+			},
+			opts:    []service.Option{service.WithBlobStore(blobs)},
+			reqs:    uploadRequests(uid.String(), img.Bytes()),
+			expCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// construct server
+			mockRepo := new(mocks.Querier)
+			tt.buildRepo(mockRepo)
+			server := service.NewUserServer(mockRepo, tt.opts...)
+
+			// test method
+			stream := &uploadStream{reqs: tt.reqs}
+			err := server.UploadAvatar(stream)
+			if tt.expCode == codes.OK {
+				require.NoError(t, err)
+				require.Regexp(t, `^https://cdn\.example\.com/avatars/`+uid.String()+`/[0-9a-f-]+/original\.png$`, stream.resp.Avatar.Url)
+				require.Len(t, stream.resp.Avatar.ThumbnailUrls, 2)
+
+				// the previous avatar is removed
+				_, err = blobs.Get(context.Background(), previous)
+				require.ErrorIs(t, err, blob.ErrNotFound)
+			} else {
+				require.Error(t, err)
+				require.Nil(t, stream.resp)
+
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, tt.expCode, st.Code())
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserServer_UploadAvatar_Interrupted(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "blobs")
+	require.NoError(t, err)
+	blobs, err := blob.NewFileStore(dir)
+	require.NoError(t, err)
+
+	// the error text of the transport is not returned
+	recvErr := errors.New("read tcp 10.0.0.1:8080->10.0.0.2:41234: connection reset by peer")
+
+	tests := []struct {
+		name    string
+		reqs    []*userpb.UploadAvatarRequest
+		expCode codes.Code
+	}{
+		{"first message", nil, codes.InvalidArgument},
+		{"chunk", uploadRequests(uuid.New().String(), []byte("partial")), codes.Canceled},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := service.NewUserServer(new(mocks.Querier), service.WithBlobStore(blobs))
+			err := server.UploadAvatar(&uploadStream{reqs: tt.reqs, err: recvErr})
+
+			st := status.Convert(err)
+			require.Equal(t, tt.expCode, st.Code())
+			require.Equal(t, "failed to receive upload", st.Message())
+		})
+	}
+}
+
+func TestUserServer_GetUser_Avatar(t *testing.T) {
+	t.Parallel()
+
+	uid := uuid.New()
+	mockRepo := new(mocks.Querier)
+	mockRepo.On("GetUser", mock.Anything, uid).Return(repo.User{
+		ID:        uid,
+		AvatarKey: sql.NullString{String: "avatars/" + uid.String() + "/upload/original.jpg", Valid: true},
+	}, nil).Once()
+
+	server := service.NewUserServer(mockRepo)
+	resp, err := server.GetUser(context.Background(), &userpb.GetUserRequest{Id: uid.String()})
+	require.NoError(t, err)
+	require.Equal(t, "/v1/avatars/"+uid.String()+"/upload/original.jpg", resp.User.Avatar.Url)
+	require.Equal(t, "/v1/avatars/"+uid.String()+"/upload/256.jpg", resp.User.Avatar.ThumbnailUrls[256])
+
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,84 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/type/date"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/chutommy/user-microservice/pkg/grpc/userpb"
+	"github.com/chutommy/user-microservice/pkg/mocks"
+	"github.com/chutommy/user-microservice/pkg/repo"
+	"github.com/chutommy/user-microservice/pkg/service"
+)
+
+func TestUserServer_RegisterUser_Birthday(t *testing.T) {
+	t.Parallel()
+
+	nextYear := int32(time.Now().Year() + 1)
+
+	tests := []struct {
+		name      string
+		birthday  string
+		birthDate *date.Date
+		expDate   string
+		expCode   codes.Code
+	}{
+		{"short form", "1990-Apr-16", nil, "1990-04-16", codes.OK},
+		{"iso form", "1990-04-16", nil, "1990-04-16", codes.OK},
+		{"date", "", &date.Date{Year: 2000, Month: 2, Day: 29}, "2000-02-29", codes.OK},
+		{"date takes precedence", "1990-04-16", &date.Date{Year: 1985, Month: 1, Day: 2}, "1985-01-02", codes.OK},
+		{"impossible day", "", &date.Date{Year: 1990, Month: 2, Day: 31}, "", codes.InvalidArgument},
+		{"common year leap day", "", &date.Date{Year: 1900, Month: 2, Day: 29}, "", codes.InvalidArgument},
+		{"impossible iso day", "1990-04-31", nil, "", codes.InvalidArgument},
+		{"missing year", "", &date.Date{Month: 4, Day: 16}, "", codes.InvalidArgument},
+		{"future date", "", &date.Date{Year: nextYear, Month: 1, Day: 1}, "", codes.InvalidArgument},
+		{"future string", time.Now().AddDate(0, 0, 2).Format(service.ISOForm), nil, "", codes.InvalidArgument},
+		{"unsupported format", "16/04/1990", nil, "", codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// construct server
+			mockRepo := new(mocks.Querier)
+			if tt.expCode == codes.OK {
+				mockRepo.On("CreateUser", mock.Anything, mock.MatchedBy(func(arg repo.CreateUserParams) bool {
+					return arg.BirthDay.Valid && arg.BirthDay.Time.Format(service.ISOForm) == tt.expDate
+				})).Return(repo.User{ID: uuid.New()}, nil).Once()
+			}
+			server := service.NewUserServer(mockRepo)
+
+			// test method
+			resp, err := server.RegisterUser(context.Background(), &userpb.RegisterUserRequest{
+				User: &userpb.User{
+					Email:     "john@example.com",
+					Password:  "secret",
+					FirstName: "John",
+					LastName:  "Doe",
+					Birthday:  tt.birthday,
+					BirthDate: tt.birthDate,
+				},
+			})
+			if tt.expCode == codes.OK {
+				require.NoError(t, err)
+				require.NotNil(t, resp)
+			} else {
+				require.Error(t, err)
+				require.Nil(t, resp)
+
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, tt.expCode, st.Code())
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/chutommy/user-microservice/pkg/apierror"
+	"github.com/chutommy/user-microservice/pkg/grpc/userpb"
+	"github.com/chutommy/user-microservice/pkg/repo"
+)
+
+func consentToPB(c repo.UserConsent) *userpb.Consent {
+	pb := &userpb.Consent{
+		Policy: &userpb.PolicyVersion{
+			Kind:    c.PolicyKind,
+			Version: c.PolicyVersion,
+		},
+		AcceptedAt: timestamppb.New(c.AcceptedAt),
+	}
+	if c.WithdrawnAt.Valid {
+		pb.WithdrawnAt = timestamppb.New(c.WithdrawnAt.Time)
+	}
+
+	return pb
+}
+
+// checkPolicy verifies that the policy version of the field is published.
+func (u *UserServer) checkPolicy(ctx context.Context, field string, p *userpb.PolicyVersion) error {
+	logger := ctxzap.Extract(ctx)
+
+	if p.GetKind() == "" {
+		logger.Info("empty policy kind")
+		return apierror.InvalidField(field+".kind", "%v: '%s.kind' field", ErrEmptyField, field)
+	}
+
+	_, err := u.repo.GetConsentPolicy(ctx, repo.GetConsentPolicyParams{
+		Kind:    p.GetKind(),
+		Version: p.GetVersion(),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.Info("unknown policy version", zap.String("kind", p.GetKind()), zap.Int32("version", p.GetVersion()))
+			return apierror.InvalidField(field, "unknown policy %s version %d", p.GetKind(), p.GetVersion())
+		}
+
+		logger.Error("retrieve policy", zap.Error(err))
+		return status.Errorf(codes.Internal, "failed to retrieve policy %s", p.GetKind())
+	}
+
+	return nil
+}
+
+func (u *UserServer) RecordConsent(ctx context.Context, req *userpb.RecordConsentRequest) (*userpb.RecordConsentResponse, error) {
+	logger := ctxzap.Extract(ctx)
+
+	// parse ID
+	uid, err := parseID(ctx, "id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	policy := req.GetPolicy()
+	if err = u.checkPolicy(ctx, "policy", policy); err != nil {
+		return nil, err
+	}
+
+	// store consent
+	consent, err := u.repo.RecordConsent(ctx, repo.RecordConsentParams{
+		UserID:        uid,
+		PolicyKind:    policy.GetKind(),
+		PolicyVersion: policy.GetVersion(),
+	})
+	if err != nil {
+		code := codes.Internal
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			code = codes.NotFound
+		}
+
+		logger.Error("failed to record consent", zap.Error(err))
+		return nil, status.Errorf(code, "failed to record consent of user with id: %s", uid)
+	}
+
+	// construct response
+	resp := &userpb.RecordConsentResponse{
+		Consent: consentToPB(consent),
+	}
+
+	return resp, nil
+}
+
+func (u *UserServer) WithdrawConsent(ctx context.Context, req *userpb.WithdrawConsentRequest) (*userpb.WithdrawConsentResponse, error) {
+	logger := ctxzap.Extract(ctx)
+
+	switch {
+	case req.GetPolicyKind() == "":
+		logger.Info("empty policy kind")
+		return nil, apierror.InvalidField("policy_kind", "%v: 'policy_kind' field", ErrEmptyField)
+	}
+
+	// parse ID
+	uid, err := parseID(ctx, "id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	// withdraw consent
+	affected, err := u.repo.WithdrawConsent(ctx, repo.WithdrawConsentParams{
+		UserID:     uid,
+		PolicyKind: req.GetPolicyKind(),
+	})
+	if err != nil || affected == 0 {
+		code := codes.Internal
+
+		if affected == 0 && err == nil {
+			code = codes.NotFound
+		}
+
+		logger.Info("failed to withdraw consent", zap.Error(err))
+		return nil, status.Errorf(code, "failed to withdraw consent %s of user with id: %s", req.GetPolicyKind(), uid)
+	}
+
+	// construct response
+	resp := &userpb.WithdrawConsentResponse{
+		Id: uid.String(),
+	}
+
+	return resp, nil
+}
+
+func (u *UserServer) ListConsents(ctx context.Context, req *userpb.ListConsentsRequest) (*userpb.ListConsentsResponse, error) {
+	logger := ctxzap.Extract(ctx)
+
+	// parse ID
+	uid, err := parseID(ctx, "id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	// retrieve consents
+	consents, err := u.repo.ListConsents(ctx, uid)
+	if err != nil {
+		logger.Error("list consents", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "failed to list consents of user with id: %s", uid)
+	}
+
+	// construct response
+	resp := &userpb.ListConsentsResponse{
+		Consents: make([]*userpb.Consent, 0, len(consents)),
+	}
+	for _, c := range consents {
+		resp.Consents = append(resp.Consents, consentToPB(c))
+	}
+
+	return resp, nil
+}
+
+// defaultPageSize is the number of the listed users if the page size is unset.
+const defaultPageSize = 100
+
+func (u *UserServer) PublishConsentPolicy(ctx context.Context, req *userpb.PublishConsentPolicyRequest) (*userpb.PublishConsentPolicyResponse, error) {
+	logger := ctxzap.Extract(ctx)
+
+	policy := req.GetPolicy()
+	switch {
+	case policy.GetKind() == "":
+		logger.Info("empty policy kind")
+		return nil, apierror.InvalidField("policy.kind", "%v: 'policy.kind' field", ErrEmptyField)
+	case policy.GetVersion() <= 0:
+		logger.Info("invalid policy version", zap.Int32("version", policy.GetVersion()))
+		return nil, apierror.InvalidField("policy.version", "policy version must be positive")
+	}
+
+	// store policy
+	published, err := u.repo.CreateConsentPolicy(ctx, repo.CreateConsentPolicyParams{
+		Kind:     policy.GetKind(),
+		Version:  policy.GetVersion(),
+		Required: req.GetRequired(),
+	})
+	if err != nil {
+		if conflict := apierror.Conflict(err); conflict != nil {
+			logger.Info("policy version already published", zap.Error(err))
+			return nil, status.Errorf(codes.AlreadyExists, "policy %s version %d is already published", policy.GetKind(), policy.GetVersion())
+		}
+
+		logger.Error("failed to publish policy", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "failed to publish policy %s", policy.GetKind())
+	}
+
+	// construct response
+	resp := &userpb.PublishConsentPolicyResponse{
+		Policy: &userpb.ConsentPolicy{
+			Policy: &userpb.PolicyVersion{
+				Kind:    published.Kind,
+				Version: published.Version,
+			},
+			Required:    published.Required,
+			PublishedAt: timestamppb.New(published.PublishedAt),
+		},
+	}
+
+	return resp, nil
+}
+
+func (u *UserServer) ListUsersRequiringConsent(ctx context.Context, req *userpb.ListUsersRequiringConsentRequest) (*userpb.ListUsersRequiringConsentResponse, error) {
+	logger := ctxzap.Extract(ctx)
+
+	if req.GetPolicyKind() == "" {
+		logger.Info("empty policy kind")
+		return nil, apierror.InvalidField("policy_kind", "%v: 'policy_kind' field", ErrEmptyField)
+	}
+
+	var after uuid.UUID
+	if token := req.GetPageToken(); token != "" {
+		var err error
+		if after, err = parseID(ctx, "page_token", token); err != nil {
+			return nil, err
+		}
+	}
+
+	pageSize := req.GetPageSize()
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+
+	// retrieve one more user to tell whether there is a next page
+	ids, err := u.repo.ListUsersRequiringConsent(ctx, repo.ListUsersRequiringConsentParams{
+		AfterID:    after,
+		PolicyKind: req.GetPolicyKind(),
+		RowLimit:   pageSize + 1,
+	})
+	if err != nil {
+		logger.Error("list users requiring consent", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "failed to list users requiring consent to policy %s", req.GetPolicyKind())
+	}
+
+	// construct response
+	resp := &userpb.ListUsersRequiringConsentResponse{}
+	if len(ids) > int(pageSize) {
+		ids = ids[:pageSize]
+		resp.NextPageToken = ids[len(ids)-1].String()
+	}
+	resp.Ids = make([]string, 0, len(ids))
+	for _, id := range ids {
+		resp.Ids = append(resp.Ids, id.String())
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,385 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/chutommy/user-microservice/pkg/grpc/userpb"
+	"github.com/chutommy/user-microservice/pkg/mocks"
+	"github.com/chutommy/user-microservice/pkg/repo"
+	"github.com/chutommy/user-microservice/pkg/service"
+)
+
+func TestUserServer_RegisterUser_AcceptedPolicies(t *testing.T) {
+	t.Parallel()
+
+	u1 := randomUser()
+	tos := &userpb.PolicyVersion{Kind: "terms_of_service", Version: 3}
+
+	tests := []struct {
+		name      string
+		buildRepo func(q *mocks.Querier)
+		expCode   codes.Code
+	}{
+		{
+			name: "ok",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("GetConsentPolicy", mock.Anything, repo.GetConsentPolicyParams{Kind: tos.Kind, Version: tos.Version}).
+					Return(repo.ConsentPolicy{Kind: tos.Kind, Version: tos.Version}, nil).Once()
+				q.On("CreateUser", mock.Anything, mock.MatchedBy(func(arg repo.CreateUserParams) bool {
+					return reflect.DeepEqual(arg.PolicyKinds, []string{tos.Kind}) &&
+						reflect.DeepEqual(arg.PolicyVersions, []int32{tos.Version})
+				})).Return(repo.User{ID: uuid.MustParse(u1.Id)}, nil).Once()
+			},
+			expCode: codes.OK,
+		},
+		{
+			name: "unknown policy",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("GetConsentPolicy", mock.Anything, mock.Anything).Return(repo.ConsentPolicy{}, sql.ErrNoRows).Once()
+			},
+			expCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// construct server
+			mockRepo := new(mocks.Querier)
+			tt.buildRepo(mockRepo)
+			server := service.NewUserServer(mockRepo)
+
+			// build request
+			req := &userpb.RegisterUserRequest{
+				User:             u1,
+				AcceptedPolicies: []*userpb.PolicyVersion{tos},
+			}
+
+			// test method
+			resp, err := server.RegisterUser(context.Background(), req)
+			if tt.expCode == codes.OK {
+				require.NoError(t, err)
+				require.Equal(t, u1.Id, resp.Id)
+			} else {
+				require.Error(t, err)
+				require.Nil(t, resp)
+
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, tt.expCode, st.Code())
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserServer_RecordConsent(t *testing.T) {
+	t.Parallel()
+
+	uid := uuid.New()
+	policy := &userpb.PolicyVersion{Kind: "marketing", Version: 1}
+
+	tests := []struct {
+		name      string
+		buildRepo func(q *mocks.Querier)
+		inpID     string
+		policy    *userpb.PolicyVersion
+		expCode   codes.Code
+	}{
+		{
+			name: "ok",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("GetConsentPolicy", mock.Anything, mock.Anything).Return(repo.ConsentPolicy{}, nil).Once()
+				q.On("RecordConsent", mock.Anything, mock.Anything).Return(repo.UserConsent{
+					UserID:        uid,
+					PolicyKind:    policy.Kind,
+					PolicyVersion: policy.Version,
+					AcceptedAt:    time.Now(),
+				}, nil).Once()
+			},
+			inpID:   uid.String(),
+			policy:  policy,
+			expCode: codes.OK,
+		},
+		{
+			name:      "empty policy kind",
+			buildRepo: func(q *mocks.Querier) {},
+			inpID:     uid.String(),
+			policy:    &userpb.PolicyVersion{Version: 1},
+			expCode:   codes.InvalidArgument,
+		},
+		{
+			name:      "invalid id",
+			buildRepo: func(q *mocks.Querier) {},
+			inpID:     "invalid_uuid",
+			policy:    policy,
+			expCode:   codes.InvalidArgument,
+		},
+		{
+			name: "user not found",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("GetConsentPolicy", mock.Anything, mock.Anything).Return(repo.ConsentPolicy{}, nil).Once()
+				q.On("RecordConsent", mock.Anything, mock.Anything).Return(repo.UserConsent{}, &pq.Error{Code: "23503"}).Once()
+			},
+			inpID:   uid.String(),
+			policy:  policy,
+			expCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// construct server
+			mockRepo := new(mocks.Querier)
+			tt.buildRepo(mockRepo)
+			server := service.NewUserServer(mockRepo)
+
+			// test method
+			resp, err := server.RecordConsent(context.Background(), &userpb.RecordConsentRequest{Id: tt.inpID, Policy: tt.policy})
+			if tt.expCode == codes.OK {
+				require.NoError(t, err)
+				require.Equal(t, policy.Kind, resp.Consent.Policy.Kind)
+				require.Nil(t, resp.Consent.WithdrawnAt)
+			} else {
+				require.Error(t, err)
+				require.Nil(t, resp)
+
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, tt.expCode, st.Code())
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserServer_WithdrawConsent(t *testing.T) {
+	t.Parallel()
+
+	uid := uuid.New()
+
+	tests := []struct {
+		name      string
+		buildRepo func(q *mocks.Querier)
+		kind      string
+		expCode   codes.Code
+	}{
+		{
+			name: "ok",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("WithdrawConsent", mock.Anything, repo.WithdrawConsentParams{UserID: uid, PolicyKind: "marketing"}).
+					Return(int64(1), nil).Once()
+			},
+			kind:    "marketing",
+			expCode: codes.OK,
+		},
+		{
+			name:      "empty kind",
+			buildRepo: func(q *mocks.Querier) {},
+			kind:      "",
+			expCode:   codes.InvalidArgument,
+		},
+		{
+			name: "no active consent",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("WithdrawConsent", mock.Anything, mock.Anything).Return(int64(0), nil).Once()
+			},
+			kind:    "marketing",
+			expCode: codes.NotFound,
+		},
+		{
+			name: "connection error",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("WithdrawConsent", mock.Anything, mock.Anything).Return(int64(0), sql.ErrConnDone).Once()
+			},
+			kind:    "marketing",
+			expCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// construct server
+			mockRepo := new(mocks.Querier)
+			tt.buildRepo(mockRepo)
+			server := service.NewUserServer(mockRepo)
+
+			// test method
+			resp, err := server.WithdrawConsent(context.Background(), &userpb.WithdrawConsentRequest{Id: uid.String(), PolicyKind: tt.kind})
+			if tt.expCode == codes.OK {
+				require.NoError(t, err)
+				require.Equal(t, uid.String(), resp.Id)
+			} else {
+				require.Error(t, err)
+				require.Nil(t, resp)
+
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, tt.expCode, st.Code())
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserServer_PublishConsentPolicy(t *testing.T) {
+	t.Parallel()
+
+	tos := &userpb.PolicyVersion{Kind: "terms_of_service", Version: 4}
+
+	tests := []struct {
+		name      string
+		buildRepo func(q *mocks.Querier)
+		policy    *userpb.PolicyVersion
+		expCode   codes.Code
+	}{
+		{
+			name: "ok",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("CreateConsentPolicy", mock.Anything, repo.CreateConsentPolicyParams{
+					Kind:     tos.Kind,
+					Version:  tos.Version,
+					Required: true,
+				}).Return(repo.ConsentPolicy{Kind: tos.Kind, Version: tos.Version, Required: true, PublishedAt: time.Now()}, nil).Once()
+			},
+			policy:  tos,
+			expCode: codes.OK,
+		},
+		{
+			name:      "empty kind",
+			buildRepo: func(q *mocks.Querier) {},
+			policy:    &userpb.PolicyVersion{Version: 1},
+			expCode:   codes.InvalidArgument,
+		},
+		{
+			name:      "invalid version",
+			buildRepo: func(q *mocks.Querier) {},
+			policy:    &userpb.PolicyVersion{Kind: tos.Kind},
+			expCode:   codes.InvalidArgument,
+		},
+		{
+			name: "already published",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("CreateConsentPolicy", mock.Anything, mock.Anything).
+					Return(repo.ConsentPolicy{}, &pq.Error{Code: "23505", Constraint: "consent_policies_pkey"}).Once()
+			},
+			policy:  tos,
+			expCode: codes.AlreadyExists,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// construct server
+			mockRepo := new(mocks.Querier)
+			tt.buildRepo(mockRepo)
+			server := service.NewUserServer(mockRepo)
+
+			// test method
+			resp, err := server.PublishConsentPolicy(context.Background(), &userpb.PublishConsentPolicyRequest{Policy: tt.policy, Required: true})
+			require.Equal(t, tt.expCode, status.Code(err))
+			if tt.expCode == codes.OK {
+				require.Equal(t, tos.Version, resp.Policy.Policy.Version)
+				require.True(t, resp.Policy.Required)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserServer_ListUsersRequiringConsent(t *testing.T) {
+	t.Parallel()
+
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	after := uuid.New()
+
+	tests := []struct {
+		name      string
+		buildRepo func(q *mocks.Querier)
+		req       *userpb.ListUsersRequiringConsentRequest
+		expIDs    int
+		expToken  string
+		expCode   codes.Code
+	}{
+		{
+			name: "first page",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("ListUsersRequiringConsent", mock.Anything, repo.ListUsersRequiringConsentParams{
+					PolicyKind: "marketing",
+					RowLimit:   3,
+				}).Return(ids, nil).Once()
+			},
+			req:      &userpb.ListUsersRequiringConsentRequest{PolicyKind: "marketing", PageSize: 2},
+			expIDs:   2,
+			expToken: ids[1].String(),
+		},
+		{
+			name: "last page",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("ListUsersRequiringConsent", mock.Anything, repo.ListUsersRequiringConsentParams{
+					AfterID:    after,
+					PolicyKind: "marketing",
+					RowLimit:   101,
+				}).Return(ids, nil).Once()
+			},
+			req:    &userpb.ListUsersRequiringConsentRequest{PolicyKind: "marketing", PageToken: after.String()},
+			expIDs: 3,
+		},
+		{
+			name:      "empty kind",
+			buildRepo: func(q *mocks.Querier) {},
+			req:       &userpb.ListUsersRequiringConsentRequest{},
+			expCode:   codes.InvalidArgument,
+		},
+		{
+			name:      "invalid token",
+			buildRepo: func(q *mocks.Querier) {},
+			req:       &userpb.ListUsersRequiringConsentRequest{PolicyKind: "marketing", PageToken: "invalid"},
+			expCode:   codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// construct server
+			mockRepo := new(mocks.Querier)
+			tt.buildRepo(mockRepo)
+			server := service.NewUserServer(mockRepo)
+
+			// test method
+			resp, err := server.ListUsersRequiringConsent(context.Background(), tt.req)
+			require.Equal(t, tt.expCode, status.Code(err))
+			if tt.expCode == codes.OK {
+				require.Len(t, resp.Ids, tt.expIDs)
+				require.Equal(t, tt.expToken, resp.NextPageToken)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
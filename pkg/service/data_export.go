@@ -0,0 +1,199 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/google/uuid"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/chutommy/user-microservice/pkg/dataexport"
+	"github.com/chutommy/user-microservice/pkg/grpc/userpb"
+	"github.com/chutommy/user-microservice/pkg/repo"
+)
+
+// dataExportKey returns the blob key of the data export archive.
+func dataExportKey(e repo.DataExport) string {
+	return fmt.Sprintf("data-exports/%s/%s.json", e.UserID, e.ID)
+}
+
+// dataExportKeys returns the blob keys of the user's data export archives,
+// which are removed once the user is gone. Failures are only logged.
+func (u *UserServer) dataExportKeys(ctx context.Context, uid uuid.UUID) []string {
+	if u.blobs == nil {
+		return nil
+	}
+
+	keys, err := u.repo.ListDataExportBlobKeys(ctx, uid)
+	if err != nil {
+		ctxzap.Extract(ctx).Warn("failed to list data export archives", zap.Error(err))
+	}
+
+	return keys
+}
+
+// deleteDataExports removes the data export archives from the blob store.
+// Failures are only logged, leftover blobs are not referenced anymore.
+func (u *UserServer) deleteDataExports(ctx context.Context, keys []string) {
+	logger := ctxzap.Extract(ctx)
+
+	for _, key := range keys {
+		if err := u.blobs.Delete(ctx, key); err != nil {
+			logger.Warn("failed to delete data export archive", zap.String("key", key), zap.Error(err))
+		}
+	}
+}
+
+func dataExportToPB(e repo.DataExport) *userpb.DataExport {
+	pb := &userpb.DataExport{
+		Id:        e.ID.String(),
+		UserId:    e.UserID.String(),
+		Status:    userpb.DataExport_Status(e.Status),
+		Signature: e.Signature,
+		CreatedAt: timestamppb.New(e.CreatedAt),
+	}
+	if e.CompletedAt.Valid {
+		pb.CompletedAt = timestamppb.New(e.CompletedAt.Time)
+	}
+
+	return pb
+}
+
+func (u *UserServer) RequestDataExport(ctx context.Context, req *userpb.RequestDataExportRequest) (*userpb.RequestDataExportResponse, error) {
+	logger := ctxzap.Extract(ctx)
+
+	// parse ID
+	uid, err := parseID(ctx, "id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if u.blobs == nil || len(u.signingKey) == 0 {
+		logger.Error("data export storage or signing key is not configured")
+		return nil, status.Errorf(codes.Unavailable, "data exports are not available")
+	}
+
+	// record the request
+	de, err := u.repo.CreateDataExport(ctx, repo.CreateDataExportParams{
+		ID:     uuid.New(),
+		UserID: uid,
+	})
+	if err != nil {
+		code := codes.Internal
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			code = codes.NotFound
+		}
+
+		logger.Error("failed to create a data export", zap.Error(err))
+		return nil, status.Errorf(code, "failed to request data export of user with id: %s", uid)
+	}
+
+	// assemble and store the archive
+	arg := repo.CompleteDataExportParams{
+		ID:     de.ID,
+		Status: int16(userpb.DataExport_READY),
+	}
+	arg.BlobKey, arg.Signature, err = u.writeDataExport(ctx, de)
+	if err != nil {
+		logger.Error("failed to write data export", zap.String("export_id", de.ID.String()), zap.Error(err))
+		arg = repo.CompleteDataExportParams{
+			ID:     de.ID,
+			Status: int16(userpb.DataExport_FAILED),
+		}
+	}
+
+	de, err = u.repo.CompleteDataExport(ctx, arg)
+	if err != nil {
+		logger.Error("failed to complete data export", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "failed to request data export of user with id: %s", uid)
+	}
+
+	// construct response
+	resp := &userpb.RequestDataExportResponse{
+		Export: dataExportToPB(de),
+	}
+
+	return resp, nil
+}
+
+// writeDataExport builds the signed archive and puts it into the blob store.
+func (u *UserServer) writeDataExport(ctx context.Context, de repo.DataExport) (key, signature string, err error) {
+	archive, err := dataexport.NewBuilder(u.repo).Build(ctx, de.ID, de.UserID)
+	if err != nil {
+		return "", "", err
+	}
+
+	data, signature, err := dataexport.Marshal(archive, u.signingKey)
+	if err != nil {
+		return "", "", err
+	}
+
+	key = dataExportKey(de)
+	if err = u.blobs.Put(ctx, key, bytes.NewReader(data)); err != nil {
+		return "", "", err
+	}
+
+	return key, signature, nil
+}
+
+func (u *UserServer) GetDataExport(ctx context.Context, req *userpb.GetDataExportRequest) (*userpb.GetDataExportResponse, error) {
+	logger := ctxzap.Extract(ctx)
+
+	// parse ID
+	eid, err := parseID(ctx, "id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	// retrieve export
+	de, err := u.repo.GetDataExport(ctx, eid)
+	if err != nil {
+		code := codes.Internal
+
+		if errors.Is(err, sql.ErrNoRows) {
+			code = codes.NotFound
+		}
+
+		logger.Error("retrieve data export", zap.Error(err))
+		return nil, status.Errorf(code, "failed to retrieve data export with id: %s", eid)
+	}
+
+	// construct response
+	resp := &userpb.GetDataExportResponse{
+		Export: dataExportToPB(de),
+	}
+	if userpb.DataExport_Status(de.Status) != userpb.DataExport_READY {
+		return resp, nil
+	}
+
+	if u.blobs == nil {
+		logger.Error("data export storage is not configured")
+		return nil, status.Errorf(codes.Unavailable, "data exports are not available")
+	}
+
+	rc, err := u.blobs.Get(ctx, de.BlobKey)
+	if err != nil {
+		logger.Error("failed to open data export archive", zap.String("blob_key", de.BlobKey), zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "failed to retrieve data export with id: %s", eid)
+	}
+	defer rc.Close()
+
+	resp.Archive, err = ioutil.ReadAll(rc)
+	if err != nil {
+		logger.Error("failed to read data export archive", zap.String("blob_key", de.BlobKey), zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "failed to retrieve data export with id: %s", eid)
+	}
+
+	return resp, nil
+}
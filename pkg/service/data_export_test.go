@@ -0,0 +1,197 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/chutommy/user-microservice/pkg/blob"
+	"github.com/chutommy/user-microservice/pkg/dataexport"
+	"github.com/chutommy/user-microservice/pkg/grpc/userpb"
+	"github.com/chutommy/user-microservice/pkg/mocks"
+	"github.com/chutommy/user-microservice/pkg/repo"
+	"github.com/chutommy/user-microservice/pkg/service"
+)
+
+func TestUserServer_DataExport(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "blobs")
+	require.NoError(t, err)
+	blobs, err := blob.NewFileStore(dir)
+	require.NoError(t, err)
+	key := []byte("signing key")
+
+	uid := uuid.New()
+	user := repo.User{
+		ID:        uid,
+		Email:     "user@example.com",
+		CreatedAt: time.Now(),
+	}
+
+	// request the export
+	var created repo.DataExport
+	var completed repo.DataExport
+	mockRepo := new(mocks.Querier)
+	mockRepo.On("CreateDataExport", mock.Anything, mock.AnythingOfType("repo.CreateDataExportParams")).
+		Return(func(_ context.Context, arg repo.CreateDataExportParams) repo.DataExport {
+			created = repo.DataExport{ID: arg.ID, UserID: arg.UserID, CreatedAt: time.Now()}
+			return created
+		}, nil).Once()
+	mockRepo.On("GetUser", mock.Anything, uid).Return(user, nil).Once()
+	mockRepo.On("ListErasureReceipts", mock.Anything, uid).Return([]repo.ErasureReceipt{}, nil).Once()
+	mockRepo.On("ListConsents", mock.Anything, uid).Return([]repo.UserConsent{}, nil).Once()
+	mockRepo.On("ListPreferences", mock.Anything, repo.ListPreferencesParams{UserID: uid}).Return([]repo.UserPreference{}, nil).Once()
+	mockRepo.On("ListAddresses", mock.Anything, repo.ListAddressesParams{UserID: uid}).Return([]repo.UserAddress{}, nil).Once()
+	mockRepo.On("ListEmails", mock.Anything, uid).Return([]repo.UserEmail{{Email: "home@example.com", UserID: uid, IsPrimary: true}}, nil).Once()
+	mockRepo.On("ListGuardianConsents", mock.Anything, uid).Return([]repo.GuardianConsent{}, nil).Once()
+	mockRepo.On("CompleteDataExport", mock.Anything, mock.AnythingOfType("repo.CompleteDataExportParams")).
+		Return(func(_ context.Context, arg repo.CompleteDataExportParams) repo.DataExport {
+			completed = created
+			completed.Status = arg.Status
+			completed.BlobKey = arg.BlobKey
+			completed.Signature = arg.Signature
+			completed.CompletedAt = sql.NullTime{Time: time.Now(), Valid: true}
+			return completed
+		}, nil).Once()
+
+	server := service.NewUserServer(mockRepo, service.WithBlobStore(blobs), service.WithSigningKey(key))
+	reqResp, err := server.RequestDataExport(context.Background(), &userpb.RequestDataExportRequest{Id: uid.String()})
+	require.NoError(t, err)
+	require.Equal(t, userpb.DataExport_READY, reqResp.Export.Status)
+	require.Equal(t, uid.String(), reqResp.Export.UserId)
+	require.NotEmpty(t, reqResp.Export.Signature)
+
+	// retrieve the archive
+	mockRepo.On("GetDataExport", mock.Anything, created.ID).Return(completed, nil).Once()
+	getResp, err := server.GetDataExport(context.Background(), &userpb.GetDataExportRequest{Id: reqResp.Export.Id})
+	require.NoError(t, err)
+	require.Contains(t, string(getResp.Archive), user.Email)
+	require.True(t, dataexport.Verify(getResp.Archive, key, getResp.Export.Signature))
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserServer_RequestDataExport(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "blobs")
+	require.NoError(t, err)
+	blobs, err := blob.NewFileStore(dir)
+	require.NoError(t, err)
+
+	uid := uuid.New()
+
+	tests := []struct {
+		name      string
+		buildRepo func(q *mocks.Querier)
+		opts      []service.Option
+		inpID     string
+		expStatus userpb.DataExport_Status
+		expCode   codes.Code
+	}{
+		{
+			name: "archive failure",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("CreateDataExport", mock.Anything, mock.Anything).Return(repo.DataExport{ID: uuid.New(), UserID: uid}, nil).Once()
+				q.On("GetUser", mock.Anything, uid).Return(repo.User{}, sql.ErrConnDone).Once()
+				q.On("CompleteDataExport", mock.Anything, mock.MatchedBy(func(arg repo.CompleteDataExportParams) bool {
+					return arg.Status == int16(userpb.DataExport_FAILED) && arg.BlobKey == ""
+				})).Return(repo.DataExport{UserID: uid, Status: int16(userpb.DataExport_FAILED)}, nil).Once()
+			},
+			opts:      []service.Option{service.WithBlobStore(blobs), service.WithSigningKey([]byte("key"))},
+			inpID:     uid.String(),
+			expStatus: userpb.DataExport_FAILED,
+			expCode:   codes.OK,
+		},
+		{
+			name:      "empty id",
+			buildRepo: func(q *mocks.Querier) {},
+			inpID:     "",
+			expCode:   codes.InvalidArgument,
+		},
+		{
+			name:      "invalid id",
+			buildRepo: func(q *mocks.Querier) {},
+			inpID:     "invalid_uuid",
+			expCode:   codes.InvalidArgument,
+		},
+		{
+			name:      "not configured",
+			buildRepo: func(q *mocks.Querier) {},
+			inpID:     uid.String(),
+			expCode:   codes.Unavailable,
+		},
+		{
+			name: "user not found",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("CreateDataExport", mock.Anything, mock.Anything).Return(repo.DataExport{}, &pq.Error{Code: "23503"}).Once()
+			},
+			opts:    []service.Option{service.WithBlobStore(blobs), service.WithSigningKey([]byte("key"))},
+			inpID:   uid.String(),
+			expCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// construct server
+			mockRepo := new(mocks.Querier)
+			tt.buildRepo(mockRepo)
+			server := service.NewUserServer(mockRepo, tt.opts...)
+
+			// test method
+			resp, err := server.RequestDataExport(context.Background(), &userpb.RequestDataExportRequest{Id: tt.inpID})
+			if tt.expCode == codes.OK {
+				require.NoError(t, err)
+				require.NotNil(t, resp)
+				require.Equal(t, tt.expStatus, resp.Export.Status)
+			} else {
+				require.Error(t, err)
+				require.Nil(t, resp)
+
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, tt.expCode, st.Code())
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserServer_DeleteUser_DataExports(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "blobs")
+	require.NoError(t, err)
+	blobs, err := blob.NewFileStore(dir)
+	require.NoError(t, err)
+
+	uid := uuid.New()
+	archive := "data-exports/" + uid.String() + "/" + uuid.New().String() + ".json"
+	require.NoError(t, blobs.Put(context.Background(), archive, strings.NewReader("{}")))
+
+	mockRepo := new(mocks.Querier)
+	mockRepo.On("ListDataExportBlobKeys", mock.Anything, uid).Return([]string{archive}, nil).Once()
+	mockRepo.On("DeleteUser", mock.Anything, uid).Return(int64(1), nil).Once()
+	server := service.NewUserServer(mockRepo, service.WithBlobStore(blobs))
+
+	_, err = server.DeleteUser(context.Background(), &userpb.DeleteUserRequest{Id: uid.String()})
+	require.NoError(t, err)
+
+	_, err = blobs.Get(context.Background(), archive)
+	require.Error(t, err)
+	mockRepo.AssertExpectations(t)
+}
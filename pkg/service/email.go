@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/chutommy/user-microservice/pkg/apierror"
+	"github.com/chutommy/user-microservice/pkg/certs"
+	"github.com/chutommy/user-microservice/pkg/grpc/userpb"
+	"github.com/chutommy/user-microservice/pkg/mailaddr"
+	"github.com/chutommy/user-microservice/pkg/repo"
+)
+
+// parseEmailRequest validates the user ID and the email of a request.
+func (u *UserServer) parseEmailRequest(ctx context.Context, id, email string) (uuid.UUID, mailaddr.Address, error) {
+	logger := ctxzap.Extract(ctx)
+
+	uid, err := parseID(ctx, "id", id)
+	if err != nil {
+		return uuid.Nil, mailaddr.Address{}, err
+	}
+
+	if email == "" {
+		logger.Info("empty email")
+		return uuid.Nil, mailaddr.Address{}, apierror.InvalidField("email", "%v: 'email' field", ErrEmptyField)
+	}
+
+	addr, err := u.normalizeEmail(ctx, "email", email)
+	if err != nil {
+		return uuid.Nil, mailaddr.Address{}, err
+	}
+
+	return uid, addr, nil
+}
+
+// listEmails returns all addresses of the user, the primary first.
+func (u *UserServer) listEmails(ctx context.Context, uid uuid.UUID) ([]*userpb.Email, error) {
+	logger := ctxzap.Extract(ctx)
+
+	emails, err := u.repo.ListEmails(ctx, uid)
+	if err != nil {
+		logger.Error("list emails", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "failed to list emails of user with id: %s", uid)
+	}
+
+	pbs := make([]*userpb.Email, 0, len(emails))
+	for _, e := range emails {
+		pbs = append(pbs, &userpb.Email{
+			Email:     e.Email,
+			Verified:  e.Verified,
+			Primary:   e.IsPrimary,
+			CreatedAt: timestamppb.New(e.CreatedAt),
+		})
+	}
+
+	return pbs, nil
+}
+
+func (u *UserServer) AddEmail(ctx context.Context, req *userpb.AddEmailRequest) (*userpb.AddEmailResponse, error) {
+	logger := ctxzap.Extract(ctx)
+
+	uid, email, err := u.parseEmailRequest(ctx, req.GetId(), req.GetEmail())
+	if err != nil {
+		return nil, err
+	}
+
+	// only the administrators vouch for the ownership
+	if req.GetVerified() {
+		if caller, ok := certs.CallerFromContext(ctx); !ok || !u.admins[caller] {
+			logger.Info("verified email added by a non-admin caller", zap.String("caller", caller))
+			return nil, status.Errorf(codes.PermissionDenied, "only the administrators may add verified emails")
+		}
+	}
+
+	// store email
+	_, err = u.repo.AddEmail(ctx, repo.AddEmailParams{
+		Email:          email.Email,
+		EmailCanonical: email.Canonical,
+		Verified:       req.GetVerified(),
+		UserID:         uid,
+	})
+	if err != nil {
+		if conflict := apierror.Conflict(err); conflict != nil {
+			logger.Info("email already exists", zap.Error(err))
+			return nil, conflict
+		}
+
+		code := codes.Internal
+		if errors.Is(err, sql.ErrNoRows) {
+			code = codes.NotFound
+		}
+
+		logger.Info("failed to add email", zap.Error(err))
+		return nil, status.Errorf(code, "failed to add email to user with id: %s", uid)
+	}
+
+	// construct response
+	emails, err := u.listEmails(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	resp := &userpb.AddEmailResponse{
+		Emails: emails,
+	}
+
+	return resp, nil
+}
+
+func (u *UserServer) RemoveEmail(ctx context.Context, req *userpb.RemoveEmailRequest) (*userpb.RemoveEmailResponse, error) {
+	logger := ctxzap.Extract(ctx)
+
+	uid, email, err := u.parseEmailRequest(ctx, req.GetId(), req.GetEmail())
+	if err != nil {
+		return nil, err
+	}
+
+	// remove email
+	affected, err := u.repo.RemoveEmail(ctx, repo.RemoveEmailParams{
+		UserID:         uid,
+		EmailCanonical: email.Canonical,
+	})
+	if err != nil {
+		logger.Error("failed to remove email", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "failed to remove email of user with id: %s", uid)
+	}
+	if affected == 0 {
+		// tell apart the primary address from an unknown one
+		user, err := u.repo.GetUser(ctx, uid)
+		if err == nil && user.EmailCanonical.String == email.Canonical {
+			logger.Info("primary email can not be removed")
+			return nil, status.Errorf(codes.FailedPrecondition, "primary email of user with id %s can not be removed", uid)
+		}
+
+		logger.Info("email to remove not found", zap.Error(err))
+		return nil, status.Errorf(codes.NotFound, "user with id %s has no email %s", uid, req.GetEmail())
+	}
+
+	// construct response
+	emails, err := u.listEmails(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	resp := &userpb.RemoveEmailResponse{
+		Emails: emails,
+	}
+
+	return resp, nil
+}
+
+func (u *UserServer) SetPrimaryEmail(ctx context.Context, req *userpb.SetPrimaryEmailRequest) (*userpb.SetPrimaryEmailResponse, error) {
+	logger := ctxzap.Extract(ctx)
+
+	uid, email, err := u.parseEmailRequest(ctx, req.GetId(), req.GetEmail())
+	if err != nil {
+		return nil, err
+	}
+
+	// switch primary email
+	_, err = u.repo.SetPrimaryEmail(ctx, repo.SetPrimaryEmailParams{
+		ID:             uid,
+		EmailCanonical: email.Canonical,
+	})
+	if err != nil {
+		code := codes.Internal
+
+		if errors.Is(err, sql.ErrNoRows) {
+			code = codes.NotFound
+		}
+
+		logger.Info("failed to set primary email", zap.Error(err))
+		return nil, status.Errorf(code, "failed to set primary email of user with id: %s", uid)
+	}
+
+	// construct response
+	emails, err := u.listEmails(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	resp := &userpb.SetPrimaryEmailResponse{
+		Emails: emails,
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,370 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/chutommy/user-microservice/pkg/certs"
+	"github.com/chutommy/user-microservice/pkg/grpc/userpb"
+	"github.com/chutommy/user-microservice/pkg/mocks"
+	"github.com/chutommy/user-microservice/pkg/repo"
+	"github.com/chutommy/user-microservice/pkg/service"
+)
+
+func TestUserServer_AddEmail(t *testing.T) {
+	t.Parallel()
+
+	uid := uuid.New()
+	admin := "spiffe://example.org/admin"
+
+	tests := []struct {
+		name      string
+		buildRepo func(q *mocks.Querier)
+		inpID     string
+		caller    string
+		email     string
+		expCode   codes.Code
+	}{
+		{
+			name: "ok",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("AddEmail", mock.Anything, repo.AddEmailParams{
+					Email:          "Work@Example.com",
+					EmailCanonical: "work@example.com",
+					Verified:       true,
+					UserID:         uid,
+				}).
+					Return(repo.UserEmail{Email: "work@example.com", UserID: uid, Verified: true}, nil).Once()
+				q.On("ListEmails", mock.Anything, uid).Return([]repo.UserEmail{
+					{Email: "home@example.com", UserID: uid, IsPrimary: true, CreatedAt: time.Now()},
+					{Email: "Work@Example.com", UserID: uid, Verified: true, CreatedAt: time.Now()},
+				}, nil).Once()
+			},
+			inpID:   uid.String(),
+			caller:  admin,
+			email:   " Work@Example.com ",
+			expCode: codes.OK,
+		},
+		{
+			name:      "empty email",
+			buildRepo: func(q *mocks.Querier) {},
+			inpID:     uid.String(),
+			caller:    admin,
+			email:     "",
+			expCode:   codes.InvalidArgument,
+		},
+		{
+			name:      "invalid email",
+			buildRepo: func(q *mocks.Querier) {},
+			inpID:     uid.String(),
+			caller:    admin,
+			email:     "Work <work@example.com>",
+			expCode:   codes.InvalidArgument,
+		},
+		{
+			name:      "invalid id",
+			buildRepo: func(q *mocks.Querier) {},
+			inpID:     "invalid_uuid",
+			caller:    admin,
+			email:     "work@example.com",
+			expCode:   codes.InvalidArgument,
+		},
+		{
+			name: "email taken",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("AddEmail", mock.Anything, mock.Anything).Return(repo.UserEmail{}, &pq.Error{Code: "23505"}).Once()
+			},
+			inpID:   uid.String(),
+			caller:  admin,
+			email:   "work@example.com",
+			expCode: codes.AlreadyExists,
+		},
+		{
+			name: "user not found",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("AddEmail", mock.Anything, mock.Anything).Return(repo.UserEmail{}, sql.ErrNoRows).Once()
+			},
+			inpID:   uid.String(),
+			caller:  admin,
+			email:   "work@example.com",
+			expCode: codes.NotFound,
+		},
+		{
+			name:      "verified by non-admin",
+			buildRepo: func(q *mocks.Querier) {},
+			inpID:     uid.String(),
+			caller:    "spiffe://example.org/frontend",
+			email:     "work@example.com",
+			expCode:   codes.PermissionDenied,
+		},
+		{
+			name:      "verified by unauthenticated caller",
+			buildRepo: func(q *mocks.Querier) {},
+			inpID:     uid.String(),
+			email:     "work@example.com",
+			expCode:   codes.PermissionDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// construct server
+			mockRepo := new(mocks.Querier)
+			tt.buildRepo(mockRepo)
+			server := service.NewUserServer(mockRepo, service.WithAdminIdentities(admin))
+
+			ctx := context.Background()
+			if tt.caller != "" {
+				ctx = certs.WithCaller(ctx, tt.caller)
+			}
+
+			// test method
+			resp, err := server.AddEmail(ctx, &userpb.AddEmailRequest{
+				Id:       tt.inpID,
+				Email:    tt.email,
+				Verified: true,
+			})
+			if tt.expCode == codes.OK {
+				require.NoError(t, err)
+				require.Len(t, resp.Emails, 2)
+				require.True(t, resp.Emails[0].Primary)
+				require.Equal(t, "Work@Example.com", resp.Emails[1].Email)
+			} else {
+				require.Error(t, err)
+				require.Nil(t, resp)
+
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, tt.expCode, st.Code())
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserServer_RemoveEmail(t *testing.T) {
+	t.Parallel()
+
+	uid := uuid.New()
+
+	tests := []struct {
+		name      string
+		buildRepo func(q *mocks.Querier)
+		email     string
+		expCode   codes.Code
+	}{
+		{
+			name: "ok",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("RemoveEmail", mock.Anything, repo.RemoveEmailParams{UserID: uid, EmailCanonical: "work@example.com"}).
+					Return(int64(1), nil).Once()
+				q.On("ListEmails", mock.Anything, uid).Return([]repo.UserEmail{
+					{Email: "home@example.com", UserID: uid, IsPrimary: true},
+				}, nil).Once()
+			},
+			email:   "WORK@example.com",
+			expCode: codes.OK,
+		},
+		{
+			name: "primary email",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("RemoveEmail", mock.Anything, mock.Anything).Return(int64(0), nil).Once()
+				q.On("GetUser", mock.Anything, uid).Return(repo.User{
+					ID:             uid,
+					Email:          "Home@example.com",
+					EmailCanonical: sql.NullString{String: "home@example.com", Valid: true},
+				}, nil).Once()
+			},
+			email:   "home@example.com",
+			expCode: codes.FailedPrecondition,
+		},
+		{
+			name: "unknown email",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("RemoveEmail", mock.Anything, mock.Anything).Return(int64(0), nil).Once()
+				q.On("GetUser", mock.Anything, uid).Return(repo.User{ID: uid, Email: "home@example.com"}, nil).Once()
+			},
+			email:   "other@example.com",
+			expCode: codes.NotFound,
+		},
+		{
+			name: "connection error",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("RemoveEmail", mock.Anything, mock.Anything).Return(int64(0), sql.ErrConnDone).Once()
+			},
+			email:   "work@example.com",
+			expCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// construct server
+			mockRepo := new(mocks.Querier)
+			tt.buildRepo(mockRepo)
+			server := service.NewUserServer(mockRepo)
+
+			// test method
+			resp, err := server.RemoveEmail(context.Background(), &userpb.RemoveEmailRequest{Id: uid.String(), Email: tt.email})
+			if tt.expCode == codes.OK {
+				require.NoError(t, err)
+				require.Len(t, resp.Emails, 1)
+			} else {
+				require.Error(t, err)
+				require.Nil(t, resp)
+
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, tt.expCode, st.Code())
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserServer_SetPrimaryEmail(t *testing.T) {
+	t.Parallel()
+
+	uid := uuid.New()
+
+	tests := []struct {
+		name      string
+		buildRepo func(q *mocks.Querier)
+		expCode   codes.Code
+	}{
+		{
+			name: "ok",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("SetPrimaryEmail", mock.Anything, repo.SetPrimaryEmailParams{ID: uid, EmailCanonical: "work@example.com"}).
+					Return(repo.User{ID: uid, Email: "work@example.com"}, nil).Once()
+				q.On("ListEmails", mock.Anything, uid).Return([]repo.UserEmail{
+					{Email: "work@example.com", UserID: uid, IsPrimary: true},
+					{Email: "home@example.com", UserID: uid},
+				}, nil).Once()
+			},
+			expCode: codes.OK,
+		},
+		{
+			name: "not an address of the user",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("SetPrimaryEmail", mock.Anything, mock.Anything).Return(repo.User{}, sql.ErrNoRows).Once()
+			},
+			expCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// construct server
+			mockRepo := new(mocks.Querier)
+			tt.buildRepo(mockRepo)
+			server := service.NewUserServer(mockRepo)
+
+			// test method
+			resp, err := server.SetPrimaryEmail(context.Background(), &userpb.SetPrimaryEmailRequest{Id: uid.String(), Email: "work@example.com"})
+			if tt.expCode == codes.OK {
+				require.NoError(t, err)
+				require.Equal(t, "work@example.com", resp.Emails[0].Email)
+				require.True(t, resp.Emails[0].Primary)
+			} else {
+				require.Error(t, err)
+				require.Nil(t, resp)
+
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, tt.expCode, st.Code())
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserServer_GetUser_ByEmail(t *testing.T) {
+	t.Parallel()
+
+	uid := uuid.New()
+
+	tests := []struct {
+		name      string
+		buildRepo func(q *mocks.Querier)
+		email     string
+		rules     bool
+		expCode   codes.Code
+	}{
+		{
+			name: "secondary email",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("GetUserByEmail", mock.Anything, "work@example.com").
+					Return(repo.User{ID: uid, Email: "home@example.com"}, nil).Once()
+			},
+			email:   "Work@EXAMPLE.com",
+			expCode: codes.OK,
+		},
+		{
+			name: "gmail alias",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("GetUserByEmail", mock.Anything, "johndoe@gmail.com").
+					Return(repo.User{ID: uid, Email: "home@example.com"}, nil).Once()
+			},
+			email:   "John.Doe+news@googlemail.com",
+			rules:   true,
+			expCode: codes.OK,
+		},
+		{
+			name:      "invalid email",
+			buildRepo: func(q *mocks.Querier) {},
+			email:     "john@@example.com",
+			expCode:   codes.InvalidArgument,
+		},
+		{
+			name: "not found",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("GetUserByEmail", mock.Anything, mock.Anything).Return(repo.User{}, sql.ErrNoRows).Once()
+			},
+			email:   "nobody@example.com",
+			expCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// construct server
+			mockRepo := new(mocks.Querier)
+			tt.buildRepo(mockRepo)
+			server := service.NewUserServer(mockRepo, service.WithEmailProviderRules(tt.rules))
+
+			// test method
+			resp, err := server.GetUser(context.Background(), &userpb.GetUserRequest{Email: tt.email})
+			if tt.expCode == codes.OK {
+				require.NoError(t, err)
+				require.Equal(t, uid.String(), resp.User.Id)
+				require.Equal(t, "home@example.com", resp.User.Email)
+			} else {
+				require.Error(t, err)
+				require.Nil(t, resp)
+
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, tt.expCode, st.Code())
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
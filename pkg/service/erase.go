@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/chutommy/user-microservice/pkg/event"
+	"github.com/chutommy/user-microservice/pkg/grpc/userpb"
+	"github.com/chutommy/user-microservice/pkg/repo"
+)
+
+// erasureEventBatch is the maximum number of the erasure events republished
+// at once.
+const erasureEventBatch = 100
+
+func (u *UserServer) EraseUser(ctx context.Context, req *userpb.EraseUserRequest) (*userpb.EraseUserResponse, error) {
+	logger := ctxzap.Extract(ctx)
+
+	// parse ID
+	uid, err := parseID(ctx, "id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	// remember the avatar and the data exports, their blobs are removed once
+	// the user is erased
+	var avatarKey sql.NullString
+	if u.blobs != nil {
+		if user, err := u.repo.GetUser(ctx, uid); err == nil {
+			avatarKey = user.AvatarKey
+		}
+	}
+	exportKeys := u.dataExportKeys(ctx, uid)
+
+	// anonymize user
+	arg := repo.EraseUserParams{
+		ID:     uuid.New(),
+		Reason: req.GetReason(),
+		UserID: uid,
+	}
+	receipt, err := u.repo.EraseUser(ctx, arg)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, u.eraseNoRowsError(ctx, uid)
+		}
+
+		logger.Error("failed to erase user", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "failed to erase user with id: %s", uid)
+	}
+
+	if avatarKey.Valid {
+		u.deleteAvatar(ctx, avatarKey.String)
+	}
+	u.deleteDataExports(ctx, exportKeys)
+
+	// notify other systems
+	if err = u.publishErasure(ctx, receipt); err != nil {
+		// the erasure is committed, RunErasureEvents publishes the event later
+		logger.Error("failed to publish erasure event", zap.String("receipt_id", receipt.ID.String()), zap.Error(err))
+	}
+
+	logger.Info("user erased", zap.String("receipt_id", receipt.ID.String()))
+
+	// construct response
+	resp := &userpb.EraseUserResponse{
+		Id:        receipt.UserID.String(),
+		ReceiptId: receipt.ID.String(),
+		ErasedAt:  timestamppb.New(receipt.ErasedAt),
+	}
+
+	return resp, nil
+}
+
+// eraseNoRowsError tells apart a missing user from an already erased one.
+func (u *UserServer) eraseNoRowsError(ctx context.Context, uid uuid.UUID) error {
+	logger := ctxzap.Extract(ctx)
+
+	user, err := u.repo.GetUser(ctx, uid)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		logger.Info("user to erase not found")
+		return status.Errorf(codes.NotFound, "failed to erase user with id: %s", uid)
+	case err != nil:
+		logger.Error("retrieve user", zap.Error(err))
+		return status.Errorf(codes.Internal, "failed to erase user with id: %s", uid)
+	case user.ErasedAt.Valid:
+		logger.Info("user already erased")
+		return status.Errorf(codes.FailedPrecondition, "user with id %s is already erased", uid)
+	default:
+		logger.Error("user was not erased")
+		return status.Errorf(codes.Internal, "failed to erase user with id: %s", uid)
+	}
+}
+
+// publishErasure publishes the event of the erasure and marks its receipt
+// as published.
+func (u *UserServer) publishErasure(ctx context.Context, receipt repo.ErasureReceipt) error {
+	e := event.Event{
+		Type:       event.TypeUserErased,
+		UserID:     receipt.UserID,
+		OccurredAt: receipt.ErasedAt,
+		Data: map[string]string{
+			"receipt_id": receipt.ID.String(),
+		},
+	}
+	if err := u.publisher.Publish(ctx, e); err != nil {
+		return err
+	}
+
+	return u.repo.MarkErasureReceiptPublished(ctx, receipt.ID)
+}
+
+// RunErasureEvents republishes the erasure events which failed to be
+// delivered every interval until the context is done. Only the erasures
+// older than the interval are republished, so an event may be delivered
+// twice only if marking its receipt fails; the consumers deduplicate the
+// events by the receipt_id.
+func (u *UserServer) RunErasureEvents(ctx context.Context, interval time.Duration) {
+	logger := ctxzap.Extract(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		receipts, err := u.repo.ListUnpublishedErasureReceipts(ctx, repo.ListUnpublishedErasureReceiptsParams{
+			ErasedBefore: time.Now().Add(-interval),
+			RowLimit:     erasureEventBatch,
+		})
+		if err != nil {
+			logger.Error("failed to list the unpublished erasure receipts", zap.Error(err))
+			continue
+		}
+
+		for _, r := range receipts {
+			if err = u.publishErasure(ctx, r); err != nil {
+				logger.Error("failed to republish erasure event", zap.String("receipt_id", r.ID.String()), zap.Error(err))
+				break
+			}
+			logger.Debug("erasure event republished", zap.String("receipt_id", r.ID.String()))
+		}
+	}
+}
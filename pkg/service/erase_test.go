@@ -0,0 +1,244 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/chutommy/user-microservice/pkg/blob"
+	"github.com/chutommy/user-microservice/pkg/event"
+	"github.com/chutommy/user-microservice/pkg/grpc/userpb"
+	"github.com/chutommy/user-microservice/pkg/mocks"
+	"github.com/chutommy/user-microservice/pkg/repo"
+	"github.com/chutommy/user-microservice/pkg/service"
+)
+
+// recordingPublisher keeps the published events.
+type recordingPublisher struct {
+	events []event.Event
+	err    error
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, e event.Event) error {
+	p.events = append(p.events, e)
+	return p.err
+}
+
+func TestUserServer_EraseUser(t *testing.T) {
+	t.Parallel()
+
+	uid := uuid.New()
+	receipt := repo.ErasureReceipt{
+		ID:       uuid.New(),
+		UserID:   uid,
+		Reason:   "user request",
+		ErasedAt: time.Now(),
+	}
+
+	tests := []struct {
+		name       string
+		buildRepo  func(q *mocks.Querier)
+		publishErr error
+		inpID      string
+		expCode    codes.Code
+	}{
+		{
+			name: "ok",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("EraseUser", mock.Anything, mock.MatchedBy(func(arg repo.EraseUserParams) bool {
+					return arg.UserID == uid && arg.Reason == "user request"
+				})).Return(receipt, nil).Once()
+				q.On("MarkErasureReceiptPublished", mock.Anything, receipt.ID).Return(nil).Once()
+			},
+			inpID:   uid.String(),
+			expCode: codes.OK,
+		},
+		{
+			name: "event not delivered",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("EraseUser", mock.Anything, mock.Anything).Return(receipt, nil).Once()
+			},
+			publishErr: errors.New("connection refused"),
+			inpID:      uid.String(),
+			expCode:    codes.OK,
+		},
+		{
+			name:      "empty id",
+			buildRepo: func(q *mocks.Querier) {},
+			inpID:     "",
+			expCode:   codes.InvalidArgument,
+		},
+		{
+			name:      "invalid id",
+			buildRepo: func(q *mocks.Querier) {},
+			inpID:     "invalid_uuid",
+			expCode:   codes.InvalidArgument,
+		},
+		{
+			name: "user not found",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("EraseUser", mock.Anything, mock.Anything).Return(repo.ErasureReceipt{}, sql.ErrNoRows).Once()
+				q.On("GetUser", mock.Anything, uid).Return(repo.User{}, sql.ErrNoRows).Once()
+			},
+			inpID:   uid.String(),
+			expCode: codes.NotFound,
+		},
+		{
+			name: "already erased",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("EraseUser", mock.Anything, mock.Anything).Return(repo.ErasureReceipt{}, sql.ErrNoRows).Once()
+				q.On("GetUser", mock.Anything, uid).Return(repo.User{
+					ID:       uid,
+					ErasedAt: sql.NullTime{Time: time.Now(), Valid: true},
+				}, nil).Once()
+			},
+			inpID:   uid.String(),
+			expCode: codes.FailedPrecondition,
+		},
+		{
+			name: "connection error",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("EraseUser", mock.Anything, mock.Anything).Return(repo.ErasureReceipt{}, sql.ErrConnDone).Once()
+			},
+			inpID:   uid.String(),
+			expCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// construct server
+			mockRepo := new(mocks.Querier)
+			tt.buildRepo(mockRepo)
+			publisher := &recordingPublisher{err: tt.publishErr}
+			server := service.NewUserServer(mockRepo, service.WithPublisher(publisher))
+
+			// build request
+			req := &userpb.EraseUserRequest{Id: tt.inpID, Reason: "user request"}
+
+			// test method
+			resp, err := server.EraseUser(context.Background(), req)
+			if tt.expCode == codes.OK {
+				require.NoError(t, err)
+				require.NotNil(t, resp)
+
+				require.Equal(t, uid.String(), resp.Id)
+				require.Equal(t, receipt.ID.String(), resp.ReceiptId)
+
+				require.Len(t, publisher.events, 1)
+				require.Equal(t, event.TypeUserErased, publisher.events[0].Type)
+				require.Equal(t, uid, publisher.events[0].UserID)
+			} else {
+				require.Error(t, err)
+				require.Nil(t, resp)
+				require.Empty(t, publisher.events)
+
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, tt.expCode, st.Code())
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserServer_EraseUser_DataExports(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "blobs")
+	require.NoError(t, err)
+	blobs, err := blob.NewFileStore(dir)
+	require.NoError(t, err)
+
+	uid := uuid.New()
+	export := repo.DataExport{ID: uuid.New(), UserID: uid, CreatedAt: time.Now()}
+	archive := "data-exports/" + uid.String() + "/" + export.ID.String() + ".json"
+	require.NoError(t, blobs.Put(context.Background(), archive, strings.NewReader("{}")))
+
+	// the erasure deletes the exports
+	erased := false
+	mockRepo := new(mocks.Querier)
+	mockRepo.On("GetUser", mock.Anything, uid).Return(repo.User{ID: uid}, nil).Once()
+	mockRepo.On("ListDataExportBlobKeys", mock.Anything, uid).Return([]string{archive}, nil).Once()
+	mockRepo.On("EraseUser", mock.Anything, mock.AnythingOfType("repo.EraseUserParams")).
+		Run(func(mock.Arguments) { erased = true }).
+		Return(repo.ErasureReceipt{ID: uuid.New(), UserID: uid, ErasedAt: time.Now()}, nil).Once()
+	mockRepo.On("MarkErasureReceiptPublished", mock.Anything, mock.Anything).Return(nil).Once()
+	mockRepo.On("GetDataExport", mock.Anything, export.ID).
+		Return(func(context.Context, uuid.UUID) repo.DataExport {
+			return export
+		}, func(context.Context, uuid.UUID) error {
+			if erased {
+				return sql.ErrNoRows
+			}
+			return nil
+		})
+	server := service.NewUserServer(mockRepo, service.WithBlobStore(blobs))
+
+	_, err = server.EraseUser(context.Background(), &userpb.EraseUserRequest{Id: uid.String()})
+	require.NoError(t, err)
+
+	_, err = blobs.Get(context.Background(), archive)
+	require.Error(t, err)
+	_, err = server.GetDataExport(context.Background(), &userpb.GetDataExportRequest{Id: export.ID.String()})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserServer_RunErasureEvents(t *testing.T) {
+	t.Parallel()
+
+	delivered := repo.ErasureReceipt{ID: uuid.New(), UserID: uuid.New(), ErasedAt: time.Now().Add(-time.Hour)}
+	failed := repo.ErasureReceipt{ID: uuid.New(), UserID: uuid.New(), ErasedAt: time.Now().Add(-time.Hour)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the first event is delivered, the rest are retried on the next tick
+	mockRepo := new(mocks.Querier)
+	mockRepo.On("ListUnpublishedErasureReceipts", mock.Anything, mock.MatchedBy(func(arg repo.ListUnpublishedErasureReceiptsParams) bool {
+		return arg.ErasedBefore.Before(time.Now()) && arg.RowLimit > 0
+	})).Return([]repo.ErasureReceipt{delivered, failed}, nil).Once()
+	mockRepo.On("ListUnpublishedErasureReceipts", mock.Anything, mock.Anything).
+		Run(func(mock.Arguments) { cancel() }).
+		Return([]repo.ErasureReceipt{}, nil)
+	mockRepo.On("MarkErasureReceiptPublished", mock.Anything, delivered.ID).Return(nil).Once()
+
+	publisher := &failingPublisher{fail: failed.UserID}
+	server := service.NewUserServer(mockRepo, service.WithPublisher(publisher))
+
+	server.RunErasureEvents(ctx, time.Millisecond)
+
+	require.Len(t, publisher.events, 2)
+	require.Equal(t, event.TypeUserErased, publisher.events[0].Type)
+	require.Equal(t, delivered.ID.String(), publisher.events[0].Data["receipt_id"])
+	mockRepo.AssertExpectations(t)
+}
+
+// failingPublisher fails to deliver the events of a user.
+type failingPublisher struct {
+	recordingPublisher
+	fail uuid.UUID
+}
+
+func (p *failingPublisher) Publish(ctx context.Context, e event.Event) error {
+	_ = p.recordingPublisher.Publish(ctx, e)
+	if e.UserID == p.fail {
+		return errors.New("connection refused")
+	}
+
+	return nil
+}
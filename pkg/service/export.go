@@ -0,0 +1,143 @@
+package service
+
+import (
+	"bufio"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/chutommy/user-microservice/pkg/apierror"
+	"github.com/chutommy/user-microservice/pkg/attribute"
+	"github.com/chutommy/user-microservice/pkg/export"
+	"github.com/chutommy/user-microservice/pkg/grpc/userpb"
+	"github.com/chutommy/user-microservice/pkg/repo"
+)
+
+var (
+	// ExportBatchSize is the number of users retrieved from the repo at once.
+	ExportBatchSize int32 = 500
+
+	// ExportChunkSize is the maximal size of a single streamed chunk in bytes.
+	ExportChunkSize = 64 * 1024
+)
+
+// chunkWriter sends everything written to it as a single chunk of the export stream.
+type chunkWriter struct {
+	stream userpb.UserService_ExportUsersServer
+}
+
+func (w chunkWriter) Write(p []byte) (int, error) {
+	// the stream may hold the message after Send returns
+	chunk := make([]byte, len(p))
+	copy(chunk, p)
+
+	if err := w.stream.Send(&userpb.ExportUsersResponse{Chunk: chunk}); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+func exportFormat(f userpb.ExportUsersRequest_Format) (export.Format, error) {
+	switch f {
+	case userpb.ExportUsersRequest_CSV:
+		return export.CSV, nil
+	case userpb.ExportUsersRequest_JSONL:
+		return export.JSONL, nil
+	case userpb.ExportUsersRequest_PARQUET:
+		return export.Parquet, nil
+	default:
+		return 0, export.ErrUnknownFormat
+	}
+}
+
+func (u *UserServer) ExportUsers(req *userpb.ExportUsersRequest, stream userpb.UserService_ExportUsersServer) error {
+	ctx := stream.Context()
+	logger := ctxzap.Extract(ctx)
+
+	format, err := exportFormat(req.GetFormat())
+	if err != nil {
+		logger.Info("unknown export format", zap.Stringer("format", req.GetFormat()))
+		return apierror.InvalidField("format", "unsupported format: %v", req.GetFormat())
+	}
+
+	// build filter
+	arg := repo.ListUsersParams{
+		AfterID:       uuid.Nil,
+		Gender:        int16(req.GetGender()),
+		RowLimit:      ExportBatchSize,
+		IncludeErased: req.GetIncludeErased(),
+	}
+	if ca := req.GetCreatedAfter(); ca != nil {
+		arg.CreatedAfter = sql.NullTime{Time: ca.AsTime(), Valid: true}
+	}
+	if cb := req.GetCreatedBefore(); cb != nil {
+		arg.CreatedBefore = sql.NullTime{Time: cb.AsTime(), Valid: true}
+	}
+
+	filters := make(map[string]interface{}, len(req.GetAttributeFilters()))
+	for path, v := range req.GetAttributeFilters() {
+		filters[path] = v.AsInterface()
+	}
+	arg.AttributesFilter, err = attribute.Filter(filters, u.indexedAttributes)
+	if err != nil {
+		logger.Info("invalid attribute filter", zap.Error(err))
+		return apierror.InvalidField("attribute_filters", "invalid attribute filter: %v", err)
+	}
+
+	bw := bufio.NewWriterSize(chunkWriter{stream: stream}, ExportChunkSize)
+	enc, err := export.NewEncoder(format, bw, req.GetColumns())
+	if err != nil {
+		logger.Info("invalid export columns", zap.Strings("columns", req.GetColumns()), zap.Error(err))
+		return apierror.InvalidField("columns", "invalid columns: %v", err)
+	}
+
+	// page through the users
+	var count int
+	for {
+		users, err := u.repo.ListUsers(ctx, arg)
+		if err != nil {
+			logger.Error("failed to list users", zap.Error(err))
+			return status.Errorf(codes.Internal, "failed to retrieve users")
+		}
+
+		for _, user := range users {
+			if err = enc.Encode(user); err != nil {
+				return exportStreamError(logger, err)
+			}
+		}
+		count += len(users)
+
+		if len(users) < int(arg.RowLimit) {
+			break
+		}
+		arg.AfterID = users[len(users)-1].ID
+	}
+
+	if err = enc.Close(); err != nil {
+		return exportStreamError(logger, err)
+	}
+	if err = bw.Flush(); err != nil {
+		return exportStreamError(logger, err)
+	}
+
+	logger.Info("users exported", zap.Int("count", count))
+
+	return nil
+}
+
+// exportStreamError converts an error which occurred while writing the stream.
+func exportStreamError(logger *zap.Logger, err error) error {
+	// failed sends are already gRPC status errors
+	if _, ok := status.FromError(err); ok {
+		logger.Info("export stream interrupted", zap.Error(err))
+		return err
+	}
+
+	logger.Error("failed to encode users", zap.Error(err))
+	return status.Errorf(codes.Internal, "failed to encode users")
+}
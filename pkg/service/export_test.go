@@ -0,0 +1,185 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/chutommy/user-microservice/pkg/grpc/userpb"
+	"github.com/chutommy/user-microservice/pkg/mocks"
+	"github.com/chutommy/user-microservice/pkg/repo"
+	"github.com/chutommy/user-microservice/pkg/service"
+)
+
+// exportStream collects the streamed export chunks.
+type exportStream struct {
+	grpc.ServerStream
+	buf bytes.Buffer
+}
+
+func (s *exportStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *exportStream) Send(resp *userpb.ExportUsersResponse) error {
+	_, err := s.buf.Write(resp.GetChunk())
+	return err
+}
+
+func TestUserServer_ExportUsers(t *testing.T) {
+	t.Parallel()
+
+	// two pages of users
+	batch := int(service.ExportBatchSize)
+	users := make([]repo.User, batch+1)
+	for i := range users {
+		u := randomUser()
+		users[i] = repo.User{
+			ID:             uuid.MustParse(u.Id),
+			Email:          u.Email,
+			HashedPassword: "secret",
+			FirstName:      u.FirstName,
+			LastName:       u.LastName,
+		}
+	}
+
+	tests := []struct {
+		name      string
+		buildRepo func(q *mocks.Querier)
+		req       *userpb.ExportUsersRequest
+		expRows   int
+		expCode   codes.Code
+	}{
+		{
+			name: "ok",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("ListUsers", mock.Anything, mock.MatchedBy(func(arg repo.ListUsersParams) bool {
+					return arg.AfterID == uuid.Nil && !arg.IncludeErased
+				})).Return(users[:batch], nil).Once()
+				q.On("ListUsers", mock.Anything, mock.MatchedBy(func(arg repo.ListUsersParams) bool {
+					return arg.AfterID == users[batch-1].ID
+				})).Return(users[batch:], nil).Once()
+			},
+			req: &userpb.ExportUsersRequest{
+				Format:  userpb.ExportUsersRequest_CSV,
+				Columns: []string{"id", "email"},
+			},
+			expRows: batch + 1,
+			expCode: codes.OK,
+		},
+		{
+			name: "empty",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("ListUsers", mock.Anything, mock.Anything).Return([]repo.User{}, nil).Once()
+			},
+			req:     &userpb.ExportUsersRequest{Format: userpb.ExportUsersRequest_CSV},
+			expRows: 0,
+			expCode: codes.OK,
+		},
+		{
+			name: "include erased",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("ListUsers", mock.Anything, mock.MatchedBy(func(arg repo.ListUsersParams) bool {
+					return arg.IncludeErased
+				})).Return([]repo.User{}, nil).Once()
+			},
+			req: &userpb.ExportUsersRequest{
+				Format:        userpb.ExportUsersRequest_CSV,
+				IncludeErased: true,
+			},
+			expRows: 0,
+			expCode: codes.OK,
+		},
+		{
+			name:      "hashed password column",
+			buildRepo: func(q *mocks.Querier) {},
+			req: &userpb.ExportUsersRequest{
+				Format:  userpb.ExportUsersRequest_JSONL,
+				Columns: []string{"id", "hashed_password"},
+			},
+			expCode: codes.InvalidArgument,
+		},
+		{
+			name:      "unknown format",
+			buildRepo: func(q *mocks.Querier) {},
+			req:       &userpb.ExportUsersRequest{Format: userpb.ExportUsersRequest_Format(42)},
+			expCode:   codes.InvalidArgument,
+		},
+		{
+			name: "attribute filter",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("ListUsers", mock.Anything, mock.MatchedBy(func(arg repo.ListUsersParams) bool {
+					return string(arg.AttributesFilter) == `{"company":{"name":"ACME"}}`
+				})).Return([]repo.User{}, nil).Once()
+			},
+			req: &userpb.ExportUsersRequest{
+				Format: userpb.ExportUsersRequest_CSV,
+				AttributeFilters: map[string]*structpb.Value{
+					"company.name": structpb.NewStringValue("ACME"),
+				},
+			},
+			expRows: 0,
+			expCode: codes.OK,
+		},
+		{
+			name:      "attribute not indexed",
+			buildRepo: func(q *mocks.Querier) {},
+			req: &userpb.ExportUsersRequest{
+				Format: userpb.ExportUsersRequest_CSV,
+				AttributeFilters: map[string]*structpb.Value{
+					"employee_number": structpb.NewNumberValue(42),
+				},
+			},
+			expCode: codes.InvalidArgument,
+		},
+		{
+			name: "connection error",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("ListUsers", mock.Anything, mock.Anything).Return(nil, sql.ErrConnDone).Once()
+			},
+			req:     &userpb.ExportUsersRequest{Format: userpb.ExportUsersRequest_CSV},
+			expCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// construct server
+			mockRepo := new(mocks.Querier)
+			tt.buildRepo(mockRepo)
+			server := service.NewUserServer(mockRepo, service.WithIndexedAttributes([]string{"company.name"}))
+
+			// test method
+			stream := &exportStream{}
+			err := server.ExportUsers(tt.req, stream)
+			if tt.expCode == codes.OK {
+				require.NoError(t, err)
+
+				require.NotContains(t, stream.buf.String(), "secret")
+
+				recs, err := csv.NewReader(&stream.buf).ReadAll()
+				require.NoError(t, err)
+				require.Len(t, recs, tt.expRows+1)
+			} else {
+				require.Error(t, err)
+
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, tt.expCode, st.Code())
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
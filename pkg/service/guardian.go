@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/chutommy/user-microservice/pkg/age"
+	"github.com/chutommy/user-microservice/pkg/apierror"
+	"github.com/chutommy/user-microservice/pkg/event"
+	"github.com/chutommy/user-microservice/pkg/grpc/userpb"
+	"github.com/chutommy/user-microservice/pkg/repo"
+	"github.com/chutommy/user-microservice/pkg/util"
+)
+
+// GuardianConsentTTL is the time a guardian has to confirm a consent request.
+const GuardianConsentTTL = 7 * 24 * time.Hour
+
+// cleanupTimeout bounds the withdrawal of a consent request whose guardian was
+// not notified, which must not be canceled with the call.
+const cleanupTimeout = 5 * time.Second
+
+// residenceCountry returns the country of the phone region, an empty string
+// for the region "001" of the non-geographic numbers, e.g. +800.
+func residenceCountry(region string) string {
+	if age.ValidateCountry(region) != nil {
+		return ""
+	}
+	return region
+}
+
+// updatedState returns the account state after a change of the birthday, the
+// country or the phone number of the user. The state is kept if the policy
+// requires no consent or the birthday is unknown.
+func (u *UserServer) updatedState(ctx context.Context, uid uuid.UUID, birthday time.Time, country, phoneCountry string) (sql.NullInt16, error) {
+	logger := ctxzap.Extract(ctx)
+
+	if u.agePolicy.IsZero() {
+		return sql.NullInt16{}, nil
+	}
+
+	current, err := u.repo.GetUser(ctx, uid)
+	if err != nil {
+		return sql.NullInt16{}, retrieveUserError(ctx, err, "id", uid.String())
+	}
+	if birthday.IsZero() {
+		if !current.BirthDay.Valid {
+			return sql.NullInt16{}, nil
+		}
+		birthday = current.BirthDay.Time
+	}
+	if country == "" {
+		country = current.Country.String
+	}
+	if country == "" {
+		country = residenceCountry(phoneCountry)
+	}
+	if country == "" {
+		country = residenceCountry(current.PhoneCountry.String)
+	}
+
+	state := sql.NullInt16{Int16: int16(userpb.User_ACTIVE), Valid: true}
+	if !u.agePolicy.RequiresConsent(birthday, country, time.Now()) {
+		return state, nil
+	}
+
+	consented, err := u.repo.HasGuardianConsent(ctx, uid)
+	if err != nil {
+		logger.Error("failed to check guardian consent", zap.Error(err))
+		return sql.NullInt16{}, status.Errorf(codes.Internal, "failed to check guardian consent of user with id: %s", uid)
+	}
+	if !consented {
+		logger.Info("guardian consent required", zap.String("country", country))
+		state.Int16 = int16(userpb.User_PENDING_GUARDIAN_CONSENT)
+	}
+
+	return state, nil
+}
+
+func (u *UserServer) RequestGuardianConsent(ctx context.Context, req *userpb.RequestGuardianConsentRequest) (*userpb.RequestGuardianConsentResponse, error) {
+	logger := ctxzap.Extract(ctx)
+
+	// parse IDs
+	minorID, err := parseID(ctx, "id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	guardianID, err := parseID(ctx, "guardian_id", req.GetGuardianId())
+	if err != nil {
+		return nil, err
+	}
+	if minorID == guardianID {
+		logger.Info("guardian of oneself")
+		return nil, apierror.InvalidField("guardian_id", "user can not be their own guardian")
+	}
+
+	// check both accounts
+	minor, err := u.repo.GetUser(ctx, minorID)
+	if err != nil {
+		return nil, retrieveUserError(ctx, err, "id", minorID.String())
+	}
+	if minor.State != int16(userpb.User_PENDING_GUARDIAN_CONSENT) {
+		logger.Info("guardian consent not required")
+		return nil, status.Errorf(codes.FailedPrecondition, "user with id %s does not need a guardian consent", minorID)
+	}
+
+	guardian, err := u.repo.GetUser(ctx, guardianID)
+	if err != nil {
+		return nil, retrieveUserError(ctx, err, "guardian_id", guardianID.String())
+	}
+	switch {
+	case guardian.ErasedAt.Valid || guardian.State != int16(userpb.User_ACTIVE):
+		logger.Info("inactive guardian")
+		return nil, status.Errorf(codes.FailedPrecondition, "guardian with id %s is not active", guardianID)
+	case !guardian.BirthDay.Valid || age.Of(guardian.BirthDay.Time, time.Now()) < age.Adult:
+		logger.Info("guardian is not an adult")
+		return nil, status.Errorf(codes.FailedPrecondition, "guardian with id %s is not known to be an adult", guardianID)
+	}
+
+	// store consent request
+	consent, err := u.repo.CreateGuardianConsent(ctx, repo.CreateGuardianConsentParams{
+		ID:         uuid.New(),
+		GuardianID: guardianID,
+		ExpiresAt:  time.Now().Add(GuardianConsentTTL),
+		MinorID:    minorID,
+	})
+	if err != nil {
+		code := codes.Internal
+
+		if errors.Is(err, sql.ErrNoRows) {
+			code = codes.NotFound
+		}
+
+		logger.Info("failed to create guardian consent", zap.Error(err))
+		return nil, status.Errorf(code, "failed to request guardian consent for user with id: %s", minorID)
+	}
+
+	// notify the guardian
+	e := event.Event{
+		Type:       event.TypeGuardianConsentRequested,
+		UserID:     guardianID,
+		OccurredAt: consent.CreatedAt,
+		Data: map[string]string{
+			"consent_id": consent.ID.String(),
+			"minor_id":   minorID.String(),
+		},
+	}
+	if err = u.publisher.Publish(ctx, e); err != nil {
+		logger.Error("failed to publish guardian consent event", zap.String("consent_id", consent.ID.String()), zap.Error(err))
+
+		// the guardian can not learn the consent ID, the request is withdrawn
+		// so that a retry does not leave a duplicate behind
+		dctx, cancel := context.WithTimeout(util.WithoutCancel(ctx), cleanupTimeout)
+		defer cancel()
+		if derr := u.repo.DeleteGuardianConsent(dctx, consent.ID); derr != nil {
+			logger.Error("failed to delete guardian consent", zap.String("consent_id", consent.ID.String()), zap.Error(derr))
+		}
+
+		return nil, status.Errorf(codes.Unavailable, "failed to notify guardian with id: %s", guardianID)
+	}
+
+	// construct response
+	resp := &userpb.RequestGuardianConsentResponse{
+		ExpiresAt: timestamppb.New(consent.ExpiresAt),
+		ConsentId: consent.ID.String(),
+	}
+
+	return resp, nil
+}
+
+func (u *UserServer) ConfirmGuardianConsent(ctx context.Context, req *userpb.ConfirmGuardianConsentRequest) (*userpb.ConfirmGuardianConsentResponse, error) {
+	logger := ctxzap.Extract(ctx)
+
+	// parse IDs
+	consentID, err := parseID(ctx, "consent_id", req.GetConsentId())
+	if err != nil {
+		return nil, err
+	}
+	guardianID, err := parseID(ctx, "guardian_id", req.GetGuardianId())
+	if err != nil {
+		return nil, err
+	}
+
+	// confirm consent
+	consent, err := u.repo.ConfirmGuardianConsent(ctx, repo.ConfirmGuardianConsentParams{
+		ID:         consentID,
+		GuardianID: guardianID,
+	})
+	if err != nil {
+		code := codes.Internal
+
+		// unknown, expired, already confirmed or requested from another guardian
+		if errors.Is(err, sql.ErrNoRows) {
+			code = codes.NotFound
+		}
+
+		logger.Info("failed to confirm guardian consent", zap.Error(err))
+		return nil, status.Errorf(code, "failed to confirm guardian consent with id: %s", consentID)
+	}
+
+	logger.Info("guardian consent confirmed", zap.String("minor_id", consent.MinorID.String()))
+
+	// construct response
+	resp := &userpb.ConfirmGuardianConsentResponse{
+		Id: consent.MinorID.String(),
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,375 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/type/date"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/chutommy/user-microservice/pkg/age"
+	"github.com/chutommy/user-microservice/pkg/event"
+	"github.com/chutommy/user-microservice/pkg/grpc/userpb"
+	"github.com/chutommy/user-microservice/pkg/mocks"
+	"github.com/chutommy/user-microservice/pkg/repo"
+	"github.com/chutommy/user-microservice/pkg/service"
+)
+
+// yearsAgo returns the date of birth of a person who is the given number of years old.
+func yearsAgo(years int) time.Time {
+	now := time.Now().UTC()
+	return time.Date(now.Year()-years, now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, -1)
+}
+
+var testAgePolicy = age.Policy{Default: 16, Countries: map[string]int{"US": 13}}
+
+func TestUserServer_RegisterUser_MinimumAge(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		age        int
+		country    string
+		phone      string
+		expCountry string
+		expState   userpb.User_State
+		expCode    codes.Code
+	}{
+		{"adult", 30, "CZ", "", "CZ", userpb.User_ACTIVE, codes.OK},
+		{"minor", 14, "CZ", "", "CZ", userpb.User_PENDING_GUARDIAN_CONSENT, codes.OK},
+		{"minor of unknown country", 14, "", "", "", userpb.User_PENDING_GUARDIAN_CONSENT, codes.OK},
+		{"old enough in country", 14, "us", "", "US", userpb.User_ACTIVE, codes.OK},
+		{"old enough in country of phone", 14, "", "+1 201 555 0123", "US", userpb.User_ACTIVE, codes.OK},
+		{"non-geographic phone", 14, "", "+800 1234 5678", "", userpb.User_PENDING_GUARDIAN_CONSENT, codes.OK},
+		{"invalid country", 30, "XX", "", "", 0, codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// construct server
+			mockRepo := new(mocks.Querier)
+			if tt.expCode == codes.OK {
+				mockRepo.On("CreateUser", mock.Anything, mock.MatchedBy(func(arg repo.CreateUserParams) bool {
+					return arg.State == int16(tt.expState) &&
+						arg.Country.String == tt.expCountry && arg.Country.Valid == (tt.expCountry != "")
+				})).Return(repo.User{ID: uuid.New(), State: int16(tt.expState)}, nil).Once()
+			}
+			server := service.NewUserServer(mockRepo, service.WithMinimumAge(testAgePolicy))
+
+			// test method
+			bd := yearsAgo(tt.age)
+			resp, err := server.RegisterUser(context.Background(), &userpb.RegisterUserRequest{
+				User: &userpb.User{
+					Email:     "kid@example.com",
+					Password:  "secret",
+					FirstName: "John",
+					LastName:  "Doe",
+					BirthDate: &date.Date{Year: int32(bd.Year()), Month: int32(bd.Month()), Day: int32(bd.Day())},
+					Country:   tt.country,
+					Phone:     tt.phone,
+				},
+			})
+			if tt.expCode == codes.OK {
+				require.NoError(t, err)
+				require.Equal(t, tt.expState, resp.State)
+			} else {
+				require.Error(t, err)
+				require.Nil(t, resp)
+
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, tt.expCode, st.Code())
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserServer_UpdateUser_MinimumAge(t *testing.T) {
+	t.Parallel()
+
+	uid := uuid.New()
+
+	tests := []struct {
+		name      string
+		age       int
+		consented bool
+		expState  userpb.User_State
+	}{
+		{"adult", 30, false, userpb.User_ACTIVE},
+		{"minor without consent", 14, false, userpb.User_PENDING_GUARDIAN_CONSENT},
+		{"minor with consent", 14, true, userpb.User_ACTIVE},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// construct server
+			mockRepo := new(mocks.Querier)
+			mockRepo.On("GetUser", mock.Anything, uid).Return(repo.User{
+				ID:      uid,
+				Country: sql.NullString{String: "CZ", Valid: true},
+			}, nil).Once()
+			if tt.age < 16 {
+				mockRepo.On("HasGuardianConsent", mock.Anything, uid).Return(tt.consented, nil).Once()
+			}
+			mockRepo.On("UpdateUser", mock.Anything, mock.MatchedBy(func(arg repo.UpdateUserParams) bool {
+				return arg.State.Valid && arg.State.Int16 == int16(tt.expState)
+			})).Return(repo.User{ID: uid}, nil).Once()
+			server := service.NewUserServer(mockRepo, service.WithMinimumAge(testAgePolicy))
+
+			// test method
+			bd := yearsAgo(tt.age)
+			_, err := server.UpdateUser(context.Background(), &userpb.UpdateUserRequest{
+				Id: uid.String(),
+				User: &userpb.User{
+					BirthDate: &date.Date{Year: int32(bd.Year()), Month: int32(bd.Month()), Day: int32(bd.Day())},
+				},
+			})
+			require.NoError(t, err)
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserServer_UpdateUser_MinimumAge_Phone(t *testing.T) {
+	t.Parallel()
+
+	// the minor is old enough in the country of the new phone number
+	uid := uuid.New()
+	mockRepo := new(mocks.Querier)
+	mockRepo.On("GetUser", mock.Anything, uid).Return(repo.User{
+		ID:       uid,
+		BirthDay: sql.NullTime{Time: yearsAgo(14), Valid: true},
+		State:    int16(userpb.User_PENDING_GUARDIAN_CONSENT),
+	}, nil).Once()
+	mockRepo.On("UpdateUser", mock.Anything, mock.MatchedBy(func(arg repo.UpdateUserParams) bool {
+		return arg.PhoneCountry.String == "US" && arg.State.Valid && arg.State.Int16 == int16(userpb.User_ACTIVE)
+	})).Return(repo.User{ID: uid}, nil).Once()
+	server := service.NewUserServer(mockRepo, service.WithMinimumAge(testAgePolicy))
+
+	_, err := server.UpdateUser(context.Background(), &userpb.UpdateUserRequest{
+		Id:   uid.String(),
+		User: &userpb.User{Phone: "+1 201 555 0123"},
+	})
+	require.NoError(t, err)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserServer_RequestGuardianConsent(t *testing.T) {
+	t.Parallel()
+
+	minorID := uuid.New()
+	guardianID := uuid.New()
+	consentID := uuid.New()
+	pending := repo.User{ID: minorID, State: int16(userpb.User_PENDING_GUARDIAN_CONSENT)}
+	adult := repo.User{ID: guardianID, BirthDay: sql.NullTime{Time: yearsAgo(40), Valid: true}}
+
+	tests := []struct {
+		name       string
+		buildRepo  func(q *mocks.Querier)
+		guardianID string
+		publishErr error
+		expCode    codes.Code
+	}{
+		{
+			name: "ok",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("GetUser", mock.Anything, minorID).Return(pending, nil).Once()
+				q.On("GetUser", mock.Anything, guardianID).Return(adult, nil).Once()
+				q.On("CreateGuardianConsent", mock.Anything, mock.MatchedBy(func(arg repo.CreateGuardianConsentParams) bool {
+					return arg.MinorID == minorID && arg.GuardianID == guardianID && arg.ExpiresAt.After(time.Now())
+				})).Return(repo.GuardianConsent{
+					ID:         consentID,
+					MinorID:    minorID,
+					GuardianID: guardianID,
+					ExpiresAt:  time.Now().Add(service.GuardianConsentTTL),
+					CreatedAt:  time.Now(),
+				}, nil).Once()
+			},
+			guardianID: guardianID.String(),
+			expCode:    codes.OK,
+		},
+		{
+			name:       "own guardian",
+			buildRepo:  func(q *mocks.Querier) {},
+			guardianID: minorID.String(),
+			expCode:    codes.InvalidArgument,
+		},
+		{
+			name:       "invalid guardian id",
+			buildRepo:  func(q *mocks.Querier) {},
+			guardianID: "invalid_uuid",
+			expCode:    codes.InvalidArgument,
+		},
+		{
+			name: "consent not required",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("GetUser", mock.Anything, minorID).Return(repo.User{ID: minorID}, nil).Once()
+			},
+			guardianID: guardianID.String(),
+			expCode:    codes.FailedPrecondition,
+		},
+		{
+			name: "guardian is a minor",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("GetUser", mock.Anything, minorID).Return(pending, nil).Once()
+				q.On("GetUser", mock.Anything, guardianID).Return(repo.User{
+					ID:       guardianID,
+					BirthDay: sql.NullTime{Time: yearsAgo(17), Valid: true},
+				}, nil).Once()
+			},
+			guardianID: guardianID.String(),
+			expCode:    codes.FailedPrecondition,
+		},
+		{
+			name: "guardian not found",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("GetUser", mock.Anything, minorID).Return(pending, nil).Once()
+				q.On("GetUser", mock.Anything, guardianID).Return(repo.User{}, sql.ErrNoRows).Once()
+			},
+			guardianID: guardianID.String(),
+			expCode:    codes.NotFound,
+		},
+		{
+			name: "notification failed",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("GetUser", mock.Anything, minorID).Return(pending, nil).Once()
+				q.On("GetUser", mock.Anything, guardianID).Return(adult, nil).Once()
+				q.On("CreateGuardianConsent", mock.Anything, mock.Anything).Return(repo.GuardianConsent{ID: consentID}, nil).Once()
+
+				// the guardian was not notified, a retry creates a new request
+				q.On("DeleteGuardianConsent", mock.Anything, consentID).Return(nil).Once()
+			},
+			guardianID: guardianID.String(),
+			publishErr: errors.New("connection refused"),
+			expCode:    codes.Unavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// construct server
+			mockRepo := new(mocks.Querier)
+			tt.buildRepo(mockRepo)
+			publisher := &recordingPublisher{err: tt.publishErr}
+			server := service.NewUserServer(mockRepo, service.WithPublisher(publisher))
+
+			// test method
+			resp, err := server.RequestGuardianConsent(context.Background(), &userpb.RequestGuardianConsentRequest{
+				Id:         minorID.String(),
+				GuardianId: tt.guardianID,
+			})
+			if tt.expCode == codes.OK {
+				require.NoError(t, err)
+				require.True(t, resp.ExpiresAt.AsTime().After(time.Now()))
+				require.Equal(t, consentID.String(), resp.ConsentId)
+
+				require.Len(t, publisher.events, 1)
+				require.Equal(t, event.TypeGuardianConsentRequested, publisher.events[0].Type)
+				require.Equal(t, guardianID, publisher.events[0].UserID)
+				require.Equal(t, minorID.String(), publisher.events[0].Data["minor_id"])
+				require.Equal(t, consentID.String(), publisher.events[0].Data["consent_id"])
+			} else {
+				require.Error(t, err)
+				require.Nil(t, resp)
+
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, tt.expCode, st.Code())
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserServer_ConfirmGuardianConsent(t *testing.T) {
+	t.Parallel()
+
+	consentID := uuid.New()
+	minorID := uuid.New()
+	guardianID := uuid.New()
+
+	tests := []struct {
+		name      string
+		buildRepo func(q *mocks.Querier)
+		consentID string
+		expCode   codes.Code
+	}{
+		{
+			name: "ok",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("ConfirmGuardianConsent", mock.Anything, repo.ConfirmGuardianConsentParams{
+					ID:         consentID,
+					GuardianID: guardianID,
+				}).Return(repo.ConfirmGuardianConsentRow{ID: consentID, MinorID: minorID, GuardianID: guardianID}, nil).Once()
+			},
+			consentID: consentID.String(),
+			expCode:   codes.OK,
+		},
+		{
+			name:      "empty consent id",
+			buildRepo: func(q *mocks.Querier) {},
+			consentID: "",
+			expCode:   codes.InvalidArgument,
+		},
+		{
+			name: "unknown or expired",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("ConfirmGuardianConsent", mock.Anything, mock.Anything).Return(repo.ConfirmGuardianConsentRow{}, sql.ErrNoRows).Once()
+			},
+			consentID: consentID.String(),
+			expCode:   codes.NotFound,
+		},
+		{
+			name: "connection error",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("ConfirmGuardianConsent", mock.Anything, mock.Anything).Return(repo.ConfirmGuardianConsentRow{}, sql.ErrConnDone).Once()
+			},
+			consentID: consentID.String(),
+			expCode:   codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// construct server
+			mockRepo := new(mocks.Querier)
+			tt.buildRepo(mockRepo)
+			server := service.NewUserServer(mockRepo)
+
+			// test method
+			resp, err := server.ConfirmGuardianConsent(context.Background(), &userpb.ConfirmGuardianConsentRequest{
+				ConsentId:  tt.consentID,
+				GuardianId: guardianID.String(),
+			})
+			if tt.expCode == codes.OK {
+				require.NoError(t, err)
+				require.Equal(t, minorID.String(), resp.Id)
+			} else {
+				require.Error(t, err)
+				require.Nil(t, resp)
+
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, tt.expCode, st.Code())
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/chutommy/user-microservice/pkg/grpc/userpb"
+	"github.com/chutommy/user-microservice/pkg/mocks"
+	"github.com/chutommy/user-microservice/pkg/repo"
+	"github.com/chutommy/user-microservice/pkg/service"
+)
+
+func TestUserServer_RegisterUser_Phone(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		defaultRegion string
+		phone         string
+		region        string
+		expPhone      string
+		expCode       codes.Code
+	}{
+		{
+			name:     "international",
+			phone:    "+1 (201) 555-0123",
+			expPhone: "+12015550123",
+			expCode:  codes.OK,
+		},
+		{
+			name:          "default region",
+			defaultRegion: "US",
+			phone:         "201.555.0123",
+			expPhone:      "+12015550123",
+			expCode:       codes.OK,
+		},
+		{
+			name:          "request region",
+			defaultRegion: "US",
+			phone:         "601 123 456",
+			region:        "CZ",
+			expPhone:      "+420601123456",
+			expCode:       codes.OK,
+		},
+		{
+			name:    "missing region",
+			phone:   "201 555 0123",
+			expCode: codes.InvalidArgument,
+		},
+		{
+			name:    "invalid number",
+			phone:   "+1 555",
+			expCode: codes.InvalidArgument,
+		},
+		{
+			name:    "unknown region",
+			phone:   "601 123 456",
+			region:  "XX",
+			expCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// construct server
+			mockRepo := new(mocks.Querier)
+			if tt.expCode == codes.OK {
+				mockRepo.On("CreateUser", mock.Anything, mock.MatchedBy(func(arg repo.CreateUserParams) bool {
+					return arg.PhoneNumber == sql.NullString{String: tt.expPhone, Valid: true} && arg.PhoneCountry.Valid
+				})).Return(repo.User{ID: uuid.New()}, nil).Once()
+			}
+			server := service.NewUserServer(mockRepo, service.WithDefaultPhoneRegion(tt.defaultRegion))
+
+			// build request
+			user := randomUser()
+			user.Phone = tt.phone
+			req := &userpb.RegisterUserRequest{User: user, PhoneRegion: tt.region}
+
+			// test method
+			resp, err := server.RegisterUser(context.Background(), req)
+			if tt.expCode == codes.OK {
+				require.NoError(t, err)
+				require.NotNil(t, resp)
+			} else {
+				require.Error(t, err)
+				require.Nil(t, resp)
+
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, tt.expCode, st.Code())
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserServer_UpdateUser_Phone(t *testing.T) {
+	t.Parallel()
+
+	uid := uuid.New()
+	mockRepo := new(mocks.Querier)
+	mockRepo.On("UpdateUser", mock.Anything, mock.MatchedBy(func(arg repo.UpdateUserParams) bool {
+		return arg.PhoneNumber == "+442079460018" && arg.PhoneCountry.String == "GB"
+	})).Return(repo.User{ID: uid}, nil).Once()
+
+	server := service.NewUserServer(mockRepo)
+	resp, err := server.UpdateUser(context.Background(), &userpb.UpdateUserRequest{
+		Id:          uid.String(),
+		User:        &userpb.User{Phone: "020 7946 0018"},
+		PhoneRegion: "GB",
+	})
+	require.NoError(t, err)
+	require.Equal(t, uid.String(), resp.Id)
+
+	mockRepo.AssertExpectations(t)
+}
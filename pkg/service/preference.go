@@ -0,0 +1,272 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/chutommy/user-microservice/pkg/apierror"
+	"github.com/chutommy/user-microservice/pkg/grpc/userpb"
+	"github.com/chutommy/user-microservice/pkg/preference"
+	"github.com/chutommy/user-microservice/pkg/repo"
+)
+
+// preferenceFromPB validates and converts a preference of a request.
+func preferenceFromPB(p *userpb.Preference) (preference.Preference, error) {
+	if err := preference.ValidateName(p.GetNamespace()); err != nil {
+		return preference.Preference{}, err
+	}
+	if err := preference.ValidateName(p.GetKey()); err != nil {
+		return preference.Preference{}, err
+	}
+
+	pref := preference.Preference{
+		Namespace: p.GetNamespace(),
+		Key:       p.GetKey(),
+	}
+	switch v := p.GetValue().(type) {
+	case *userpb.Preference_StringValue:
+		pref.Value = preference.StringValue(v.StringValue)
+	case *userpb.Preference_BoolValue:
+		pref.Value = preference.BoolValue(v.BoolValue)
+	case *userpb.Preference_IntValue:
+		pref.Value = preference.IntValue(v.IntValue)
+	case *userpb.Preference_JsonValue:
+		jv, err := preference.JSONValue(v.JsonValue)
+		if err != nil {
+			return preference.Preference{}, err
+		}
+		pref.Value = jv
+	default:
+		return preference.Preference{}, preference.ErrInvalidValue
+	}
+
+	return pref, nil
+}
+
+func preferenceToPB(p preference.Preference) (*userpb.Preference, error) {
+	pb := &userpb.Preference{
+		Namespace: p.Namespace,
+		Key:       p.Key,
+		Default:   p.Default,
+	}
+
+	switch p.Value.Type {
+	case preference.TypeString:
+		s, err := p.Value.String()
+		if err != nil {
+			return nil, err
+		}
+		pb.Value = &userpb.Preference_StringValue{StringValue: s}
+	case preference.TypeBool:
+		b, err := p.Value.Bool()
+		if err != nil {
+			return nil, err
+		}
+		pb.Value = &userpb.Preference_BoolValue{BoolValue: b}
+	case preference.TypeInt:
+		i, err := p.Value.Int()
+		if err != nil {
+			return nil, err
+		}
+		pb.Value = &userpb.Preference_IntValue{IntValue: i}
+	case preference.TypeJSON:
+		pb.Value = &userpb.Preference_JsonValue{JsonValue: string(p.Value.Raw)}
+	default:
+		return nil, preference.ErrInvalidValue
+	}
+
+	return pb, nil
+}
+
+func preferencesToPB(prefs []preference.Preference) ([]*userpb.Preference, error) {
+	pbs := make([]*userpb.Preference, 0, len(prefs))
+	for _, p := range prefs {
+		pb, err := preferenceToPB(p)
+		if err != nil {
+			return nil, err
+		}
+		pbs = append(pbs, pb)
+	}
+
+	return pbs, nil
+}
+
+func (u *UserServer) GetPreferences(ctx context.Context, req *userpb.GetPreferencesRequest) (*userpb.GetPreferencesResponse, error) {
+	logger := ctxzap.Extract(ctx)
+
+	// parse ID
+	uid, err := parseID(ctx, "id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if ns := req.GetNamespace(); ns != "" {
+		if err = preference.ValidateName(ns); err != nil {
+			logger.Info("invalid namespace", zap.Error(err))
+			return nil, apierror.InvalidField("namespace", "invalid namespace: %v", err)
+		}
+	}
+
+	// the defaults are returned only for existing users
+	if _, err = u.repo.GetUser(ctx, uid); err != nil {
+		code := codes.Internal
+
+		if errors.Is(err, sql.ErrNoRows) {
+			code = codes.NotFound
+		}
+
+		logger.Error("retrieve user", zap.Error(err))
+		return nil, status.Errorf(code, "failed to retrieve user with id: %s", uid)
+	}
+
+	// retrieve preferences
+	stored, err := u.repo.ListPreferences(ctx, repo.ListPreferencesParams{
+		UserID:    uid,
+		Namespace: req.GetNamespace(),
+	})
+	if err != nil {
+		logger.Error("list preferences", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "failed to list preferences of user with id: %s", uid)
+	}
+
+	prefs := make([]preference.Preference, 0, len(stored))
+	for _, p := range stored {
+		prefs = append(prefs, preference.FromRepo(p))
+	}
+
+	pbs, err := preferencesToPB(u.preferenceDefaults.Merge(req.GetNamespace(), prefs))
+	if err != nil {
+		logger.Error("malformed stored preference", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "failed to list preferences of user with id: %s", uid)
+	}
+
+	// construct response
+	resp := &userpb.GetPreferencesResponse{
+		Preferences: pbs,
+	}
+
+	return resp, nil
+}
+
+func (u *UserServer) SetPreferences(ctx context.Context, req *userpb.SetPreferencesRequest) (*userpb.SetPreferencesResponse, error) {
+	logger := ctxzap.Extract(ctx)
+
+	switch {
+	case len(req.GetPreferences()) == 0:
+		logger.Info("empty preferences")
+		return nil, apierror.InvalidField("preferences", "%v: 'preferences' field", ErrEmptyField)
+	}
+
+	// parse ID
+	uid, err := parseID(ctx, "id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	// build argument
+	arg := repo.SetPreferencesParams{
+		UserID: uid,
+	}
+	for i, pb := range req.GetPreferences() {
+		p, err := preferenceFromPB(pb)
+		if err != nil {
+			logger.Info("invalid preference", zap.Error(err))
+			return nil, apierror.InvalidField(fmt.Sprintf("preferences[%d]", i),
+				"invalid preference %s/%s: %v", pb.GetNamespace(), pb.GetKey(), err)
+		}
+
+		arg.Namespaces = append(arg.Namespaces, p.Namespace)
+		arg.Keys = append(arg.Keys, p.Key)
+		arg.ValueTypes = append(arg.ValueTypes, int16(p.Value.Type))
+		arg.PreferenceValues = append(arg.PreferenceValues, string(p.Value.Raw))
+	}
+
+	// store preferences
+	stored, err := u.repo.SetPreferences(ctx, arg)
+	if err != nil {
+		code := codes.Internal
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) {
+			switch pqErr.Code {
+			case "23503":
+				code = codes.NotFound
+			case "21000":
+				logger.Info("duplicate preferences", zap.Error(err))
+				return nil, apierror.InvalidField("preferences", "preferences contain the same key more than once")
+			}
+		}
+
+		logger.Error("failed to set preferences", zap.Error(err))
+		return nil, status.Errorf(code, "failed to set preferences of user with id: %s", uid)
+	}
+
+	prefs := make([]preference.Preference, 0, len(stored))
+	for _, p := range stored {
+		prefs = append(prefs, preference.FromRepo(p))
+	}
+
+	pbs, err := preferencesToPB(prefs)
+	if err != nil {
+		logger.Error("malformed stored preference", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "failed to set preferences of user with id: %s", uid)
+	}
+
+	// construct response
+	resp := &userpb.SetPreferencesResponse{
+		Preferences: pbs,
+	}
+
+	return resp, nil
+}
+
+func (u *UserServer) DeletePreference(ctx context.Context, req *userpb.DeletePreferenceRequest) (*userpb.DeletePreferenceResponse, error) {
+	logger := ctxzap.Extract(ctx)
+
+	switch {
+	case req.GetNamespace() == "":
+		logger.Info("empty namespace")
+		return nil, apierror.InvalidField("namespace", "%v: 'namespace' field", ErrEmptyField)
+	case req.GetKey() == "":
+		logger.Info("empty key")
+		return nil, apierror.InvalidField("key", "%v: 'key' field", ErrEmptyField)
+	}
+
+	// parse ID
+	uid, err := parseID(ctx, "id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	// remove preference
+	affected, err := u.repo.DeletePreference(ctx, repo.DeletePreferenceParams{
+		UserID:    uid,
+		Namespace: req.GetNamespace(),
+		Key:       req.GetKey(),
+	})
+	if err != nil || affected != 1 {
+		code := codes.Internal
+
+		if affected == 0 && err == nil {
+			code = codes.NotFound
+		}
+
+		logger.Info("failed to delete preference", zap.Error(err))
+		return nil, status.Errorf(code, "failed to delete preference %s/%s of user with id: %s", req.GetNamespace(), req.GetKey(), uid)
+	}
+
+	// construct response
+	resp := &userpb.DeletePreferenceResponse{
+		Id: uid.String(),
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,264 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/chutommy/user-microservice/pkg/grpc/userpb"
+	"github.com/chutommy/user-microservice/pkg/mocks"
+	"github.com/chutommy/user-microservice/pkg/preference"
+	"github.com/chutommy/user-microservice/pkg/repo"
+	"github.com/chutommy/user-microservice/pkg/service"
+)
+
+func TestUserServer_GetPreferences(t *testing.T) {
+	t.Parallel()
+
+	uid := uuid.New()
+	defaults := preference.Defaults{
+		"ui": {
+			"theme":     preference.StringValue("light"),
+			"font_size": preference.IntValue(12),
+		},
+	}
+
+	tests := []struct {
+		name      string
+		buildRepo func(q *mocks.Querier)
+		namespace string
+		expPrefs  []*userpb.Preference
+		expCode   codes.Code
+	}{
+		{
+			name: "ok",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("GetUser", mock.Anything, uid).Return(repo.User{ID: uid}, nil).Once()
+				q.On("ListPreferences", mock.Anything, repo.ListPreferencesParams{UserID: uid, Namespace: "ui"}).
+					Return([]repo.UserPreference{{
+						UserID:    uid,
+						Namespace: "ui",
+						Key:       "theme",
+						ValueType: int16(preference.TypeString),
+						Value:     json.RawMessage(`"dark"`),
+						UpdatedAt: time.Now(),
+					}}, nil).Once()
+			},
+			namespace: "ui",
+			expPrefs: []*userpb.Preference{
+				{Namespace: "ui", Key: "font_size", Value: &userpb.Preference_IntValue{IntValue: 12}, Default: true},
+				{Namespace: "ui", Key: "theme", Value: &userpb.Preference_StringValue{StringValue: "dark"}},
+			},
+			expCode: codes.OK,
+		},
+		{
+			name:      "invalid namespace",
+			buildRepo: func(q *mocks.Querier) {},
+			namespace: "Invalid Namespace",
+			expCode:   codes.InvalidArgument,
+		},
+		{
+			name: "user not found",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("GetUser", mock.Anything, uid).Return(repo.User{}, sql.ErrNoRows).Once()
+			},
+			expCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// construct server
+			mockRepo := new(mocks.Querier)
+			tt.buildRepo(mockRepo)
+			server := service.NewUserServer(mockRepo, service.WithPreferenceDefaults(defaults))
+
+			// test method
+			resp, err := server.GetPreferences(context.Background(), &userpb.GetPreferencesRequest{
+				Id:        uid.String(),
+				Namespace: tt.namespace,
+			})
+			if tt.expCode == codes.OK {
+				require.NoError(t, err)
+				require.Len(t, resp.Preferences, len(tt.expPrefs))
+				for i, p := range tt.expPrefs {
+					require.Equal(t, p.String(), resp.Preferences[i].String())
+				}
+			} else {
+				require.Error(t, err)
+				require.Nil(t, resp)
+
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, tt.expCode, st.Code())
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserServer_SetPreferences(t *testing.T) {
+	t.Parallel()
+
+	uid := uuid.New()
+
+	tests := []struct {
+		name      string
+		buildRepo func(q *mocks.Querier)
+		prefs     []*userpb.Preference
+		expCode   codes.Code
+	}{
+		{
+			name: "ok",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("SetPreferences", mock.Anything, repo.SetPreferencesParams{
+					UserID:           uid,
+					Namespaces:       []string{"ui", "notifications"},
+					Keys:             []string{"theme", "channels"},
+					ValueTypes:       []int16{int16(preference.TypeString), int16(preference.TypeJSON)},
+					PreferenceValues: []string{`"dark"`, `["email","sms"]`},
+				}).Return([]repo.UserPreference{
+					{Namespace: "ui", Key: "theme", ValueType: int16(preference.TypeString), Value: json.RawMessage(`"dark"`)},
+					{Namespace: "notifications", Key: "channels", ValueType: int16(preference.TypeJSON), Value: json.RawMessage(`["email","sms"]`)},
+				}, nil).Once()
+			},
+			prefs: []*userpb.Preference{
+				{Namespace: "ui", Key: "theme", Value: &userpb.Preference_StringValue{StringValue: "dark"}},
+				{Namespace: "notifications", Key: "channels", Value: &userpb.Preference_JsonValue{JsonValue: `["email", "sms"]`}},
+			},
+			expCode: codes.OK,
+		},
+		{
+			name:      "empty preferences",
+			buildRepo: func(q *mocks.Querier) {},
+			prefs:     nil,
+			expCode:   codes.InvalidArgument,
+		},
+		{
+			name:      "missing value",
+			buildRepo: func(q *mocks.Querier) {},
+			prefs:     []*userpb.Preference{{Namespace: "ui", Key: "theme"}},
+			expCode:   codes.InvalidArgument,
+		},
+		{
+			name:      "malformed json",
+			buildRepo: func(q *mocks.Querier) {},
+			prefs:     []*userpb.Preference{{Namespace: "ui", Key: "layout", Value: &userpb.Preference_JsonValue{JsonValue: "{"}}},
+			expCode:   codes.InvalidArgument,
+		},
+		{
+			name: "user not found",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("SetPreferences", mock.Anything, mock.Anything).Return(nil, &pq.Error{Code: "23503"}).Once()
+			},
+			prefs:   []*userpb.Preference{{Namespace: "ui", Key: "compact", Value: &userpb.Preference_BoolValue{BoolValue: true}}},
+			expCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// construct server
+			mockRepo := new(mocks.Querier)
+			tt.buildRepo(mockRepo)
+			server := service.NewUserServer(mockRepo)
+
+			// test method
+			resp, err := server.SetPreferences(context.Background(), &userpb.SetPreferencesRequest{
+				Id:          uid.String(),
+				Preferences: tt.prefs,
+			})
+			if tt.expCode == codes.OK {
+				require.NoError(t, err)
+				require.Len(t, resp.Preferences, len(tt.prefs))
+			} else {
+				require.Error(t, err)
+				require.Nil(t, resp)
+
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, tt.expCode, st.Code())
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserServer_DeletePreference(t *testing.T) {
+	t.Parallel()
+
+	uid := uuid.New()
+
+	tests := []struct {
+		name      string
+		buildRepo func(q *mocks.Querier)
+		key       string
+		expCode   codes.Code
+	}{
+		{
+			name: "ok",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("DeletePreference", mock.Anything, mock.Anything).Return(int64(1), nil).Once()
+			},
+			key:     "theme",
+			expCode: codes.OK,
+		},
+		{
+			name:      "empty key",
+			buildRepo: func(q *mocks.Querier) {},
+			key:       "",
+			expCode:   codes.InvalidArgument,
+		},
+		{
+			name: "not found",
+			buildRepo: func(q *mocks.Querier) {
+				q.On("DeletePreference", mock.Anything, mock.Anything).Return(int64(0), nil).Once()
+			},
+			key:     "theme",
+			expCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// construct server
+			mockRepo := new(mocks.Querier)
+			tt.buildRepo(mockRepo)
+			server := service.NewUserServer(mockRepo)
+
+			// test method
+			resp, err := server.DeletePreference(context.Background(), &userpb.DeletePreferenceRequest{
+				Id:        uid.String(),
+				Namespace: "ui",
+				Key:       tt.key,
+			})
+			if tt.expCode == codes.OK {
+				require.NoError(t, err)
+				require.Equal(t, uid.String(), resp.Id)
+			} else {
+				require.Error(t, err)
+				require.Nil(t, resp)
+
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, tt.expCode, st.Code())
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
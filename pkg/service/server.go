@@ -3,25 +3,49 @@ package service
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
-	"github.com/lib/pq"
+	"github.com/prometheus/client_golang/prometheus"
+	otelattr "go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/genproto/googleapis/type/date"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
-
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/chutommy/user-microservice/pkg/age"
+	"github.com/chutommy/user-microservice/pkg/apierror"
+	"github.com/chutommy/user-microservice/pkg/attribute"
+	"github.com/chutommy/user-microservice/pkg/blob"
+	"github.com/chutommy/user-microservice/pkg/event"
 	"github.com/chutommy/user-microservice/pkg/grpc/userpb"
+	"github.com/chutommy/user-microservice/pkg/mailaddr"
+	"github.com/chutommy/user-microservice/pkg/phone"
+	"github.com/chutommy/user-microservice/pkg/preference"
 	"github.com/chutommy/user-microservice/pkg/repo"
+	"github.com/chutommy/user-microservice/pkg/tracing"
+	"github.com/chutommy/user-microservice/pkg/util"
 )
 
+// tracer traces the expensive steps of the calls.
+var tracer = tracing.Tracer("github.com/chutommy/user-microservice/pkg/service")
+
 var (
 	// ShortForm is a format of a birthday date.
 	ShortForm = "2006-Jan-02"
 
+	// ISOForm is the ISO 8601 format of a birthday date.
+	ISOForm = "2006-01-02"
+
 	// ErrMissingArgument is returned if the argument is invalid because of an empty
 	// mandatory field.
 	ErrEmptyField = errors.New("required field has empty value")
@@ -31,211 +55,603 @@ var (
 type UserServer struct {
 	userpb.UnimplementedUserServiceServer
 
-	repo repo.Querier
+	repo       repo.Querier
+	publisher  event.Publisher
+	blobs      blob.Store
+	signingKey []byte
+
+	preferenceDefaults preference.Defaults
+
+	attributes        *attribute.Validator
+	indexedAttributes []string
+
+	avatarBaseURL string
+
+	phoneRegion string
+
+	emailProviderRules bool
+
+	// admins are the caller identities which may add verified emails
+	admins map[string]bool
+
+	agePolicy age.Policy
+
+	bcryptDuration prometheus.Observer
 
 	// TODO: add logger middleware
 }
 
+// Option configures a UserServer.
+type Option func(*UserServer)
+
+// WithPublisher sets the publisher of the domain events. Events are discarded by default.
+func WithPublisher(p event.Publisher) Option {
+	return func(u *UserServer) {
+		u.publisher = p
+	}
+}
+
+// WithBlobStore sets the storage of the data export archives and the avatars.
+func WithBlobStore(s blob.Store) Option {
+	return func(u *UserServer) {
+		u.blobs = s
+	}
+}
+
+// WithSigningKey sets the key used to sign the data export archives.
+func WithSigningKey(key []byte) Option {
+	return func(u *UserServer) {
+		u.signingKey = key
+	}
+}
+
+// WithPreferenceDefaults sets the default preferences per namespace.
+func WithPreferenceDefaults(d preference.Defaults) Option {
+	return func(u *UserServer) {
+		u.preferenceDefaults = d
+	}
+}
+
+// WithAttributeSchema sets the validator of the custom profile attributes.
+// Any JSON object is accepted by default.
+func WithAttributeSchema(v *attribute.Validator) Option {
+	return func(u *UserServer) {
+		u.attributes = v
+	}
+}
+
+// WithIndexedAttributes sets the attribute paths which may be used in filters.
+func WithIndexedAttributes(paths []string) Option {
+	return func(u *UserServer) {
+		u.indexedAttributes = paths
+	}
+}
+
+// WithAvatarBaseURL sets the URL prefix of the avatar links, "/v1/avatars" by default.
+func WithAvatarBaseURL(url string) Option {
+	return func(u *UserServer) {
+		u.avatarBaseURL = url
+	}
+}
+
+// WithDefaultPhoneRegion sets the region of phone numbers given without the
+// international prefix if the request specifies none.
+func WithDefaultPhoneRegion(region string) Option {
+	return func(u *UserServer) {
+		u.phoneRegion = region
+	}
+}
+
+// WithEmailProviderRules enables the provider-specific canonicalization of the
+// email addresses, e.g. ignoring the dots and the plus-tag of Gmail addresses.
+func WithEmailProviderRules(enabled bool) Option {
+	return func(u *UserServer) {
+		u.emailProviderRules = enabled
+	}
+}
+
+// WithAdminIdentities sets the identities of the client certificates which
+// may add verified email addresses. No caller may by default.
+func WithAdminIdentities(ids ...string) Option {
+	return func(u *UserServer) {
+		u.admins = make(map[string]bool, len(ids))
+		for _, id := range ids {
+			u.admins[id] = true
+		}
+	}
+}
+
+// WithMinimumAge sets the minimum age per country under which the accounts
+// stay pending until a guardian consents. No consent is required by default.
+func WithMinimumAge(p age.Policy) Option {
+	return func(u *UserServer) {
+		u.agePolicy = p
+	}
+}
+
+// WithBcryptObserver sets the observer of the password hashing duration in seconds.
+func WithBcryptObserver(o prometheus.Observer) Option {
+	return func(u *UserServer) {
+		u.bcryptDuration = o
+	}
+}
+
 // NewUserServer constructs a UserServer.
-func NewUserServer(repo repo.Querier) *UserServer {
-	return &UserServer{
-		repo: repo,
+func NewUserServer(repo repo.Querier, opts ...Option) *UserServer {
+	u := &UserServer{
+		repo:          repo,
+		publisher:     event.NopPublisher{},
+		avatarBaseURL: DefaultAvatarBaseURL,
+	}
+
+	for _, opt := range opts {
+		opt(u)
 	}
+
+	return u
 }
 
 func (u *UserServer) RegisterUser(ctx context.Context, req *userpb.RegisterUserRequest) (*userpb.RegisterUserResponse, error) {
 	logger := ctxzap.Extract(ctx)
 	user := req.GetUser()
 
+	// The proto rules check the format of the fields. The User message is
+	// shared with the partial UpdateUser, so the required ones are checked here.
 	switch {
 	case user.GetEmail() == "":
 		logger.Info("empty email")
-		return nil, status.Errorf(codes.InvalidArgument, "%v: 'email' field", ErrEmptyField)
+		return nil, apierror.InvalidField("user.email", "%v: 'email' field", ErrEmptyField)
 	case user.GetPassword() == "":
 		logger.Info("empty password")
-		return nil, status.Errorf(codes.InvalidArgument, "%v: 'password' field", ErrEmptyField)
+		return nil, apierror.InvalidField("user.password", "%v: 'password' field", ErrEmptyField)
 	case user.GetFirstName() == "":
 		logger.Info("empty first name")
-		return nil, status.Errorf(codes.InvalidArgument, "%v: 'first_name' field", ErrEmptyField)
+		return nil, apierror.InvalidField("user.first_name", "%v: 'first_name' field", ErrEmptyField)
 	case user.GetLastName() == "":
 		logger.Info("empty last name")
-		return nil, status.Errorf(codes.InvalidArgument, "%v: 'last_name' field", ErrEmptyField)
+		return nil, apierror.InvalidField("user.last_name", "%v: 'last_name' field", ErrEmptyField)
+	}
+
+	// check accepted policies
+	for i, p := range req.GetAcceptedPolicies() {
+		if err := u.checkPolicy(ctx, fmt.Sprintf("accepted_policies[%d]", i), p); err != nil {
+			return nil, err
+		}
+	}
+
+	// process email
+	email, err := u.normalizeEmail(ctx, "user.email", user.GetEmail())
+	if err != nil {
+		return nil, err
+	}
+
+	// process phone number
+	phoneNumber, err := u.normalizePhone(ctx, user.GetPhone(), req.GetPhoneRegion())
+	if err != nil {
+		return nil, err
 	}
 
 	// process password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.GetPassword()), bcrypt.DefaultCost)
+	hashedPassword, err := u.hashPassword(ctx, user.GetPassword())
 	if err != nil {
 		logger.Error("failed to hash the password", zap.Error(err))
 		return nil, status.Errorf(codes.Internal, "fail to hash password")
 	}
 
+	// process attributes
+	attrs, err := u.encodeAttributes(ctx, user.GetAttributes())
+	if err != nil {
+		return nil, err
+	}
+	if attrs == "" {
+		attrs = "{}"
+	}
+
 	// process birthday
-	var bdTime sql.NullTime
-	if bd := user.GetBirthday(); bd != "" {
-		parsedBD, err := time.Parse(ShortForm, bd)
-		if err != nil {
-			logger.Info("failed to parse birthday", zap.Error(err))
-			return nil, status.Errorf(codes.InvalidArgument, "field time is in unsupported format: %v instead of %v", err, ShortForm)
-		}
+	parsedBD, err := parseBirthday(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	bdTime := sql.NullTime{
+		Time:  parsedBD,
+		Valid: !parsedBD.IsZero(),
+	}
 
-		bdTime = sql.NullTime{
-			Time:  parsedBD,
-			Valid: true,
-		}
+	// check minimum age
+	country, err := parseCountry(ctx, user.GetCountry())
+	if err != nil {
+		return nil, err
+	}
+	if country == "" {
+		country = residenceCountry(phoneNumber.Country)
+	}
+	state := userpb.User_ACTIVE
+	if bdTime.Valid && u.agePolicy.RequiresConsent(bdTime.Time, country, time.Now()) {
+		logger.Info("guardian consent required", zap.String("country", country))
+		state = userpb.User_PENDING_GUARDIAN_CONSENT
 	}
 
 	// build argument
 	arg := repo.CreateUserParams{
-		ID:    uuid.New(),
-		Email: user.GetEmail(),
+		ID:             uuid.New(),
+		Email:          email.Email,
+		EmailCanonical: email.Canonical,
 		PhoneNumber: sql.NullString{
-			String: user.GetPhone(),
-			Valid:  user.GetPhone() != "",
+			String: phoneNumber.E164,
+			Valid:  phoneNumber.E164 != "",
+		},
+		PhoneCountry: sql.NullString{
+			String: phoneNumber.Country,
+			Valid:  phoneNumber.Country != "",
 		},
 		HashedPassword: string(hashedPassword),
 		FirstName:      user.GetFirstName(),
 		LastName:       user.GetLastName(),
 		Gender:         int16(user.GetGender()),
 		BirthDay:       bdTime,
+		Attributes:     json.RawMessage(attrs),
+		Country: sql.NullString{
+			String: country,
+			Valid:  country != "",
+		},
+		State: int16(state),
+	}
+	for _, p := range req.GetAcceptedPolicies() {
+		arg.PolicyKinds = append(arg.PolicyKinds, p.GetKind())
+		arg.PolicyVersions = append(arg.PolicyVersions, p.GetVersion())
 	}
 
-	// store user
+	// store user with the consents
 	newUser, err := u.repo.CreateUser(ctx, arg)
 	if err != nil {
-		code := codes.Internal
-
-		var pqErr *pq.Error
-		if errors.As(err, &pqErr) {
-			if pqErr.Code == "23505" {
-				code = codes.AlreadyExists
-			}
+		if conflict := apierror.Conflict(err); conflict != nil {
+			logger.Info("user already exists", zap.Error(err))
+			return nil, conflict
 		}
 
 		logger.Error("failed to create a new user", zap.Error(err))
-		return nil, status.Errorf(code, "cannot create a new user: %v", err)
+		return nil, status.Errorf(codes.Internal, "cannot create a new user")
 	}
 
 	// construct response
 	resp := &userpb.RegisterUserResponse{
-		Id: newUser.ID.String(),
+		Id:    newUser.ID.String(),
+		State: userpb.User_State(newUser.State),
 	}
 
 	return resp, nil
 }
 
-func (u *UserServer) GetUser(ctx context.Context, req *userpb.GetUserRequest) (*userpb.GetUserResponse, error) {
+// hashPassword hashes the password with bcrypt.
+func (u *UserServer) hashPassword(ctx context.Context, password string) ([]byte, error) {
+	_, span := tracer.Start(ctx, "bcrypt.GenerateFromPassword",
+		trace.WithAttributes(otelattr.Int("bcrypt.cost", bcrypt.DefaultCost)))
+	defer span.End()
+
+	start := time.Now()
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if u.bcryptDuration != nil {
+		u.bcryptDuration.Observe(time.Since(start).Seconds())
+	}
+
+	return hash, err
+}
+
+// normalizeEmail validates the email address of the request field and
+// derives its canonical form. An empty address is returned as is.
+func (u *UserServer) normalizeEmail(ctx context.Context, field, raw string) (mailaddr.Address, error) {
+	logger := ctxzap.Extract(ctx)
+
+	if raw == "" {
+		return mailaddr.Address{}, nil
+	}
+
+	addr, err := mailaddr.Normalize(raw, u.emailProviderRules)
+	if err != nil {
+		logger.Info("invalid email", zap.Error(err))
+		return mailaddr.Address{}, apierror.InvalidField(field, "%v", err)
+	}
+
+	return addr, nil
+}
+
+// normalizePhone converts the phone number into the E.164 format. Numbers
+// without the international prefix are parsed in the given region, or in the
+// default one if the region is empty. An empty number is returned as is.
+func (u *UserServer) normalizePhone(ctx context.Context, raw, region string) (phone.Number, error) {
+	logger := ctxzap.Extract(ctx)
+
+	if raw == "" {
+		return phone.Number{}, nil
+	}
+	if region == "" {
+		region = u.phoneRegion
+	}
+
+	n, err := phone.Normalize(raw, region)
+	if err != nil {
+		logger.Info("invalid phone number", zap.Error(err))
+		return phone.Number{}, apierror.InvalidField("user.phone", "%v", err)
+	}
+
+	return n, nil
+}
+
+// parseCountry validates the ISO 3166-1 alpha-2 country code and returns it
+// in upper case. An empty code is returned as is.
+func parseCountry(ctx context.Context, country string) (string, error) {
+	logger := ctxzap.Extract(ctx)
+
+	country = strings.ToUpper(country)
+	if err := age.ValidateCountry(country); err != nil {
+		logger.Info("invalid country", zap.Error(err))
+		return "", apierror.InvalidField("user.country", "%v", err)
+	}
+
+	return country, nil
+}
+
+// parseBirthday returns the date of birth of the user. The birth_date field
+// takes precedence over the deprecated birthday string, which is accepted in
+// both ShortForm and ISOForm. Impossible calendar days and future dates are
+// rejected. A zero time is returned if neither field is set.
+func parseBirthday(ctx context.Context, user *userpb.User) (time.Time, error) {
+	logger := ctxzap.Extract(ctx)
+
+	var bd time.Time
+	field := "user.birth_date"
+	if d := user.GetBirthDate(); d != nil {
+		if d.GetYear() == 0 {
+			logger.Info("birth date without year")
+			return time.Time{}, apierror.InvalidField("user.birth_date.year", "%v: 'birth_date.year' field", ErrEmptyField)
+		}
+		if !util.ValidateDate(d.GetYear(), d.GetMonth(), d.GetDay()) {
+			logger.Info("invalid birth date")
+			return time.Time{}, apierror.InvalidField("user.birth_date", "invalid birth date %04d-%02d-%02d", d.GetYear(), d.GetMonth(), d.GetDay())
+		}
+
+		bd = time.Date(int(d.GetYear()), time.Month(d.GetMonth()), int(d.GetDay()), 0, 0, 0, 0, time.UTC)
+	} else if s := user.GetBirthday(); s != "" {
+		field = "user.birthday"
+
+		var err error
+		bd, err = time.Parse(ShortForm, s)
+		if err != nil {
+			bd, err = time.Parse(ISOForm, s)
+		}
+		if err != nil {
+			logger.Info("failed to parse birthday", zap.Error(err))
+			return time.Time{}, apierror.InvalidField("user.birthday", "field time is in unsupported format: %v instead of %v or %v", err, ShortForm, ISOForm)
+		}
+	} else {
+		return time.Time{}, nil
+	}
+
+	if bd.After(time.Now()) {
+		logger.Info("birthday in the future")
+		return time.Time{}, apierror.InvalidField(field, "birthday %s is in the future", bd.Format(ISOForm))
+	}
+
+	return bd, nil
+}
+
+// encodeAttributes validates the custom attributes and encodes them as JSON.
+// An empty string is returned if no attributes are given.
+func (u *UserServer) encodeAttributes(ctx context.Context, attrs *structpb.Struct) (string, error) {
+	logger := ctxzap.Extract(ctx)
+
+	if attrs == nil {
+		return "", nil
+	}
+
+	if err := u.attributes.Validate(attrs.AsMap()); err != nil {
+		logger.Info("invalid attributes", zap.Error(err))
+		return "", apierror.InvalidField("user.attributes", "%v", err)
+	}
+
+	data, err := protojson.Marshal(attrs)
+	if err != nil {
+		logger.Error("failed to encode attributes", zap.Error(err))
+		return "", status.Errorf(codes.Internal, "fail to encode attributes")
+	}
+
+	return string(data), nil
+}
+
+// parseID validates and parses the UUID of the named request field.
+func parseID(ctx context.Context, field, id string) (uuid.UUID, error) {
 	logger := ctxzap.Extract(ctx)
 
-	id := req.GetId()
 	if id == "" {
-		logger.Info("empty id")
-		return nil, status.Errorf(codes.InvalidArgument, "%v: 'id' field", ErrEmptyField)
+		logger.Info("empty id", zap.String("field", field))
+		return uuid.Nil, apierror.InvalidField(field, "%v: '%s' field", ErrEmptyField, field)
 	}
 
-	// parse ID
 	uid, err := uuid.Parse(id)
 	if err != nil {
 		logger.Info("invalid uuid", zap.String("uuid", id), zap.Error(err))
-		return nil, status.Errorf(codes.InvalidArgument, "invalid id '%v': does not follow UUID pattern", id)
+		return uuid.Nil, apierror.InvalidField(field, "invalid %s '%v': does not follow UUID pattern", field, id)
+	}
+
+	return uid, nil
+}
+
+func (u *UserServer) GetUser(ctx context.Context, req *userpb.GetUserRequest) (*userpb.GetUserResponse, error) {
+	logger := ctxzap.Extract(ctx)
+
+	id := req.GetId()
+	if id == "" && req.GetEmail() == "" {
+		logger.Info("empty id")
+		return nil, apierror.InvalidField("id", "%v: 'id' or 'email' field", ErrEmptyField)
 	}
 
 	// retrieve user
-	user, err := u.repo.GetUser(ctx, uid)
-	if err != nil {
-		code := codes.Internal
+	var user repo.User
+	var err error
+	if id != "" {
+		var uid uuid.UUID
+		uid, err = parseID(ctx, "id", id)
+		if err != nil {
+			return nil, err
+		}
 
-		if errors.Is(err, sql.ErrNoRows) {
-			code = codes.NotFound
+		user, err = u.repo.GetUser(ctx, uid)
+		if err != nil {
+			return nil, retrieveUserError(ctx, err, "id", id)
+		}
+	} else {
+		// any address of the user matches
+		var email mailaddr.Address
+		email, err = u.normalizeEmail(ctx, "email", req.GetEmail())
+		if err != nil {
+			return nil, err
 		}
 
-		logger.Error("retrieve user", zap.Error(err))
-		return nil, status.Errorf(code, "failed to retrieve user with id: %s", id)
+		user, err = u.repo.GetUserByEmail(ctx, email.Canonical)
+		if err != nil {
+			return nil, retrieveUserError(ctx, err, "email", req.GetEmail())
+		}
 	}
+	id = user.ID.String()
 
 	// construct response
 	resp := &userpb.GetUserResponse{
 		User: &userpb.User{
-			Id:        user.ID.String(),
-			Email:     user.Email,
-			Phone:     user.PhoneNumber.String,
-			FirstName: user.FirstName,
-			LastName:  user.LastName,
-			Gender:    userpb.User_Gender(user.Gender),
+			Id:           user.ID.String(),
+			Email:        user.Email,
+			Phone:        user.PhoneNumber.String,
+			PhoneCountry: user.PhoneCountry.String,
+			FirstName:    user.FirstName,
+			LastName:     user.LastName,
+			Gender:       userpb.User_Gender(user.Gender),
+			Country:      user.Country.String,
+			State:        userpb.User_State(user.State),
 		},
 	}
 	if user.BirthDay.Valid {
 		resp.User.Birthday = user.BirthDay.Time.Format(ShortForm)
+		resp.User.BirthDate = &date.Date{
+			Year:  int32(user.BirthDay.Time.Year()),
+			Month: int32(user.BirthDay.Time.Month()),
+			Day:   int32(user.BirthDay.Time.Day()),
+		}
+	}
+	if len(user.Attributes) > 0 {
+		resp.User.Attributes = &structpb.Struct{}
+		if err = protojson.Unmarshal(user.Attributes, resp.User.Attributes); err != nil {
+			logger.Error("malformed stored attributes", zap.Error(err))
+			return nil, status.Errorf(codes.Internal, "failed to retrieve user with id: %s", id)
+		}
+	}
+	if user.AvatarKey.Valid {
+		resp.User.Avatar = u.avatarToPB(user.AvatarKey.String)
 	}
 
 	return resp, nil
 }
 
+// retrieveUserError converts an error of a user lookup into a status error.
+func retrieveUserError(ctx context.Context, err error, field, value string) error {
+	logger := ctxzap.Extract(ctx)
+
+	code := codes.Internal
+
+	if errors.Is(err, sql.ErrNoRows) {
+		code = codes.NotFound
+	}
+
+	logger.Error("retrieve user", zap.Error(err))
+	return status.Errorf(code, "failed to retrieve user with %s: %s", field, value)
+}
+
 func (u *UserServer) UpdateUser(ctx context.Context, req *userpb.UpdateUserRequest) (*userpb.UpdateUserResponse, error) {
 	logger := ctxzap.Extract(ctx)
 	user := req.GetUser()
 
 	id := req.GetId()
-	if id == "" {
-		logger.Info("empty id")
-		return nil, status.Errorf(codes.InvalidArgument, "%v: 'id' field", ErrEmptyField)
-	}
-
-	// process id
-	uid, err := uuid.Parse(id)
+	uid, err := parseID(ctx, "id", id)
 	if err != nil {
-		logger.Info("invalid uuid id", zap.Error(err))
-		return nil, status.Errorf(codes.InvalidArgument, "invalid id '%v': does not follow UUID pattern", id)
+		return nil, err
 	}
 
 	// process password
 	var hashedPassword []byte
 	if p := user.GetPassword(); p != "" {
-		hashedPassword, err = bcrypt.GenerateFromPassword([]byte(p), bcrypt.DefaultCost)
+		hashedPassword, err = u.hashPassword(ctx, p)
 		if err != nil {
 			logger.Error("invalid hashed password", zap.Error(err))
 			return nil, status.Errorf(codes.Internal, "fail to hash password")
 		}
 	}
 
+	// process email
+	email, err := u.normalizeEmail(ctx, "user.email", user.GetEmail())
+	if err != nil {
+		return nil, err
+	}
+
+	// process phone number
+	phoneNumber, err := u.normalizePhone(ctx, user.GetPhone(), req.GetPhoneRegion())
+	if err != nil {
+		return nil, err
+	}
+
 	// process birthday
-	var bdTime time.Time
-	if bd := user.GetBirthday(); bd != "" {
-		bdTime, err = time.Parse(ShortForm, bd)
+	bdTime, err := parseBirthday(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	// check minimum age
+	country, err := parseCountry(ctx, user.GetCountry())
+	if err != nil {
+		return nil, err
+	}
+	var state sql.NullInt16
+	if !bdTime.IsZero() || country != "" || phoneNumber.Country != "" {
+		state, err = u.updatedState(ctx, uid, bdTime, country, phoneNumber.Country)
 		if err != nil {
-			logger.Info("invalid birthday format")
-			return nil, status.Errorf(codes.InvalidArgument, "field time is in unsupported format: %v instead of %v", err, ShortForm)
+			return nil, err
 		}
 	}
 
+	// process attributes
+	attrs, err := u.encodeAttributes(ctx, user.GetAttributes())
+	if err != nil {
+		return nil, err
+	}
+
 	// construct na argument
 	arg := repo.UpdateUserParams{
 		ID: uid,
 
-		Email:          user.GetEmail(),
-		PhoneNumber:    user.GetPhone(),
+		Email:          email.Email,
+		EmailCanonical: email.Canonical,
+		PhoneNumber:    phoneNumber.E164,
+		PhoneCountry:   sql.NullString{String: phoneNumber.Country, Valid: phoneNumber.Country != ""},
 		HashedPassword: string(hashedPassword),
 		FirstName:      user.GetFirstName(),
 		LastName:       user.GetLastName(),
 		Gender:         int16(user.GetGender()),
 		BirthDay:       bdTime,
+		Attributes:     attrs,
+		Country:        country,
+		State:          state,
 	}
 
 	// update user
 	updUser, err := u.repo.UpdateUser(ctx, arg)
 	if err != nil {
-		code := codes.Internal
+		if conflict := apierror.Conflict(err); conflict != nil {
+			logger.Info("user already exists", zap.Error(err))
+			return nil, conflict
+		}
 
+		code := codes.Internal
 		if errors.Is(err, sql.ErrNoRows) {
 			code = codes.NotFound
 		}
 
-		var pqErr *pq.Error
-		if errors.As(err, &pqErr) {
-			if pqErr.Code == "23505" {
-				code = codes.AlreadyExists
-			}
-		}
-
 		logger.Error("failed to update user", zap.Error(err))
 		return nil, status.Errorf(code, "failed to update user with an id '%s'", id)
 	}
@@ -252,18 +668,14 @@ func (u *UserServer) DeleteUser(ctx context.Context, req *userpb.DeleteUserReque
 	logger := ctxzap.Extract(ctx)
 
 	id := req.GetId()
-	if id == "" {
-		logger.Info("empty id")
-		return nil, status.Errorf(codes.InvalidArgument, "%v: 'id' field", ErrEmptyField)
-	}
-
-	// parse ID
-	uid, err := uuid.Parse(id)
+	uid, err := parseID(ctx, "id", id)
 	if err != nil {
-		logger.Info("infalid uuid id", zap.Error(err))
-		return nil, status.Errorf(codes.InvalidArgument, "invalid id '%v': does not follow UUID pattern", id)
+		return nil, err
 	}
 
+	// remember the data exports, their archives are removed with the user
+	exportKeys := u.dataExportKeys(ctx, uid)
+
 	// remove user
 	affected, err := u.repo.DeleteUser(ctx, uid)
 	if err != nil || affected != 1 {
@@ -276,6 +688,7 @@ func (u *UserServer) DeleteUser(ctx context.Context, req *userpb.DeleteUserReque
 		logger.Info("failed to delete user", zap.Error(err))
 		return nil, status.Errorf(code, "failed to delete user with id: %s", id)
 	}
+	u.deleteDataExports(ctx, exportKeys)
 
 	// construct response
 	resp := &userpb.DeleteUserResponse{
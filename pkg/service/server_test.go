@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/genproto/googleapis/type/date"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
@@ -127,6 +128,21 @@ func TestUserServer_RegisterUser(t *testing.T) {
 			expID:   "",
 			expCode: codes.InvalidArgument,
 		},
+		{
+			name:      "invalid email",
+			buildRepo: func(q *mocks.Querier) {},
+			argUser: &userpb.User{
+				Email:     "john.doe@localhost",
+				Phone:     u1.Phone,
+				Password:  u1.Password,
+				FirstName: u1.FirstName,
+				LastName:  u1.LastName,
+				Gender:    u1.Gender,
+				Birthday:  u1.Birthday,
+			},
+			expID:   "",
+			expCode: codes.InvalidArgument,
+		},
 		{
 			name:      "empty password",
 			buildRepo: func(q *mocks.Querier) {},
@@ -241,6 +257,7 @@ func TestUserServer_GetUser(t *testing.T) {
 	u1p, _ := bcrypt.GenerateFromPassword([]byte(u1.Password), bcrypt.DefaultCost)
 	u1t, err := time.Parse(service.ShortForm, u1.Birthday)
 	require.NoError(t, err)
+	u1.BirthDate = &date.Date{Year: int32(u1t.Year()), Month: int32(u1t.Month()), Day: int32(u1t.Day())}
 
 	tests := []struct {
 		name      string
@@ -0,0 +1,47 @@
+package tracing
+
+import (
+	"context"
+
+	gtags "github.com/grpc-ecosystem/go-grpc-middleware/tags"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+)
+
+// Log fields of the trace context.
+const (
+	TraceIDField = "trace_id"
+	SpanIDField  = "span_id"
+)
+
+// tagSpan records the trace context of the call in the request tags, which
+// are attached to all log lines of the call.
+func tagSpan(ctx context.Context) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return
+	}
+
+	gtags.Extract(ctx).
+		Set(TraceIDField, sc.TraceID().String()).
+		Set(SpanIDField, sc.SpanID().String())
+}
+
+// UnaryServerInterceptor tags the log lines of the unary calls with the trace
+// context. It must be chained after the tracing and the tags interceptors,
+// and before the logging interceptor.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		tagSpan(ctx)
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor tags the log lines of the streaming calls with the
+// trace context, see UnaryServerInterceptor.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		tagSpan(ss.Context())
+		return handler(srv, ss)
+	}
+}
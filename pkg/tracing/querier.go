@@ -0,0 +1,432 @@
+package tracing
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/semconv"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/chutommy/user-microservice/pkg/repo"
+)
+
+// Querier decorates a repo.Querier with a span around every query.
+type Querier struct {
+	next   repo.Querier
+	tracer trace.Tracer
+}
+
+var _ repo.Querier = (*Querier)(nil)
+
+// NewQuerier constructs a Querier tracing the queries of the next querier.
+func NewQuerier(next repo.Querier) *Querier {
+	return &Querier{
+		next:   next,
+		tracer: Tracer("github.com/chutommy/user-microservice/pkg/repo"),
+	}
+}
+
+// start starts the span of the query.
+func (q *Querier) start(ctx context.Context, query string) (context.Context, trace.Span) {
+	return q.tracer.Start(ctx, "repo."+query,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgres, semconv.DBOperationKey.String(query)),
+	)
+}
+
+// end ends the span of the query. A missing row is an expected outcome, not
+// an error of the span.
+func end(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (q *Querier) AddEmail(ctx context.Context, arg repo.AddEmailParams) (repo.UserEmail, error) {
+	ctx, span := q.start(ctx, "AddEmail")
+	r, err := q.next.AddEmail(ctx, arg)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) CompleteDataExport(ctx context.Context, arg repo.CompleteDataExportParams) (repo.DataExport, error) {
+	ctx, span := q.start(ctx, "CompleteDataExport")
+	r, err := q.next.CompleteDataExport(ctx, arg)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) CompleteIdempotencyKey(ctx context.Context, arg repo.CompleteIdempotencyKeyParams) (int64, error) {
+	ctx, span := q.start(ctx, "CompleteIdempotencyKey")
+	r, err := q.next.CompleteIdempotencyKey(ctx, arg)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) ConfirmGuardianConsent(ctx context.Context, arg repo.ConfirmGuardianConsentParams) (repo.ConfirmGuardianConsentRow, error) {
+	ctx, span := q.start(ctx, "ConfirmGuardianConsent")
+	r, err := q.next.ConfirmGuardianConsent(ctx, arg)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) CountUncanonicalEmails(ctx context.Context) (int64, error) {
+	ctx, span := q.start(ctx, "CountUncanonicalEmails")
+	r, err := q.next.CountUncanonicalEmails(ctx)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) CountUsers(ctx context.Context) ([]repo.CountUsersRow, error) {
+	ctx, span := q.start(ctx, "CountUsers")
+	r, err := q.next.CountUsers(ctx)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) CreateAddress(ctx context.Context, arg repo.CreateAddressParams) (repo.UserAddress, error) {
+	ctx, span := q.start(ctx, "CreateAddress")
+	r, err := q.next.CreateAddress(ctx, arg)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) CreateConsentPolicy(ctx context.Context, arg repo.CreateConsentPolicyParams) (repo.ConsentPolicy, error) {
+	ctx, span := q.start(ctx, "CreateConsentPolicy")
+	r, err := q.next.CreateConsentPolicy(ctx, arg)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) CreateDataExport(ctx context.Context, arg repo.CreateDataExportParams) (repo.DataExport, error) {
+	ctx, span := q.start(ctx, "CreateDataExport")
+	r, err := q.next.CreateDataExport(ctx, arg)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) CreateGuardianConsent(ctx context.Context, arg repo.CreateGuardianConsentParams) (repo.GuardianConsent, error) {
+	ctx, span := q.start(ctx, "CreateGuardianConsent")
+	r, err := q.next.CreateGuardianConsent(ctx, arg)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) CreateUser(ctx context.Context, arg repo.CreateUserParams) (repo.User, error) {
+	ctx, span := q.start(ctx, "CreateUser")
+	r, err := q.next.CreateUser(ctx, arg)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) DeleteAddress(ctx context.Context, arg repo.DeleteAddressParams) (int64, error) {
+	ctx, span := q.start(ctx, "DeleteAddress")
+	r, err := q.next.DeleteAddress(ctx, arg)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	ctx, span := q.start(ctx, "DeleteExpiredIdempotencyKeys")
+	r, err := q.next.DeleteExpiredIdempotencyKeys(ctx)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) DeleteGuardianConsent(ctx context.Context, id uuid.UUID) error {
+	ctx, span := q.start(ctx, "DeleteGuardianConsent")
+	err := q.next.DeleteGuardianConsent(ctx, id)
+	end(span, err)
+
+	return err
+}
+
+func (q *Querier) DeletePreference(ctx context.Context, arg repo.DeletePreferenceParams) (int64, error) {
+	ctx, span := q.start(ctx, "DeletePreference")
+	r, err := q.next.DeletePreference(ctx, arg)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) DeleteUser(ctx context.Context, id uuid.UUID) (int64, error) {
+	ctx, span := q.start(ctx, "DeleteUser")
+	r, err := q.next.DeleteUser(ctx, id)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) EraseUser(ctx context.Context, arg repo.EraseUserParams) (repo.ErasureReceipt, error) {
+	ctx, span := q.start(ctx, "EraseUser")
+	r, err := q.next.EraseUser(ctx, arg)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) GetConsentPolicy(ctx context.Context, arg repo.GetConsentPolicyParams) (repo.ConsentPolicy, error) {
+	ctx, span := q.start(ctx, "GetConsentPolicy")
+	r, err := q.next.GetConsentPolicy(ctx, arg)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) GetDataExport(ctx context.Context, id uuid.UUID) (repo.DataExport, error) {
+	ctx, span := q.start(ctx, "GetDataExport")
+	r, err := q.next.GetDataExport(ctx, id)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) GetIdempotencyKey(ctx context.Context, arg repo.GetIdempotencyKeyParams) (repo.IdempotencyKey, error) {
+	ctx, span := q.start(ctx, "GetIdempotencyKey")
+	r, err := q.next.GetIdempotencyKey(ctx, arg)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) GetUser(ctx context.Context, id uuid.UUID) (repo.User, error) {
+	ctx, span := q.start(ctx, "GetUser")
+	r, err := q.next.GetUser(ctx, id)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) GetUserByEmail(ctx context.Context, emailCanonical string) (repo.User, error) {
+	ctx, span := q.start(ctx, "GetUserByEmail")
+	r, err := q.next.GetUserByEmail(ctx, emailCanonical)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) HasGuardianConsent(ctx context.Context, minorID uuid.UUID) (bool, error) {
+	ctx, span := q.start(ctx, "HasGuardianConsent")
+	r, err := q.next.HasGuardianConsent(ctx, minorID)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) ListAddresses(ctx context.Context, arg repo.ListAddressesParams) ([]repo.UserAddress, error) {
+	ctx, span := q.start(ctx, "ListAddresses")
+	r, err := q.next.ListAddresses(ctx, arg)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) ListConsents(ctx context.Context, userID uuid.UUID) ([]repo.UserConsent, error) {
+	ctx, span := q.start(ctx, "ListConsents")
+	r, err := q.next.ListConsents(ctx, userID)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) ListDataExportBlobKeys(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	ctx, span := q.start(ctx, "ListDataExportBlobKeys")
+	r, err := q.next.ListDataExportBlobKeys(ctx, userID)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) ListEmailCanonicals(ctx context.Context, arg repo.ListEmailCanonicalsParams) ([]repo.ListEmailCanonicalsRow, error) {
+	ctx, span := q.start(ctx, "ListEmailCanonicals")
+	r, err := q.next.ListEmailCanonicals(ctx, arg)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) ListEmails(ctx context.Context, userID uuid.UUID) ([]repo.UserEmail, error) {
+	ctx, span := q.start(ctx, "ListEmails")
+	r, err := q.next.ListEmails(ctx, userID)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) ListErasureReceipts(ctx context.Context, userID uuid.UUID) ([]repo.ErasureReceipt, error) {
+	ctx, span := q.start(ctx, "ListErasureReceipts")
+	r, err := q.next.ListErasureReceipts(ctx, userID)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) ListGuardianConsents(ctx context.Context, userID uuid.UUID) ([]repo.GuardianConsent, error) {
+	ctx, span := q.start(ctx, "ListGuardianConsents")
+	r, err := q.next.ListGuardianConsents(ctx, userID)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) ListPreferences(ctx context.Context, arg repo.ListPreferencesParams) ([]repo.UserPreference, error) {
+	ctx, span := q.start(ctx, "ListPreferences")
+	r, err := q.next.ListPreferences(ctx, arg)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) ListUnnormalizedPhones(ctx context.Context, arg repo.ListUnnormalizedPhonesParams) ([]repo.ListUnnormalizedPhonesRow, error) {
+	ctx, span := q.start(ctx, "ListUnnormalizedPhones")
+	r, err := q.next.ListUnnormalizedPhones(ctx, arg)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) ListUnpublishedErasureReceipts(ctx context.Context, arg repo.ListUnpublishedErasureReceiptsParams) ([]repo.ErasureReceipt, error) {
+	ctx, span := q.start(ctx, "ListUnpublishedErasureReceipts")
+	r, err := q.next.ListUnpublishedErasureReceipts(ctx, arg)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) ListUsers(ctx context.Context, arg repo.ListUsersParams) ([]repo.User, error) {
+	ctx, span := q.start(ctx, "ListUsers")
+	r, err := q.next.ListUsers(ctx, arg)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) ListUsersRequiringConsent(ctx context.Context, arg repo.ListUsersRequiringConsentParams) ([]uuid.UUID, error) {
+	ctx, span := q.start(ctx, "ListUsersRequiringConsent")
+	r, err := q.next.ListUsersRequiringConsent(ctx, arg)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) MarkErasureReceiptPublished(ctx context.Context, id uuid.UUID) error {
+	ctx, span := q.start(ctx, "MarkErasureReceiptPublished")
+	err := q.next.MarkErasureReceiptPublished(ctx, id)
+	end(span, err)
+
+	return err
+}
+
+func (q *Querier) RecordConsent(ctx context.Context, arg repo.RecordConsentParams) (repo.UserConsent, error) {
+	ctx, span := q.start(ctx, "RecordConsent")
+	r, err := q.next.RecordConsent(ctx, arg)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) ReleaseIdempotencyKey(ctx context.Context, arg repo.ReleaseIdempotencyKeyParams) (int64, error) {
+	ctx, span := q.start(ctx, "ReleaseIdempotencyKey")
+	r, err := q.next.ReleaseIdempotencyKey(ctx, arg)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) RemoveEmail(ctx context.Context, arg repo.RemoveEmailParams) (int64, error) {
+	ctx, span := q.start(ctx, "RemoveEmail")
+	r, err := q.next.RemoveEmail(ctx, arg)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) ReserveIdempotencyKey(ctx context.Context, arg repo.ReserveIdempotencyKeyParams) (repo.IdempotencyKey, error) {
+	ctx, span := q.start(ctx, "ReserveIdempotencyKey")
+	r, err := q.next.ReserveIdempotencyKey(ctx, arg)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) SetEmailCanonical(ctx context.Context, arg repo.SetEmailCanonicalParams) error {
+	ctx, span := q.start(ctx, "SetEmailCanonical")
+	err := q.next.SetEmailCanonical(ctx, arg)
+	end(span, err)
+
+	return err
+}
+
+func (q *Querier) SetPreferences(ctx context.Context, arg repo.SetPreferencesParams) ([]repo.UserPreference, error) {
+	ctx, span := q.start(ctx, "SetPreferences")
+	r, err := q.next.SetPreferences(ctx, arg)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) SetPrimaryEmail(ctx context.Context, arg repo.SetPrimaryEmailParams) (repo.User, error) {
+	ctx, span := q.start(ctx, "SetPrimaryEmail")
+	r, err := q.next.SetPrimaryEmail(ctx, arg)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) SetUserAvatar(ctx context.Context, arg repo.SetUserAvatarParams) (sql.NullString, error) {
+	ctx, span := q.start(ctx, "SetUserAvatar")
+	r, err := q.next.SetUserAvatar(ctx, arg)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) SetUserPhone(ctx context.Context, arg repo.SetUserPhoneParams) error {
+	ctx, span := q.start(ctx, "SetUserPhone")
+	err := q.next.SetUserPhone(ctx, arg)
+	end(span, err)
+
+	return err
+}
+
+func (q *Querier) UpdateAddress(ctx context.Context, arg repo.UpdateAddressParams) (repo.UserAddress, error) {
+	ctx, span := q.start(ctx, "UpdateAddress")
+	r, err := q.next.UpdateAddress(ctx, arg)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) UpdateUser(ctx context.Context, arg repo.UpdateUserParams) (repo.User, error) {
+	ctx, span := q.start(ctx, "UpdateUser")
+	r, err := q.next.UpdateUser(ctx, arg)
+	end(span, err)
+
+	return r, err
+}
+
+func (q *Querier) WithdrawConsent(ctx context.Context, arg repo.WithdrawConsentParams) (int64, error) {
+	ctx, span := q.start(ctx, "WithdrawConsent")
+	r, err := q.next.WithdrawConsent(ctx, arg)
+	end(span, err)
+
+	return r, err
+}
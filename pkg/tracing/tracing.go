@@ -0,0 +1,85 @@
+// Package tracing sets up the OpenTelemetry tracing of the user service.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpgrpc"
+	"go.opentelemetry.io/otel/exporters/stdout"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/semconv"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ServiceName identifies the user service in the traces.
+const ServiceName = "user-service"
+
+// Exporters of the spans.
+const (
+	ExporterNone   = ""
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// DefaultOTLPEndpoint is the address of a local OpenTelemetry collector.
+const DefaultOTLPEndpoint = "localhost:4317"
+
+// ErrUnknownExporter is returned if the exporter is not supported.
+var ErrUnknownExporter = errors.New("unknown trace exporter")
+
+// Tracer returns the tracer of the instrumentation library with the given name.
+func Tracer(name string) trace.Tracer {
+	return otel.Tracer(name)
+}
+
+// Setup installs the global tracer provider exporting the spans with the
+// exporter, and the W3C trace context and baggage propagators. No spans are
+// recorded by ExporterNone, the trace context is still propagated. The
+// returned function flushes and stops the provider.
+func Setup(ctx context.Context, exporter, endpoint string) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var exp sdktrace.SpanExporter
+	switch exporter {
+	case ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		e, err := stdout.NewExporter(stdout.WithWriter(os.Stdout), stdout.WithoutMetricExport())
+		if err != nil {
+			return nil, err
+		}
+		exp = e
+	case ExporterOTLP:
+		if endpoint == "" {
+			endpoint = DefaultOTLPEndpoint
+		}
+		e, err := otlp.NewExporter(ctx, otlpgrpc.NewDriver(
+			otlpgrpc.WithInsecure(),
+			otlpgrpc.WithEndpoint(endpoint),
+		))
+		if err != nil {
+			return nil, err
+		}
+		exp = e
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownExporter, exporter)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.ServiceNameKey.String(ServiceName))),
+	)
+	otel.SetTracerProvider(tp)
+
+	return tp.Shutdown, nil
+}
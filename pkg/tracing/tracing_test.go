@@ -0,0 +1,118 @@
+package tracing_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	gtags "github.com/grpc-ecosystem/go-grpc-middleware/tags"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/semconv"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+
+	"github.com/chutommy/user-microservice/pkg/mocks"
+	"github.com/chutommy/user-microservice/pkg/repo"
+	"github.com/chutommy/user-microservice/pkg/tracing"
+)
+
+// record installs a global tracer provider recording the spans in memory.
+func record(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+
+	exp := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp))
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() {
+		require.NoError(t, tp.Shutdown(context.Background()))
+	})
+
+	return exp
+}
+
+func TestQuerier(t *testing.T) {
+	exp := record(t)
+
+	id := uuid.New()
+	dbErr := errors.New("connection reset")
+
+	tests := []struct {
+		name   string
+		err    error
+		status codes.Code
+	}{
+		{name: "ok", status: codes.Unset},
+		{name: "no rows", err: sql.ErrNoRows, status: codes.Unset},
+		{name: "error", err: dbErr, status: codes.Error},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exp.Reset()
+
+			q := new(mocks.Querier)
+			q.On("GetUser", mock.Anything, id).Return(repo.User{ID: id}, tt.err).Once()
+
+			ctx, parent := otel.Tracer("test").Start(context.Background(), "parent")
+			u, err := tracing.NewQuerier(q).GetUser(ctx, id)
+			parent.End()
+
+			require.Equal(t, tt.err, err)
+			require.Equal(t, id, u.ID)
+			q.AssertExpectations(t)
+
+			spans := exp.GetSpans()
+			require.Len(t, spans, 2)
+			span := spans[0]
+			require.Equal(t, "repo.GetUser", span.Name)
+			require.Equal(t, spans[1].SpanContext.SpanID(), span.Parent.SpanID())
+			require.Equal(t, tt.status, span.StatusCode)
+			require.Contains(t, span.Attributes, semconv.DBSystemPostgres)
+			require.Contains(t, span.Attributes, semconv.DBOperationKey.String("GetUser"))
+		})
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	record(t)
+
+	tests := []struct {
+		name   string
+		traced bool
+	}{
+		{name: "traced", traced: true},
+		{name: "untraced", traced: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := gtags.SetInContext(context.Background(), gtags.NewTags())
+			if tt.traced {
+				var span trace.Span
+				ctx, span = otel.Tracer("test").Start(ctx, "call")
+				defer span.End()
+			}
+
+			_, err := tracing.UnaryServerInterceptor()(ctx, nil, &grpc.UnaryServerInfo{},
+				func(ctx context.Context, _ interface{}) (interface{}, error) {
+					return nil, nil
+				})
+			require.NoError(t, err)
+
+			values := gtags.Extract(ctx).Values()
+			if !tt.traced {
+				require.NotContains(t, values, tracing.TraceIDField)
+				return
+			}
+			require.Len(t, values[tracing.TraceIDField], 32)
+			require.Len(t, values[tracing.SpanIDField], 16)
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package util
+
+import (
+	"context"
+	"time"
+)
+
+// detached is a context which is never canceled but carries the values of
+// its parent.
+type detached struct {
+	parent context.Context
+}
+
+func (detached) Deadline() (time.Time, bool)         { return time.Time{}, false }
+func (detached) Done() <-chan struct{}               { return nil }
+func (detached) Err() error                          { return nil }
+func (d detached) Value(key interface{}) interface{} { return d.parent.Value(key) }
+
+// WithoutCancel returns a copy of the parent context which is not canceled
+// when the parent is. It lets the cleanups of a call outlive the call.
+func WithoutCancel(parent context.Context) context.Context {
+	return detached{parent: parent}
+}
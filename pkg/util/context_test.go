@@ -0,0 +1,27 @@
+package util_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/chutommy/user-microservice/pkg/util"
+)
+
+type key struct{}
+
+func TestWithoutCancel(t *testing.T) {
+	t.Parallel()
+
+	parent, cancel := context.WithCancel(context.WithValue(context.Background(), key{}, "value"))
+	ctx := util.WithoutCancel(parent)
+	cancel()
+
+	require.Error(t, parent.Err())
+	require.NoError(t, ctx.Err())
+	require.Nil(t, ctx.Done())
+	_, ok := ctx.Deadline()
+	require.False(t, ok)
+	require.Equal(t, "value", ctx.Value(key{}))
+}
@@ -1,10 +1,20 @@
 package util
 
+import "time"
+
 // ValidateDate returns false if the given calendar date
-// is out of the normalized range.
+// is out of the normalized range or the day does not exist
+// in the month, e.g. 31 April or 29 February of a common year.
 func ValidateDate(year, month, day int32) bool {
-	if (year < 0) || (month > 12 || month < 1) || (day > 31 || day < 1) {
+	if (year < 0) || (month > 12 || month < 1) || (day < 1) {
 		return false
 	}
-	return true
+	return day <= DaysIn(year, month)
+}
+
+// DaysIn returns the number of days in the month of the year
+// of the proleptic Gregorian calendar.
+func DaysIn(year, month int32) int32 {
+	// day 0 of the next month is the last day of the month
+	return int32(time.Date(int(year), time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC).Day())
 }
@@ -101,6 +101,54 @@ func TestValidateDate(t *testing.T) {
 			day:   32,
 			valid: false,
 		},
+		{
+			year:  2003,
+			month: 2,
+			day:   31,
+			valid: false,
+		},
+		{
+			year:  2003,
+			month: 4,
+			day:   31,
+			valid: false,
+		},
+		{
+			year:  2003,
+			month: 4,
+			day:   30,
+			valid: true,
+		},
+		{
+			year:  2003,
+			month: 2,
+			day:   29,
+			valid: false,
+		},
+		{
+			year:  2004,
+			month: 2,
+			day:   29,
+			valid: true,
+		},
+		{
+			year:  1900,
+			month: 2,
+			day:   29,
+			valid: false,
+		},
+		{
+			year:  2000,
+			month: 2,
+			day:   29,
+			valid: true,
+		},
+		{
+			year:  2004,
+			month: 2,
+			day:   30,
+			valid: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -110,3 +158,25 @@ func TestValidateDate(t *testing.T) {
 		})
 	}
 }
+
+func TestDaysIn(t *testing.T) {
+	tests := []struct {
+		year  int32
+		month int32
+		days  int32
+	}{
+		{year: 2021, month: 1, days: 31},
+		{year: 2021, month: 2, days: 28},
+		{year: 2020, month: 2, days: 29},
+		{year: 2100, month: 2, days: 28},
+		{year: 2400, month: 2, days: 29},
+		{year: 2021, month: 4, days: 30},
+		{year: 2021, month: 12, days: 31},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d/%d (mm/yyyy)", tt.month, tt.year), func(t *testing.T) {
+			require.Equal(t, tt.days, util.DaysIn(tt.year, tt.month))
+		})
+	}
+}
@@ -47,11 +47,13 @@ func RandomEmail() string {
 	return sb.String()
 }
 
-// RandomEmail returns a random phone number.
+// RandomPhoneNumber returns a random valid phone number in the E.164 format.
 func RandomPhoneNumber() string {
 	var sb strings.Builder
 
-	for n := 0; n < 9; n++ {
+	// czech mobile number prefix
+	sb.WriteString("+420601")
+	for n := 0; n < 6; n++ {
 		sb.WriteByte(numbers[rand.Intn(len(numbers))])
 	}
 
@@ -0,0 +1,71 @@
+// Package validation enforces the validation rules annotated in the proto
+// definitions on the incoming requests before they reach the handlers. The
+// rules limit the lengths of the email addresses only, their syntax is checked
+// by mailaddr.Normalize, which accepts internationalized domain names.
+package validation
+
+import (
+	"context"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	"github.com/chutommy/user-microservice/pkg/apierror"
+)
+
+// validator is a message generated with the validation rules.
+type validator interface {
+	ValidateAll() error
+}
+
+// Validate checks the message by its rules. Messages without rules are
+// always valid. The returned error is an InvalidArgument status with the
+// BadRequest violations of all the invalid fields.
+func Validate(ctx context.Context, msg interface{}) error {
+	v, ok := msg.(validator)
+	if !ok {
+		return nil
+	}
+
+	if err := v.ValidateAll(); err != nil {
+		ctxzap.Extract(ctx).Info("invalid request", zap.Error(err))
+		return apierror.Validation(err)
+	}
+
+	return nil
+}
+
+// UnaryServerInterceptor rejects the invalid requests of the unary calls.
+// The gateway forwards its requests to the gRPC server, so they are checked
+// as well.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := Validate(ctx, req); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor rejects the invalid messages received by the
+// streaming calls, the handler gets the error from RecvMsg.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &stream{ServerStream: ss})
+	}
+}
+
+// stream validates the received messages.
+type stream struct {
+	grpc.ServerStream
+}
+
+func (s *stream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	return Validate(s.Context(), m)
+}
@@ -0,0 +1,282 @@
+package validation_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/chutommy/user-microservice/pkg/grpc/userpb"
+	"github.com/chutommy/user-microservice/pkg/validation"
+)
+
+const id = "4f6d3b8a-1c1e-4e7a-9a53-7c1c2f0f6a11"
+
+func validUser() *userpb.User {
+	return &userpb.User{
+		Email:     "alice@example.org",
+		Phone:     "+12015550123",
+		Password:  "secret",
+		FirstName: "Alice",
+		LastName:  "Smith",
+		Gender:    userpb.User_FEMALE,
+		Country:   "US",
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		req    interface{}
+		fields []string
+	}{
+		{
+			name: "register",
+			req:  &userpb.RegisterUserRequest{User: validUser()},
+		},
+		{
+			name:   "register without user",
+			req:    &userpb.RegisterUserRequest{},
+			fields: []string{"user"},
+		},
+		{
+			name: "internationalized email",
+			req: &userpb.RegisterUserRequest{User: func() *userpb.User {
+				u := validUser()
+				u.Email = "user@bücher.de"
+				return u
+			}()},
+		},
+		{
+			name: "long email",
+			req: &userpb.RegisterUserRequest{User: func() *userpb.User {
+				u := validUser()
+				u.Email = strings.Repeat("a", 250) + "@example.org"
+				return u
+			}()},
+			fields: []string{"user.email"},
+		},
+		{
+			name: "long first name",
+			req: &userpb.RegisterUserRequest{User: func() *userpb.User {
+				u := validUser()
+				u.FirstName = strings.Repeat("á", 65)
+				return u
+			}()},
+			fields: []string{"user.first_name"},
+		},
+		{
+			name: "long password",
+			req: &userpb.RegisterUserRequest{User: func() *userpb.User {
+				u := validUser()
+				u.Password = strings.Repeat("p", 73)
+				return u
+			}()},
+			fields: []string{"user.password"},
+		},
+		{
+			name: "undefined gender",
+			req: &userpb.RegisterUserRequest{User: func() *userpb.User {
+				u := validUser()
+				u.Gender = 9
+				return u
+			}()},
+			fields: []string{"user.gender"},
+		},
+		{
+			name:   "phone region",
+			req:    &userpb.RegisterUserRequest{User: validUser(), PhoneRegion: "USA"},
+			fields: []string{"phone_region"},
+		},
+		{
+			name: "get by email",
+			req:  &userpb.GetUserRequest{Email: "alice@example.org"},
+		},
+		{
+			name: "get by internationalized email",
+			req:  &userpb.GetUserRequest{Email: "user@bücher.de"},
+		},
+		{
+			name:   "get by invalid id",
+			req:    &userpb.GetUserRequest{Id: "42"},
+			fields: []string{"id"},
+		},
+		{
+			name: "partial update",
+			req:  &userpb.UpdateUserRequest{Id: id, User: &userpb.User{LastName: "Jones"}},
+		},
+		{
+			name:   "update without id",
+			req:    &userpb.UpdateUserRequest{User: &userpb.User{LastName: "Jones"}},
+			fields: []string{"id"},
+		},
+		{
+			name: "delete",
+			req:  &userpb.DeleteUserRequest{Id: id},
+		},
+		{
+			name:   "delete by invalid id",
+			req:    &userpb.DeleteUserRequest{Id: "not-a-uuid"},
+			fields: []string{"id"},
+		},
+		{
+			name:   "erase by invalid id",
+			req:    &userpb.EraseUserRequest{Id: "42"},
+			fields: []string{"id"},
+		},
+		{
+			name: "record consent",
+			req:  &userpb.RecordConsentRequest{Id: id, Policy: &userpb.PolicyVersion{Kind: "tos", Version: 1}},
+		},
+		{
+			name:   "record consent without policy",
+			req:    &userpb.RecordConsentRequest{Id: id},
+			fields: []string{"policy"},
+		},
+		{
+			name:   "record consent of unversioned policy",
+			req:    &userpb.RecordConsentRequest{Id: id, Policy: &userpb.PolicyVersion{Kind: "tos"}},
+			fields: []string{"policy.version"},
+		},
+		{
+			name:   "withdraw consent without policy kind",
+			req:    &userpb.WithdrawConsentRequest{Id: id},
+			fields: []string{"policy_kind"},
+		},
+		{
+			name: "set preferences",
+			req: &userpb.SetPreferencesRequest{Id: id, Preferences: []*userpb.Preference{
+				{Namespace: "notifications", Key: "email_digest", Value: &userpb.Preference_BoolValue{BoolValue: true}},
+			}},
+		},
+		{
+			name: "set preferences with invalid key",
+			req: &userpb.SetPreferencesRequest{Id: id, Preferences: []*userpb.Preference{
+				{Namespace: "notifications", Key: "Email Digest"},
+			}},
+			fields: []string{"preferences[0].key"},
+		},
+		{
+			name:   "delete preference without namespace",
+			req:    &userpb.DeletePreferenceRequest{Id: id, Key: "email_digest"},
+			fields: []string{"namespace"},
+		},
+		{
+			name:   "add email without email",
+			req:    &userpb.AddEmailRequest{Id: id},
+			fields: []string{"email"},
+		},
+		{
+			name:   "list addresses of undefined kind",
+			req:    &userpb.ListAddressesRequest{UserId: id, Kind: 42},
+			fields: []string{"kind"},
+		},
+		{
+			name:   "confirm guardian consent by invalid id",
+			req:    &userpb.ConfirmGuardianConsentRequest{ConsentId: "42", GuardianId: id},
+			fields: []string{"consent_id"},
+		},
+		{
+			name: "create address",
+			req: &userpb.CreateAddressRequest{UserId: id, Address: &userpb.Address{
+				Recipient: "Alice Smith", Line1: "1600 Amphitheatre Parkway", City: "Mountain View", CountryCode: "US",
+			}},
+		},
+		{
+			name: "create address with long fields",
+			req: &userpb.CreateAddressRequest{UserId: id, Address: &userpb.Address{
+				Recipient: "Alice Smith", Line1: strings.Repeat("1", 129), City: "Mountain View",
+				PostalCode: strings.Repeat("9", 17), CountryCode: "USA",
+			}},
+			fields: []string{"address.line1", "address.postal_code", "address.country_code"},
+		},
+		{
+			name: "message without rules",
+			req:  "plain",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			called := false
+			_, err := validation.UnaryServerInterceptor()(context.Background(), tt.req, &grpc.UnaryServerInfo{},
+				func(context.Context, interface{}) (interface{}, error) {
+					called = true
+					return nil, nil
+				})
+
+			if tt.fields == nil {
+				require.NoError(t, err)
+				require.True(t, called)
+				return
+			}
+
+			require.False(t, called)
+			require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+			details := status.Convert(err).Details()
+			require.Len(t, details, 1)
+
+			var fields []string
+			for _, v := range details[0].(*errdetails.BadRequest).GetFieldViolations() {
+				fields = append(fields, v.GetField())
+				require.Contains(t, status.Convert(err).Message(), v.GetField())
+			}
+			require.Equal(t, tt.fields, fields)
+		})
+	}
+}
+
+// recvStream receives the message of the test.
+type recvStream struct {
+	grpc.ServerStream
+	msg proto.Message
+}
+
+func (s *recvStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *recvStream) RecvMsg(m interface{}) error {
+	proto.Merge(m.(proto.Message), s.msg)
+	return nil
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		msg  proto.Message
+		code codes.Code
+	}{
+		{"valid", &userpb.ExportUsersRequest{Gender: userpb.User_MALE}, codes.OK},
+		{"invalid", &userpb.DeleteUserRequest{Id: "42"}, codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validation.StreamServerInterceptor()(nil, &recvStream{msg: tt.msg}, &grpc.StreamServerInfo{},
+				func(_ interface{}, ss grpc.ServerStream) error {
+					return ss.RecvMsg(proto.Clone(tt.msg))
+				})
+			require.Equal(t, tt.code, status.Code(err))
+		})
+	}
+}